@@ -61,6 +61,7 @@ var RootCmd = &cobra.Command{
 
 var (
 	logLevelRaw string
+	logFormat   string
 	logger      = capnslog.NewPackageLogger("github.com/rook/rook", "rookcmd")
 )
 
@@ -70,6 +71,7 @@ var (
 //  3. command line parameter
 func init() {
 	RootCmd.PersistentFlags().StringVar(&logLevelRaw, "log-level", "INFO", "logging level for logging/tracing output (valid values: ERROR,WARNING,INFO,DEBUG)")
+	RootCmd.PersistentFlags().StringVar(&logFormat, "log-format", util.LogFormatText, "format of the logs (valid values: text,json)")
 	RootCmd.InitDefaultHelpCmd()
 	RootCmd.InitDefaultHelpFlag()
 	RootCmd.InitDefaultCompletionCmd()
@@ -100,8 +102,9 @@ func init() {
 	//log.SetLogger(logr.New(log.NullLogSink{}))
 }
 
-// SetLogLevel set log level based on provided log option.
+// SetLogLevel set log level and format based on provided log options.
 func SetLogLevel() {
+	util.SetLogFormat(logFormat, logger)
 	util.SetGlobalLogLevel(logLevelRaw, logger)
 }
 
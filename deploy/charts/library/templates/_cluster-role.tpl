@@ -87,6 +87,8 @@ rules:
       - cephfilesystemsubvolumegroups
       - cephblockpoolradosnamespaces
       - cephcosidrivers
+      - cephcrushrules
+      - cephdashboardusers
     verbs:
       - get
       - list
@@ -20,6 +20,26 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+const (
+	// RemapMonAddressesAnnotation is an annotation on the CephCluster requesting the operator to
+	// rewrite the addresses in the monmap to the current IPs of the mon nodes. The operator removes
+	// the annotation once the mon addresses are remapped.
+	RemapMonAddressesAnnotation = "ceph.rook.io/remap-mon-addresses"
+
+	// RestoreMonQuorumAnnotation is an annotation on the CephCluster requesting the operator to
+	// restore the mon quorum from a single surviving mon. The value is the name of the mon to
+	// restore the quorum from, or empty for the operator to pick the healthiest surviving mon. The
+	// quorum is not restored while any mon is in quorum. The operator removes the annotation once
+	// the quorum is restored or the restore is refused.
+	RestoreMonQuorumAnnotation = "ceph.rook.io/restore-mon-quorum"
+
+	// DisableStretchModeAnnotation is an annotation on the CephCluster confirming that the Ceph
+	// stretch mode can be disabled when the stretch cluster is changed to the pools mode, which moves
+	// all the pools off the stretch rule. The operator removes the annotation once the stretch mode
+	// is disabled.
+	DisableStretchModeAnnotation = "ceph.rook.io/disable-stretch-mode"
+)
+
 // AnnotationsSpec is the main spec annotation for all daemons
 // +kubebuilder:pruning:PreserveUnknownFields
 // +nullable
@@ -37,6 +37,12 @@ func (c *ClusterSpec) IsStretchCluster() bool {
 	return c.Mon.StretchCluster != nil && len(c.Mon.StretchCluster.Zones) > 0
 }
 
+// StretchModeEnabled returns whether the Ceph stretch mode is enabled for a stretch cluster, as
+// opposed to a stretch cluster only stretching the pools with stretch settings
+func (c *ClusterSpec) StretchModeEnabled() bool {
+	return c.IsStretchCluster() && c.Mon.StretchCluster.Mode != StretchModePools
+}
+
 func (c *ClusterSpec) ZonesRequired() bool {
 	return c.IsStretchCluster() || len(c.Mon.Zones) > 0
 }
@@ -44,3 +50,24 @@ func (c *ClusterSpec) ZonesRequired() bool {
 func (c *CephCluster) GetStatusConditions() *[]Condition {
 	return &c.Status.Conditions
 }
+
+func (u *CephDashboardUser) GetStatusConditions() *[]Condition {
+	return &u.Status.Conditions
+}
+
+// GetUsername returns the name of the user in the dashboard
+func (u *CephDashboardUser) GetUsername() string {
+	if u.Spec.Username != "" {
+		return u.Spec.Username
+	}
+	return u.Name
+}
+
+// GetUsernames returns the names of the users in the dashboard, the members of the group if the
+// CR declares a group
+func (u *CephDashboardUser) GetUsernames() []string {
+	if len(u.Spec.Members) > 0 {
+		return u.Spec.Members
+	}
+	return []string{u.GetUsername()}
+}
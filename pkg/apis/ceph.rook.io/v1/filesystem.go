@@ -19,3 +19,27 @@ package v1
 func (c *CephFilesystem) GetStatusConditions() *[]Condition {
 	return &c.Status.Conditions
 }
+
+// ActiveMDSCount returns the number of active MDS ranks of the filesystem. When the MDS autoscaler
+// is enabled, this is the count chosen by the autoscaler, otherwise the activeCount of the spec.
+func (c *CephFilesystem) ActiveMDSCount() int32 {
+	autoscaler := c.Spec.MetadataServer.Autoscaler
+	if autoscaler == nil || !autoscaler.Enabled || c.Status == nil || c.Status.MDSAutoscaler == nil || c.Status.MDSAutoscaler.ActiveCount < 1 {
+		return c.Spec.MetadataServer.ActiveCount
+	}
+	return c.Status.MDSAutoscaler.ActiveCount
+}
+
+// MaxMDS returns the number of active MDS ranks to configure in Ceph (max_mds). This is lower than the
+// active MDS count while the autoscaler waits for the removed ranks to stop.
+func (c *CephFilesystem) MaxMDS() int32 {
+	activeCount := c.ActiveMDSCount()
+	autoscaler := c.Spec.MetadataServer.Autoscaler
+	if autoscaler == nil || !autoscaler.Enabled || c.Status == nil || c.Status.MDSAutoscaler == nil {
+		return activeCount
+	}
+	if pending := c.Status.MDSAutoscaler.PendingActiveCount; pending > 0 && pending < activeCount {
+		return pending
+	}
+	return activeCount
+}
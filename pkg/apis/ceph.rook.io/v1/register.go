@@ -89,6 +89,10 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&CephBlockPoolRadosNamespaceList{},
 		&CephCOSIDriver{},
 		&CephCOSIDriverList{},
+		&CephCrushRule{},
+		&CephCrushRuleList{},
+		&CephDashboardUser{},
+		&CephDashboardUserList{},
 	)
 	metav1.AddToGroupVersion(scheme, SchemeGroupVersion)
 	scheme.AddKnownTypes(bktv1alpha1.SchemeGroupVersion,
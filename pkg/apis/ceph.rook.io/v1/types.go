@@ -198,6 +198,12 @@ type ClusterSpec struct {
 	// +nullable
 	Mgr MgrSpec `json:"mgr,omitempty"`
 
+	// Balancer is the configuration of the mgr balancer module. When set, the status of the
+	// balancer is reported in the ceph status.
+	// +optional
+	// +nullable
+	Balancer *BalancerSpec `json:"balancer,omitempty"`
+
 	// Remove the OSD that is out and safe to remove only if this option is true
 	// +optional
 	RemoveOSDsIfOutAndSafeToRemove bool `json:"removeOSDsIfOutAndSafeToRemove,omitempty"`
@@ -366,6 +372,93 @@ type DashboardSpec struct {
 	// Whether to verify the ssl endpoint for prometheus. Set to false for a self-signed cert.
 	// +optional
 	PrometheusEndpointSSLVerify bool `json:"prometheusEndpointSSLVerify,omitempty"`
+	// TLS is the certificate of the dashboard when SSL is enabled. If not set, the dashboard
+	// generates a self-signed certificate.
+	// +optional
+	// +nullable
+	TLS *DashboardTLSSpec `json:"tls,omitempty"`
+	// SSO configures the single sign-on of the dashboard with an identity provider. The users
+	// signing in must exist in the dashboard, see the CephDashboardUser CRD.
+	// +optional
+	// +nullable
+	SSO *DashboardSSOSpec `json:"sso,omitempty"`
+}
+
+// DashboardSSOSpec represents the single sign-on settings of the dashboard
+type DashboardSSOSpec struct {
+	// Protocol is the single sign-on protocol, "saml2" requires the saml2 settings. "oauth2" is not
+	// supported since it relies on an oauth2-proxy in front of the dashboard that Rook does not deploy.
+	// +kubebuilder:validation:Enum=saml2
+	Protocol string `json:"protocol"`
+	// SAML2 are the settings of the SAML 2.0 identity provider
+	// +optional
+	// +nullable
+	SAML2 *DashboardSAML2Spec `json:"saml2,omitempty"`
+}
+
+// DashboardSAML2Spec represents the SAML 2.0 settings of the dashboard
+type DashboardSAML2Spec struct {
+	// BaseURL is the URL the users access the dashboard with, e.g. the URL of an ingress
+	// +kubebuilder:validation:MinLength=1
+	BaseURL string `json:"baseURL"`
+	// IDPMetadata is the URL or the XML content of the metadata of the identity provider
+	// +kubebuilder:validation:MinLength=1
+	IDPMetadata string `json:"idpMetadata"`
+	// IDPUsernameAttribute is the attribute of the identity provider response with the username
+	// +kubebuilder:default=uid
+	// +optional
+	IDPUsernameAttribute string `json:"idpUsernameAttribute,omitempty"`
+	// IDPEntityID is the entity ID of the identity provider, required when the metadata has more
+	// than one entity
+	// +optional
+	IDPEntityID string `json:"idpEntityID,omitempty"`
+}
+
+// DashboardTLSSpec represents the certificate of the dashboard
+type DashboardTLSSpec struct {
+	// SecretName is the name of the kubernetes.io/tls secret with the certificate and the key of
+	// the dashboard, in the namespace of the cluster. The certificate is applied again to the
+	// dashboard when the secret is updated.
+	// +kubebuilder:validation:MinLength=1
+	SecretName string `json:"secretName"`
+	// CertManager creates a cert-manager Certificate that issues the secret. If not set, the secret
+	// is provided by the user.
+	// +optional
+	// +nullable
+	CertManager *DashboardCertManagerSpec `json:"certManager,omitempty"`
+}
+
+// DashboardCertManagerSpec represents the cert-manager Certificate of the dashboard
+type DashboardCertManagerSpec struct {
+	// IssuerRef is the cert-manager issuer of the certificate
+	IssuerRef CertManagerIssuerReference `json:"issuerRef"`
+	// DNSNames are the DNS names of the certificate. The DNS names of the dashboard service are
+	// always included.
+	// +optional
+	DNSNames []string `json:"dnsNames,omitempty"`
+	// Duration is the requested lifetime of the certificate
+	// +optional
+	// +nullable
+	Duration *metav1.Duration `json:"duration,omitempty"`
+	// RenewBefore is how long before the expiry the certificate is renewed
+	// +optional
+	// +nullable
+	RenewBefore *metav1.Duration `json:"renewBefore,omitempty"`
+}
+
+// CertManagerIssuerReference is a reference to a cert-manager issuer
+type CertManagerIssuerReference struct {
+	// Name is the name of the issuer
+	// +kubebuilder:validation:MinLength=1
+	Name string `json:"name"`
+	// Kind is the kind of the issuer, Issuer or ClusterIssuer
+	// +kubebuilder:default=Issuer
+	// +optional
+	Kind string `json:"kind,omitempty"`
+	// Group is the API group of the issuer
+	// +kubebuilder:default=cert-manager.io
+	// +optional
+	Group string `json:"group,omitempty"`
 }
 
 // MonitoringSpec represents the settings for Prometheus based Ceph monitoring
@@ -400,6 +493,47 @@ type MonitoringSpec struct {
 	// Interval determines prometheus scrape interval
 	// +optional
 	Interval *metav1.Duration `json:"interval,omitempty"`
+
+	// PrometheusRule are the settings of the PrometheusRule with the Ceph alerts managed by the operator.
+	// The rule is only created when monitoring is enabled.
+	// +optional
+	// +nullable
+	PrometheusRule *PrometheusRuleSpec `json:"prometheusRule,omitempty"`
+}
+
+// PrometheusRuleSpec represents the settings of the PrometheusRule with the Ceph alerts
+type PrometheusRuleSpec struct {
+	// Enabled determines whether the operator creates the PrometheusRule with the Ceph alerts. The alerts
+	// are updated with the operator, any manual change to the PrometheusRule is overwritten.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// Alerts overrides the settings of the alerts, by alert name
+	// +optional
+	// +nullable
+	Alerts map[string]PrometheusAlertSpec `json:"alerts,omitempty"`
+}
+
+// PrometheusAlertSpec represents the settings overriding the default settings of an alert
+type PrometheusAlertSpec struct {
+	// Disabled removes the alert from the PrometheusRule
+	// +optional
+	Disabled bool `json:"disabled,omitempty"`
+
+	// Threshold replaces the threshold of the alert. Only supported by the alerts whose expression ends
+	// with an inequality to a number, such as "CephOSDDownHigh" or "CephPGImbalance".
+	// +kubebuilder:validation:Pattern=`^-?[0-9]+(\.[0-9]+)?$`
+	// +optional
+	Threshold string `json:"threshold,omitempty"`
+
+	// For is the duration the alert condition must hold before the alert fires, such as "5m"
+	// +kubebuilder:validation:Pattern=`^(0|(([0-9]+)y)?(([0-9]+)w)?(([0-9]+)d)?(([0-9]+)h)?(([0-9]+)m)?(([0-9]+)s)?(([0-9]+)ms)?)$`
+	// +optional
+	For string `json:"for,omitempty"`
+
+	// Severity replaces the severity label of the alert
+	// +optional
+	Severity string `json:"severity,omitempty"`
 }
 
 // ClusterStatus represents the status of a Ceph cluster
@@ -414,6 +548,63 @@ type ClusterStatus struct {
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// CrushTopology is the state of the CRUSH map compared to the crush topology of the storage spec
+	// +optional
+	CrushTopology *CrushTopologyStatus `json:"crushTopology,omitempty"`
+	// MonStores is the size of the mon stores, reported when the mon store settings are set
+	// +optional
+	MonStores []MonStoreStatus `json:"monStores,omitempty"`
+	// MgrModuleSettings are the settings applied from the mgr modules of the spec, by module. Only
+	// these settings are removed from the mgr config when they are removed from the spec.
+	// +optional
+	MgrModuleSettings map[string][]string `json:"mgrModuleSettings,omitempty"`
+}
+
+// MonStoreStatus represents the size of a mon store and the space available to the mon
+type MonStoreStatus struct {
+	// Name is the name of the mon
+	Name string `json:"name"`
+	// StoreBytes is the size of the mon store
+	// +optional
+	StoreBytes uint64 `json:"storeBytes,omitempty"`
+	// TotalBytes is the size of the disk of the mon data directory
+	// +optional
+	TotalBytes uint64 `json:"totalBytes,omitempty"`
+	// AvailableBytes is the space available on the disk of the mon data directory
+	// +optional
+	AvailableBytes uint64 `json:"availableBytes,omitempty"`
+	// PVCSize is the requested size of the mon PVC, if the mon runs on a PVC
+	// +optional
+	PVCSize string `json:"pvcSize,omitempty"`
+	// LastCompaction is the last time the operator started to compact the mon store
+	// +optional
+	LastCompaction string `json:"lastCompaction,omitempty"`
+	// LastChecked is the last time the mon store was checked
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details is the error of the last check, if any
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// CrushTopologyStatus represents the state of the CRUSH map compared to the desired crush topology
+type CrushTopologyStatus struct {
+	// InSync is whether the CRUSH map matches the topology
+	InSync bool `json:"inSync"`
+	// Drift lists the differences between the CRUSH map and the topology at the last check
+	// +optional
+	Drift []string `json:"drift,omitempty"`
+	// LastChecked is the last time the CRUSH map was compared to the topology
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Message is the error of the last reconcile of the topology
+	// +optional
+	Message string `json:"message,omitempty"`
+	// PinnedOSDs are the OSDs whose crush location update on start is disabled by the operator to keep
+	// them in the bucket of the topology. The update is enabled again when an OSD is no longer placed
+	// in a bucket by the topology.
+	// +optional
+	PinnedOSDs []int `json:"pinnedOSDs,omitempty"`
 }
 
 // CephDaemonsVersions show the current ceph version for different ceph daemons
@@ -455,6 +646,53 @@ type CephStatus struct {
 	// +optional
 	Versions *CephDaemonsVersions `json:"versions,omitempty"`
 	FSID     string               `json:"fsid,omitempty"`
+	// Balancer is the status of the mgr balancer module, reported when the balancer is configured
+	// in the cluster spec
+	// +optional
+	Balancer *BalancerStatus `json:"balancer,omitempty"`
+	// MgrModules is the status of the mgr modules of the cluster spec
+	// +optional
+	MgrModules []MgrModuleStatus `json:"mgrModules,omitempty"`
+}
+
+// MgrModuleStatus represents the status of a mgr module
+type MgrModuleStatus struct {
+	// Name is the name of the module
+	Name string `json:"name"`
+	// Enabled is whether the module is enabled
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+	// Error is the reason the module cannot run, if any
+	// +optional
+	Error string `json:"error,omitempty"`
+}
+
+// BalancerStatus represents the status of the mgr balancer module
+type BalancerStatus struct {
+	// Active is whether the automatic balancing is on
+	// +optional
+	Active bool `json:"active,omitempty"`
+	// Mode is the balancer mode
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// LastOptimizationStarted is the time the last optimization was started by the balancer
+	// +optional
+	LastOptimizationStarted string `json:"lastOptimizationStarted,omitempty"`
+	// LastOptimizationDuration is the duration of the last optimization
+	// +optional
+	LastOptimizationDuration string `json:"lastOptimizationDuration,omitempty"`
+	// OptimizationResult is the result of the last optimization
+	// +optional
+	OptimizationResult string `json:"optimizationResult,omitempty"`
+	// Score is the score of the current data distribution, lower is better
+	// +optional
+	Score string `json:"score,omitempty"`
+	// LastChecked is the last time the balancer status was checked
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details contains the error of the last check, if any
+	// +optional
+	Details string `json:"details,omitempty"`
 }
 
 // Capacity is the capacity information of a Ceph Cluster
@@ -534,6 +772,16 @@ const (
 	// ObjectHasNoDependentsReason represents when a resource object has no dependents that are
 	// blocking deletion.
 	ObjectHasNoDependentsReason ConditionReason = "ObjectHasNoDependents"
+
+	// PoolQuotaNearlyFullReason represents when the usage of a pool is close to its quota
+	PoolQuotaNearlyFullReason ConditionReason = "PoolQuotaNearlyFull"
+	// PoolQuotaAvailableReason represents when the usage of a pool is not close to its quota
+	PoolQuotaAvailableReason ConditionReason = "PoolQuotaAvailable"
+
+	// MonMigrationInProgressReason represents when the storage of the mons is being migrated
+	MonMigrationInProgressReason ConditionReason = "MonMigrationInProgress"
+	// MonMigrationCompletedReason represents when the storage of all the mons matches the spec
+	MonMigrationCompletedReason ConditionReason = "MonMigrationCompleted"
 )
 
 // ConditionType represent a resource's status
@@ -555,6 +803,13 @@ const (
 
 	// ConditionDeletionIsBlocked represents when deletion of the object is blocked.
 	ConditionDeletionIsBlocked ConditionType = "DeletionIsBlocked"
+
+	// ConditionPoolQuotaNearlyFull represents when the usage of a pool is close to its quota
+	ConditionPoolQuotaNearlyFull ConditionType = "PoolQuotaNearlyFull"
+
+	// ConditionMonMigration represents the migration of the storage of the mons between the host
+	// path and PVCs, or between storage classes
+	ConditionMonMigration ConditionType = "MonMigration"
 )
 
 // ClusterState represents the state of a Ceph Cluster
@@ -599,6 +854,43 @@ type MonSpec struct {
 	// +kubebuilder:pruning:PreserveUnknownFields
 	// +optional
 	VolumeClaimTemplate *VolumeClaimTemplate `json:"volumeClaimTemplate,omitempty"`
+	// Store is the monitoring and remediation of the size of the mon stores. The size of the
+	// stores is reported in the cluster status when set.
+	// +optional
+	Store *MonStoreSpec `json:"store,omitempty"`
+}
+
+// MonStoreSpec represents the monitoring and remediation settings of the mon stores
+type MonStoreSpec struct {
+	// CompactionInterval is the interval to compact the mon stores, the stores are not compacted
+	// on a schedule if not set
+	// +optional
+	CompactionInterval *metav1.Duration `json:"compactionInterval,omitempty"`
+	// CompactionThreshold is the size of a mon store above which the store is compacted
+	// +optional
+	CompactionThreshold *resource.Quantity `json:"compactionThreshold,omitempty"`
+	// PVCExpansion expands the mon PVCs before the disk is full. The storage class of the PVCs
+	// must allow volume expansion.
+	// +optional
+	PVCExpansion *MonPVCExpansionSpec `json:"pvcExpansion,omitempty"`
+}
+
+// MonPVCExpansionSpec represents the automatic expansion of the mon PVCs
+type MonPVCExpansionSpec struct {
+	// UsedPercentThreshold is the percentage of used space of a mon PVC above which the PVC is expanded
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=99
+	// +kubebuilder:default=80
+	// +optional
+	UsedPercentThreshold int `json:"usedPercentThreshold,omitempty"`
+	// GrowthPercent is the percentage of the current size of a mon PVC added on each expansion
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=20
+	// +optional
+	GrowthPercent int `json:"growthPercent,omitempty"`
+	// MaxSize is the size above which the mon PVCs are not expanded
+	// +optional
+	MaxSize *resource.Quantity `json:"maxSize,omitempty"`
 }
 
 // VolumeClaimTemplate is a simplified version of K8s corev1's PVC. It has no type meta or status.
@@ -626,8 +918,26 @@ type StretchClusterSpec struct {
 	// +optional
 	// +nullable
 	Zones []MonZoneSpec `json:"zones,omitempty"`
+	// Mode is the stretch mode of the cluster. "cluster" (the default) enables the Ceph stretch mode
+	// with two data zones and an arbiter zone. "pools" spreads the mons across the zones without
+	// enabling the Ceph stretch mode, any number of data zones is supported and only the pools with
+	// stretch settings are stretched across the zones.
+	// +kubebuilder:validation:Enum="";cluster;pools
+	// +optional
+	Mode StretchMode `json:"mode,omitempty"`
 }
 
+// StretchMode is the stretch mode of a stretch cluster
+type StretchMode string
+
+const (
+	// StretchModeCluster enables the Ceph stretch mode with two data zones and an arbiter zone
+	StretchModeCluster StretchMode = "cluster"
+	// StretchModePools spreads the mons across the zones without the Ceph stretch mode, only the
+	// pools with stretch settings are stretched
+	StretchModePools StretchMode = "pools"
+)
+
 // MonZoneSpec represents the specification of a zone in a Ceph Cluster
 type MonZoneSpec struct {
 	// Name is the name of the zone
@@ -666,6 +976,59 @@ type Module struct {
 	// Enabled determines whether a module should be enabled or not
 	// +optional
 	Enabled bool `json:"enabled,omitempty"`
+	// Settings are the settings of the module, applied as the "mgr/<module>/<setting>" options of
+	// the mgr. When set, the other settings of the module are removed from the mgr options. The
+	// settings that Rook configures from other cluster settings cannot be set.
+	// +optional
+	Settings map[string]string `json:"settings,omitempty"`
+}
+
+// BalancerSpec represents the configuration of the mgr balancer module
+type BalancerSpec struct {
+	// Mode is the balancer mode. The upmap and crush-compat modes balance the placement of the PGs,
+	// the read mode balances the primary OSDs of the PGs and the upmap-read mode does both. The
+	// read modes require Ceph Squid and clients of at least Reef.
+	// +kubebuilder:validation:Enum=upmap;crush-compat;read;upmap-read
+	// +kubebuilder:default=upmap
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Active turns on the automatic balancing (default: true)
+	// +optional
+	Active *bool `json:"active,omitempty"`
+	// MaxMisplacedRatio is the maximum ratio of misplaced objects the balancer is allowed to cause
+	// at a time
+	// +kubebuilder:validation:Minimum=0.0
+	// +kubebuilder:validation:Maximum=1.0
+	// +optional
+	// +nullable
+	MaxMisplacedRatio *float64 `json:"maxMisplacedRatio,omitempty"`
+	// UpmapMaxDeviation is the number of PGs an OSD can deviate from the target before the upmap
+	// balancer optimizes it
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	UpmapMaxDeviation int `json:"upmapMaxDeviation,omitempty"`
+	// BeginTime is the time of the day in the mgr time zone from which automatic balancing is
+	// allowed, in the form HHMM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3])[0-5][0-9]$`
+	// +optional
+	BeginTime string `json:"beginTime,omitempty"`
+	// EndTime is the time of the day in the mgr time zone until which automatic balancing is
+	// allowed, in the form HHMM
+	// +kubebuilder:validation:Pattern=`^([01][0-9]|2[0-3])[0-5][0-9]$`
+	// +optional
+	EndTime string `json:"endTime,omitempty"`
+	// BeginWeekday is the first day of the week on which automatic balancing is allowed, 0 is Sunday
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=6
+	// +optional
+	// +nullable
+	BeginWeekday *int `json:"beginWeekday,omitempty"`
+	// EndWeekday is the day of the week before which automatic balancing is allowed, 0 is Sunday
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=6
+	// +optional
+	// +nullable
+	EndWeekday *int `json:"endWeekday,omitempty"`
 }
 
 // ExternalSpec represents the options supported by an external cluster
@@ -694,6 +1057,10 @@ type CrashCollectorSpec struct {
 
 // CephBlockPool represents a Ceph Storage Pool
 // +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:printcolumn:name="Stored",type=string,JSONPath=`.status.usage.stored`,description="Data stored in the pool"
+// +kubebuilder:printcolumn:name="Available",type=string,JSONPath=`.status.usage.available`,description="Space available to the pool"
+// +kubebuilder:printcolumn:name="QuotaUsed%",type=integer,JSONPath=`.status.usage.quota.bytesUsedPercent`,description="Percentage of the bytes quota used",priority=1
+// +kubebuilder:printcolumn:name="PGs",type=integer,JSONPath=`.status.usage.pgs.pgNum`,priority=1
 // +kubebuilder:subresource:status
 type CephBlockPool struct {
 	metav1.TypeMeta   `json:",inline"`
@@ -734,6 +1101,11 @@ type PoolSpec struct {
 	// +nullable
 	DeviceClass string `json:"deviceClass,omitempty"`
 
+	// CrushRule is the name of a CephCrushRule to use for the pool instead of a rule generated from
+	// the failure domain, crush root and device class
+	// +optional
+	CrushRule string `json:"crushRule,omitempty"`
+
 	// DEPRECATED: use Parameters instead, e.g., Parameters["compression_mode"] = "force"
 	// The inline compression mode in Bluestore OSD to set to (options are: none, passive, aggressive, force)
 	// +kubebuilder:validation:Enum=none;passive;aggressive;force;""
@@ -774,6 +1146,143 @@ type PoolSpec struct {
 	// The application name to set on the pool. Only expected to be set for rgw pools.
 	// +optional
 	Application string `json:"application"`
+
+	// QoS are the IO limits of the RBD images of the pool. Only applied to CephBlockPools.
+	// +optional
+	// +nullable
+	QoS *RBDQoSSpec `json:"qos,omitempty"`
+
+	// ScheduledSnapshots are crash-consistent snapshots of all the RBD images of the pool, taken
+	// periodically by the operator independently of mirroring. Only applied to CephBlockPools.
+	// +optional
+	// +nullable
+	ScheduledSnapshots *RBDScheduledSnapshotsSpec `json:"scheduledSnapshots,omitempty"`
+
+	// Stretch are the stretch pool settings, applying the peering rules of a stretch cluster to the
+	// pool only. Requires a replicated pool and a cluster that is not in the Ceph stretch mode.
+	// +optional
+	// +nullable
+	Stretch *StretchPoolSpec `json:"stretch,omitempty"`
+}
+
+// StretchPoolSpec represents the stretch settings of a pool
+type StretchPoolSpec struct {
+	// PeeringCrushBucketCount is the number of crush buckets of the barrier type that must have an
+	// OSD in the acting set of a placement group for it to peer
+	// +kubebuilder:validation:Minimum=1
+	PeeringCrushBucketCount int `json:"peeringCrushBucketCount"`
+	// PeeringCrushBucketTarget is the number of crush buckets of the barrier type the acting set of
+	// a placement group is spread across. Defaults to the peering crush bucket count.
+	// +kubebuilder:validation:Minimum=0
+	// +optional
+	PeeringCrushBucketTarget int `json:"peeringCrushBucketTarget,omitempty"`
+	// PeeringCrushBucketBarrier is the crush bucket type the placement groups are stretched across.
+	// Defaults to the failure domain label of the stretch cluster, or "zone".
+	// +optional
+	PeeringCrushBucketBarrier string `json:"peeringCrushBucketBarrier,omitempty"`
+}
+
+// RBDQoSSpec represents the IO limits of the RBD images of a pool or a rados namespace. The limits
+// apply to each image. A limit that is not set or 0 is unlimited, unless set at a higher level such
+// as the global configuration.
+type RBDQoSSpec struct {
+	// IOPSLimit is the limit of IO operations per second
+	// +optional
+	IOPSLimit uint64 `json:"iopsLimit,omitempty"`
+	// IOPSBurst is the burst of IO operations per second allowed above the limit
+	// +optional
+	IOPSBurst uint64 `json:"iopsBurst,omitempty"`
+	// ReadIOPSLimit is the limit of read operations per second
+	// +optional
+	ReadIOPSLimit uint64 `json:"readIOPSLimit,omitempty"`
+	// ReadIOPSBurst is the burst of read operations per second allowed above the limit
+	// +optional
+	ReadIOPSBurst uint64 `json:"readIOPSBurst,omitempty"`
+	// WriteIOPSLimit is the limit of write operations per second
+	// +optional
+	WriteIOPSLimit uint64 `json:"writeIOPSLimit,omitempty"`
+	// WriteIOPSBurst is the burst of write operations per second allowed above the limit
+	// +optional
+	WriteIOPSBurst uint64 `json:"writeIOPSBurst,omitempty"`
+	// BPSLimit is the limit of bytes per second as a quantity, e.g. 100Mi
+	// +kubebuilder:validation:Pattern=`^[0-9]+[\.]?[0-9]*([KMGTPE]i|[kMGTPE])?$`
+	// +optional
+	BPSLimit string `json:"bpsLimit,omitempty"`
+	// BPSBurst is the burst of bytes per second allowed above the limit
+	// +kubebuilder:validation:Pattern=`^[0-9]+[\.]?[0-9]*([KMGTPE]i|[kMGTPE])?$`
+	// +optional
+	BPSBurst string `json:"bpsBurst,omitempty"`
+	// ReadBPSLimit is the limit of bytes read per second
+	// +kubebuilder:validation:Pattern=`^[0-9]+[\.]?[0-9]*([KMGTPE]i|[kMGTPE])?$`
+	// +optional
+	ReadBPSLimit string `json:"readBPSLimit,omitempty"`
+	// ReadBPSBurst is the burst of bytes read per second allowed above the limit
+	// +kubebuilder:validation:Pattern=`^[0-9]+[\.]?[0-9]*([KMGTPE]i|[kMGTPE])?$`
+	// +optional
+	ReadBPSBurst string `json:"readBPSBurst,omitempty"`
+	// WriteBPSLimit is the limit of bytes written per second
+	// +kubebuilder:validation:Pattern=`^[0-9]+[\.]?[0-9]*([KMGTPE]i|[kMGTPE])?$`
+	// +optional
+	WriteBPSLimit string `json:"writeBPSLimit,omitempty"`
+	// WriteBPSBurst is the burst of bytes written per second allowed above the limit
+	// +kubebuilder:validation:Pattern=`^[0-9]+[\.]?[0-9]*([KMGTPE]i|[kMGTPE])?$`
+	// +optional
+	WriteBPSBurst string `json:"writeBPSBurst,omitempty"`
+}
+
+// RBDScheduledSnapshotsSpec represents the scheduled snapshots of the RBD images of a pool or a
+// rados namespace
+type RBDScheduledSnapshotsSpec struct {
+	// Interval is the periodicity of the snapshots, a number followed by a time unit among
+	// m(inute), h(our) and d(ay), e.g. "6h"
+	// +kubebuilder:validation:Pattern=`^[0-9]+[mhd]$`
+	Interval string `json:"interval"`
+	// Retention is the number of scheduled snapshots kept for each image, the oldest scheduled
+	// snapshots are removed beyond it. Snapshots that are not taken by the schedule are never removed.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:default=7
+	// +optional
+	Retention int `json:"retention,omitempty"`
+}
+
+// RBDScheduledSnapshotsStatus represents the status of the scheduled snapshots of the RBD images of
+// a pool or a rados namespace
+type RBDScheduledSnapshotsStatus struct {
+	// LastRun is the time the snapshots were last taken
+	// +optional
+	LastRun string `json:"lastRun,omitempty"`
+	// LastSnapshotName is the name of the snapshots taken by the last run
+	// +optional
+	LastSnapshotName string `json:"lastSnapshotName,omitempty"`
+	// NextRun is the time of the next scheduled snapshots
+	// +optional
+	NextRun string `json:"nextRun,omitempty"`
+	// Images is the number of images of the last run
+	// +optional
+	Images int `json:"images,omitempty"`
+	// SnapshotsCreated is the number of snapshots created by the last run
+	// +optional
+	SnapshotsCreated int `json:"snapshotsCreated,omitempty"`
+	// SnapshotsRemoved is the number of snapshots removed by the retention during the last run
+	// +optional
+	SnapshotsRemoved int `json:"snapshotsRemoved,omitempty"`
+	// Details contains the errors of the last run, if any
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// RBDQoSStatus represents the effective IO limits of the RBD images of a pool or a rados namespace
+type RBDQoSStatus struct {
+	// Limits are the rbd_qos options that are not unlimited and their effective value, which can
+	// come from the pool, the rados namespace or the global configuration
+	// +optional
+	// +nullable
+	Limits map[string]string `json:"limits,omitempty"`
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details contains the error of the last attempt to apply the limits
+	// +optional
+	Details string `json:"details,omitempty"`
 }
 
 // NamedBlockPoolSpec allows a block pool to be created with a non-default name.
@@ -786,6 +1295,21 @@ type NamedBlockPoolSpec struct {
 	Name string `json:"name,omitempty"`
 	// The core pool configuration
 	PoolSpec `json:",inline"`
+	// Migration is the migration of the data of the pool when its layout changes
+	// +optional
+	// +nullable
+	Migration *PoolMigrationSpec `json:"migration,omitempty"`
+}
+
+// PoolMigrationSpec represents the migration of the data of a pool to a new layout
+type PoolMigrationSpec struct {
+	// Enabled allows migrating the pool when its layout changes between replicated and erasure coded,
+	// or when its erasure coding chunks change. A new pool is created with the new layout, the data
+	// of the RBD images is live-migrated to the new pool and the new pool replaces the pool. The
+	// images of a replicated pool migrated to an erasure coded layout are kept in the pool and their
+	// data is migrated to a new erasure coded data pool.
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
 }
 
 // NamedPoolSpec represents the named ceph pool spec
@@ -801,6 +1325,10 @@ type MirrorHealthCheckSpec struct {
 	// +optional
 	// +nullable
 	Mirror HealthCheckSpec `json:"mirror,omitempty"`
+	// Usage is the check of the usage of the pool reported in the status of a CephBlockPool
+	// +optional
+	// +nullable
+	Usage HealthCheckSpec `json:"usage,omitempty"`
 }
 
 // CephBlockPoolStatus represents the mirroring status of Ceph Storage Pool
@@ -813,6 +1341,21 @@ type CephBlockPoolStatus struct {
 	MirroringInfo *MirroringInfoSpec `json:"mirroringInfo,omitempty"`
 	// +optional
 	SnapshotScheduleStatus *SnapshotScheduleStatusSpec `json:"snapshotScheduleStatus,omitempty"`
+	// MirroringRoleStatus is the progress of the promotion or demotion of the mirrored images
+	// +optional
+	MirroringRoleStatus *MirroringRoleStatusSpec `json:"mirroringRoleStatus,omitempty"`
+	// MigrationStatus is the progress of the migration of the pool to a new layout
+	// +optional
+	MigrationStatus *PoolMigrationStatus `json:"migrationStatus,omitempty"`
+	// QoSStatus is the effective IO limits of the RBD images of the pool
+	// +optional
+	QoSStatus *RBDQoSStatus `json:"qosStatus,omitempty"`
+	// ScheduledSnapshots is the status of the scheduled snapshots of the RBD images of the pool
+	// +optional
+	ScheduledSnapshots *RBDScheduledSnapshotsStatus `json:"scheduledSnapshots,omitempty"`
+	// Usage is the usage of the pool, refreshed periodically
+	// +optional
+	Usage *PoolUsageStatus `json:"usage,omitempty"`
 	// +optional
 	// +nullable
 	Info map[string]string `json:"info,omitempty"`
@@ -822,11 +1365,145 @@ type CephBlockPoolStatus struct {
 	Conditions         []Condition `json:"conditions,omitempty"`
 }
 
+// PoolUsageStatus represents the usage of a pool
+type PoolUsageStatus struct {
+	// Stored is the size of the data stored in the pool, before replication or erasure coding
+	// +optional
+	Stored string `json:"stored,omitempty"`
+	// StoredBytes is the number of bytes stored in the pool, before replication or erasure coding
+	// +optional
+	StoredBytes uint64 `json:"storedBytes,omitempty"`
+	// UsedBytes is the raw number of bytes used by the pool in the cluster
+	// +optional
+	UsedBytes uint64 `json:"usedBytes,omitempty"`
+	// Available is the size of the data that can still be stored in the pool
+	// +optional
+	Available string `json:"available,omitempty"`
+	// AvailableBytes is the number of bytes that can still be stored in the pool
+	// +optional
+	AvailableBytes uint64 `json:"availableBytes,omitempty"`
+	// Objects is the number of objects in the pool
+	// +optional
+	Objects uint64 `json:"objects,omitempty"`
+	// Quota is the usage of the pool compared to its quotas
+	// +optional
+	Quota *PoolQuotaStatus `json:"quota,omitempty"`
+	// PGs is the number of placement groups of the pool and the target of the PG autoscaler
+	// +optional
+	PGs *PoolPGStatus `json:"pgs,omitempty"`
+	// Compression is the space saved by the compression of the pool
+	// +optional
+	Compression *PoolCompressionStatus `json:"compression,omitempty"`
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details contains the error of the last check of the usage
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// PoolQuotaStatus represents the usage of a pool compared to its quotas
+type PoolQuotaStatus struct {
+	// MaxBytes is the quota in bytes, 0 if the pool has no bytes quota
+	// +optional
+	MaxBytes uint64 `json:"maxBytes,omitempty"`
+	// BytesUsedPercent is the percentage of the bytes quota used
+	// +optional
+	BytesUsedPercent int `json:"bytesUsedPercent,omitempty"`
+	// MaxObjects is the quota in objects, 0 if the pool has no objects quota
+	// +optional
+	MaxObjects uint64 `json:"maxObjects,omitempty"`
+	// ObjectsUsedPercent is the percentage of the objects quota used
+	// +optional
+	ObjectsUsedPercent int `json:"objectsUsedPercent,omitempty"`
+}
+
+// PoolPGStatus represents the placement groups of a pool
+type PoolPGStatus struct {
+	// PGNum is the current number of placement groups of the pool
+	// +optional
+	PGNum int `json:"pgNum,omitempty"`
+	// AutoscaleMode is the mode of the PG autoscaler for the pool: on, off or warn
+	// +optional
+	AutoscaleMode string `json:"autoscaleMode,omitempty"`
+	// TargetPGNum is the number of placement groups the autoscaler would set
+	// +optional
+	TargetPGNum int `json:"targetPGNum,omitempty"`
+}
+
+// PoolCompressionStatus represents the space saved by the compression of a pool
+type PoolCompressionStatus struct {
+	// CompressedBytes is the number of bytes used by the compressed data
+	// +optional
+	CompressedBytes uint64 `json:"compressedBytes,omitempty"`
+	// UncompressedBytes is the number of bytes of the data before it was compressed
+	// +optional
+	UncompressedBytes uint64 `json:"uncompressedBytes,omitempty"`
+	// SavedBytes is the number of bytes saved by the compression
+	// +optional
+	SavedBytes uint64 `json:"savedBytes,omitempty"`
+}
+
+// PoolMigrationStatus is the progress of the migration of a pool to a new layout
+type PoolMigrationStatus struct {
+	// Phase is Progressing until the pool is migrated, Ready once migrated or Failure if the last
+	// attempt to migrate the pool failed, in which case the migration is retried
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// SourceLayout is the layout of the pool before the migration
+	// +optional
+	SourceLayout string `json:"sourceLayout,omitempty"`
+	// TargetLayout is the layout of the pool after the migration
+	// +optional
+	TargetLayout string `json:"targetLayout,omitempty"`
+	// TargetPool is the name of the pool created with the new layout until it replaces the pool
+	// +optional
+	TargetPool string `json:"targetPool,omitempty"`
+	// SourcePool is the name of the pool with the previous layout once it has been replaced, until
+	// it is deleted
+	// +optional
+	SourcePool string `json:"sourcePool,omitempty"`
+	// DataPool is the erasure coded pool storing the data of the RBD images of the pool. It is created
+	// when a replicated pool with RBD images is migrated to an erasure coded layout, the pool then
+	// keeps the metadata of the images with its replicated layout.
+	// +optional
+	DataPool string `json:"dataPool,omitempty"`
+	// ImageCount is the number of RBD images with their data in the pool to migrate
+	// +optional
+	ImageCount int `json:"imageCount,omitempty"`
+	// MigratedCount is the number of RBD images migrated
+	// +optional
+	MigratedCount int `json:"migratedCount,omitempty"`
+	// InUseImages are the images that cannot be migrated until they are no longer in use by a client
+	// +optional
+	// +nullable
+	InUseImages []string `json:"inUseImages,omitempty"`
+	// StartTime is the time the migration started
+	// +optional
+	StartTime string `json:"startTime,omitempty"`
+	// CompletionTime is the time the migration completed
+	// +optional
+	CompletionTime string `json:"completionTime,omitempty"`
+	// LastChecked is the last time the progress of the migration was updated
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details contains the reason of a failure
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
 // MirroringStatusSpec is the status of the pool mirroring
 type MirroringStatusSpec struct {
 	// PoolMirroringStatus is the mirroring status of a pool
 	// +optional
 	PoolMirroringStatus `json:",inline"`
+	// ImageCount is the number of mirrored images
+	// +optional
+	ImageCount int `json:"imageCount,omitempty"`
+	// WorstImages are the mirrored images that are the most behind, the images in error first. The
+	// status of all the images is exported as Prometheus metrics.
+	// +optional
+	// +nullable
+	WorstImages []MirroredImageStatusSpec `json:"worstImages,omitempty"`
 	// LastChecked is the last time time the status was checked
 	// +optional
 	LastChecked string `json:"lastChecked,omitempty"`
@@ -838,6 +1515,31 @@ type MirroringStatusSpec struct {
 	Details string `json:"details,omitempty"`
 }
 
+// MirroredImageStatusSpec is the replication status of a mirrored image
+type MirroredImageStatusSpec struct {
+	// Name is the name of the image
+	Name string `json:"name"`
+	// State is the mirroring state of the image, or of its peer when the image is primary
+	// +optional
+	State string `json:"state,omitempty"`
+	// ReplayState is the state of the replay of the image
+	// +optional
+	ReplayState string `json:"replayState,omitempty"`
+	// LastSyncTime is the time of the last snapshot synced to the non-primary image
+	// +optional
+	LastSyncTime string `json:"lastSyncTime,omitempty"`
+	// LagSeconds is the time between the last snapshot of the primary image and the last snapshot
+	// synced to the non-primary image
+	// +optional
+	LagSeconds int64 `json:"lagSeconds,omitempty"`
+	// BytesBehind is the estimated number of bytes of the snapshot being synced that remain to be synced
+	// +optional
+	BytesBehind int64 `json:"bytesBehind,omitempty"`
+	// EntriesBehind is the number of journal entries the non-primary image is behind, journal mode only
+	// +optional
+	EntriesBehind int64 `json:"entriesBehind,omitempty"`
+}
+
 // PoolMirroringStatus is the pool mirror status
 type PoolMirroringStatus struct {
 	// Summary is the mirroring status summary
@@ -1045,6 +1747,89 @@ type MirroringSpec struct {
 	// +nullable
 	// +optional
 	Peers *MirroringPeerSpec `json:"peers,omitempty"`
+
+	// Role is the role of the mirrored images of the pool on this cluster: either primary or
+	// secondary. Changing the role promotes or demotes all the mirrored images of the pool for a
+	// planned failover or failback. If not set, the images are neither promoted nor demoted.
+	// +kubebuilder:validation:Enum="";primary;secondary
+	// +optional
+	Role MirroringRole `json:"role,omitempty"`
+
+	// ForcePromote promotes the images even if they are still primary on the peer cluster,
+	// typically when the peer cluster is unreachable. The images are in split-brain on the peer
+	// cluster when it is back, and must be resynced there once its role is set to secondary.
+	// +optional
+	ForcePromote bool `json:"forcePromote,omitempty"`
+
+	// ResyncSplitBrain resyncs the secondary images that are in split-brain from the peer cluster.
+	// The resync discards the local data of the images, so it is only expected after a forced
+	// promotion on the peer cluster. If not set, the images in split-brain are only reported.
+	// +optional
+	ResyncSplitBrain bool `json:"resyncSplitBrain,omitempty"`
+}
+
+// MirroringRole is the role of the mirrored images on this cluster
+type MirroringRole string
+
+const (
+	// MirroringRolePrimary is the role of the images that are written to and mirrored to the peers
+	MirroringRolePrimary MirroringRole = "primary"
+	// MirroringRoleSecondary is the role of the images that are mirrored from a peer
+	MirroringRoleSecondary MirroringRole = "secondary"
+)
+
+// MirroringRoleStatusSpec is the progress of the promotion or demotion of the mirrored images
+type MirroringRoleStatusSpec struct {
+	// Role is the role the mirrored images are converging to
+	// +optional
+	Role MirroringRole `json:"role,omitempty"`
+	// Phase is Progressing until all the mirrored images have the role and are healthy, then Ready.
+	// It is Failure if an image could not be promoted, demoted or resynced.
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// ImageCount is the number of mirrored images
+	// +optional
+	ImageCount int `json:"imageCount,omitempty"`
+	// ConvergedCount is the number of mirrored images that have the role
+	// +optional
+	ConvergedCount int `json:"convergedCount,omitempty"`
+	// SplitBrainCount is the number of secondary images in split-brain
+	// +optional
+	SplitBrainCount int `json:"splitBrainCount,omitempty"`
+	// Images is the progress of the mirrored images that have not converged yet
+	// +optional
+	// +nullable
+	Images []MirroredImageRoleStatus `json:"images,omitempty"`
+	// LastChecked is the last time time the status was checked
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// LastChanged is the last time the role changed
+	// +optional
+	LastChanged string `json:"lastChanged,omitempty"`
+	// Details contains potential status errors
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// MirroredImageRoleStatus is the progress of the promotion or demotion of a mirrored image
+type MirroredImageRoleStatus struct {
+	// Name is the name of the image
+	Name string `json:"name"`
+	// Primary is whether the image is primary on this cluster
+	// +optional
+	Primary bool `json:"primary,omitempty"`
+	// State is the mirroring state of the image
+	// +optional
+	State string `json:"state,omitempty"`
+	// Description is the description of the mirroring state of the image
+	// +optional
+	Description string `json:"description,omitempty"`
+	// SplitBrain is whether the image is in split-brain and must be resynced from the peer cluster
+	// +optional
+	SplitBrain bool `json:"splitBrain,omitempty"`
+	// Error is the error of the last promotion, demotion or resync of the image
+	// +optional
+	Error string `json:"error,omitempty"`
 }
 
 // SnapshotScheduleSpec represents the snapshot scheduling settings of a mirrored pool
@@ -1135,23 +1920,107 @@ type FilesystemSpec struct {
 
 	// Preserve pools on filesystem deletion
 	// +optional
-	PreservePoolsOnDelete bool `json:"preservePoolsOnDelete,omitempty"`
+	PreservePoolsOnDelete bool `json:"preservePoolsOnDelete,omitempty"`
+
+	// Preserve the fs in the cluster on CephFilesystem CR deletion. Setting this to true automatically implies PreservePoolsOnDelete is true.
+	// +optional
+	PreserveFilesystemOnDelete bool `json:"preserveFilesystemOnDelete,omitempty"`
+
+	// The mds pod info
+	MetadataServer MetadataServerSpec `json:"metadataServer"`
+
+	// The mirroring settings
+	// +nullable
+	// +optional
+	Mirroring *FSMirroringSpec `json:"mirroring,omitempty"`
+
+	// The mirroring statusCheck
+	// +kubebuilder:pruning:PreserveUnknownFields
+	StatusCheck MirrorHealthCheckSpec `json:"statusCheck,omitempty"`
+
+	// SnapshotSchedules is the scheduling of snapshots of filesystem directories, independently of mirroring
+	// +optional
+	SnapshotSchedules []FilesystemSnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+
+	// Pinning is the list of filesystem directories pinned to MDS ranks
+	// +optional
+	Pinning []FilesystemDirectoryPinningSpec `json:"pinning,omitempty"`
+
+	// Scrub is the scheduling of the forward scrubs of the filesystem metadata. When set, the damage
+	// tables of the MDS ranks are also reported in the status.
+	// +optional
+	// +nullable
+	Scrub *FilesystemScrubSpec `json:"scrub,omitempty"`
+}
+
+// FilesystemScrubSpec represents the scheduling of the scrubs of a filesystem
+type FilesystemScrubSpec struct {
+	// Schedule is the interval between two scrubs, a number followed by a time unit among h(our),
+	// d(ay) and w(eek), e.g. "1w". If empty, no scrub is started and only the damages are reported.
+	// +kubebuilder:validation:Pattern=`^([0-9]+[hdw])?$`
+	// +optional
+	Schedule string `json:"schedule,omitempty"`
+	// Path is the path of the directory to scrub
+	// +kubebuilder:default="/"
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Recursive scrubs the whole directory tree under the path
+	// +optional
+	Recursive bool `json:"recursive,omitempty"`
+	// Repair repairs the metadata inconsistencies found by the scrub
+	// +optional
+	Repair bool `json:"repair,omitempty"`
+}
+
+// FilesystemDirectoryPinningSpec represents the pinning of a filesystem directory to MDS ranks.
+// Only one of export, distributed or random can be set.
+type FilesystemDirectoryPinningSpec struct {
+	// Path is the absolute path of the directory in the filesystem, e.g. "/volumes"
+	// +kubebuilder:validation:Pattern=`^/`
+	Path string `json:"path"`
+	// Export pins the directory to the given MDS rank, -1 removes the pin
+	// +kubebuilder:validation:Minimum=-1
+	// +kubebuilder:validation:Maximum=256
+	// +optional
+	// +nullable
+	Export *int `json:"export,omitempty"`
+	// Distributed spreads the immediate children of the directory across the active MDS ranks
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=1
+	// +optional
+	// +nullable
+	Distributed *int `json:"distributed,omitempty"`
+	// Random pins the descendant directories to a random MDS rank with the given probability
+	// +kubebuilder:validation:Minimum=0.0
+	// +kubebuilder:validation:Maximum=1.0
+	// +optional
+	// +nullable
+	Random *float64 `json:"random,omitempty"`
+}
 
-	// Preserve the fs in the cluster on CephFilesystem CR deletion. Setting this to true automatically implies PreservePoolsOnDelete is true.
+// FilesystemSnapshotScheduleSpec represents a snapshot schedule of a filesystem directory
+type FilesystemSnapshotScheduleSpec struct {
+	// Path is the path of the directory to snapshot. For a subvolume group, the path is relative to
+	// the subvolume group directory.
+	// +kubebuilder:default="/"
 	// +optional
-	PreserveFilesystemOnDelete bool `json:"preserveFilesystemOnDelete,omitempty"`
+	Path string `json:"path,omitempty"`
 
-	// The mds pod info
-	MetadataServer MetadataServerSpec `json:"metadataServer"`
+	// Interval is the periodicity of the snapshots, a number followed by a time unit among
+	// m(inute), h(our), d(ay), w(eek), M(onth) and y(ear), e.g. "6h"
+	// +kubebuilder:validation:Pattern=`^[0-9]+[mhdwMy]$`
+	Interval string `json:"interval"`
 
-	// The mirroring settings
-	// +nullable
+	// StartTime is the time of the first snapshot in ISO format, e.g. "2024-01-01T00:00:00"
 	// +optional
-	Mirroring *FSMirroringSpec `json:"mirroring,omitempty"`
+	StartTime string `json:"startTime,omitempty"`
 
-	// The mirroring statusCheck
-	// +kubebuilder:pruning:PreserveUnknownFields
-	StatusCheck MirrorHealthCheckSpec `json:"statusCheck,omitempty"`
+	// Retention is the retention policy of the snapshots of the path, as a list of count-time period
+	// pairs, e.g. "24h7d" keeps 24 hourly and 7 daily snapshots. A single retention policy applies
+	// to all the schedules of a path.
+	// +kubebuilder:validation:Pattern=`^([0-9]+[mhdwMyn])*$`
+	// +optional
+	Retention string `json:"retention,omitempty"`
 }
 
 // MetadataServerSpec represents the specification of a Ceph Metadata Server
@@ -1199,6 +2068,78 @@ type MetadataServerSpec struct {
 
 	// +optional
 	StartupProbe *ProbeSpec `json:"startupProbe,omitempty"`
+
+	// Autoscaler adjusts the number of active MDS ranks based on the load of the metadata servers.
+	// When enabled, activeCount is the initial number of active ranks.
+	// +optional
+	// +nullable
+	Autoscaler *MDSAutoscalerSpec `json:"autoscaler,omitempty"`
+}
+
+// MDSAutoscalerSpec represents the settings to scale the number of active MDS ranks based on load
+type MDSAutoscalerSpec struct {
+	// Enabled whether the number of active MDS ranks is adjusted automatically
+	// +optional
+	Enabled bool `json:"enabled,omitempty"`
+
+	// MinActiveCount is the minimum number of active MDS ranks. Defaults to activeCount.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	MinActiveCount int32 `json:"minActiveCount,omitempty"`
+
+	// MaxActiveCount is the maximum number of active MDS ranks. Defaults to activeCount.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=50
+	// +optional
+	MaxActiveCount int32 `json:"maxActiveCount,omitempty"`
+
+	// RequestRateThreshold is the average number of client requests per second per active MDS
+	// above which an active rank is added. Defaults to 5000.
+	// +kubebuilder:validation:Minimum=1
+	// +optional
+	RequestRateThreshold int64 `json:"requestRateThreshold,omitempty"`
+
+	// CachePressureThreshold is the average percentage of mds_cache_memory_limit used by the
+	// active MDS above which an active rank is added. Defaults to 80.
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=100
+	// +optional
+	CachePressureThreshold int32 `json:"cachePressureThreshold,omitempty"`
+
+	// ScaleUpCooldown is the minimum time to wait after scaling before adding an active rank. Defaults to 5m.
+	// +optional
+	ScaleUpCooldown *metav1.Duration `json:"scaleUpCooldown,omitempty"`
+
+	// ScaleDownCooldown is the minimum time to wait after scaling before removing an active rank. Defaults to 15m.
+	// +optional
+	ScaleDownCooldown *metav1.Duration `json:"scaleDownCooldown,omitempty"`
+}
+
+// MDSAutoscalerStatus represents the status of the MDS autoscaler of a filesystem
+type MDSAutoscalerStatus struct {
+	// ActiveCount is the number of active MDS ranks chosen by the autoscaler
+	// +optional
+	ActiveCount int32 `json:"activeCount,omitempty"`
+	// PendingActiveCount is the number of active MDS ranks the autoscaler is scaling down to, set
+	// while the removed ranks are stopping
+	// +optional
+	PendingActiveCount int32 `json:"pendingActiveCount,omitempty"`
+	// RequestRate is the total number of client requests per second served by the active MDS
+	// +optional
+	RequestRate int64 `json:"requestRate,omitempty"`
+	// CachePressure is the average percentage of the cache memory limit used by the active MDS
+	// +optional
+	CachePressure int32 `json:"cachePressure,omitempty"`
+	// LastChecked is the last time the load of the MDS was checked
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// LastScaleTime is the last time the number of active MDS ranks was changed by the autoscaler
+	// +optional
+	LastScaleTime string `json:"lastScaleTime,omitempty"`
+	// Details contains the reason of the last scaling decision or an error
+	// +optional
+	Details string `json:"details,omitempty"`
 }
 
 // FSMirroringSpec represents the setting for a mirrored filesystem
@@ -1251,6 +2192,134 @@ type CephFilesystemStatus struct {
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// SnapshotSchedules is the status of the snapshot schedules of the filesystem
+	// +optional
+	// +nullable
+	SnapshotSchedules []FilesystemSnapshotScheduleStatus `json:"snapshotSchedules,omitempty"`
+	// AppliedSnapshotSchedules are the snapshot schedules of the spec applied by the operator. Only these
+	// schedules are removed from Ceph when they are removed from the spec.
+	// +optional
+	// +nullable
+	AppliedSnapshotSchedules []FilesystemSnapshotScheduleSpec `json:"appliedSnapshotSchedules,omitempty"`
+	// MDSAutoscaler is the status of the MDS autoscaler
+	// +optional
+	// +nullable
+	MDSAutoscaler *MDSAutoscalerStatus `json:"mdsAutoscaler,omitempty"`
+	// Pinning is the status of the directory pinning and of the distribution of the subtrees across the MDS ranks
+	// +optional
+	// +nullable
+	Pinning *FilesystemPinningStatus `json:"pinning,omitempty"`
+	// Scrub is the status of the scrubs and of the damages of the filesystem
+	// +optional
+	// +nullable
+	Scrub *FilesystemScrubStatus `json:"scrub,omitempty"`
+}
+
+// FilesystemScrubStatus is the status of the scrubs and of the damages of a filesystem
+type FilesystemScrubStatus struct {
+	// Active is whether a scrub is running
+	// +optional
+	Active bool `json:"active,omitempty"`
+	// State is the scrub state reported by the MDS
+	// +optional
+	State string `json:"state,omitempty"`
+	// LastScrubStart is the time the last scrub was started by the operator
+	// +optional
+	LastScrubStart string `json:"lastScrubStart,omitempty"`
+	// LastScrubTag is the tag of the last scrub started by the operator
+	// +optional
+	LastScrubTag string `json:"lastScrubTag,omitempty"`
+	// NextScrub is the time of the next scheduled scrub
+	// +optional
+	NextScrub string `json:"nextScrub,omitempty"`
+	// DamageCount is the number of entries in the damage tables of the MDS ranks
+	// +optional
+	DamageCount int `json:"damageCount,omitempty"`
+	// Damages are the entries of the damage tables, the list is truncated for large numbers of damages
+	// +optional
+	Damages []FilesystemDamage `json:"damages,omitempty"`
+	// LastChecked is the last time the scrub status and the damages were checked
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details contains the errors of the last check, if any
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// FilesystemDamage is an entry of the damage table of an MDS rank
+type FilesystemDamage struct {
+	// Rank is the MDS rank reporting the damage
+	Rank int `json:"rank"`
+	// ID is the identifier of the damage, to use with "damage rm"
+	ID string `json:"id"`
+	// DamageType is the type of the damage, e.g. "dir_frag", "dentry" or "backtrace"
+	// +optional
+	DamageType string `json:"damageType,omitempty"`
+	// Ino is the inode number of the damaged metadata
+	// +optional
+	Ino uint64 `json:"ino,omitempty"`
+	// Path is the path of the damaged metadata, when known
+	// +optional
+	Path string `json:"path,omitempty"`
+}
+
+// FilesystemPinningStatus is the status of the directory pinning of a filesystem
+type FilesystemPinningStatus struct {
+	// PinnedPaths are the paths of the directories pinned by the operator
+	// +optional
+	PinnedPaths []string `json:"pinnedPaths,omitempty"`
+	// Ranks is the distribution of the subtrees across the active MDS ranks
+	// +optional
+	Ranks []FilesystemRankSubtreesStatus `json:"ranks,omitempty"`
+	// LastChecked is the last time the subtrees were checked
+	// +optional
+	LastChecked string `json:"lastChecked,omitempty"`
+	// Details contains the errors of the pinning, if any
+	// +optional
+	Details string `json:"details,omitempty"`
+}
+
+// FilesystemRankSubtreesStatus represents the subtrees an active MDS rank is authoritative for
+type FilesystemRankSubtreesStatus struct {
+	// Rank is the MDS rank
+	Rank int `json:"rank"`
+	// MDS is the name of the MDS daemon holding the rank
+	// +optional
+	MDS string `json:"mds,omitempty"`
+	// SubtreeCount is the number of subtrees of the rank
+	// +optional
+	SubtreeCount int `json:"subtreeCount,omitempty"`
+	// Subtrees are the paths of the subtrees of the rank, the list is truncated for large numbers of subtrees
+	// +optional
+	Subtrees []string `json:"subtrees,omitempty"`
+}
+
+// FilesystemSnapshotScheduleStatus is the status of a snapshot schedule of a filesystem directory
+type FilesystemSnapshotScheduleStatus struct {
+	// Path is the path of the directory on the filesystem
+	// +optional
+	Path string `json:"path,omitempty"`
+	// Interval is the periodicity of the snapshots
+	// +optional
+	Interval string `json:"interval,omitempty"`
+	// Active is whether the schedule is active
+	// +optional
+	Active bool `json:"active,omitempty"`
+	// LastSnapshot is when the last snapshot was taken
+	// +optional
+	LastSnapshot string `json:"lastSnapshot,omitempty"`
+	// NextSnapshot is when the next snapshot is expected to be taken
+	// +optional
+	NextSnapshot string `json:"nextSnapshot,omitempty"`
+	// CreatedCount is the number of snapshots taken by the schedule
+	// +optional
+	CreatedCount int `json:"createdCount,omitempty"`
+	// PrunedCount is the number of snapshots pruned by the retention policy
+	// +optional
+	PrunedCount int `json:"prunedCount,omitempty"`
+	// Details contains potential status errors
+	// +optional
+	Details string `json:"details,omitempty"`
 }
 
 // FilesystemMirroringInfo is the status of the pool mirroring
@@ -2808,6 +3877,57 @@ type StorageScopeSpec struct {
 	// User needs to manually restart the OSD pod if they manage to fix the underlying OSD flapping issue before the restart interval.
 	// The sleep will be disabled if this interval is set to 0.
 	FlappingRestartIntervalHours int `json:"flappingRestartIntervalHours"`
+	// CrushTopology is the desired hierarchy of the CRUSH map on top of the hosts and the topology
+	// derived from the node labels
+	// +optional
+	// +nullable
+	CrushTopology *CrushTopologySpec `json:"crushTopology,omitempty"`
+}
+
+// CrushTopologySpec represents the desired hierarchy of the CRUSH map. The operator creates the
+// buckets, moves them under their parent and places and weights the OSDs accordingly.
+type CrushTopologySpec struct {
+	// Buckets are the buckets of the CRUSH map, for example rows, pods or chassis. A bucket is created
+	// if it does not exist and moved under its parent. Existing buckets such as hosts can be listed
+	// to move them under a new parent.
+	// +optional
+	Buckets []CrushBucketSpec `json:"buckets,omitempty"`
+	// OSDs are the overrides of the bucket and the weight of individual OSDs
+	// +optional
+	OSDs []CrushOSDSpec `json:"osds,omitempty"`
+	// ReportOnly only reports the drift of the CRUSH map from the topology in the status without
+	// correcting it
+	// +optional
+	ReportOnly bool `json:"reportOnly,omitempty"`
+}
+
+// CrushBucketSpec represents a bucket of the CRUSH map
+type CrushBucketSpec struct {
+	// Name is the name of the bucket
+	// +kubebuilder:validation:Pattern=`^[A-Za-z0-9_.-]+$`
+	Name string `json:"name"`
+	// Type is the type of the bucket, for example row, pod, chassis, rack or host
+	// +kubebuilder:validation:MinLength=1
+	Type string `json:"type"`
+	// Parent is the name of the bucket the bucket is placed under. A bucket without parent is not
+	// moved, which is the case of a root.
+	// +optional
+	Parent string `json:"parent,omitempty"`
+}
+
+// CrushOSDSpec represents the overrides of the location and the weight of an OSD in the CRUSH map
+type CrushOSDSpec struct {
+	// ID is the ID of the OSD
+	// +kubebuilder:validation:Minimum=0
+	ID int `json:"id"`
+	// Bucket is the name of the bucket the OSD is placed in instead of its host. The OSD no longer
+	// updates its location in the CRUSH map when it starts.
+	// +optional
+	Bucket string `json:"bucket,omitempty"`
+	// Weight is the CRUSH weight of the OSD instead of its capacity in TiB
+	// +kubebuilder:validation:Pattern=`^[0-9]+(\.[0-9]+)?$`
+	// +optional
+	Weight string `json:"weight,omitempty"`
 }
 
 // OSDStore is the backend storage type used for creating the OSDs
@@ -2999,6 +4119,26 @@ type CephFilesystemSubVolumeGroupSpec struct {
 	// only one out of (export, distributed, random) can be set at a time
 	// +optional
 	Pinning CephFilesystemSubVolumeGroupSpecPinning `json:"pinning,omitempty"`
+	// Quota size of the Ceph Filesystem subvolume group. The subvolume group is resized whenever
+	// the quota changes. If not set, the subvolume group size is not limited.
+	// See https://pkg.go.dev/k8s.io/apimachinery/pkg/api/resource#Quantity for more info.
+	// +optional
+	// +nullable
+	Quota *resource.Quantity `json:"quota,omitempty"`
+	// The data pool name for the Ceph Filesystem subvolume group layout, if the default CephFS pool is not desired.
+	// The pool must be one of the data pools of the filesystem, e.g. "myfs-replicated".
+	// Only applied when the subvolume group is created.
+	// +optional
+	DataPoolName string `json:"dataPoolName,omitempty"`
+	// The octal permission mode of the subvolume group directory, e.g. "755". Only applied when the
+	// subvolume group is created.
+	// +kubebuilder:validation:Pattern=`^0?[0-7]{3}$`
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// SnapshotSchedules is the scheduling of snapshots of the subvolume group directories. The paths
+	// are relative to the subvolume group directory.
+	// +optional
+	SnapshotSchedules []FilesystemSnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
 }
 
 // CephFilesystemSubVolumeGroupSpecPinning represents the pinning configuration of SubVolumeGroup
@@ -3031,6 +4171,35 @@ type CephFilesystemSubVolumeGroupStatus struct {
 	// ObservedGeneration is the latest generation observed by the controller.
 	// +optional
 	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Usage is the current space usage of the subvolume group
+	// +optional
+	// +nullable
+	Usage *CephFilesystemSubVolumeGroupUsage `json:"usage,omitempty"`
+	// SnapshotSchedules is the status of the snapshot schedules of the subvolume group
+	// +optional
+	// +nullable
+	SnapshotSchedules []FilesystemSnapshotScheduleStatus `json:"snapshotSchedules,omitempty"`
+	// AppliedSnapshotSchedules are the snapshot schedules of the spec applied by the operator. Only these
+	// schedules are removed from Ceph when they are removed from the spec.
+	// +optional
+	// +nullable
+	AppliedSnapshotSchedules []FilesystemSnapshotScheduleSpec `json:"appliedSnapshotSchedules,omitempty"`
+}
+
+// CephFilesystemSubVolumeGroupUsage represents the space usage of a Ceph Filesystem SubVolumeGroup
+type CephFilesystemSubVolumeGroupUsage struct {
+	// BytesUsed is the amount of data stored in the subvolume group
+	// +optional
+	BytesUsed uint64 `json:"bytesUsed,omitempty"`
+	// BytesQuota is the size limit of the subvolume group, unset when the size is not limited
+	// +optional
+	BytesQuota uint64 `json:"bytesQuota,omitempty"`
+	// PercentUsed is the percentage of the quota in use, unset when the size is not limited
+	// +optional
+	PercentUsed string `json:"percentUsed,omitempty"`
+	// DataPool is the data pool of the subvolume group layout
+	// +optional
+	DataPool string `json:"dataPool,omitempty"`
 }
 
 // +genclient
@@ -3069,6 +4238,56 @@ type CephBlockPoolRadosNamespaceSpec struct {
 	// the CephBlockPool CR.
 	// +kubebuilder:validation:XValidation:message="blockPoolName is immutable",rule="self == oldSelf"
 	BlockPoolName string `json:"blockPoolName"`
+	// Mirroring configuration of CephBlockPoolRadosNamespace
+	// +optional
+	Mirroring *RadosNamespaceMirroring `json:"mirroring,omitempty"`
+	// QoS are the IO limits of the RBD images of the rados namespace, which override the limits of
+	// the pool
+	// +optional
+	QoS *RBDQoSSpec `json:"qos,omitempty"`
+	// ScheduledSnapshots are crash-consistent snapshots of all the RBD images of the rados
+	// namespace, taken periodically by the operator independently of mirroring
+	// +optional
+	// +nullable
+	ScheduledSnapshots *RBDScheduledSnapshotsSpec `json:"scheduledSnapshots,omitempty"`
+}
+
+// RadosNamespaceMirroringMode represents the mode of the RadosNamespace
+type RadosNamespaceMirroringMode string
+
+const (
+	// RadosNamespaceMirroringModePool represents the pool mode
+	RadosNamespaceMirroringModePool RadosNamespaceMirroringMode = "pool"
+	// RadosNamespaceMirroringModeImage represents the image mode
+	RadosNamespaceMirroringModeImage RadosNamespaceMirroringMode = "image"
+)
+
+// RadosNamespaceMirroring represents the mirroring configuration of CephBlockPoolRadosNamespace
+type RadosNamespaceMirroring struct {
+	// RemoteNamespace is the name of the rados namespace the namespace is mirrored to on the peer
+	// cluster. If not set, the namespace is mirrored to the namespace with the same name. A different
+	// name requires Ceph Squid 19.2 or newer.
+	// +optional
+	RemoteNamespace *string `json:"remoteNamespace,omitempty"`
+	// Mode is the mirroring mode; either pool or image
+	// +kubebuilder:validation:Enum=pool;image
+	Mode RadosNamespaceMirroringMode `json:"mode"`
+	// SnapshotSchedules is the scheduling of snapshot for mirrored images
+	// +optional
+	SnapshotSchedules []SnapshotScheduleSpec `json:"snapshotSchedules,omitempty"`
+	// Role is the role of the mirrored images of the namespace on this cluster: either primary or
+	// secondary. Changing the role promotes or demotes all the mirrored images of the namespace.
+	// If not set, the images are neither promoted nor demoted.
+	// +kubebuilder:validation:Enum="";primary;secondary
+	// +optional
+	Role MirroringRole `json:"role,omitempty"`
+	// ForcePromote promotes the images even if they are still primary on the peer cluster
+	// +optional
+	ForcePromote bool `json:"forcePromote,omitempty"`
+	// ResyncSplitBrain resyncs the secondary images that are in split-brain from the peer cluster,
+	// which discards their local data
+	// +optional
+	ResyncSplitBrain bool `json:"resyncSplitBrain,omitempty"`
 }
 
 // CephBlockPoolRadosNamespaceStatus represents the Status of Ceph BlockPool
@@ -3079,6 +4298,198 @@ type CephBlockPoolRadosNamespaceStatus struct {
 	// +optional
 	// +nullable
 	Info map[string]string `json:"info,omitempty"`
+	// +optional
+	MirroringStatus *MirroringStatusSpec `json:"mirroringStatus,omitempty"`
+	// +optional
+	MirroringInfo *MirroringInfoSpec `json:"mirroringInfo,omitempty"`
+	// +optional
+	SnapshotScheduleStatus *SnapshotScheduleStatusSpec `json:"snapshotScheduleStatus,omitempty"`
+	// MirroringRoleStatus is the progress of the promotion or demotion of the mirrored images
+	// +optional
+	MirroringRoleStatus *MirroringRoleStatusSpec `json:"mirroringRoleStatus,omitempty"`
+	// QoSStatus is the effective IO limits of the RBD images of the rados namespace
+	// +optional
+	QoSStatus *RBDQoSStatus `json:"qosStatus,omitempty"`
+	// ScheduledSnapshots is the status of the scheduled snapshots of the RBD images of the rados
+	// namespace
+	// +optional
+	ScheduledSnapshots *RBDScheduledSnapshotsStatus `json:"scheduledSnapshots,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephCrushRule represents a custom CRUSH rule. The rule is named after the CR in the CRUSH map.
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:subresource:status
+type CephCrushRule struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of a CRUSH rule
+	Spec CrushRuleSpec `json:"spec"`
+	// Status represents the status of a CRUSH rule
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *CrushRuleStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephCrushRuleList represents a list of CRUSH rules
+type CephCrushRuleList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephCrushRule `json:"items"`
+}
+
+// CrushRuleSpec represents the specification of a CRUSH rule
+type CrushRuleSpec struct {
+	// Type is the type of pool the rule is used for. The "msr_firstn" and "msr_indep" types are the
+	// multi-step retry rules of replicated and erasure coded pools, which require Ceph Squid.
+	// +kubebuilder:validation:Enum=replicated;erasure;msr_firstn;msr_indep
+	// +kubebuilder:default=replicated
+	// +optional
+	Type string `json:"type,omitempty"`
+	// Steps are the steps of the rule, in order. A rule usually takes a root of the CRUSH hierarchy,
+	// chooses the buckets and OSDs under it and emits the result.
+	// +kubebuilder:validation:MinItems=1
+	Steps []CrushRuleStep `json:"steps"`
+}
+
+// CrushRuleStepOp is the operation of a CRUSH rule step
+type CrushRuleStepOp string
+
+const (
+	// CrushRuleStepTake selects a bucket of the CRUSH hierarchy to start from
+	CrushRuleStepTake CrushRuleStepOp = "take"
+	// CrushRuleStepChoose selects buckets of a type under the current buckets
+	CrushRuleStepChoose CrushRuleStepOp = "choose"
+	// CrushRuleStepChooseLeaf selects buckets of a type under the current buckets and an OSD under each of them
+	CrushRuleStepChooseLeaf CrushRuleStepOp = "chooseleaf"
+	// CrushRuleStepChooseMSR selects buckets of a type under the current buckets in a multi-step retry rule
+	CrushRuleStepChooseMSR CrushRuleStepOp = "choosemsr"
+	// CrushRuleStepEmit outputs the current items
+	CrushRuleStepEmit CrushRuleStepOp = "emit"
+)
+
+// CrushRuleStep represents a step of a CRUSH rule
+type CrushRuleStep struct {
+	// Op is the operation of the step
+	// +kubebuilder:validation:Enum=take;choose;chooseleaf;choosemsr;emit
+	Op CrushRuleStepOp `json:"op"`
+	// Item is the name of the bucket to take, only for the "take" step
+	// +optional
+	Item string `json:"item,omitempty"`
+	// DeviceClass restricts the bucket taken to the OSDs of a device class, only for the "take" step
+	// +optional
+	DeviceClass string `json:"deviceClass,omitempty"`
+	// Mode is the mode of the "choose" and "chooseleaf" steps. "firstn" is used by replicated pools
+	// and "indep" by erasure coded pools.
+	// +kubebuilder:validation:Enum=firstn;indep;""
+	// +optional
+	Mode string `json:"mode,omitempty"`
+	// Number is the number of buckets to choose, only for the "choose", "chooseleaf" and "choosemsr"
+	// steps. Zero chooses as many buckets as the pool size, a negative number as many as the pool
+	// size minus the number.
+	// +optional
+	Number int `json:"number,omitempty"`
+	// Type is the type of the buckets to choose, only for the "choose", "chooseleaf" and "choosemsr" steps
+	// +optional
+	Type string `json:"type,omitempty"`
+}
+
+// CrushRuleStatus represents the status of a CRUSH rule
+type CrushRuleStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// RuleID is the ID of the rule in the CRUSH map
+	// +optional
+	RuleID *int `json:"ruleID,omitempty"`
+	// Owned is true when the rule was created in the CRUSH map by the operator. The operator only
+	// updates and deletes the rules it created, a rule that already exists is not taken over.
+	// +optional
+	Owned bool `json:"owned,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
+}
+
+// +genclient
+// +genclient:noStatus
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephDashboardUser represents a user of the Ceph dashboard and its roles. The user is named after
+// the CR unless a username is set.
+// +kubebuilder:printcolumn:name="Phase",type=string,JSONPath=`.status.phase`
+// +kubebuilder:subresource:status
+type CephDashboardUser struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata"`
+	// Spec represents the specification of a dashboard user
+	Spec DashboardUserSpec `json:"spec"`
+	// Status represents the status of a dashboard user
+	// +kubebuilder:pruning:PreserveUnknownFields
+	// +optional
+	Status *DashboardUserStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// CephDashboardUserList represents a list of dashboard users
+type CephDashboardUserList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+	Items           []CephDashboardUser `json:"items"`
+}
+
+// DashboardUserSpec represents the specification of a dashboard user
+type DashboardUserSpec struct {
+	// Username is the name of the user in the dashboard, e.g. the username sent by the identity
+	// provider with single sign-on. If not set, the name of the CR is used.
+	// +optional
+	Username string `json:"username,omitempty"`
+	// Members are the usernames of a group of users that get the roles of the CR, e.g. the members
+	// of a group of the identity provider. A dashboard user is created for each member instead of a
+	// single user, and the members can only sign in with single sign-on.
+	// +optional
+	Members []string `json:"members,omitempty"`
+	// Roles are the dashboard roles of the user, e.g. "administrator", "read-only",
+	// "block-manager", "cephfs-manager", "cluster-manager", "ganesha-manager", "pool-manager" or
+	// "rgw-manager"
+	// +kubebuilder:validation:MinItems=1
+	Roles []string `json:"roles"`
+	// PasswordSecretName is the name of a secret with the password of the user in the "password"
+	// key. If not set, the user can only sign in with single sign-on.
+	// +optional
+	PasswordSecretName string `json:"passwordSecretName,omitempty"`
+	// Name is the full name of the user
+	// +optional
+	Name string `json:"name,omitempty"`
+	// Email is the email address of the user
+	// +optional
+	Email string `json:"email,omitempty"`
+	// Enabled determines whether the user can sign in, true by default
+	// +optional
+	// +nullable
+	Enabled *bool `json:"enabled,omitempty"`
+}
+
+// DashboardUserStatus represents the status of a dashboard user
+type DashboardUserStatus struct {
+	// +optional
+	Phase ConditionType `json:"phase,omitempty"`
+	// ObservedGeneration is the latest generation observed by the controller
+	// +optional
+	ObservedGeneration int64 `json:"observedGeneration,omitempty"`
+	// Users are the dashboard users created by the operator for the CR. Only these users are
+	// deleted, the users that already existed in the dashboard are not taken over.
+	// +optional
+	Users []string `json:"users,omitempty"`
+	// +optional
+	Conditions []Condition `json:"conditions,omitempty"`
 }
 
 // Represents the source of a volume to mount.
@@ -123,6 +123,58 @@ func (in AnnotationsSpec) DeepCopy() AnnotationsSpec {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BalancerSpec) DeepCopyInto(out *BalancerSpec) {
+	*out = *in
+	if in.Active != nil {
+		in, out := &in.Active, &out.Active
+		*out = new(bool)
+		**out = **in
+	}
+	if in.MaxMisplacedRatio != nil {
+		in, out := &in.MaxMisplacedRatio, &out.MaxMisplacedRatio
+		*out = new(float64)
+		**out = **in
+	}
+	if in.BeginWeekday != nil {
+		in, out := &in.BeginWeekday, &out.BeginWeekday
+		*out = new(int)
+		**out = **in
+	}
+	if in.EndWeekday != nil {
+		in, out := &in.EndWeekday, &out.EndWeekday
+		*out = new(int)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BalancerSpec.
+func (in *BalancerSpec) DeepCopy() *BalancerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(BalancerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *BalancerStatus) DeepCopyInto(out *BalancerStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new BalancerStatus.
+func (in *BalancerStatus) DeepCopy() *BalancerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(BalancerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *BucketNotificationSpec) DeepCopyInto(out *BucketNotificationSpec) {
 	*out = *in
@@ -327,7 +379,7 @@ func (in *CephBlockPoolRadosNamespace) DeepCopyInto(out *CephBlockPoolRadosNames
 	*out = *in
 	out.TypeMeta = in.TypeMeta
 	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
-	out.Spec = in.Spec
+	in.Spec.DeepCopyInto(&out.Spec)
 	if in.Status != nil {
 		in, out := &in.Status, &out.Status
 		*out = new(CephBlockPoolRadosNamespaceStatus)
@@ -390,6 +442,21 @@ func (in *CephBlockPoolRadosNamespaceList) DeepCopyObject() runtime.Object {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephBlockPoolRadosNamespaceSpec) DeepCopyInto(out *CephBlockPoolRadosNamespaceSpec) {
 	*out = *in
+	if in.Mirroring != nil {
+		in, out := &in.Mirroring, &out.Mirroring
+		*out = new(RadosNamespaceMirroring)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QoS != nil {
+		in, out := &in.QoS, &out.QoS
+		*out = new(RBDQoSSpec)
+		**out = **in
+	}
+	if in.ScheduledSnapshots != nil {
+		in, out := &in.ScheduledSnapshots, &out.ScheduledSnapshots
+		*out = new(RBDScheduledSnapshotsSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -413,6 +480,36 @@ func (in *CephBlockPoolRadosNamespaceStatus) DeepCopyInto(out *CephBlockPoolRado
 			(*out)[key] = val
 		}
 	}
+	if in.MirroringStatus != nil {
+		in, out := &in.MirroringStatus, &out.MirroringStatus
+		*out = new(MirroringStatusSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MirroringInfo != nil {
+		in, out := &in.MirroringInfo, &out.MirroringInfo
+		*out = new(MirroringInfoSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SnapshotScheduleStatus != nil {
+		in, out := &in.SnapshotScheduleStatus, &out.SnapshotScheduleStatus
+		*out = new(SnapshotScheduleStatusSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MirroringRoleStatus != nil {
+		in, out := &in.MirroringRoleStatus, &out.MirroringRoleStatus
+		*out = new(MirroringRoleStatusSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QoSStatus != nil {
+		in, out := &in.QoSStatus, &out.QoSStatus
+		*out = new(RBDQoSStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledSnapshots != nil {
+		in, out := &in.ScheduledSnapshots, &out.ScheduledSnapshots
+		*out = new(RBDScheduledSnapshotsStatus)
+		**out = **in
+	}
 	return
 }
 
@@ -444,6 +541,31 @@ func (in *CephBlockPoolStatus) DeepCopyInto(out *CephBlockPoolStatus) {
 		*out = new(SnapshotScheduleStatusSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.MirroringRoleStatus != nil {
+		in, out := &in.MirroringRoleStatus, &out.MirroringRoleStatus
+		*out = new(MirroringRoleStatusSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MigrationStatus != nil {
+		in, out := &in.MigrationStatus, &out.MigrationStatus
+		*out = new(PoolMigrationStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.QoSStatus != nil {
+		in, out := &in.QoSStatus, &out.QoSStatus
+		*out = new(RBDQoSStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ScheduledSnapshots != nil {
+		in, out := &in.ScheduledSnapshots, &out.ScheduledSnapshots
+		*out = new(RBDScheduledSnapshotsStatus)
+		**out = **in
+	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(PoolUsageStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Info != nil {
 		in, out := &in.Info, &out.Info
 		*out = make(map[string]string, len(*in))
@@ -875,6 +997,71 @@ func (in *CephClusterList) DeepCopyObject() runtime.Object {
 	return nil
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCrushRule) DeepCopyInto(out *CephCrushRule) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(CrushRuleStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCrushRule.
+func (in *CephCrushRule) DeepCopy() *CephCrushRule {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCrushRule)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephCrushRule) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephCrushRuleList) DeepCopyInto(out *CephCrushRuleList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephCrushRule, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephCrushRuleList.
+func (in *CephCrushRuleList) DeepCopy() *CephCrushRuleList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephCrushRuleList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephCrushRuleList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephDaemonsVersions) DeepCopyInto(out *CephDaemonsVersions) {
 	*out = *in
@@ -947,6 +1134,71 @@ func (in *CephDaemonsVersions) DeepCopy() *CephDaemonsVersions {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDashboardUser) DeepCopyInto(out *CephDashboardUser) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	if in.Status != nil {
+		in, out := &in.Status, &out.Status
+		*out = new(DashboardUserStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDashboardUser.
+func (in *CephDashboardUser) DeepCopy() *CephDashboardUser {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDashboardUser)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephDashboardUser) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephDashboardUserList) DeepCopyInto(out *CephDashboardUserList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]CephDashboardUser, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephDashboardUserList.
+func (in *CephDashboardUserList) DeepCopy() *CephDashboardUserList {
+	if in == nil {
+		return nil
+	}
+	out := new(CephDashboardUserList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *CephDashboardUserList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephFilesystem) DeepCopyInto(out *CephFilesystem) {
 	*out = *in
@@ -1104,6 +1356,31 @@ func (in *CephFilesystemStatus) DeepCopyInto(out *CephFilesystemStatus) {
 			(*in)[i].DeepCopyInto(&(*out)[i])
 		}
 	}
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]FilesystemSnapshotScheduleStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedSnapshotSchedules != nil {
+		in, out := &in.AppliedSnapshotSchedules, &out.AppliedSnapshotSchedules
+		*out = make([]FilesystemSnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.MDSAutoscaler != nil {
+		in, out := &in.MDSAutoscaler, &out.MDSAutoscaler
+		*out = new(MDSAutoscalerStatus)
+		**out = **in
+	}
+	if in.Pinning != nil {
+		in, out := &in.Pinning, &out.Pinning
+		*out = new(FilesystemPinningStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Scrub != nil {
+		in, out := &in.Scrub, &out.Scrub
+		*out = new(FilesystemScrubStatus)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -1186,6 +1463,16 @@ func (in *CephFilesystemSubVolumeGroupList) DeepCopyObject() runtime.Object {
 func (in *CephFilesystemSubVolumeGroupSpec) DeepCopyInto(out *CephFilesystemSubVolumeGroupSpec) {
 	*out = *in
 	in.Pinning.DeepCopyInto(&out.Pinning)
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]FilesystemSnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1240,6 +1527,21 @@ func (in *CephFilesystemSubVolumeGroupStatus) DeepCopyInto(out *CephFilesystemSu
 			(*out)[key] = val
 		}
 	}
+	if in.Usage != nil {
+		in, out := &in.Usage, &out.Usage
+		*out = new(CephFilesystemSubVolumeGroupUsage)
+		**out = **in
+	}
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]FilesystemSnapshotScheduleStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.AppliedSnapshotSchedules != nil {
+		in, out := &in.AppliedSnapshotSchedules, &out.AppliedSnapshotSchedules
+		*out = make([]FilesystemSnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1253,6 +1555,22 @@ func (in *CephFilesystemSubVolumeGroupStatus) DeepCopy() *CephFilesystemSubVolum
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CephFilesystemSubVolumeGroupUsage) DeepCopyInto(out *CephFilesystemSubVolumeGroupUsage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CephFilesystemSubVolumeGroupUsage.
+func (in *CephFilesystemSubVolumeGroupUsage) DeepCopy() *CephFilesystemSubVolumeGroupUsage {
+	if in == nil {
+		return nil
+	}
+	out := new(CephFilesystemSubVolumeGroupUsage)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CephHealthMessage) DeepCopyInto(out *CephHealthMessage) {
 	*out = *in
@@ -1740,6 +2058,16 @@ func (in *CephStatus) DeepCopyInto(out *CephStatus) {
 		*out = new(CephDaemonsVersions)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Balancer != nil {
+		in, out := &in.Balancer, &out.Balancer
+		*out = new(BalancerStatus)
+		**out = **in
+	}
+	if in.MgrModules != nil {
+		in, out := &in.MgrModules, &out.MgrModules
+		*out = make([]MgrModuleStatus, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -1791,6 +2119,22 @@ func (in *CephVersionSpec) DeepCopy() *CephVersionSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertManagerIssuerReference) DeepCopyInto(out *CertManagerIssuerReference) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertManagerIssuerReference.
+func (in *CertManagerIssuerReference) DeepCopy() *CertManagerIssuerReference {
+	if in == nil {
+		return nil
+	}
+	out := new(CertManagerIssuerReference)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *CleanupPolicySpec) DeepCopyInto(out *CleanupPolicySpec) {
 	*out = *in
@@ -1895,10 +2239,15 @@ func (in *ClusterSpec) DeepCopyInto(out *ClusterSpec) {
 	out.DisruptionManagement = in.DisruptionManagement
 	in.Mon.DeepCopyInto(&out.Mon)
 	out.CrashCollector = in.CrashCollector
-	out.Dashboard = in.Dashboard
+	in.Dashboard.DeepCopyInto(&out.Dashboard)
 	in.Monitoring.DeepCopyInto(&out.Monitoring)
 	out.External = in.External
 	in.Mgr.DeepCopyInto(&out.Mgr)
+	if in.Balancer != nil {
+		in, out := &in.Balancer, &out.Balancer
+		*out = new(BalancerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	out.CleanupPolicy = in.CleanupPolicy
 	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
 	in.Security.DeepCopyInto(&out.Security)
@@ -1959,6 +2308,31 @@ func (in *ClusterStatus) DeepCopyInto(out *ClusterStatus) {
 		*out = new(ClusterVersion)
 		**out = **in
 	}
+	if in.CrushTopology != nil {
+		in, out := &in.CrushTopology, &out.CrushTopology
+		*out = new(CrushTopologyStatus)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.MonStores != nil {
+		in, out := &in.MonStores, &out.MonStores
+		*out = make([]MonStoreStatus, len(*in))
+		copy(*out, *in)
+	}
+	if in.MgrModuleSettings != nil {
+		in, out := &in.MgrModuleSettings, &out.MgrModuleSettings
+		*out = make(map[string][]string, len(*in))
+		for key, val := range *in {
+			var outVal []string
+			if val == nil {
+				(*out)[key] = nil
+			} else {
+				in, out := &val, &outVal
+				*out = make([]string, len(*in))
+				copy(*out, *in)
+			}
+			(*out)[key] = outVal
+		}
+	}
 	return
 }
 
@@ -2111,10 +2485,159 @@ func (in *CrashCollectorSpec) DeepCopy() *CrashCollectorSpec {
 }
 
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
-func (in *DaemonHealthSpec) DeepCopyInto(out *DaemonHealthSpec) {
+func (in *CrushBucketSpec) DeepCopyInto(out *CrushBucketSpec) {
 	*out = *in
-	in.Status.DeepCopyInto(&out.Status)
-	in.Monitor.DeepCopyInto(&out.Monitor)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushBucketSpec.
+func (in *CrushBucketSpec) DeepCopy() *CrushBucketSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushBucketSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushOSDSpec) DeepCopyInto(out *CrushOSDSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushOSDSpec.
+func (in *CrushOSDSpec) DeepCopy() *CrushOSDSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushOSDSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushRuleSpec) DeepCopyInto(out *CrushRuleSpec) {
+	*out = *in
+	if in.Steps != nil {
+		in, out := &in.Steps, &out.Steps
+		*out = make([]CrushRuleStep, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushRuleSpec.
+func (in *CrushRuleSpec) DeepCopy() *CrushRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushRuleStatus) DeepCopyInto(out *CrushRuleStatus) {
+	*out = *in
+	if in.RuleID != nil {
+		in, out := &in.RuleID, &out.RuleID
+		*out = new(int)
+		**out = **in
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushRuleStatus.
+func (in *CrushRuleStatus) DeepCopy() *CrushRuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushRuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushRuleStep) DeepCopyInto(out *CrushRuleStep) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushRuleStep.
+func (in *CrushRuleStep) DeepCopy() *CrushRuleStep {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushRuleStep)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushTopologySpec) DeepCopyInto(out *CrushTopologySpec) {
+	*out = *in
+	if in.Buckets != nil {
+		in, out := &in.Buckets, &out.Buckets
+		*out = make([]CrushBucketSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.OSDs != nil {
+		in, out := &in.OSDs, &out.OSDs
+		*out = make([]CrushOSDSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushTopologySpec.
+func (in *CrushTopologySpec) DeepCopy() *CrushTopologySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushTopologySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CrushTopologyStatus) DeepCopyInto(out *CrushTopologyStatus) {
+	*out = *in
+	if in.Drift != nil {
+		in, out := &in.Drift, &out.Drift
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PinnedOSDs != nil {
+		in, out := &in.PinnedOSDs, &out.PinnedOSDs
+		*out = make([]int, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CrushTopologyStatus.
+func (in *CrushTopologyStatus) DeepCopy() *CrushTopologyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CrushTopologyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DaemonHealthSpec) DeepCopyInto(out *DaemonHealthSpec) {
+	*out = *in
+	in.Status.DeepCopyInto(&out.Status)
+	in.Monitor.DeepCopyInto(&out.Monitor)
 	in.ObjectStorageDaemon.DeepCopyInto(&out.ObjectStorageDaemon)
 	return
 }
@@ -2129,9 +2652,88 @@ func (in *DaemonHealthSpec) DeepCopy() *DaemonHealthSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardCertManagerSpec) DeepCopyInto(out *DashboardCertManagerSpec) {
+	*out = *in
+	out.IssuerRef = in.IssuerRef
+	if in.DNSNames != nil {
+		in, out := &in.DNSNames, &out.DNSNames
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Duration != nil {
+		in, out := &in.Duration, &out.Duration
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.RenewBefore != nil {
+		in, out := &in.RenewBefore, &out.RenewBefore
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardCertManagerSpec.
+func (in *DashboardCertManagerSpec) DeepCopy() *DashboardCertManagerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardCertManagerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardSAML2Spec) DeepCopyInto(out *DashboardSAML2Spec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSAML2Spec.
+func (in *DashboardSAML2Spec) DeepCopy() *DashboardSAML2Spec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardSAML2Spec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardSSOSpec) DeepCopyInto(out *DashboardSSOSpec) {
+	*out = *in
+	if in.SAML2 != nil {
+		in, out := &in.SAML2, &out.SAML2
+		*out = new(DashboardSAML2Spec)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardSSOSpec.
+func (in *DashboardSSOSpec) DeepCopy() *DashboardSSOSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardSSOSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *DashboardSpec) DeepCopyInto(out *DashboardSpec) {
 	*out = *in
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(DashboardTLSSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.SSO != nil {
+		in, out := &in.SSO, &out.SSO
+		*out = new(DashboardSSOSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2145,6 +2747,86 @@ func (in *DashboardSpec) DeepCopy() *DashboardSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardTLSSpec) DeepCopyInto(out *DashboardTLSSpec) {
+	*out = *in
+	if in.CertManager != nil {
+		in, out := &in.CertManager, &out.CertManager
+		*out = new(DashboardCertManagerSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardTLSSpec.
+func (in *DashboardTLSSpec) DeepCopy() *DashboardTLSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardTLSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardUserSpec) DeepCopyInto(out *DashboardUserSpec) {
+	*out = *in
+	if in.Members != nil {
+		in, out := &in.Members, &out.Members
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Roles != nil {
+		in, out := &in.Roles, &out.Roles
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Enabled != nil {
+		in, out := &in.Enabled, &out.Enabled
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardUserSpec.
+func (in *DashboardUserSpec) DeepCopy() *DashboardUserSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardUserSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *DashboardUserStatus) DeepCopyInto(out *DashboardUserStatus) {
+	*out = *in
+	if in.Users != nil {
+		in, out := &in.Users, &out.Users
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new DashboardUserStatus.
+func (in *DashboardUserStatus) DeepCopy() *DashboardUserStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(DashboardUserStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Device) DeepCopyInto(out *Device) {
 	*out = *in
@@ -2295,6 +2977,53 @@ func (in *FSMirroringSpec) DeepCopy() *FSMirroringSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemDamage) DeepCopyInto(out *FilesystemDamage) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemDamage.
+func (in *FilesystemDamage) DeepCopy() *FilesystemDamage {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemDamage)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemDirectoryPinningSpec) DeepCopyInto(out *FilesystemDirectoryPinningSpec) {
+	*out = *in
+	if in.Export != nil {
+		in, out := &in.Export, &out.Export
+		*out = new(int)
+		**out = **in
+	}
+	if in.Distributed != nil {
+		in, out := &in.Distributed, &out.Distributed
+		*out = new(int)
+		**out = **in
+	}
+	if in.Random != nil {
+		in, out := &in.Random, &out.Random
+		*out = new(float64)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemDirectoryPinningSpec.
+func (in *FilesystemDirectoryPinningSpec) DeepCopy() *FilesystemDirectoryPinningSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemDirectoryPinningSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FilesystemMirrorInfoPeerSpec) DeepCopyInto(out *FilesystemMirrorInfoPeerSpec) {
 	*out = *in
@@ -2399,6 +3128,124 @@ func (in *FilesystemMirroringSpec) DeepCopy() *FilesystemMirroringSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemPinningStatus) DeepCopyInto(out *FilesystemPinningStatus) {
+	*out = *in
+	if in.PinnedPaths != nil {
+		in, out := &in.PinnedPaths, &out.PinnedPaths
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.Ranks != nil {
+		in, out := &in.Ranks, &out.Ranks
+		*out = make([]FilesystemRankSubtreesStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemPinningStatus.
+func (in *FilesystemPinningStatus) DeepCopy() *FilesystemPinningStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemPinningStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemRankSubtreesStatus) DeepCopyInto(out *FilesystemRankSubtreesStatus) {
+	*out = *in
+	if in.Subtrees != nil {
+		in, out := &in.Subtrees, &out.Subtrees
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemRankSubtreesStatus.
+func (in *FilesystemRankSubtreesStatus) DeepCopy() *FilesystemRankSubtreesStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemRankSubtreesStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemScrubSpec) DeepCopyInto(out *FilesystemScrubSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemScrubSpec.
+func (in *FilesystemScrubSpec) DeepCopy() *FilesystemScrubSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemScrubSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemScrubStatus) DeepCopyInto(out *FilesystemScrubStatus) {
+	*out = *in
+	if in.Damages != nil {
+		in, out := &in.Damages, &out.Damages
+		*out = make([]FilesystemDamage, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemScrubStatus.
+func (in *FilesystemScrubStatus) DeepCopy() *FilesystemScrubStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemScrubStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemSnapshotScheduleSpec) DeepCopyInto(out *FilesystemSnapshotScheduleSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSnapshotScheduleSpec.
+func (in *FilesystemSnapshotScheduleSpec) DeepCopy() *FilesystemSnapshotScheduleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemSnapshotScheduleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *FilesystemSnapshotScheduleStatus) DeepCopyInto(out *FilesystemSnapshotScheduleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new FilesystemSnapshotScheduleStatus.
+func (in *FilesystemSnapshotScheduleStatus) DeepCopy() *FilesystemSnapshotScheduleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(FilesystemSnapshotScheduleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *FilesystemSnapshotScheduleStatusRetention) DeepCopyInto(out *FilesystemSnapshotScheduleStatusRetention) {
 	*out = *in
@@ -2471,6 +3318,23 @@ func (in *FilesystemSpec) DeepCopyInto(out *FilesystemSpec) {
 		(*in).DeepCopyInto(*out)
 	}
 	in.StatusCheck.DeepCopyInto(&out.StatusCheck)
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]FilesystemSnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
+	if in.Pinning != nil {
+		in, out := &in.Pinning, &out.Pinning
+		*out = make([]FilesystemDirectoryPinningSpec, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	if in.Scrub != nil {
+		in, out := &in.Scrub, &out.Scrub
+		*out = new(FilesystemScrubSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -2860,6 +3724,48 @@ func (in *LogCollectorSpec) DeepCopy() *LogCollectorSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MDSAutoscalerSpec) DeepCopyInto(out *MDSAutoscalerSpec) {
+	*out = *in
+	if in.ScaleUpCooldown != nil {
+		in, out := &in.ScaleUpCooldown, &out.ScaleUpCooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.ScaleDownCooldown != nil {
+		in, out := &in.ScaleDownCooldown, &out.ScaleDownCooldown
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MDSAutoscalerSpec.
+func (in *MDSAutoscalerSpec) DeepCopy() *MDSAutoscalerSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MDSAutoscalerSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MDSAutoscalerStatus) DeepCopyInto(out *MDSAutoscalerStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MDSAutoscalerStatus.
+func (in *MDSAutoscalerStatus) DeepCopy() *MDSAutoscalerStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MDSAutoscalerStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MetadataServerSpec) DeepCopyInto(out *MetadataServerSpec) {
 	*out = *in
@@ -2889,6 +3795,11 @@ func (in *MetadataServerSpec) DeepCopyInto(out *MetadataServerSpec) {
 		*out = new(ProbeSpec)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Autoscaler != nil {
+		in, out := &in.Autoscaler, &out.Autoscaler
+		*out = new(MDSAutoscalerSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -2902,13 +3813,31 @@ func (in *MetadataServerSpec) DeepCopy() *MetadataServerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MgrModuleStatus) DeepCopyInto(out *MgrModuleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MgrModuleStatus.
+func (in *MgrModuleStatus) DeepCopy() *MgrModuleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MgrModuleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MgrSpec) DeepCopyInto(out *MgrSpec) {
 	*out = *in
 	if in.Modules != nil {
 		in, out := &in.Modules, &out.Modules
 		*out = make([]Module, len(*in))
-		copy(*out, *in)
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
 	}
 	return
 }
@@ -2927,6 +3856,7 @@ func (in *MgrSpec) DeepCopy() *MgrSpec {
 func (in *MirrorHealthCheckSpec) DeepCopyInto(out *MirrorHealthCheckSpec) {
 	*out = *in
 	in.Mirror.DeepCopyInto(&out.Mirror)
+	in.Usage.DeepCopyInto(&out.Usage)
 	return
 }
 
@@ -2940,6 +3870,38 @@ func (in *MirrorHealthCheckSpec) DeepCopy() *MirrorHealthCheckSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroredImageRoleStatus) DeepCopyInto(out *MirroredImageRoleStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroredImageRoleStatus.
+func (in *MirroredImageRoleStatus) DeepCopy() *MirroredImageRoleStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroredImageRoleStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroredImageStatusSpec) DeepCopyInto(out *MirroredImageStatusSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroredImageStatusSpec.
+func (in *MirroredImageStatusSpec) DeepCopy() *MirroredImageStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroredImageStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MirroringInfoSpec) DeepCopyInto(out *MirroringInfoSpec) {
 	*out = *in
@@ -2982,6 +3944,27 @@ func (in *MirroringPeerSpec) DeepCopy() *MirroringPeerSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MirroringRoleStatusSpec) DeepCopyInto(out *MirroringRoleStatusSpec) {
+	*out = *in
+	if in.Images != nil {
+		in, out := &in.Images, &out.Images
+		*out = make([]MirroredImageRoleStatus, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MirroringRoleStatusSpec.
+func (in *MirroringRoleStatusSpec) DeepCopy() *MirroringRoleStatusSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MirroringRoleStatusSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MirroringSpec) DeepCopyInto(out *MirroringSpec) {
 	*out = *in
@@ -3012,6 +3995,11 @@ func (in *MirroringSpec) DeepCopy() *MirroringSpec {
 func (in *MirroringStatusSpec) DeepCopyInto(out *MirroringStatusSpec) {
 	*out = *in
 	in.PoolMirroringStatus.DeepCopyInto(&out.PoolMirroringStatus)
+	if in.WorstImages != nil {
+		in, out := &in.WorstImages, &out.WorstImages
+		*out = make([]MirroredImageStatusSpec, len(*in))
+		copy(*out, *in)
+	}
 	return
 }
 
@@ -3028,6 +4016,13 @@ func (in *MirroringStatusSpec) DeepCopy() *MirroringStatusSpec {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Module) DeepCopyInto(out *Module) {
 	*out = *in
+	if in.Settings != nil {
+		in, out := &in.Settings, &out.Settings
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
 	return
 }
 
@@ -3036,7 +4031,28 @@ func (in *Module) DeepCopy() *Module {
 	if in == nil {
 		return nil
 	}
-	out := new(Module)
+	out := new(Module)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonPVCExpansionSpec) DeepCopyInto(out *MonPVCExpansionSpec) {
+	*out = *in
+	if in.MaxSize != nil {
+		in, out := &in.MaxSize, &out.MaxSize
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonPVCExpansionSpec.
+func (in *MonPVCExpansionSpec) DeepCopy() *MonPVCExpansionSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonPVCExpansionSpec)
 	in.DeepCopyInto(out)
 	return out
 }
@@ -3061,6 +4077,11 @@ func (in *MonSpec) DeepCopyInto(out *MonSpec) {
 		*out = new(VolumeClaimTemplate)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Store != nil {
+		in, out := &in.Store, &out.Store
+		*out = new(MonStoreSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3074,6 +4095,53 @@ func (in *MonSpec) DeepCopy() *MonSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonStoreSpec) DeepCopyInto(out *MonStoreSpec) {
+	*out = *in
+	if in.CompactionInterval != nil {
+		in, out := &in.CompactionInterval, &out.CompactionInterval
+		*out = new(metav1.Duration)
+		**out = **in
+	}
+	if in.CompactionThreshold != nil {
+		in, out := &in.CompactionThreshold, &out.CompactionThreshold
+		x := (*in).DeepCopy()
+		*out = &x
+	}
+	if in.PVCExpansion != nil {
+		in, out := &in.PVCExpansion, &out.PVCExpansion
+		*out = new(MonPVCExpansionSpec)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonStoreSpec.
+func (in *MonStoreSpec) DeepCopy() *MonStoreSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(MonStoreSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *MonStoreStatus) DeepCopyInto(out *MonStoreStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new MonStoreStatus.
+func (in *MonStoreStatus) DeepCopy() *MonStoreStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(MonStoreStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *MonZoneSpec) DeepCopyInto(out *MonZoneSpec) {
 	*out = *in
@@ -3110,6 +4178,11 @@ func (in *MonitoringSpec) DeepCopyInto(out *MonitoringSpec) {
 		*out = new(metav1.Duration)
 		**out = **in
 	}
+	if in.PrometheusRule != nil {
+		in, out := &in.PrometheusRule, &out.PrometheusRule
+		*out = new(PrometheusRuleSpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -3192,6 +4265,11 @@ func (in *NFSSecuritySpec) DeepCopy() *NFSSecuritySpec {
 func (in *NamedBlockPoolSpec) DeepCopyInto(out *NamedBlockPoolSpec) {
 	*out = *in
 	in.PoolSpec.DeepCopyInto(&out.PoolSpec)
+	if in.Migration != nil {
+		in, out := &in.Migration, &out.Migration
+		*out = new(PoolMigrationSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -3474,6 +4552,22 @@ func (in *ObjectRealmSpec) DeepCopy() *ObjectRealmSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ObjectSharedPoolsSpec) DeepCopyInto(out *ObjectSharedPoolsSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ObjectSharedPoolsSpec.
+func (in *ObjectSharedPoolsSpec) DeepCopy() *ObjectSharedPoolsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(ObjectSharedPoolsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ObjectStoreHostingSpec) DeepCopyInto(out *ObjectStoreHostingSpec) {
 	*out = *in
@@ -3518,6 +4612,7 @@ func (in *ObjectStoreSpec) DeepCopyInto(out *ObjectStoreSpec) {
 	*out = *in
 	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
 	in.DataPool.DeepCopyInto(&out.DataPool)
+	out.SharedPools = in.SharedPools
 	in.Gateway.DeepCopyInto(&out.Gateway)
 	out.Zone = in.Zone
 	in.HealthCheck.DeepCopyInto(&out.HealthCheck)
@@ -3697,6 +4792,7 @@ func (in *ObjectZoneSpec) DeepCopyInto(out *ObjectZoneSpec) {
 	*out = *in
 	in.MetadataPool.DeepCopyInto(&out.MetadataPool)
 	in.DataPool.DeepCopyInto(&out.DataPool)
+	out.SharedPools = in.SharedPools
 	if in.CustomEndpoints != nil {
 		in, out := &in.CustomEndpoints, &out.CustomEndpoints
 		*out = make([]string, len(*in))
@@ -3830,6 +4926,59 @@ func (in PlacementSpec) DeepCopy() PlacementSpec {
 	return *out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolCompressionStatus) DeepCopyInto(out *PoolCompressionStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolCompressionStatus.
+func (in *PoolCompressionStatus) DeepCopy() *PoolCompressionStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolCompressionStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolMigrationSpec) DeepCopyInto(out *PoolMigrationSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolMigrationSpec.
+func (in *PoolMigrationSpec) DeepCopy() *PoolMigrationSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolMigrationSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolMigrationStatus) DeepCopyInto(out *PoolMigrationStatus) {
+	*out = *in
+	if in.InUseImages != nil {
+		in, out := &in.InUseImages, &out.InUseImages
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolMigrationStatus.
+func (in *PoolMigrationStatus) DeepCopy() *PoolMigrationStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolMigrationStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolMirroringInfo) DeepCopyInto(out *PoolMirroringInfo) {
 	*out = *in
@@ -3889,6 +5038,38 @@ func (in *PoolMirroringStatusSummarySpec) DeepCopy() *PoolMirroringStatusSummary
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolPGStatus) DeepCopyInto(out *PoolPGStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolPGStatus.
+func (in *PoolPGStatus) DeepCopy() *PoolPGStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolPGStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolQuotaStatus) DeepCopyInto(out *PoolQuotaStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolQuotaStatus.
+func (in *PoolQuotaStatus) DeepCopy() *PoolQuotaStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolQuotaStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
 	*out = *in
@@ -3904,6 +5085,21 @@ func (in *PoolSpec) DeepCopyInto(out *PoolSpec) {
 	in.Mirroring.DeepCopyInto(&out.Mirroring)
 	in.StatusCheck.DeepCopyInto(&out.StatusCheck)
 	in.Quotas.DeepCopyInto(&out.Quotas)
+	if in.QoS != nil {
+		in, out := &in.QoS, &out.QoS
+		*out = new(RBDQoSSpec)
+		**out = **in
+	}
+	if in.ScheduledSnapshots != nil {
+		in, out := &in.ScheduledSnapshots, &out.ScheduledSnapshots
+		*out = new(RBDScheduledSnapshotsSpec)
+		**out = **in
+	}
+	if in.Stretch != nil {
+		in, out := &in.Stretch, &out.Stretch
+		*out = new(StretchPoolSpec)
+		**out = **in
+	}
 	return
 }
 
@@ -3917,6 +5113,37 @@ func (in *PoolSpec) DeepCopy() *PoolSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PoolUsageStatus) DeepCopyInto(out *PoolUsageStatus) {
+	*out = *in
+	if in.Quota != nil {
+		in, out := &in.Quota, &out.Quota
+		*out = new(PoolQuotaStatus)
+		**out = **in
+	}
+	if in.PGs != nil {
+		in, out := &in.PGs, &out.PGs
+		*out = new(PoolPGStatus)
+		**out = **in
+	}
+	if in.Compression != nil {
+		in, out := &in.Compression, &out.Compression
+		*out = new(PoolCompressionStatus)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PoolUsageStatus.
+func (in *PoolUsageStatus) DeepCopy() *PoolUsageStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(PoolUsageStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in PriorityClassNamesSpec) DeepCopyInto(out *PriorityClassNamesSpec) {
 	{
@@ -3960,6 +5187,45 @@ func (in *ProbeSpec) DeepCopy() *ProbeSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusAlertSpec) DeepCopyInto(out *PrometheusAlertSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusAlertSpec.
+func (in *PrometheusAlertSpec) DeepCopy() *PrometheusAlertSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusAlertSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *PrometheusRuleSpec) DeepCopyInto(out *PrometheusRuleSpec) {
+	*out = *in
+	if in.Alerts != nil {
+		in, out := &in.Alerts, &out.Alerts
+		*out = make(map[string]PrometheusAlertSpec, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new PrometheusRuleSpec.
+func (in *PrometheusRuleSpec) DeepCopy() *PrometheusRuleSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(PrometheusRuleSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *PullSpec) DeepCopyInto(out *PullSpec) {
 	*out = *in
@@ -4040,6 +5306,77 @@ func (in *RBDMirroringSpec) DeepCopy() *RBDMirroringSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBDQoSSpec) DeepCopyInto(out *RBDQoSSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDQoSSpec.
+func (in *RBDQoSSpec) DeepCopy() *RBDQoSSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBDQoSSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBDQoSStatus) DeepCopyInto(out *RBDQoSStatus) {
+	*out = *in
+	if in.Limits != nil {
+		in, out := &in.Limits, &out.Limits
+		*out = make(map[string]string, len(*in))
+		for key, val := range *in {
+			(*out)[key] = val
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDQoSStatus.
+func (in *RBDQoSStatus) DeepCopy() *RBDQoSStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RBDQoSStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBDScheduledSnapshotsSpec) DeepCopyInto(out *RBDScheduledSnapshotsSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDScheduledSnapshotsSpec.
+func (in *RBDScheduledSnapshotsSpec) DeepCopy() *RBDScheduledSnapshotsSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RBDScheduledSnapshotsSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RBDScheduledSnapshotsStatus) DeepCopyInto(out *RBDScheduledSnapshotsStatus) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RBDScheduledSnapshotsStatus.
+func (in *RBDScheduledSnapshotsStatus) DeepCopy() *RBDScheduledSnapshotsStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RBDScheduledSnapshotsStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *RGWServiceSpec) DeepCopyInto(out *RGWServiceSpec) {
 	*out = *in
@@ -4063,6 +5400,32 @@ func (in *RGWServiceSpec) DeepCopy() *RGWServiceSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RadosNamespaceMirroring) DeepCopyInto(out *RadosNamespaceMirroring) {
+	*out = *in
+	if in.RemoteNamespace != nil {
+		in, out := &in.RemoteNamespace, &out.RemoteNamespace
+		*out = new(string)
+		**out = **in
+	}
+	if in.SnapshotSchedules != nil {
+		in, out := &in.SnapshotSchedules, &out.SnapshotSchedules
+		*out = make([]SnapshotScheduleSpec, len(*in))
+		copy(*out, *in)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RadosNamespaceMirroring.
+func (in *RadosNamespaceMirroring) DeepCopy() *RadosNamespaceMirroring {
+	if in == nil {
+		return nil
+	}
+	out := new(RadosNamespaceMirroring)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ReadAffinitySpec) DeepCopyInto(out *ReadAffinitySpec) {
 	*out = *in
@@ -4478,6 +5841,11 @@ func (in *StorageScopeSpec) DeepCopyInto(out *StorageScopeSpec) {
 		}
 	}
 	out.Store = in.Store
+	if in.CrushTopology != nil {
+		in, out := &in.CrushTopology, &out.CrushTopology
+		*out = new(CrushTopologySpec)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -4514,6 +5882,22 @@ func (in *StretchClusterSpec) DeepCopy() *StretchClusterSpec {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *StretchPoolSpec) DeepCopyInto(out *StretchPoolSpec) {
+	*out = *in
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new StretchPoolSpec.
+func (in *StretchPoolSpec) DeepCopy() *StretchPoolSpec {
+	if in == nil {
+		return nil
+	}
+	out := new(StretchPoolSpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TopicEndpointSpec) DeepCopyInto(out *TopicEndpointSpec) {
 	*out = *in
@@ -33,6 +33,8 @@ type CephV1Interface interface {
 	CephCOSIDriversGetter
 	CephClientsGetter
 	CephClustersGetter
+	CephCrushRulesGetter
+	CephDashboardUsersGetter
 	CephFilesystemsGetter
 	CephFilesystemMirrorsGetter
 	CephFilesystemSubVolumeGroupsGetter
@@ -78,6 +80,14 @@ func (c *CephV1Client) CephClusters(namespace string) CephClusterInterface {
 	return newCephClusters(c, namespace)
 }
 
+func (c *CephV1Client) CephCrushRules(namespace string) CephCrushRuleInterface {
+	return newCephCrushRules(c, namespace)
+}
+
+func (c *CephV1Client) CephDashboardUsers(namespace string) CephDashboardUserInterface {
+	return newCephDashboardUsers(c, namespace)
+}
+
 func (c *CephV1Client) CephFilesystems(namespace string) CephFilesystemInterface {
 	return newCephFilesystems(c, namespace)
 }
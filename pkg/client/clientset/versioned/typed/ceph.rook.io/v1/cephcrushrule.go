@@ -0,0 +1,178 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	scheme "github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CephCrushRulesGetter has a method to return a CephCrushRuleInterface.
+// A group's client should implement this interface.
+type CephCrushRulesGetter interface {
+	CephCrushRules(namespace string) CephCrushRuleInterface
+}
+
+// CephCrushRuleInterface has methods to work with CephCrushRule resources.
+type CephCrushRuleInterface interface {
+	Create(ctx context.Context, cephCrushRule *v1.CephCrushRule, opts metav1.CreateOptions) (*v1.CephCrushRule, error)
+	Update(ctx context.Context, cephCrushRule *v1.CephCrushRule, opts metav1.UpdateOptions) (*v1.CephCrushRule, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.CephCrushRule, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.CephCrushRuleList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CephCrushRule, err error)
+	CephCrushRuleExpansion
+}
+
+// cephCrushRules implements CephCrushRuleInterface
+type cephCrushRules struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCephCrushRules returns a CephCrushRules
+func newCephCrushRules(c *CephV1Client, namespace string) *cephCrushRules {
+	return &cephCrushRules{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cephCrushRule, and returns the corresponding cephCrushRule object, and an error if there is any.
+func (c *cephCrushRules) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.CephCrushRule, err error) {
+	result = &v1.CephCrushRule{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cephcrushrules").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CephCrushRules that match those selectors.
+func (c *cephCrushRules) List(ctx context.Context, opts metav1.ListOptions) (result *v1.CephCrushRuleList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.CephCrushRuleList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cephcrushrules").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cephCrushRules.
+func (c *cephCrushRules) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cephcrushrules").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a cephCrushRule and creates it.  Returns the server's representation of the cephCrushRule, and an error, if there is any.
+func (c *cephCrushRules) Create(ctx context.Context, cephCrushRule *v1.CephCrushRule, opts metav1.CreateOptions) (result *v1.CephCrushRule, err error) {
+	result = &v1.CephCrushRule{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cephcrushrules").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cephCrushRule).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cephCrushRule and updates it. Returns the server's representation of the cephCrushRule, and an error, if there is any.
+func (c *cephCrushRules) Update(ctx context.Context, cephCrushRule *v1.CephCrushRule, opts metav1.UpdateOptions) (result *v1.CephCrushRule, err error) {
+	result = &v1.CephCrushRule{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cephcrushrules").
+		Name(cephCrushRule.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cephCrushRule).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the cephCrushRule and deletes it. Returns an error if one occurs.
+func (c *cephCrushRules) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cephcrushrules").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cephCrushRules) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cephcrushrules").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched cephCrushRule.
+func (c *cephCrushRules) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CephCrushRule, err error) {
+	result = &v1.CephCrushRule{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cephcrushrules").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -0,0 +1,178 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	"context"
+	"time"
+
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	scheme "github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	rest "k8s.io/client-go/rest"
+)
+
+// CephDashboardUsersGetter has a method to return a CephDashboardUserInterface.
+// A group's client should implement this interface.
+type CephDashboardUsersGetter interface {
+	CephDashboardUsers(namespace string) CephDashboardUserInterface
+}
+
+// CephDashboardUserInterface has methods to work with CephDashboardUser resources.
+type CephDashboardUserInterface interface {
+	Create(ctx context.Context, cephDashboardUser *v1.CephDashboardUser, opts metav1.CreateOptions) (*v1.CephDashboardUser, error)
+	Update(ctx context.Context, cephDashboardUser *v1.CephDashboardUser, opts metav1.UpdateOptions) (*v1.CephDashboardUser, error)
+	Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error
+	DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error
+	Get(ctx context.Context, name string, opts metav1.GetOptions) (*v1.CephDashboardUser, error)
+	List(ctx context.Context, opts metav1.ListOptions) (*v1.CephDashboardUserList, error)
+	Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error)
+	Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CephDashboardUser, err error)
+	CephDashboardUserExpansion
+}
+
+// cephDashboardUsers implements CephDashboardUserInterface
+type cephDashboardUsers struct {
+	client rest.Interface
+	ns     string
+}
+
+// newCephDashboardUsers returns a CephDashboardUsers
+func newCephDashboardUsers(c *CephV1Client, namespace string) *cephDashboardUsers {
+	return &cephDashboardUsers{
+		client: c.RESTClient(),
+		ns:     namespace,
+	}
+}
+
+// Get takes name of the cephDashboardUser, and returns the corresponding cephDashboardUser object, and an error if there is any.
+func (c *cephDashboardUsers) Get(ctx context.Context, name string, options metav1.GetOptions) (result *v1.CephDashboardUser, err error) {
+	result = &v1.CephDashboardUser{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cephdashboardusers").
+		Name(name).
+		VersionedParams(&options, scheme.ParameterCodec).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// List takes label and field selectors, and returns the list of CephDashboardUsers that match those selectors.
+func (c *cephDashboardUsers) List(ctx context.Context, opts metav1.ListOptions) (result *v1.CephDashboardUserList, err error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	result = &v1.CephDashboardUserList{}
+	err = c.client.Get().
+		Namespace(c.ns).
+		Resource("cephdashboardusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Watch returns a watch.Interface that watches the requested cephDashboardUsers.
+func (c *cephDashboardUsers) Watch(ctx context.Context, opts metav1.ListOptions) (watch.Interface, error) {
+	var timeout time.Duration
+	if opts.TimeoutSeconds != nil {
+		timeout = time.Duration(*opts.TimeoutSeconds) * time.Second
+	}
+	opts.Watch = true
+	return c.client.Get().
+		Namespace(c.ns).
+		Resource("cephdashboardusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Watch(ctx)
+}
+
+// Create takes the representation of a cephDashboardUser and creates it.  Returns the server's representation of the cephDashboardUser, and an error, if there is any.
+func (c *cephDashboardUsers) Create(ctx context.Context, cephDashboardUser *v1.CephDashboardUser, opts metav1.CreateOptions) (result *v1.CephDashboardUser, err error) {
+	result = &v1.CephDashboardUser{}
+	err = c.client.Post().
+		Namespace(c.ns).
+		Resource("cephdashboardusers").
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cephDashboardUser).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Update takes the representation of a cephDashboardUser and updates it. Returns the server's representation of the cephDashboardUser, and an error, if there is any.
+func (c *cephDashboardUsers) Update(ctx context.Context, cephDashboardUser *v1.CephDashboardUser, opts metav1.UpdateOptions) (result *v1.CephDashboardUser, err error) {
+	result = &v1.CephDashboardUser{}
+	err = c.client.Put().
+		Namespace(c.ns).
+		Resource("cephdashboardusers").
+		Name(cephDashboardUser.Name).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(cephDashboardUser).
+		Do(ctx).
+		Into(result)
+	return
+}
+
+// Delete takes name of the cephDashboardUser and deletes it. Returns an error if one occurs.
+func (c *cephDashboardUsers) Delete(ctx context.Context, name string, opts metav1.DeleteOptions) error {
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cephdashboardusers").
+		Name(name).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *cephDashboardUsers) DeleteCollection(ctx context.Context, opts metav1.DeleteOptions, listOpts metav1.ListOptions) error {
+	var timeout time.Duration
+	if listOpts.TimeoutSeconds != nil {
+		timeout = time.Duration(*listOpts.TimeoutSeconds) * time.Second
+	}
+	return c.client.Delete().
+		Namespace(c.ns).
+		Resource("cephdashboardusers").
+		VersionedParams(&listOpts, scheme.ParameterCodec).
+		Timeout(timeout).
+		Body(&opts).
+		Do(ctx).
+		Error()
+}
+
+// Patch applies the patch and returns the patched cephDashboardUser.
+func (c *cephDashboardUsers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts metav1.PatchOptions, subresources ...string) (result *v1.CephDashboardUser, err error) {
+	result = &v1.CephDashboardUser{}
+	err = c.client.Patch(pt).
+		Namespace(c.ns).
+		Resource("cephdashboardusers").
+		Name(name).
+		SubResource(subresources...).
+		VersionedParams(&opts, scheme.ParameterCodec).
+		Body(data).
+		Do(ctx).
+		Into(result)
+	return
+}
@@ -56,6 +56,14 @@ func (c *FakeCephV1) CephClusters(namespace string) v1.CephClusterInterface {
 	return &FakeCephClusters{c, namespace}
 }
 
+func (c *FakeCephV1) CephCrushRules(namespace string) v1.CephCrushRuleInterface {
+	return &FakeCephCrushRules{c, namespace}
+}
+
+func (c *FakeCephV1) CephDashboardUsers(namespace string) v1.CephDashboardUserInterface {
+	return &FakeCephDashboardUsers{c, namespace}
+}
+
 func (c *FakeCephV1) CephFilesystems(namespace string) v1.CephFilesystemInterface {
 	return &FakeCephFilesystems{c, namespace}
 }
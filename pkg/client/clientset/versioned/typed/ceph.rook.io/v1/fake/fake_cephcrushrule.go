@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	cephrookiov1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCephCrushRules implements CephCrushRuleInterface
+type FakeCephCrushRules struct {
+	Fake *FakeCephV1
+	ns   string
+}
+
+var cephcrushrulesResource = schema.GroupVersionResource{Group: "ceph.rook.io", Version: "v1", Resource: "cephcrushrules"}
+
+var cephcrushrulesKind = schema.GroupVersionKind{Group: "ceph.rook.io", Version: "v1", Kind: "CephCrushRule"}
+
+// Get takes name of the cephCrushRule, and returns the corresponding cephCrushRule object, and an error if there is any.
+func (c *FakeCephCrushRules) Get(ctx context.Context, name string, options v1.GetOptions) (result *cephrookiov1.CephCrushRule, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cephcrushrulesResource, c.ns, name), &cephrookiov1.CephCrushRule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephCrushRule), err
+}
+
+// List takes label and field selectors, and returns the list of CephCrushRules that match those selectors.
+func (c *FakeCephCrushRules) List(ctx context.Context, opts v1.ListOptions) (result *cephrookiov1.CephCrushRuleList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cephcrushrulesResource, cephcrushrulesKind, c.ns, opts), &cephrookiov1.CephCrushRuleList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &cephrookiov1.CephCrushRuleList{ListMeta: obj.(*cephrookiov1.CephCrushRuleList).ListMeta}
+	for _, item := range obj.(*cephrookiov1.CephCrushRuleList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cephCrushRules.
+func (c *FakeCephCrushRules) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cephcrushrulesResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cephCrushRule and creates it.  Returns the server's representation of the cephCrushRule, and an error, if there is any.
+func (c *FakeCephCrushRules) Create(ctx context.Context, cephCrushRule *cephrookiov1.CephCrushRule, opts v1.CreateOptions) (result *cephrookiov1.CephCrushRule, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cephcrushrulesResource, c.ns, cephCrushRule), &cephrookiov1.CephCrushRule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephCrushRule), err
+}
+
+// Update takes the representation of a cephCrushRule and updates it. Returns the server's representation of the cephCrushRule, and an error, if there is any.
+func (c *FakeCephCrushRules) Update(ctx context.Context, cephCrushRule *cephrookiov1.CephCrushRule, opts v1.UpdateOptions) (result *cephrookiov1.CephCrushRule, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cephcrushrulesResource, c.ns, cephCrushRule), &cephrookiov1.CephCrushRule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephCrushRule), err
+}
+
+// Delete takes name of the cephCrushRule and deletes it. Returns an error if one occurs.
+func (c *FakeCephCrushRules) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cephcrushrulesResource, c.ns, name), &cephrookiov1.CephCrushRule{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCephCrushRules) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cephcrushrulesResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &cephrookiov1.CephCrushRuleList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cephCrushRule.
+func (c *FakeCephCrushRules) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *cephrookiov1.CephCrushRule, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cephcrushrulesResource, c.ns, name, pt, data, subresources...), &cephrookiov1.CephCrushRule{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephCrushRule), err
+}
@@ -0,0 +1,130 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by client-gen. DO NOT EDIT.
+
+package fake
+
+import (
+	"context"
+
+	cephrookiov1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	labels "k8s.io/apimachinery/pkg/labels"
+	schema "k8s.io/apimachinery/pkg/runtime/schema"
+	types "k8s.io/apimachinery/pkg/types"
+	watch "k8s.io/apimachinery/pkg/watch"
+	testing "k8s.io/client-go/testing"
+)
+
+// FakeCephDashboardUsers implements CephDashboardUserInterface
+type FakeCephDashboardUsers struct {
+	Fake *FakeCephV1
+	ns   string
+}
+
+var cephdashboardusersResource = schema.GroupVersionResource{Group: "ceph.rook.io", Version: "v1", Resource: "cephdashboardusers"}
+
+var cephdashboardusersKind = schema.GroupVersionKind{Group: "ceph.rook.io", Version: "v1", Kind: "CephDashboardUser"}
+
+// Get takes name of the cephDashboardUser, and returns the corresponding cephDashboardUser object, and an error if there is any.
+func (c *FakeCephDashboardUsers) Get(ctx context.Context, name string, options v1.GetOptions) (result *cephrookiov1.CephDashboardUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewGetAction(cephdashboardusersResource, c.ns, name), &cephrookiov1.CephDashboardUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephDashboardUser), err
+}
+
+// List takes label and field selectors, and returns the list of CephDashboardUsers that match those selectors.
+func (c *FakeCephDashboardUsers) List(ctx context.Context, opts v1.ListOptions) (result *cephrookiov1.CephDashboardUserList, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewListAction(cephdashboardusersResource, cephdashboardusersKind, c.ns, opts), &cephrookiov1.CephDashboardUserList{})
+
+	if obj == nil {
+		return nil, err
+	}
+
+	label, _, _ := testing.ExtractFromListOptions(opts)
+	if label == nil {
+		label = labels.Everything()
+	}
+	list := &cephrookiov1.CephDashboardUserList{ListMeta: obj.(*cephrookiov1.CephDashboardUserList).ListMeta}
+	for _, item := range obj.(*cephrookiov1.CephDashboardUserList).Items {
+		if label.Matches(labels.Set(item.Labels)) {
+			list.Items = append(list.Items, item)
+		}
+	}
+	return list, err
+}
+
+// Watch returns a watch.Interface that watches the requested cephDashboardUsers.
+func (c *FakeCephDashboardUsers) Watch(ctx context.Context, opts v1.ListOptions) (watch.Interface, error) {
+	return c.Fake.
+		InvokesWatch(testing.NewWatchAction(cephdashboardusersResource, c.ns, opts))
+
+}
+
+// Create takes the representation of a cephDashboardUser and creates it.  Returns the server's representation of the cephDashboardUser, and an error, if there is any.
+func (c *FakeCephDashboardUsers) Create(ctx context.Context, cephDashboardUser *cephrookiov1.CephDashboardUser, opts v1.CreateOptions) (result *cephrookiov1.CephDashboardUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewCreateAction(cephdashboardusersResource, c.ns, cephDashboardUser), &cephrookiov1.CephDashboardUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephDashboardUser), err
+}
+
+// Update takes the representation of a cephDashboardUser and updates it. Returns the server's representation of the cephDashboardUser, and an error, if there is any.
+func (c *FakeCephDashboardUsers) Update(ctx context.Context, cephDashboardUser *cephrookiov1.CephDashboardUser, opts v1.UpdateOptions) (result *cephrookiov1.CephDashboardUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewUpdateAction(cephdashboardusersResource, c.ns, cephDashboardUser), &cephrookiov1.CephDashboardUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephDashboardUser), err
+}
+
+// Delete takes name of the cephDashboardUser and deletes it. Returns an error if one occurs.
+func (c *FakeCephDashboardUsers) Delete(ctx context.Context, name string, opts v1.DeleteOptions) error {
+	_, err := c.Fake.
+		Invokes(testing.NewDeleteAction(cephdashboardusersResource, c.ns, name), &cephrookiov1.CephDashboardUser{})
+
+	return err
+}
+
+// DeleteCollection deletes a collection of objects.
+func (c *FakeCephDashboardUsers) DeleteCollection(ctx context.Context, opts v1.DeleteOptions, listOpts v1.ListOptions) error {
+	action := testing.NewDeleteCollectionAction(cephdashboardusersResource, c.ns, listOpts)
+
+	_, err := c.Fake.Invokes(action, &cephrookiov1.CephDashboardUserList{})
+	return err
+}
+
+// Patch applies the patch and returns the patched cephDashboardUser.
+func (c *FakeCephDashboardUsers) Patch(ctx context.Context, name string, pt types.PatchType, data []byte, opts v1.PatchOptions, subresources ...string) (result *cephrookiov1.CephDashboardUser, err error) {
+	obj, err := c.Fake.
+		Invokes(testing.NewPatchSubresourceAction(cephdashboardusersResource, c.ns, name, pt, data, subresources...), &cephrookiov1.CephDashboardUser{})
+
+	if obj == nil {
+		return nil, err
+	}
+	return obj.(*cephrookiov1.CephDashboardUser), err
+}
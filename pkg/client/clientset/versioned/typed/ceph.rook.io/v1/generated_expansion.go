@@ -32,6 +32,10 @@ type CephClientExpansion interface{}
 
 type CephClusterExpansion interface{}
 
+type CephCrushRuleExpansion interface{}
+
+type CephDashboardUserExpansion interface{}
+
 type CephFilesystemExpansion interface{}
 
 type CephFilesystemMirrorExpansion interface{}
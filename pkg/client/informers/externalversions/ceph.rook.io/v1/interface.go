@@ -38,6 +38,10 @@ type Interface interface {
 	CephClients() CephClientInformer
 	// CephClusters returns a CephClusterInformer.
 	CephClusters() CephClusterInformer
+	// CephCrushRules returns a CephCrushRuleInformer.
+	CephCrushRules() CephCrushRuleInformer
+	// CephDashboardUsers returns a CephDashboardUserInformer.
+	CephDashboardUsers() CephDashboardUserInformer
 	// CephFilesystems returns a CephFilesystemInformer.
 	CephFilesystems() CephFilesystemInformer
 	// CephFilesystemMirrors returns a CephFilesystemMirrorInformer.
@@ -106,6 +110,16 @@ func (v *version) CephClusters() CephClusterInformer {
 	return &cephClusterInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
 
+// CephCrushRules returns a CephCrushRuleInformer.
+func (v *version) CephCrushRules() CephCrushRuleInformer {
+	return &cephCrushRuleInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// CephDashboardUsers returns a CephDashboardUserInformer.
+func (v *version) CephDashboardUsers() CephDashboardUserInformer {
+	return &cephDashboardUserInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // CephFilesystems returns a CephFilesystemInformer.
 func (v *version) CephFilesystems() CephFilesystemInformer {
 	return &cephFilesystemInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
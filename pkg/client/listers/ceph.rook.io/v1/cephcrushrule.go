@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CephCrushRuleLister helps list CephCrushRules.
+// All objects returned here must be treated as read-only.
+type CephCrushRuleLister interface {
+	// List lists all CephCrushRules in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CephCrushRule, err error)
+	// CephCrushRules returns an object that can list and get CephCrushRules.
+	CephCrushRules(namespace string) CephCrushRuleNamespaceLister
+	CephCrushRuleListerExpansion
+}
+
+// cephCrushRuleLister implements the CephCrushRuleLister interface.
+type cephCrushRuleLister struct {
+	indexer cache.Indexer
+}
+
+// NewCephCrushRuleLister returns a new CephCrushRuleLister.
+func NewCephCrushRuleLister(indexer cache.Indexer) CephCrushRuleLister {
+	return &cephCrushRuleLister{indexer: indexer}
+}
+
+// List lists all CephCrushRules in the indexer.
+func (s *cephCrushRuleLister) List(selector labels.Selector) (ret []*v1.CephCrushRule, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephCrushRule))
+	})
+	return ret, err
+}
+
+// CephCrushRules returns an object that can list and get CephCrushRules.
+func (s *cephCrushRuleLister) CephCrushRules(namespace string) CephCrushRuleNamespaceLister {
+	return cephCrushRuleNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CephCrushRuleNamespaceLister helps list and get CephCrushRules.
+// All objects returned here must be treated as read-only.
+type CephCrushRuleNamespaceLister interface {
+	// List lists all CephCrushRules in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CephCrushRule, err error)
+	// Get retrieves the CephCrushRule from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.CephCrushRule, error)
+	CephCrushRuleNamespaceListerExpansion
+}
+
+// cephCrushRuleNamespaceLister implements the CephCrushRuleNamespaceLister
+// interface.
+type cephCrushRuleNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CephCrushRules in the indexer for a given namespace.
+func (s cephCrushRuleNamespaceLister) List(selector labels.Selector) (ret []*v1.CephCrushRule, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephCrushRule))
+	})
+	return ret, err
+}
+
+// Get retrieves the CephCrushRule from the indexer for a given namespace and name.
+func (s cephCrushRuleNamespaceLister) Get(name string) (*v1.CephCrushRule, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cephcrushrule"), name)
+	}
+	return obj.(*v1.CephCrushRule), nil
+}
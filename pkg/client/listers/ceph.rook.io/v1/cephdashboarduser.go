@@ -0,0 +1,99 @@
+/*
+Copyright The Kubernetes Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Code generated by lister-gen. DO NOT EDIT.
+
+package v1
+
+import (
+	v1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/client-go/tools/cache"
+)
+
+// CephDashboardUserLister helps list CephDashboardUsers.
+// All objects returned here must be treated as read-only.
+type CephDashboardUserLister interface {
+	// List lists all CephDashboardUsers in the indexer.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CephDashboardUser, err error)
+	// CephDashboardUsers returns an object that can list and get CephDashboardUsers.
+	CephDashboardUsers(namespace string) CephDashboardUserNamespaceLister
+	CephDashboardUserListerExpansion
+}
+
+// cephDashboardUserLister implements the CephDashboardUserLister interface.
+type cephDashboardUserLister struct {
+	indexer cache.Indexer
+}
+
+// NewCephDashboardUserLister returns a new CephDashboardUserLister.
+func NewCephDashboardUserLister(indexer cache.Indexer) CephDashboardUserLister {
+	return &cephDashboardUserLister{indexer: indexer}
+}
+
+// List lists all CephDashboardUsers in the indexer.
+func (s *cephDashboardUserLister) List(selector labels.Selector) (ret []*v1.CephDashboardUser, err error) {
+	err = cache.ListAll(s.indexer, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephDashboardUser))
+	})
+	return ret, err
+}
+
+// CephDashboardUsers returns an object that can list and get CephDashboardUsers.
+func (s *cephDashboardUserLister) CephDashboardUsers(namespace string) CephDashboardUserNamespaceLister {
+	return cephDashboardUserNamespaceLister{indexer: s.indexer, namespace: namespace}
+}
+
+// CephDashboardUserNamespaceLister helps list and get CephDashboardUsers.
+// All objects returned here must be treated as read-only.
+type CephDashboardUserNamespaceLister interface {
+	// List lists all CephDashboardUsers in the indexer for a given namespace.
+	// Objects returned here must be treated as read-only.
+	List(selector labels.Selector) (ret []*v1.CephDashboardUser, err error)
+	// Get retrieves the CephDashboardUser from the indexer for a given namespace and name.
+	// Objects returned here must be treated as read-only.
+	Get(name string) (*v1.CephDashboardUser, error)
+	CephDashboardUserNamespaceListerExpansion
+}
+
+// cephDashboardUserNamespaceLister implements the CephDashboardUserNamespaceLister
+// interface.
+type cephDashboardUserNamespaceLister struct {
+	indexer   cache.Indexer
+	namespace string
+}
+
+// List lists all CephDashboardUsers in the indexer for a given namespace.
+func (s cephDashboardUserNamespaceLister) List(selector labels.Selector) (ret []*v1.CephDashboardUser, err error) {
+	err = cache.ListAllByNamespace(s.indexer, s.namespace, selector, func(m interface{}) {
+		ret = append(ret, m.(*v1.CephDashboardUser))
+	})
+	return ret, err
+}
+
+// Get retrieves the CephDashboardUser from the indexer for a given namespace and name.
+func (s cephDashboardUserNamespaceLister) Get(name string) (*v1.CephDashboardUser, error) {
+	obj, exists, err := s.indexer.GetByKey(s.namespace + "/" + name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, errors.NewNotFound(v1.Resource("cephdashboarduser"), name)
+	}
+	return obj.(*v1.CephDashboardUser), nil
+}
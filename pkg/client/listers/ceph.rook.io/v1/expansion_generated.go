@@ -74,6 +74,22 @@ type CephClusterListerExpansion interface{}
 // CephClusterNamespaceLister.
 type CephClusterNamespaceListerExpansion interface{}
 
+// CephCrushRuleListerExpansion allows custom methods to be added to
+// CephCrushRuleLister.
+type CephCrushRuleListerExpansion interface{}
+
+// CephCrushRuleNamespaceListerExpansion allows custom methods to be added to
+// CephCrushRuleNamespaceLister.
+type CephCrushRuleNamespaceListerExpansion interface{}
+
+// CephDashboardUserListerExpansion allows custom methods to be added to
+// CephDashboardUserLister.
+type CephDashboardUserListerExpansion interface{}
+
+// CephDashboardUserNamespaceListerExpansion allows custom methods to be added to
+// CephDashboardUserNamespaceLister.
+type CephDashboardUserNamespaceListerExpansion interface{}
+
 // CephFilesystemListerExpansion allows custom methods to be added to
 // CephFilesystemLister.
 type CephFilesystemListerExpansion interface{}
@@ -47,6 +47,8 @@ const (
 	CrushTool = "crushtool"
 	// GaneshaRadosGraceTool is the name of the CLI tool for 'ganesha-rados-grace'
 	GaneshaRadosGraceTool = "ganesha-rados-grace"
+	// PythonTool is the name of the python interpreter, used to call the Ceph python bindings
+	PythonTool = "python3"
 	// DefaultPGCount will cause Ceph to use the internal default PG count
 	DefaultPGCount = "0"
 	// CommandProxyInitContainerName is the name of the init container for proxying ceph command when multus is used
@@ -69,7 +71,7 @@ func FinalizeCephCommandArgs(command string, clusterInfo *ClusterInfo, args []st
 	// some tools not support the '--connect-timeout' option
 	// so we only use it for the 'ceph' command
 	switch command {
-	case RBDTool, CrushTool, RadosTool, "radosgw-admin":
+	case RBDTool, CrushTool, RadosTool, PythonTool, "radosgw-admin":
 		// do not add timeout flag
 	case GaneshaRadosGraceTool:
 		// do not add timeout flag
@@ -166,6 +168,20 @@ func NewGaneshaRadosGraceCommand(context *clusterd.Context, clusterInfo *Cluster
 	return cmd
 }
 
+// NewPythonCommand runs a python script using the Ceph python bindings. The standard flags for config and
+// keyring are appended to the arguments of the script.
+func NewPythonCommand(context *clusterd.Context, clusterInfo *ClusterInfo, script string, args []string) *CephToolCommand {
+	cmd := newCephToolCommand(PythonTool, context, clusterInfo, append([]string{"-c", script}, args...))
+	cmd.JsonOutput = false
+
+	// When Multus is enabled, the script should run inside the proxy container
+	if clusterInfo.NetworkSpec.IsMultus() {
+		cmd.RemoteExecution = true
+	}
+
+	return cmd
+}
+
 func (c *CephToolCommand) run() ([]byte, error) {
 	// Return if the context has been canceled
 	if c.clusterInfo.Context.Err() != nil {
@@ -193,7 +209,7 @@ func (c *CephToolCommand) run() ([]byte, error) {
 	} else {
 		// the `rbd` tool doesn't use special flag for plain format
 		switch c.tool {
-		case RBDTool, RadosTool, GaneshaRadosGraceTool:
+		case RBDTool, RadosTool, GaneshaRadosGraceTool, PythonTool:
 			// do not add format option
 		default:
 			args = append(args, "--format", "plain")
@@ -203,9 +219,13 @@ func (c *CephToolCommand) run() ([]byte, error) {
 	var output, stderr string
 	var err error
 
+	// Trace the command as part of the reconcile of the context, if any
+	endSpan := exec.StartCommandSpan(c.clusterInfo.Context, c.tool, c.args...)
+	defer func() { endSpan(err) }()
+
 	// NewRBDCommand does not use the --out-file option so we only check for remote execution here
 	// Still forcing the check for the command if the behavior changes in the future
-	if command == RBDTool || command == RadosTool || command == GaneshaRadosGraceTool {
+	if command == RBDTool || command == RadosTool || command == GaneshaRadosGraceTool || command == PythonTool {
 		if c.RemoteExecution {
 			output, stderr, err = c.context.RemoteExecutor.ExecCommandInContainerWithFullOutputWithTimeout(c.clusterInfo.Context, ProxyAppLabel, CommandProxyInitContainerName, c.clusterInfo.Namespace, append([]string{command}, args...)...)
 			if err != nil {
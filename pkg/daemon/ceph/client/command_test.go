@@ -161,6 +161,38 @@ func TestNewRBDCommand(t *testing.T) {
 
 }
 
+func TestNewPythonCommand(t *testing.T) {
+	t.Run("python command with no multus", func(t *testing.T) {
+		executor := &exectest.MockExecutor{
+			MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+				assert.Equal(t, "python3", command)
+				assert.Equal(t, []string{"-c", "print(1)", "arg"}, args[:3])
+				// the standard flags are passed to the script, but no format or timeout
+				assert.Contains(t, args, "--name=client.admin")
+				assert.NotContains(t, args, "--format")
+				assert.NotContains(t, strings.Join(args, " "), "--connect-timeout")
+				return "1", nil
+			},
+		}
+		cmd := NewPythonCommand(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("rook"), "print(1)", []string{"arg"})
+		assert.False(t, cmd.RemoteExecution)
+		output, err := cmd.RunWithTimeout(time.Second)
+		assert.NoError(t, err)
+		assert.Equal(t, "1", string(output))
+	})
+
+	t.Run("python command with multus", func(t *testing.T) {
+		clusterInfo := AdminTestClusterInfo("rook")
+		clusterInfo.NetworkSpec.Provider = "multus"
+		context := &clusterd.Context{Executor: &exectest.MockExecutor{}, RemoteExecutor: exec.RemotePodCommandExecutor{ClientSet: test.New(t, 3)}}
+		cmd := NewPythonCommand(context, clusterInfo, "print(1)", []string{"arg"})
+		assert.True(t, cmd.RemoteExecution)
+		_, err := cmd.Run()
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "no pods found with selector \"rook-ceph-mgr\"")
+	})
+}
+
 func TestNewGaneshaRadosGraceCommand(t *testing.T) {
 	anyArgContains := func(substr string, args []string) bool {
 		for _, arg := range args {
@@ -208,6 +208,51 @@ func GetOSDOnHost(context *clusterd.Context, clusterInfo *ClusterInfo, node stri
 	return string(buf), nil
 }
 
+// CreateCrushBucket adds a bucket to the CRUSH map. The bucket is not placed in the hierarchy until
+// it is moved under a parent.
+func CreateCrushBucket(context *clusterd.Context, clusterInfo *ClusterInfo, name, bucketType string) error {
+	args := []string{"osd", "crush", "add-bucket", name, bucketType}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to add %s bucket %q to the crush map. %s", bucketType, name, string(buf))
+	}
+
+	return nil
+}
+
+// MoveCrushBucket moves a bucket of the CRUSH map under a parent bucket
+func MoveCrushBucket(context *clusterd.Context, clusterInfo *ClusterInfo, name, parentType, parentName string) error {
+	args := []string{"osd", "crush", "move", name, formatProperty(parentType, parentName)}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to move bucket %q under %s %q. %s", name, parentType, parentName, string(buf))
+	}
+
+	return nil
+}
+
+// SetOSDCrushLocation places an OSD in a bucket of the CRUSH map with the given CRUSH weight
+func SetOSDCrushLocation(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int, weight float64, bucketType, bucketName string) error {
+	args := []string{"osd", "crush", "set", fmt.Sprintf("osd.%d", osdID), strconv.FormatFloat(weight, 'f', -1, 64), formatProperty(bucketType, bucketName)}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to place osd.%d in %s %q. %s", osdID, bucketType, bucketName, string(buf))
+	}
+
+	return nil
+}
+
+// ReweightOSD sets the CRUSH weight of an OSD
+func ReweightOSD(context *clusterd.Context, clusterInfo *ClusterInfo, osdID int, weight float64) error {
+	args := []string{"osd", "crush", "reweight", fmt.Sprintf("osd.%d", osdID), strconv.FormatFloat(weight, 'f', -1, 64)}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the crush weight of osd.%d to %g. %s", osdID, weight, string(buf))
+	}
+
+	return nil
+}
+
 func compileCRUSHMap(context *clusterd.Context, crushMapPath string) error {
 	mapFile := buildCompileCRUSHFileName(crushMapPath)
 	args := []string{"--compile", crushMapPath, "--outfn", mapFile}
@@ -19,12 +19,20 @@ package client
 import (
 	"encoding/json"
 	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 )
 
+// MSRCrushRuleVersion is the minimum ceph version supporting the multi-step retry CRUSH rules
+var MSRCrushRuleVersion = cephver.CephVersion{Major: 19, Minor: 2}
+
 const (
 	crushReplicatedType      = 1
 	ruleMinSizeDefault       = 1
@@ -199,3 +207,180 @@ func getCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, name stri
 
 	return rule, nil
 }
+
+// BuildCustomCrushRule generates the plain text of a CRUSH rule from the steps of a CephCrushRule
+func BuildCustomCrushRule(ruleName string, ruleID int, spec cephv1.CrushRuleSpec) (string, error) {
+	ruleType := spec.Type
+	if ruleType == "" {
+		ruleType = "replicated"
+	}
+	defaultMode := "firstn"
+	if ruleType == "erasure" {
+		defaultMode = "indep"
+	}
+	msr := IsMSRCrushRule(spec)
+
+	var rule strings.Builder
+	fmt.Fprintf(&rule, "\nrule %s {\n        id %d\n        type %s\n        min_size %d\n        max_size %d\n", ruleName, ruleID, ruleType, ruleMinSizeDefault, ruleMaxSizeDefault)
+	for i, step := range spec.Steps {
+		switch step.Op {
+		case cephv1.CrushRuleStepTake:
+			if step.Item == "" {
+				return "", errors.Errorf("step %d of crush rule %q takes no item", i, ruleName)
+			}
+			fmt.Fprintf(&rule, "        step take %s", step.Item)
+			if step.DeviceClass != "" {
+				fmt.Fprintf(&rule, " class %s", step.DeviceClass)
+			}
+			rule.WriteString("\n")
+		case cephv1.CrushRuleStepChoose, cephv1.CrushRuleStepChooseLeaf:
+			if msr {
+				return "", errors.Errorf("step %d of multi-step retry crush rule %q must use %q instead of %q", i, ruleName, cephv1.CrushRuleStepChooseMSR, step.Op)
+			}
+			if step.Type == "" {
+				return "", errors.Errorf("step %d of crush rule %q chooses no bucket type", i, ruleName)
+			}
+			mode := step.Mode
+			if mode == "" {
+				mode = defaultMode
+			}
+			fmt.Fprintf(&rule, "        step %s %s %d type %s\n", step.Op, mode, step.Number, step.Type)
+		case cephv1.CrushRuleStepChooseMSR:
+			if !msr {
+				return "", errors.Errorf("step %d of crush rule %q uses %q, which requires a multi-step retry rule type", i, ruleName, step.Op)
+			}
+			if step.Type == "" {
+				return "", errors.Errorf("step %d of crush rule %q chooses no bucket type", i, ruleName)
+			}
+			fmt.Fprintf(&rule, "        step choosemsr %d type %s\n", step.Number, step.Type)
+		case cephv1.CrushRuleStepEmit:
+			rule.WriteString("        step emit\n")
+		default:
+			return "", errors.Errorf("step %d of crush rule %q has unknown operation %q", i, ruleName, step.Op)
+		}
+	}
+	if len(spec.Steps) == 0 || spec.Steps[len(spec.Steps)-1].Op != cephv1.CrushRuleStepEmit {
+		return "", errors.Errorf("crush rule %q must end with an emit step", ruleName)
+	}
+	rule.WriteString("}\n")
+
+	return rule.String(), nil
+}
+
+// IsMSRCrushRule returns whether a CephCrushRule is a multi-step retry rule, supported since Ceph Squid
+func IsMSRCrushRule(spec cephv1.CrushRuleSpec) bool {
+	return strings.HasPrefix(spec.Type, "msr_")
+}
+
+// CreateOrUpdateCrushRule creates a CRUSH rule from the steps of a CephCrushRule, or replaces the
+// steps of the rule if it already exists. The rule keeps its ID when it is updated so the pools
+// using it are not affected other than by the data movement. The ID of the rule is returned.
+func CreateOrUpdateCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, ruleName string, spec cephv1.CrushRuleSpec) (int, error) {
+	crushMap, err := getCurrentCrushMap(context, clusterInfo)
+	if err != nil {
+		return 0, err
+	}
+
+	ruleID := 0
+	if len(crushMap.Rules) > 0 {
+		ruleID = generateRuleID(crushMap.Rules)
+	}
+	for _, rule := range crushMap.Rules {
+		if rule.Name == ruleName {
+			ruleID = rule.ID
+			break
+		}
+	}
+
+	ruleset, err := BuildCustomCrushRule(ruleName, ruleID, spec)
+	if err != nil {
+		return 0, err
+	}
+
+	logger.Infof("applying crush rule %q with id %d", ruleName, ruleID)
+	if err := replaceCrushRule(context, clusterInfo, ruleName, ruleset); err != nil {
+		return 0, errors.Wrapf(err, "failed to apply crush rule %q", ruleName)
+	}
+
+	return ruleID, nil
+}
+
+// DeleteCrushRule deletes a CRUSH rule. Ceph refuses to delete a rule used by a pool.
+func DeleteCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, ruleName string) error {
+	args := []string{"osd", "crush", "rule", "rm", ruleName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to delete crush rule %q. %s", ruleName, string(output))
+	}
+
+	logger.Infof("deleted crush rule %q", ruleName)
+	return nil
+}
+
+// RenameCrushRule renames a CRUSH rule
+func RenameCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, oldName, newName string) error {
+	args := []string{"osd", "crush", "rule", "rename", oldName, newName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to rename crush rule %q to %q. %s", oldName, newName, string(output))
+	}
+
+	logger.Infof("renamed crush rule %q to %q", oldName, newName)
+	return nil
+}
+
+// CrushRuleExists returns whether a CRUSH rule exists
+func CrushRuleExists(context *clusterd.Context, clusterInfo *ClusterInfo, ruleName string) (bool, error) {
+	crushMap, err := getCurrentCrushMap(context, clusterInfo)
+	if err != nil {
+		return false, err
+	}
+
+	return crushRuleExists(crushMap, ruleName), nil
+}
+
+// replaceCrushRule removes a rule from the decompiled CRUSH map, appends the plain text of the new
+// rule, then compiles and injects the CRUSH map
+func replaceCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, ruleName, ruleset string) error {
+	compiledCRUSHMapFilePath, err := GetCompiledCrushMap(context, clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get crush map")
+	}
+	defer func() {
+		if err := os.Remove(compiledCRUSHMapFilePath); err != nil {
+			logger.Errorf("failed to remove file %q. %v", compiledCRUSHMapFilePath, err)
+		}
+	}()
+
+	if err := decompileCRUSHMap(context, compiledCRUSHMapFilePath); err != nil {
+		return errors.Wrap(err, "failed to decompile crush map")
+	}
+	decompiledCRUSHMapFilePath := buildDecompileCRUSHFileName(compiledCRUSHMapFilePath)
+	defer func() {
+		if err := os.Remove(decompiledCRUSHMapFilePath); err != nil {
+			logger.Errorf("failed to remove file %q. %v", decompiledCRUSHMapFilePath, err)
+		}
+	}()
+
+	crushMap, err := os.ReadFile(filepath.Clean(decompiledCRUSHMapFilePath))
+	if err != nil {
+		return errors.Wrapf(err, "failed to read decompiled crush map %q", decompiledCRUSHMapFilePath)
+	}
+	ruleRegex := regexp.MustCompile(`(?ms)^rule\s+` + regexp.QuoteMeta(ruleName) + `\s*\{.*?^\}\s*$\n?`)
+	crushMap = append(ruleRegex.ReplaceAll(crushMap, nil), []byte(ruleset)...)
+	if err := os.WriteFile(decompiledCRUSHMapFilePath, crushMap, 0600); err != nil {
+		return errors.Wrapf(err, "failed to write decompiled crush map %q", decompiledCRUSHMapFilePath)
+	}
+
+	if err := compileCRUSHMap(context, decompiledCRUSHMapFilePath); err != nil {
+		return errors.Wrap(err, "failed to compile crush map")
+	}
+	compiledRuleCRUSHMapFilePath := buildCompileCRUSHFileName(decompiledCRUSHMapFilePath)
+	defer func() {
+		if err := os.Remove(compiledRuleCRUSHMapFilePath); err != nil {
+			logger.Errorf("failed to remove file %q. %v", compiledRuleCRUSHMapFilePath, err)
+		}
+	}()
+
+	return injectCRUSHMap(context, clusterInfo, compiledRuleCRUSHMapFilePath)
+}
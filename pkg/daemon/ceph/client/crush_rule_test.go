@@ -18,6 +18,8 @@ package client
 
 import (
 	"encoding/json"
+	"os"
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -135,3 +137,163 @@ func Test_generateRuleID(t *testing.T) {
 		})
 	}
 }
+
+func TestBuildCustomCrushRule(t *testing.T) {
+	spec := cephv1.CrushRuleSpec{
+		Steps: []cephv1.CrushRuleStep{
+			{Op: cephv1.CrushRuleStepTake, Item: "default", DeviceClass: "ssd"},
+			{Op: cephv1.CrushRuleStepChoose, Number: 2, Type: "datacenter"},
+			{Op: cephv1.CrushRuleStepChooseLeaf, Number: 2, Type: "host"},
+			{Op: cephv1.CrushRuleStepEmit},
+		},
+	}
+
+	t.Run("replicated rule", func(t *testing.T) {
+		rule, err := BuildCustomCrushRule("two-per-dc", 3, spec)
+		assert.NoError(t, err)
+		assert.Equal(t, `
+rule two-per-dc {
+        id 3
+        type replicated
+        min_size 1
+        max_size 10
+        step take default class ssd
+        step choose firstn 2 type datacenter
+        step chooseleaf firstn 2 type host
+        step emit
+}
+`, rule)
+	})
+
+	t.Run("erasure rule", func(t *testing.T) {
+		spec := *spec.DeepCopy()
+		spec.Type = "erasure"
+		spec.Steps[2].Mode = "firstn"
+		rule, err := BuildCustomCrushRule("ec-per-dc", 4, spec)
+		assert.NoError(t, err)
+		assert.Contains(t, rule, "type erasure\n")
+		assert.Contains(t, rule, "step choose indep 2 type datacenter\n")
+		assert.Contains(t, rule, "step chooseleaf firstn 2 type host\n")
+	})
+
+	t.Run("multi-step retry rule", func(t *testing.T) {
+		msrSpec := cephv1.CrushRuleSpec{
+			Type: "msr_indep",
+			Steps: []cephv1.CrushRuleStep{
+				{Op: cephv1.CrushRuleStepTake, Item: "default"},
+				{Op: cephv1.CrushRuleStepChooseMSR, Number: 4, Type: "host"},
+				{Op: cephv1.CrushRuleStepChooseMSR, Number: 2, Type: "osd"},
+				{Op: cephv1.CrushRuleStepEmit},
+			},
+		}
+		assert.True(t, IsMSRCrushRule(msrSpec))
+		assert.False(t, IsMSRCrushRule(spec))
+		rule, err := BuildCustomCrushRule("ec-msr", 5, msrSpec)
+		assert.NoError(t, err)
+		assert.Contains(t, rule, "type msr_indep\n")
+		assert.Contains(t, rule, "step choosemsr 4 type host\n        step choosemsr 2 type osd\n")
+
+		_, err = BuildCustomCrushRule("invalid", 3, cephv1.CrushRuleSpec{Type: "msr_firstn", Steps: spec.Steps})
+		assert.ErrorContains(t, err, "must use \"choosemsr\"")
+
+		_, err = BuildCustomCrushRule("invalid", 3, cephv1.CrushRuleSpec{Steps: msrSpec.Steps})
+		assert.ErrorContains(t, err, "requires a multi-step retry rule type")
+	})
+
+	t.Run("invalid steps", func(t *testing.T) {
+		_, err := BuildCustomCrushRule("invalid", 3, cephv1.CrushRuleSpec{Steps: spec.Steps[:3]})
+		assert.ErrorContains(t, err, "must end with an emit step")
+
+		_, err = BuildCustomCrushRule("invalid", 3, cephv1.CrushRuleSpec{Steps: []cephv1.CrushRuleStep{{Op: cephv1.CrushRuleStepTake}, {Op: cephv1.CrushRuleStepEmit}}})
+		assert.ErrorContains(t, err, "takes no item")
+
+		_, err = BuildCustomCrushRule("invalid", 3, cephv1.CrushRuleSpec{Steps: []cephv1.CrushRuleStep{{Op: cephv1.CrushRuleStepChooseLeaf}, {Op: cephv1.CrushRuleStepEmit}}})
+		assert.ErrorContains(t, err, "chooses no bucket type")
+	})
+}
+
+func TestCreateOrUpdateCrushRule(t *testing.T) {
+	decompiledCrushMap := `# begin crush map
+root default {
+	id -1
+	alg straw2
+	hash 0
+}
+
+# rules
+rule replicated_ruleset {
+	id 0
+	type replicated
+	step take default
+	step chooseleaf firstn 0 type host
+	step emit
+}
+rule hybrid_ruleset {
+	id 1
+	type replicated
+	step take default class hdd
+	step chooseleaf firstn 0 type host
+	step emit
+}
+
+# end crush map
+`
+	var compiledCrushMap string
+	injected := false
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if command == "crushtool" {
+			if args[0] == "--decompile" {
+				return "", os.WriteFile(args[3], []byte(decompiledCrushMap), 0600)
+			}
+			if args[0] == "--compile" {
+				buf, err := os.ReadFile(args[1])
+				compiledCrushMap = string(buf)
+				return "", err
+			}
+		}
+		if args[0] == "osd" {
+			if args[1] == "crush" && args[2] == "dump" {
+				return testCrushMap, nil
+			}
+			if args[1] == "getcrushmap" {
+				return "", nil
+			}
+			if args[1] == "setcrushmap" {
+				injected = true
+				return "", nil
+			}
+		}
+		return "", errors.Errorf("unexpected ceph command '%v'", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	spec := cephv1.CrushRuleSpec{
+		Steps: []cephv1.CrushRuleStep{
+			{Op: cephv1.CrushRuleStepTake, Item: "default", DeviceClass: "ssd"},
+			{Op: cephv1.CrushRuleStepChooseLeaf, Type: "host"},
+			{Op: cephv1.CrushRuleStepEmit},
+		},
+	}
+
+	t.Run("new rule", func(t *testing.T) {
+		ruleID, err := CreateOrUpdateCrushRule(context, AdminTestClusterInfo("mycluster"), "ssd-rule", spec)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, ruleID)
+		assert.True(t, injected)
+		assert.Contains(t, compiledCrushMap, "rule hybrid_ruleset {")
+		assert.True(t, strings.HasSuffix(compiledCrushMap, "\nrule ssd-rule {\n        id 2\n        type replicated\n        min_size 1\n        max_size 10\n        step take default class ssd\n        step chooseleaf firstn 0 type host\n        step emit\n}\n"))
+	})
+
+	t.Run("existing rule", func(t *testing.T) {
+		injected = false
+		ruleID, err := CreateOrUpdateCrushRule(context, AdminTestClusterInfo("mycluster"), "hybrid_ruleset", spec)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, ruleID)
+		assert.True(t, injected)
+		assert.Equal(t, 1, strings.Count(compiledCrushMap, "rule hybrid_ruleset {"))
+		assert.NotContains(t, compiledCrushMap, "step take default class hdd")
+		assert.Contains(t, compiledCrushMap, "rule replicated_ruleset {\n\tid 0\n")
+		assert.Contains(t, compiledCrushMap, "step take default class ssd")
+	})
+}
@@ -0,0 +1,193 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"strings"
+	"syscall"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+// DashboardUser is a user of the dashboard as returned by 'ceph dashboard ac-user-show'
+type DashboardUser struct {
+	Username string   `json:"username"`
+	Roles    []string `json:"roles"`
+	Name     string   `json:"name"`
+	Email    string   `json:"email"`
+	Enabled  bool     `json:"enabled"`
+}
+
+// GetDashboardUser returns a user of the dashboard, or nil if the user does not exist
+func GetDashboardUser(context *clusterd.Context, clusterInfo *ClusterInfo, username string) (*DashboardUser, error) {
+	args := []string{"dashboard", "ac-user-show", username}
+	buf, err := NewCephCommand(context, clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to get dashboard user %q. %s", username, string(buf))
+	}
+
+	var user DashboardUser
+	if err := json.Unmarshal(buf, &user); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal dashboard user %q", username)
+	}
+	return &user, nil
+}
+
+// CreateDashboardUser creates a user of the dashboard with the given password
+func CreateDashboardUser(context *clusterd.Context, clusterInfo *ClusterInfo, username, password string) error {
+	err := runDashboardCommandWithInput(context, clusterInfo, []string{"dashboard", "ac-user-create", username}, password)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create dashboard user %q", username)
+	}
+	logger.Infof("created dashboard user %q", username)
+	return nil
+}
+
+// SetDashboardUserPassword sets the password of a user of the dashboard
+func SetDashboardUserPassword(context *clusterd.Context, clusterInfo *ClusterInfo, username, password string) error {
+	err := runDashboardCommandWithInput(context, clusterInfo, []string{"dashboard", "ac-user-set-password", username}, password)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the password of dashboard user %q", username)
+	}
+	return nil
+}
+
+// SetDashboardUserRoles replaces the roles of a user of the dashboard
+func SetDashboardUserRoles(context *clusterd.Context, clusterInfo *ClusterInfo, username string, roles []string) error {
+	args := append([]string{"dashboard", "ac-user-set-roles", username}, roles...)
+	if err := runDashboardCommand(context, clusterInfo, args); err != nil {
+		return errors.Wrapf(err, "failed to set the roles of dashboard user %q", username)
+	}
+	logger.Infof("set the roles of dashboard user %q to %v", username, roles)
+	return nil
+}
+
+// SetDashboardUserInfo sets the full name and the email of a user of the dashboard
+func SetDashboardUserInfo(context *clusterd.Context, clusterInfo *ClusterInfo, username, name, email string) error {
+	args := []string{"dashboard", "ac-user-set-info", username, name, email}
+	if err := runDashboardCommand(context, clusterInfo, args); err != nil {
+		return errors.Wrapf(err, "failed to set the info of dashboard user %q", username)
+	}
+	return nil
+}
+
+// SetDashboardUserEnabled enables or disables the sign in of a user of the dashboard
+func SetDashboardUserEnabled(context *clusterd.Context, clusterInfo *ClusterInfo, username string, enabled bool) error {
+	command := "ac-user-disable"
+	if enabled {
+		command = "ac-user-enable"
+	}
+	if err := runDashboardCommand(context, clusterInfo, []string{"dashboard", command, username}); err != nil {
+		return errors.Wrapf(err, "failed to run %s on dashboard user %q", command, username)
+	}
+	logger.Infof("dashboard user %q enabled: %t", username, enabled)
+	return nil
+}
+
+// DeleteDashboardUser deletes a user of the dashboard, the user may not exist
+func DeleteDashboardUser(context *clusterd.Context, clusterInfo *ClusterInfo, username string) error {
+	args := []string{"dashboard", "ac-user-delete", username}
+	buf, err := NewCephCommand(context, clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("dashboard user %q does not exist", username)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete dashboard user %q. %s", username, string(buf))
+	}
+	logger.Infof("deleted dashboard user %q", username)
+	return nil
+}
+
+// SetupDashboardSAML2 configures the SAML 2.0 identity provider of the dashboard
+func SetupDashboardSAML2(context *clusterd.Context, clusterInfo *ClusterInfo, baseURL, idpMetadata, usernameAttribute, entityID string) error {
+	args := []string{"dashboard", "sso", "setup", "saml2", baseURL, idpMetadata}
+	if usernameAttribute != "" || entityID != "" {
+		if usernameAttribute == "" {
+			usernameAttribute = "uid"
+		}
+		args = append(args, usernameAttribute)
+	}
+	if entityID != "" {
+		args = append(args, entityID)
+	}
+	if err := runDashboardCommand(context, clusterInfo, args); err != nil {
+		return errors.Wrap(err, "failed to set up dashboard saml2 sso")
+	}
+	return nil
+}
+
+// EnableDashboardSSO enables the single sign-on of the dashboard with the given protocol
+func EnableDashboardSSO(context *clusterd.Context, clusterInfo *ClusterInfo, protocol string) error {
+	if err := runDashboardCommand(context, clusterInfo, []string{"dashboard", "sso", "enable", protocol}); err != nil {
+		return errors.Wrapf(err, "failed to enable dashboard %s sso", protocol)
+	}
+	return nil
+}
+
+// DisableDashboardSSO disables the single sign-on of the dashboard if it is enabled
+func DisableDashboardSSO(context *clusterd.Context, clusterInfo *ClusterInfo) error {
+	cmd := NewCephCommand(context, clusterInfo, []string{"dashboard", "sso", "status"})
+	cmd.JsonOutput = false
+	buf, err := cmd.RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get dashboard sso status. %s", string(buf))
+	}
+	// e.g. 'SSO is "disabled".' or 'SSO is "enabled" with "SAML2" protocol.'
+	if !strings.Contains(string(buf), `"enabled"`) {
+		return nil
+	}
+	if err := runDashboardCommand(context, clusterInfo, []string{"dashboard", "sso", "disable"}); err != nil {
+		return errors.Wrap(err, "failed to disable dashboard sso")
+	}
+	logger.Info("disabled dashboard sso")
+	return nil
+}
+
+func runDashboardCommand(context *clusterd.Context, clusterInfo *ClusterInfo, args []string) error {
+	cmd := NewCephCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	buf, err := cmd.RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "%s", string(buf))
+	}
+	return nil
+}
+
+// runDashboardCommandWithInput runs a dashboard command that reads a secret from a file so that the
+// secret is not logged
+func runDashboardCommandWithInput(context *clusterd.Context, clusterInfo *ClusterInfo, args []string, input string) error {
+	file, err := util.CreateTempFile(input)
+	if err != nil {
+		return errors.Wrap(err, "failed to create a temporary file")
+	}
+	defer func() {
+		if err := os.Remove(file.Name()); err != nil {
+			logger.Errorf("failed to clean up file %q. %v", file.Name(), err)
+		}
+	}()
+
+	return runDashboardCommand(context, clusterInfo, append(args, "-i", file.Name()))
+}
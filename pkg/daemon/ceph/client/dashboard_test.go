@@ -0,0 +1,82 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetDashboardUser(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			assert.Equal(t, "ac-user-show", args[1])
+			if args[2] == "alice" {
+				return `{"username": "alice", "password": "$2b$12$hash", "roles": ["read-only"], "name": "Alice", "email": "alice@example.com", "lastUpdate": 1700000000, "enabled": true}`, nil
+			}
+			return "Error ENOENT: User 'bob' does not exist", syscall.ENOENT
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	user, err := GetDashboardUser(context, AdminTestClusterInfo("mycluster"), "alice")
+	assert.NoError(t, err)
+	assert.Equal(t, &DashboardUser{Username: "alice", Roles: []string{"read-only"}, Name: "Alice", Email: "alice@example.com", Enabled: true}, user)
+
+	user, err = GetDashboardUser(context, AdminTestClusterInfo("mycluster"), "bob")
+	assert.NoError(t, err)
+	assert.Nil(t, user)
+}
+
+func TestCreateDashboardUser(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			assert.Equal(t, []string{"dashboard", "ac-user-create", "alice", "-i"}, args[:4])
+			// the password is passed in a file
+			password, err := os.ReadFile(args[4])
+			assert.NoError(t, err)
+			assert.Equal(t, "secret", string(password))
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	assert.NoError(t, CreateDashboardUser(context, AdminTestClusterInfo("mycluster"), "alice", "secret"))
+}
+
+func TestDeleteDashboardUser(t *testing.T) {
+	deleteErr := error(syscall.ENOENT)
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			assert.Equal(t, []string{"dashboard", "ac-user-delete", "alice"}, args[:3])
+			return "", deleteErr
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	// the user does not exist anymore
+	assert.NoError(t, DeleteDashboardUser(context, AdminTestClusterInfo("mycluster"), "alice"))
+
+	deleteErr = syscall.EINVAL
+	assert.Error(t, DeleteDashboardUser(context, AdminTestClusterInfo("mycluster"), "alice"))
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const mdsCacheMemoryLimit = "mds_cache_memory_limit"
+
+// FilesystemStatus is a representation of the json structure returned by 'ceph fs status'
+type FilesystemStatus struct {
+	MDSMap []MDSStatus `json:"mdsmap"`
+}
+
+// MDSStatus is a representation of the mds daemon sub-structure returned by 'ceph fs status'
+type MDSStatus struct {
+	Name     string  `json:"name"`
+	Rank     int     `json:"rank"`
+	State    string  `json:"state"`
+	Rate     float64 `json:"rate"`
+	Dentries int64   `json:"dns"`
+	Inodes   int64   `json:"inos"`
+}
+
+// MDSCacheStatus is a representation of the json structure returned by 'ceph tell mds.<id> cache status'
+type MDSCacheStatus struct {
+	Pool struct {
+		Items int64  `json:"items"`
+		Bytes uint64 `json:"bytes"`
+	} `json:"pool"`
+}
+
+// MDSLoad is the load of the active mds daemons of a filesystem
+type MDSLoad struct {
+	// ActiveCount is the number of active mds daemons
+	ActiveCount int32
+	// RequestRate is the total number of client requests per second served by the active mds daemons
+	RequestRate float64
+	// CachePressure is the average percentage of the cache memory limit used by the active mds daemons
+	CachePressure int32
+}
+
+// GetFilesystemStatus returns the status of the mds daemons of a filesystem
+func GetFilesystemStatus(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) (*FilesystemStatus, error) {
+	args := []string{"fs", "status", fsName}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get status of filesystem %q", fsName)
+	}
+
+	var status FilesystemStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal filesystem %q status. %s", fsName, string(buf))
+	}
+
+	return &status, nil
+}
+
+// GetMDSCacheUsage returns the memory used by the cache of an mds daemon and its cache memory limit
+func GetMDSCacheUsage(context *clusterd.Context, clusterInfo *ClusterInfo, mdsName string) (uint64, uint64, error) {
+	daemon := fmt.Sprintf("mds.%s", mdsName)
+	buf, err := NewCephCommand(context, clusterInfo, []string{"tell", daemon, "cache", "status"}).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to get cache status of %q", daemon)
+	}
+	var cacheStatus MDSCacheStatus
+	if err := json.Unmarshal(buf, &cacheStatus); err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to unmarshal cache status of %q. %s", daemon, string(buf))
+	}
+
+	buf, err = NewCephCommand(context, clusterInfo, []string{"tell", daemon, "config", "get", mdsCacheMemoryLimit}).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to get %q of %q", mdsCacheMemoryLimit, daemon)
+	}
+	var config map[string]string
+	if err := json.Unmarshal(buf, &config); err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to unmarshal %q of %q. %s", mdsCacheMemoryLimit, daemon, string(buf))
+	}
+	limit, err := strconv.ParseUint(config[mdsCacheMemoryLimit], 10, 64)
+	if err != nil {
+		return 0, 0, errors.Wrapf(err, "failed to parse %q of %q", mdsCacheMemoryLimit, daemon)
+	}
+
+	return cacheStatus.Pool.Bytes, limit, nil
+}
+
+// GetMDSLoad returns the load of the active mds daemons of a filesystem. The daemons whose cache
+// usage cannot be retrieved are ignored when computing the cache pressure.
+func GetMDSLoad(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) (*MDSLoad, error) {
+	status, err := GetFilesystemStatus(context, clusterInfo, fsName)
+	if err != nil {
+		return nil, err
+	}
+
+	load := &MDSLoad{}
+	var totalPressure uint64
+	var cacheCount uint64
+	for _, mds := range status.MDSMap {
+		if mds.State != "active" {
+			continue
+		}
+		load.ActiveCount++
+		load.RequestRate += mds.Rate
+
+		used, limit, err := GetMDSCacheUsage(context, clusterInfo, mds.Name)
+		if err != nil {
+			logger.Warningf("failed to get cache usage of mds %q of filesystem %q. %v", mds.Name, fsName, err)
+			continue
+		}
+		if limit == 0 {
+			continue
+		}
+		totalPressure += used * 100 / limit
+		cacheCount++
+	}
+	if cacheCount > 0 {
+		load.CachePressure = int32(totalPressure / cacheCount)
+	}
+
+	return load, nil
+}
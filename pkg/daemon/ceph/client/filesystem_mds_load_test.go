@@ -0,0 +1,73 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// response of "ceph fs status myfs"
+var fsStatus = `{"clients": [{"clients": 2, "fs": "myfs"}], "mds_version": [{"daemon": ["myfs-a", "myfs-b", "myfs-c"], "version": "ceph version 18.2.0"}],
+"mdsmap": [{"caps": 5, "dirs": 12, "dns": 10, "inos": 13, "name": "myfs-a", "rank": 0, "rate": 1200.5, "state": "active"},
+{"caps": 5, "dirs": 12, "dns": 10, "inos": 13, "name": "myfs-b", "rank": 1, "rate": 300, "state": "active"},
+{"events": 0, "name": "myfs-c", "rank": 0, "state": "standby-replay"}],
+"pools": [{"avail": 1000, "id": 1, "name": "myfs-metadata", "type": "metadata", "used": 100}]}`
+
+func TestGetMDSLoad(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "status" {
+				return fsStatus, nil
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "tell" && args[2] == "cache" {
+				if args[1] == "mds.myfs-a" {
+					return `{"pool": {"items": 1000, "bytes": 900}}`, nil
+				}
+				return "", errors.New("mds not responding")
+			}
+			if args[0] == "tell" && args[2] == "config" {
+				return `{"mds_cache_memory_limit": "1000"}`, nil
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	load, err := GetMDSLoad(context, AdminTestClusterInfo("mycluster"), "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, int32(2), load.ActiveCount)
+	assert.Equal(t, 1500.5, load.RequestRate)
+	// the cache pressure of the mds that does not respond is ignored
+	assert.Equal(t, int32(90), load.CachePressure)
+
+	used, limit, err := GetMDSCacheUsage(context, AdminTestClusterInfo("mycluster"), "myfs-a")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(900), used)
+	assert.Equal(t, uint64(1000), limit)
+
+	_, _, err = GetMDSCacheUsage(context, AdminTestClusterInfo("mycluster"), "myfs-b")
+	assert.Error(t, err)
+}
@@ -0,0 +1,171 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	exportPinXattr      = "ceph.dir.pin"
+	distributedPinXattr = "ceph.dir.pin.distributed"
+	randomPinXattr      = "ceph.dir.pin.random"
+)
+
+// setDirectoryXattrsScript sets extended attributes of a directory with libcephfs, so the filesystem does
+// not need to be mounted. Its arguments are the filesystem, the directory and the "<xattr>=<value>" to
+// set, followed by the standard flags for config and keyring. In the proxy container, the flags are
+// set in the CEPH_ARGS environment variable instead.
+const setDirectoryXattrsScript = `
+import os
+import shlex
+import sys
+
+import cephfs
+
+fs_name, path = sys.argv[1:3]
+xattrs = [arg.split("=", 1) for arg in sys.argv[3:] if not arg.startswith("--")]
+flags = dict(arg[2:].split("=", 1) for arg in sys.argv[3:] if arg.startswith("--") and "=" in arg)
+
+fs = cephfs.LibCephFS(conffile=flags.get("conf"), auth_id=flags.get("name", "client.admin").split(".", 1)[-1])
+if "keyring" in flags:
+    fs.conf_set("keyring", flags["keyring"])
+fs.conf_parse_argv([sys.argv[0]] + shlex.split(os.environ.get("CEPH_ARGS", "")))
+fs.mount(filesystem_name=fs_name)
+try:
+    for xattr, value in xattrs:
+        fs.setxattr(path, xattr, value.encode(), 0)
+finally:
+    fs.shutdown()
+`
+
+// MDSSubtree is a representation of a subtree returned by 'ceph tell mds.<id> get subtrees'
+type MDSSubtree struct {
+	IsAuth    bool `json:"is_auth"`
+	AuthFirst int  `json:"auth_first"`
+	ExportPin int  `json:"export_pin"`
+	Dir       struct {
+		Path string `json:"path"`
+	} `json:"dir"`
+}
+
+// PinFilesystemDirectory pins a directory of the filesystem to the mds ranks. The pins of the other
+// types are removed from the directory.
+func PinFilesystemDirectory(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string, pin cephv1.FilesystemDirectoryPinningSpec) error {
+	err := validatePinningValues(cephv1.CephFilesystemSubVolumeGroupSpecPinning{Export: pin.Export, Distributed: pin.Distributed, Random: pin.Random})
+	if err != nil {
+		return errors.Wrapf(err, "failed to pin directory %q of filesystem %q", pin.Path, fsName)
+	}
+
+	export, distributed, random := "-1", "0", "0"
+	if pin.Export != nil {
+		export = strconv.Itoa(*pin.Export)
+	} else if pin.Distributed != nil {
+		distributed = strconv.Itoa(*pin.Distributed)
+	} else if pin.Random != nil {
+		random = strconv.FormatFloat(*pin.Random, 'f', -1, 64)
+	}
+
+	xattrs := []string{exportPinXattr + "=" + export, distributedPinXattr + "=" + distributed, randomPinXattr + "=" + random}
+	if err := setFilesystemDirectoryXattrs(context, clusterInfo, fsName, pin.Path, xattrs); err != nil {
+		return errors.Wrapf(err, "failed to pin directory %q of filesystem %q", pin.Path, fsName)
+	}
+	logger.Infof("pinned directory %q of filesystem %q with %s", pin.Path, fsName, strings.Join(xattrs, " "))
+	return nil
+}
+
+// UnpinFilesystemDirectory removes all the pins of a directory of the filesystem
+func UnpinFilesystemDirectory(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path string) error {
+	xattrs := []string{exportPinXattr + "=-1", distributedPinXattr + "=0", randomPinXattr + "=0"}
+	if err := setFilesystemDirectoryXattrs(context, clusterInfo, fsName, path, xattrs); err != nil {
+		return errors.Wrapf(err, "failed to unpin directory %q of filesystem %q", path, fsName)
+	}
+	logger.Infof("unpinned directory %q of filesystem %q", path, fsName)
+	return nil
+}
+
+func setFilesystemDirectoryXattrs(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path string, xattrs []string) error {
+	args := append([]string{fsName, path}, xattrs...)
+	output, err := NewPythonCommand(context, clusterInfo, setDirectoryXattrsScript, args).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the extended attributes %v. %s", xattrs, string(output))
+	}
+	return nil
+}
+
+// GetMDSSubtrees returns the subtrees known by an mds daemon
+func GetMDSSubtrees(context *clusterd.Context, clusterInfo *ClusterInfo, mdsName string) ([]MDSSubtree, error) {
+	daemon := fmt.Sprintf("mds.%s", mdsName)
+	buf, err := NewCephCommand(context, clusterInfo, []string{"tell", daemon, "get", "subtrees"}).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get subtrees of %q", daemon)
+	}
+	var subtrees []MDSSubtree
+	if err := json.Unmarshal(buf, &subtrees); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal subtrees of %q. %s", daemon, string(buf))
+	}
+	return subtrees, nil
+}
+
+// GetFilesystemSubtreeDistribution returns the subtrees each active mds rank of the filesystem is
+// authoritative for. At most maxPaths subtree paths are returned per rank.
+func GetFilesystemSubtreeDistribution(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string, maxPaths int) ([]cephv1.FilesystemRankSubtreesStatus, error) {
+	status, err := GetFilesystemStatus(context, clusterInfo, fsName)
+	if err != nil {
+		return nil, err
+	}
+
+	ranks := []cephv1.FilesystemRankSubtreesStatus{}
+	for _, mds := range status.MDSMap {
+		if mds.State != "active" {
+			continue
+		}
+		subtrees, err := GetMDSSubtrees(context, clusterInfo, mds.Name)
+		if err != nil {
+			return nil, err
+		}
+
+		paths := []string{}
+		for _, subtree := range subtrees {
+			// the stray directories of the mds are internal
+			if !subtree.IsAuth || subtree.AuthFirst != mds.Rank || strings.HasPrefix(subtree.Dir.Path, "~mds") {
+				continue
+			}
+			paths = append(paths, "/"+strings.TrimPrefix(subtree.Dir.Path, "/"))
+		}
+		sort.Strings(paths)
+
+		rank := cephv1.FilesystemRankSubtreesStatus{Rank: mds.Rank, MDS: mds.Name, SubtreeCount: len(paths)}
+		if len(paths) > maxPaths {
+			paths = paths[:maxPaths]
+		}
+		rank.Subtrees = paths
+		ranks = append(ranks, rank)
+	}
+	sort.Slice(ranks, func(i, j int) bool { return ranks[i].Rank < ranks[j].Rank })
+
+	return ranks, nil
+}
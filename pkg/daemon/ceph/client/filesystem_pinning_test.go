@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPinFilesystemDirectory(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			assert.Equal(t, "python3", command)
+			assert.Equal(t, "-c", args[0])
+			assert.Equal(t, setDirectoryXattrsScript, args[1])
+			// skip the script and the standard flags
+			xattrs := []string{}
+			for _, arg := range args[2:] {
+				if !strings.HasPrefix(arg, "--") {
+					xattrs = append(xattrs, arg)
+				}
+			}
+			assert.Contains(t, args, "--name=client.admin")
+			commands = append(commands, strings.Join(xattrs, " "))
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	export, distributed, random := 1, 1, 0.5
+
+	commands = nil
+	err := PinFilesystemDirectory(context, AdminTestClusterInfo("mycluster"), "myfs", cephv1.FilesystemDirectoryPinningSpec{Path: "/volumes", Export: &export})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myfs /volumes ceph.dir.pin=1 ceph.dir.pin.distributed=0 ceph.dir.pin.random=0"}, commands)
+
+	commands = nil
+	err = PinFilesystemDirectory(context, AdminTestClusterInfo("mycluster"), "myfs", cephv1.FilesystemDirectoryPinningSpec{Path: "/shared", Distributed: &distributed})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myfs /shared ceph.dir.pin=-1 ceph.dir.pin.distributed=1 ceph.dir.pin.random=0"}, commands)
+
+	commands = nil
+	err = PinFilesystemDirectory(context, AdminTestClusterInfo("mycluster"), "myfs", cephv1.FilesystemDirectoryPinningSpec{Path: "/home", Random: &random})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myfs /home ceph.dir.pin=-1 ceph.dir.pin.distributed=0 ceph.dir.pin.random=0.5"}, commands)
+
+	// only one pinning can be set
+	commands = nil
+	err = PinFilesystemDirectory(context, AdminTestClusterInfo("mycluster"), "myfs", cephv1.FilesystemDirectoryPinningSpec{Path: "/home", Random: &random, Export: &export})
+	assert.Error(t, err)
+	assert.Empty(t, commands)
+
+	commands = nil
+	err = UnpinFilesystemDirectory(context, AdminTestClusterInfo("mycluster"), "myfs", "/shared")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"myfs /shared ceph.dir.pin=-1 ceph.dir.pin.distributed=0 ceph.dir.pin.random=0"}, commands)
+
+	// the directory does not exist
+	executor.MockExecuteCommandWithTimeout = func(timeout time.Duration, command string, args ...string) (string, error) {
+		return "cephfs.ObjectNotFound: error in setxattr: No such file or directory [Errno 2]", errors.New("exit status 1")
+	}
+	err = PinFilesystemDirectory(context, AdminTestClusterInfo("mycluster"), "myfs", cephv1.FilesystemDirectoryPinningSpec{Path: "/missing", Export: &export})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "No such file or directory")
+}
+
+func TestGetFilesystemSubtreeDistribution(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "status" {
+				return fsStatus, nil
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "tell" && args[2] == "get" && args[3] == "subtrees" {
+				if args[1] == "mds.myfs-a" {
+					return `[{"is_auth": true, "auth_first": 0, "export_pin": -1, "dir": {"path": ""}},
+{"is_auth": true, "auth_first": 0, "export_pin": -1, "dir": {"path": "~mds0"}},
+{"is_auth": false, "auth_first": 1, "export_pin": 1, "dir": {"path": "/volumes"}}]`, nil
+				}
+				return `[{"is_auth": true, "auth_first": 1, "export_pin": 1, "dir": {"path": "/volumes"}},
+{"is_auth": true, "auth_first": 1, "export_pin": -1, "dir": {"path": "/home/b"}},
+{"is_auth": true, "auth_first": 1, "export_pin": -1, "dir": {"path": "/home/a"}}]`, nil
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	ranks, err := GetFilesystemSubtreeDistribution(context, AdminTestClusterInfo("mycluster"), "myfs", 2)
+	assert.NoError(t, err)
+	assert.Equal(t, []cephv1.FilesystemRankSubtreesStatus{
+		{Rank: 0, MDS: "myfs-a", SubtreeCount: 1, Subtrees: []string{"/"}},
+		{Rank: 1, MDS: "myfs-b", SubtreeCount: 3, Subtrees: []string{"/home/a", "/home/b"}},
+	}, ranks)
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+// ScrubStartResult is a representation of the json structure returned by 'ceph tell mds.<fs>:0 scrub start'
+type ScrubStartResult struct {
+	ReturnCode int    `json:"return_code"`
+	ScrubTag   string `json:"scrub_tag"`
+	Mode       string `json:"mode"`
+}
+
+// ScrubStatus is a representation of the json structure returned by 'ceph tell mds.<fs>:0 scrub status'
+type ScrubStatus struct {
+	Status string               `json:"status"`
+	Scrubs map[string]ScrubInfo `json:"scrubs"`
+}
+
+// ScrubInfo is a representation of a running scrub returned by 'ceph tell mds.<fs>:0 scrub status'
+type ScrubInfo struct {
+	Path    string `json:"path"`
+	Tag     string `json:"tag"`
+	Options string `json:"options"`
+}
+
+// MDSDamage is a representation of an entry returned by 'ceph tell mds.<fs>:<rank> damage ls'
+type MDSDamage struct {
+	ID         json.Number `json:"id"`
+	DamageType string      `json:"damage_type"`
+	Ino        uint64      `json:"ino"`
+	Path       string      `json:"path"`
+}
+
+// mdsRankDaemon returns the name of the daemon holding the rank of the filesystem
+func mdsRankDaemon(fsName string, rank int) string {
+	return fmt.Sprintf("mds.%s:%d", fsName, rank)
+}
+
+// StartFilesystemScrub starts a forward scrub of a directory of the filesystem and returns the scrub tag
+func StartFilesystemScrub(context *clusterd.Context, clusterInfo *ClusterInfo, fsName, path string, recursive, repair bool) (string, error) {
+	args := []string{"tell", mdsRankDaemon(fsName, 0), "scrub", "start", path}
+	options := []string{}
+	if recursive {
+		options = append(options, "recursive")
+	}
+	if repair {
+		options = append(options, "repair")
+	}
+	if len(options) > 0 {
+		args = append(args, strings.Join(options, ","))
+	}
+
+	buf, err := NewCephCommand(context, clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to start scrub of %q on filesystem %q. %s", path, fsName, string(buf))
+	}
+	var result ScrubStartResult
+	if err := json.Unmarshal(buf, &result); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal scrub start result. %s", string(buf))
+	}
+	if result.ReturnCode != 0 {
+		return "", errors.Errorf("failed to start scrub of %q on filesystem %q. return code %d", path, fsName, result.ReturnCode)
+	}
+
+	logger.Infof("started scrub %q of %q on filesystem %q", result.ScrubTag, path, fsName)
+	return result.ScrubTag, nil
+}
+
+// GetFilesystemScrubStatus returns the status of the scrubs of the filesystem
+func GetFilesystemScrubStatus(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string) (*ScrubStatus, error) {
+	args := []string{"tell", mdsRankDaemon(fsName, 0), "scrub", "status"}
+	buf, err := NewCephCommand(context, clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get scrub status of filesystem %q", fsName)
+	}
+	var status ScrubStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal scrub status. %s", string(buf))
+	}
+	return &status, nil
+}
+
+// ListFilesystemDamage returns the damage table of an mds rank of the filesystem
+func ListFilesystemDamage(context *clusterd.Context, clusterInfo *ClusterInfo, fsName string, rank int) ([]MDSDamage, error) {
+	args := []string{"tell", mdsRankDaemon(fsName, rank), "damage", "ls"}
+	buf, err := NewCephCommand(context, clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list damage of rank %d of filesystem %q", rank, fsName)
+	}
+	var damages []MDSDamage
+	if err := json.Unmarshal(buf, &damages); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal damage list. %s", string(buf))
+	}
+	return damages, nil
+}
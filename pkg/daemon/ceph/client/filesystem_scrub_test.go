@@ -0,0 +1,68 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFilesystemScrub(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] != "tell" {
+				return "", errors.Errorf("unknown command %v", args)
+			}
+			switch args[2] {
+			case "scrub":
+				if args[3] == "start" {
+					assert.Equal(t, "mds.myfs:0", args[1])
+					assert.Equal(t, "/volumes", args[4])
+					assert.Equal(t, "recursive,repair", args[5])
+					return `{"return_code": 0, "scrub_tag": "9e2cb5a1", "mode": "asynchronous"}`, nil
+				}
+				return `{"status": "scrub active (42 inodes in the stack)", "scrubs": {"9e2cb5a1": {"path": "/volumes", "tag": "9e2cb5a1", "options": "recursive,repair"}}}`, nil
+			case "damage":
+				assert.Equal(t, "mds.myfs:1", args[1])
+				return `[{"damage_type": "backtrace", "id": 3760765989, "ino": 1099511627776, "path": "/volumes/a"}]`, nil
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	tag, err := StartFilesystemScrub(context, AdminTestClusterInfo("mycluster"), "myfs", "/volumes", true, true)
+	assert.NoError(t, err)
+	assert.Equal(t, "9e2cb5a1", tag)
+
+	status, err := GetFilesystemScrubStatus(context, AdminTestClusterInfo("mycluster"), "myfs")
+	assert.NoError(t, err)
+	assert.Equal(t, "scrub active (42 inodes in the stack)", status.Status)
+	assert.Equal(t, "/volumes", status.Scrubs["9e2cb5a1"].Path)
+
+	damages, err := ListFilesystemDamage(context, AdminTestClusterInfo("mycluster"), "myfs", 1)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(damages))
+	assert.Equal(t, "3760765989", damages[0].ID.String())
+	assert.Equal(t, "backtrace", damages[0].DamageType)
+	assert.Equal(t, uint64(1099511627776), damages[0].Ino)
+}
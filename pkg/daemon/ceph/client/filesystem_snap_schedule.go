@@ -0,0 +1,377 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"path"
+	"reflect"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+	"unicode"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+// snapshotScheduleTimeLayout is the layout of the times reported by the snap_schedule mgr module
+const snapshotScheduleTimeLayout = "2006-01-02T15:04:05"
+
+// SnapshotScheduleInfo is the representation Ceph returns for a snapshot schedule of a filesystem path
+type SnapshotScheduleInfo struct {
+	Path         string         `json:"path"`
+	Schedule     string         `json:"schedule"`
+	Retention    map[string]int `json:"retention"`
+	Start        string         `json:"start"`
+	Created      string         `json:"created"`
+	First        string         `json:"first"`
+	Last         string         `json:"last"`
+	LastPruned   string         `json:"last_pruned"`
+	CreatedCount int            `json:"created_count"`
+	PrunedCount  int            `json:"pruned_count"`
+	Active       bool           `json:"active"`
+}
+
+// GetSnapshotSchedules returns the snapshot schedules of the given path of a ceph filesystem
+func GetSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, path, filesystem string) ([]SnapshotScheduleInfo, error) {
+	// Using Debug level since this is called in a recurrent go routine
+	logger.Debugf("retrieving snapshot schedules of ceph filesystem %q on path %q", filesystem, path)
+
+	args := []string{"fs", "snap-schedule", "status", path, fmt.Sprintf("fs=%s", filesystem)}
+	cmd := NewCephCommand(context, clusterInfo, args)
+
+	output, err := cmd.Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			// no schedule on that path
+			return []SnapshotScheduleInfo{}, nil
+		}
+		return nil, errors.Wrapf(err, "failed to retrieve snapshot schedules of ceph filesystem %q on path %q. %s", filesystem, path, output)
+	}
+
+	// the command outputs a new line first which breaks the json parsing, see GetSnapshotScheduleStatus
+	var schedules []SnapshotScheduleInfo
+	if err := json.Unmarshal([]byte(strings.ReplaceAll(string(output), "\n", "")), &schedules); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal snapshot schedules of ceph filesystem %q on path %q", filesystem, path)
+	}
+
+	return schedules, nil
+}
+
+// PruneSnapshotSchedules removes the applied snapshot schedules that are not in the desired schedules, as
+// well as the applied retention policies of the paths whose desired schedules have no retention. The
+// schedules that were not applied are never removed, such as the schedules added manually. The schedule
+// paths are relative to basePath.
+func PruneSnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, filesystem, basePath string, applied, desired []cephv1.FilesystemSnapshotScheduleSpec) error {
+	desiredIntervals := map[string]bool{}
+	desiredRetentions := map[string]bool{}
+	for _, schedule := range desired {
+		schedulePath := SnapshotSchedulePath(basePath, schedule.Path)
+		desiredIntervals[schedulePath+" "+schedule.Interval] = true
+		if schedule.Retention != "" {
+			desiredRetentions[schedulePath] = true
+		}
+	}
+
+	removedIntervals := map[string]bool{}
+	removedRetentions := map[string]bool{}
+	for _, schedule := range applied {
+		schedulePath := SnapshotSchedulePath(basePath, schedule.Path)
+		key := schedulePath + " " + schedule.Interval
+		if !desiredIntervals[key] && !removedIntervals[key] {
+			err := RemoveSnapshotSchedule(context, clusterInfo, schedulePath, schedule.Interval, filesystem)
+			if err != nil {
+				return err
+			}
+			removedIntervals[key] = true
+		}
+		if schedule.Retention == "" || desiredRetentions[schedulePath] || removedRetentions[schedulePath] {
+			continue
+		}
+		// the retention policy of the path is removed as a whole since it is shared by all its schedules
+		current, err := GetSnapshotSchedules(context, clusterInfo, schedulePath, filesystem)
+		if err != nil {
+			return err
+		}
+		if len(current) > 0 && len(current[0].Retention) > 0 {
+			err = RemoveSnapshotScheduleRetention(context, clusterInfo, schedulePath, current[0].RetentionSpec(), filesystem)
+			if err != nil {
+				return err
+			}
+		}
+		removedRetentions[schedulePath] = true
+	}
+
+	return nil
+}
+
+// MergeSnapshotSchedules returns the schedules of both lists, without duplicates
+func MergeSnapshotSchedules(a, b []cephv1.FilesystemSnapshotScheduleSpec) []cephv1.FilesystemSnapshotScheduleSpec {
+	merged := []cephv1.FilesystemSnapshotScheduleSpec{}
+	for _, schedules := range [][]cephv1.FilesystemSnapshotScheduleSpec{a, b} {
+		for _, schedule := range schedules {
+			if !slices.Contains(merged, schedule) {
+				merged = append(merged, schedule)
+			}
+		}
+	}
+	return merged
+}
+
+// RemoveSnapshotSchedule removes the snapshot schedule with the given interval on a path of a ceph filesystem
+func RemoveSnapshotSchedule(context *clusterd.Context, clusterInfo *ClusterInfo, path, interval, filesystem string) error {
+	logger.Infof("removing snapshot schedule every %q from ceph filesystem %q on path %q", interval, filesystem, path)
+
+	// Example command: "ceph fs snap-schedule remove / 4d fs=myfs2"
+	args := []string{"fs", "snap-schedule", "remove", path, interval, fmt.Sprintf("fs=%s", filesystem)}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+
+	output, err := cmd.Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("snapshot schedule every %q does not exist on ceph filesystem %q path %q", interval, filesystem, path)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove snapshot schedule every %q from ceph filesystem %q on path %q. %s", interval, filesystem, path, output)
+	}
+
+	logger.Infof("successfully removed snapshot schedule every %q from ceph filesystem %q on path %q", interval, filesystem, path)
+	return nil
+}
+
+// RemoveSnapshotScheduleRetention removes a retention policy from a path of a ceph filesystem
+func RemoveSnapshotScheduleRetention(context *clusterd.Context, clusterInfo *ClusterInfo, path, retention, filesystem string) error {
+	logger.Infof("removing snapshot schedule retention %s from ceph filesystem %q on path %q", retention, filesystem, path)
+
+	// Example command: "ceph fs snap-schedule retention remove / 24h fs=myfs2"
+	args := []string{"fs", "snap-schedule", "retention", "remove", path, retention, fmt.Sprintf("fs=%s", filesystem)}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+
+	output, err := cmd.Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			logger.Debugf("snapshot schedule retention %s does not exist on ceph filesystem %q path %q", retention, filesystem, path)
+			return nil
+		}
+		return errors.Wrapf(err, "failed to remove snapshot schedule retention %s from ceph filesystem %q on path %q. %s", retention, filesystem, path, output)
+	}
+
+	logger.Infof("successfully removed snapshot schedule retention %s from ceph filesystem %q on path %q", retention, filesystem, path)
+	return nil
+}
+
+// RetentionSpec returns the retention policy of the schedule in its compressed form, e.g. "24h7d"
+func (s *SnapshotScheduleInfo) RetentionSpec() string {
+	periods := make([]string, 0, len(s.Retention))
+	for period := range s.Retention {
+		periods = append(periods, period)
+	}
+	sort.Strings(periods)
+
+	var spec strings.Builder
+	for _, period := range periods {
+		spec.WriteString(fmt.Sprintf("%d%s", s.Retention[period], period))
+	}
+	return spec.String()
+}
+
+// ApplySnapshotSchedules adds the snapshot schedules and their retention policies to a ceph filesystem.
+// The schedule paths are relative to basePath, which is "/" for the filesystem root.
+func ApplySnapshotSchedules(context *clusterd.Context, clusterInfo *ClusterInfo, filesystem, basePath string, schedules []cephv1.FilesystemSnapshotScheduleSpec) error {
+	if len(schedules) == 0 {
+		return nil
+	}
+
+	// Enable the snap_schedule module
+	err := MgrEnableModule(context, clusterInfo, "snap_schedule", false)
+	if err != nil {
+		return errors.Wrap(err, "failed to enable snap_schedule mgr module")
+	}
+
+	retentions := map[string]string{}
+	for _, schedule := range schedules {
+		schedulePath := SnapshotSchedulePath(basePath, schedule.Path)
+		err = AddSnapshotSchedule(context, clusterInfo, schedulePath, schedule.Interval, schedule.StartTime, filesystem)
+		if err != nil {
+			return errors.Wrapf(err, "failed to add snapshot schedule on path %q", schedulePath)
+		}
+		if schedule.Retention != "" {
+			retentions[schedulePath] = schedule.Retention
+		}
+	}
+
+	for schedulePath, retention := range retentions {
+		err = applySnapshotScheduleRetention(context, clusterInfo, filesystem, schedulePath, retention)
+		if err != nil {
+			return errors.Wrapf(err, "failed to set snapshot retention on path %q", schedulePath)
+		}
+	}
+
+	return nil
+}
+
+// applySnapshotScheduleRetention replaces the retention policy of a path if it differs from the desired one
+func applySnapshotScheduleRetention(context *clusterd.Context, clusterInfo *ClusterInfo, filesystem, schedulePath, retention string) error {
+	current, err := GetSnapshotSchedules(context, clusterInfo, schedulePath, filesystem)
+	if err != nil {
+		return err
+	}
+	// the retention policy is shared by all the schedules of a path
+	if len(current) > 0 && len(current[0].Retention) > 0 {
+		if reflect.DeepEqual(current[0].Retention, parseRetentionSpec(retention)) {
+			return nil
+		}
+		err = RemoveSnapshotScheduleRetention(context, clusterInfo, schedulePath, current[0].RetentionSpec(), filesystem)
+		if err != nil {
+			return err
+		}
+	}
+
+	return AddSnapshotScheduleRetention(context, clusterInfo, schedulePath, retention, filesystem)
+}
+
+// GetSnapshotSchedulesStatus returns the status of the snapshot schedules of a ceph filesystem, with
+// their last and next snapshot times. The schedule paths are relative to basePath.
+func GetSnapshotSchedulesStatus(context *clusterd.Context, clusterInfo *ClusterInfo, filesystem, basePath string, schedules []cephv1.FilesystemSnapshotScheduleSpec, now time.Time) []cephv1.FilesystemSnapshotScheduleStatus {
+	statuses := []cephv1.FilesystemSnapshotScheduleStatus{}
+	schedulesByPath := map[string][]SnapshotScheduleInfo{}
+	errorsByPath := map[string]error{}
+	for _, schedule := range schedules {
+		schedulePath := SnapshotSchedulePath(basePath, schedule.Path)
+		status := cephv1.FilesystemSnapshotScheduleStatus{Path: schedulePath, Interval: schedule.Interval}
+
+		if _, ok := schedulesByPath[schedulePath]; !ok && errorsByPath[schedulePath] == nil {
+			infos, err := GetSnapshotSchedules(context, clusterInfo, schedulePath, filesystem)
+			if err != nil {
+				errorsByPath[schedulePath] = err
+			} else {
+				schedulesByPath[schedulePath] = infos
+			}
+		}
+		if err := errorsByPath[schedulePath]; err != nil {
+			status.Details = err.Error()
+			statuses = append(statuses, status)
+			continue
+		}
+
+		found := false
+		for _, info := range schedulesByPath[schedulePath] {
+			if info.Schedule != schedule.Interval {
+				continue
+			}
+			found = true
+			status.Active = info.Active
+			status.LastSnapshot = formatSnapshotScheduleTime(info.Last)
+			status.CreatedCount = info.CreatedCount
+			status.PrunedCount = info.PrunedCount
+			if info.Active {
+				status.NextSnapshot = nextSnapshotTime(info.Start, info.Schedule, now)
+			}
+			break
+		}
+		if !found {
+			status.Details = "snapshot schedule not found"
+		}
+		statuses = append(statuses, status)
+	}
+
+	return statuses
+}
+
+// SnapshotSchedulePath returns the absolute path of a snapshot schedule path relative to basePath
+func SnapshotSchedulePath(basePath, schedulePath string) string {
+	return path.Join("/", basePath, schedulePath)
+}
+
+// parseRetentionSpec converts a compressed retention spec such as "24h7d" into a map of periods and counts
+func parseRetentionSpec(spec string) map[string]int {
+	retention := map[string]int{}
+	count := 0
+	for _, c := range spec {
+		if unicode.IsDigit(c) {
+			count = count*10 + int(c-'0')
+			continue
+		}
+		retention[string(c)] = count
+		count = 0
+	}
+	return retention
+}
+
+// formatSnapshotScheduleTime converts a snapshot schedule time reported by ceph to RFC3339
+func formatSnapshotScheduleTime(scheduleTime string) string {
+	if scheduleTime == "" {
+		return ""
+	}
+	t, err := time.Parse(snapshotScheduleTimeLayout, scheduleTime)
+	if err != nil {
+		return scheduleTime
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+// nextSnapshotTime computes when the next snapshot of a schedule is due given its start time and interval
+func nextSnapshotTime(start, interval string, now time.Time) string {
+	next, err := time.Parse(snapshotScheduleTimeLayout, start)
+	if err != nil || len(interval) < 2 {
+		return ""
+	}
+	count, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || count <= 0 {
+		return ""
+	}
+
+	var period time.Duration
+	switch interval[len(interval)-1] {
+	case 'm':
+		period = time.Duration(count) * time.Minute
+	case 'h':
+		period = time.Duration(count) * time.Hour
+	case 'd':
+		period = time.Duration(count) * 24 * time.Hour
+	case 'w':
+		period = time.Duration(count) * 7 * 24 * time.Hour
+	case 'M':
+		for !next.After(now) {
+			next = next.AddDate(0, count, 0)
+		}
+		return next.UTC().Format(time.RFC3339)
+	case 'y':
+		for !next.After(now) {
+			next = next.AddDate(count, 0, 0)
+		}
+		return next.UTC().Format(time.RFC3339)
+	default:
+		return ""
+	}
+
+	if next.After(now) {
+		return next.UTC().Format(time.RFC3339)
+	}
+	elapsedPeriods := now.Sub(next) / period
+	next = next.Add((elapsedPeriods + 1) * period)
+	return next.UTC().Format(time.RFC3339)
+}
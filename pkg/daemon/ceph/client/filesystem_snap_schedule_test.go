@@ -0,0 +1,151 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+// response of "ceph fs snap-schedule status /volumes fs=myfs"
+var snapScheduleStatus = `
+[{"fs": "myfs", "subvol": null, "path": "/volumes", "rel_path": "/volumes", "schedule": "1h", "retention": {"h": 24}, "start": "2024-01-01T00:00:00", "created": "2024-01-01T00:00:00", "first": "2024-01-01T01:00:00", "last": "2024-01-02T10:00:00", "last_pruned": null, "created_count": 34, "pruned_count": 10, "active": true}]`
+
+func TestNextSnapshotTime(t *testing.T) {
+	now := time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)
+
+	assert.Equal(t, "2024-01-02T11:00:00Z", nextSnapshotTime("2024-01-01T00:00:00", "1h", now))
+	assert.Equal(t, "2024-01-02T12:00:00Z", nextSnapshotTime("2024-01-01T00:00:00", "6h", now))
+	assert.Equal(t, "2024-01-03T00:00:00Z", nextSnapshotTime("2024-01-01T00:00:00", "1d", now))
+	assert.Equal(t, "2024-01-08T00:00:00Z", nextSnapshotTime("2024-01-01T00:00:00", "1w", now))
+	assert.Equal(t, "2024-02-01T00:00:00Z", nextSnapshotTime("2024-01-01T00:00:00", "1M", now))
+	assert.Equal(t, "2025-01-01T00:00:00Z", nextSnapshotTime("2024-01-01T00:00:00", "1y", now))
+	assert.Equal(t, "2024-01-02T10:45:00Z", nextSnapshotTime("2024-01-01T00:00:00", "15m", now))
+	// start time in the future
+	assert.Equal(t, "2024-02-01T00:00:00Z", nextSnapshotTime("2024-02-01T00:00:00", "1h", now))
+	// invalid input
+	assert.Equal(t, "", nextSnapshotTime("", "1h", now))
+	assert.Equal(t, "", nextSnapshotTime("2024-01-01T00:00:00", "1x", now))
+}
+
+func TestParseRetentionSpec(t *testing.T) {
+	assert.Equal(t, map[string]int{}, parseRetentionSpec(""))
+	assert.Equal(t, map[string]int{"h": 24}, parseRetentionSpec("24h"))
+	assert.Equal(t, map[string]int{"h": 24, "d": 7, "n": 10}, parseRetentionSpec("24h7d10n"))
+
+	info := SnapshotScheduleInfo{Retention: map[string]int{"h": 24, "d": 7}}
+	assert.Equal(t, "7d24h", info.RetentionSpec())
+}
+
+func TestApplySnapshotSchedules(t *testing.T) {
+	var commands []string
+	mockCommand := func(args ...string) (string, error) {
+		if args[0] == "fs" && args[1] == "snap-schedule" && args[2] == "status" {
+			return snapScheduleStatus, nil
+		}
+		// only record the command without the connection flags
+		for i, arg := range args {
+			if strings.HasPrefix(arg, "--") {
+				args = args[:i]
+				break
+			}
+		}
+		commands = append(commands, strings.Join(args, " "))
+		return "", nil
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			return mockCommand(args...)
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return mockCommand(args...)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	t.Run("no schedules", func(t *testing.T) {
+		commands = nil
+		err := ApplySnapshotSchedules(context, AdminTestClusterInfo("mycluster"), "myfs", "/", nil)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("same retention", func(t *testing.T) {
+		commands = nil
+		schedules := []cephv1.FilesystemSnapshotScheduleSpec{{Path: "/volumes", Interval: "1h", Retention: "24h"}}
+		err := ApplySnapshotSchedules(context, AdminTestClusterInfo("mycluster"), "myfs", "/", schedules)
+		assert.NoError(t, err)
+		assert.Contains(t, commands, "fs snap-schedule add /volumes 1h fs=myfs")
+		for _, command := range commands {
+			assert.NotContains(t, command, "retention")
+		}
+	})
+
+	t.Run("retention changed", func(t *testing.T) {
+		commands = nil
+		schedules := []cephv1.FilesystemSnapshotScheduleSpec{{Path: "/", Interval: "1h", Retention: "12h7d"}}
+		err := ApplySnapshotSchedules(context, AdminTestClusterInfo("mycluster"), "myfs", "/volumes", schedules)
+		assert.NoError(t, err)
+		assert.Contains(t, commands, "fs snap-schedule add /volumes 1h fs=myfs")
+		assert.Contains(t, commands, "fs snap-schedule retention remove /volumes 24h fs=myfs")
+		assert.Contains(t, commands, "fs snap-schedule retention add /volumes 12h7d fs=myfs")
+	})
+}
+
+func TestGetSnapshotSchedulesStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "snap-schedule" && args[2] == "status" {
+				if args[3] == "/volumes" {
+					return snapScheduleStatus, nil
+				}
+				return "", errors.New("failed to get status")
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	now := time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)
+
+	schedules := []cephv1.FilesystemSnapshotScheduleSpec{
+		{Path: "/volumes", Interval: "1h"},
+		{Path: "/volumes", Interval: "1d"},
+		{Path: "/other", Interval: "1h"},
+	}
+	statuses := GetSnapshotSchedulesStatus(context, AdminTestClusterInfo("mycluster"), "myfs", "/", schedules, now)
+	assert.Equal(t, 3, len(statuses))
+
+	assert.Equal(t, cephv1.FilesystemSnapshotScheduleStatus{
+		Path:         "/volumes",
+		Interval:     "1h",
+		Active:       true,
+		LastSnapshot: "2024-01-02T10:00:00Z",
+		NextSnapshot: "2024-01-02T11:00:00Z",
+		CreatedCount: 34,
+		PrunedCount:  10,
+	}, statuses[0])
+	assert.Equal(t, "snapshot schedule not found", statuses[1].Details)
+	assert.Equal(t, "/other", statuses[2].Path)
+	assert.Contains(t, statuses[2].Details, "failed to get status")
+}
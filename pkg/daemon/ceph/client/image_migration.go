@@ -0,0 +1,266 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/util/exec"
+)
+
+const (
+	// ImageMigrationPrepared is the state of an image migration once prepared
+	ImageMigrationPrepared = "prepared"
+	// ImageMigrationExecuted is the state of an image migration once the data is copied
+	ImageMigrationExecuted = "executed"
+)
+
+// ImageStatus is the status of an image returned by 'rbd status'
+type ImageStatus struct {
+	Watchers  []ImageWatcher        `json:"watchers"`
+	Migration *ImageMigrationStatus `json:"migration,omitempty"`
+}
+
+// ImageWatcher is a client watching an image, i.e. a client that has the image open
+type ImageWatcher struct {
+	Address string `json:"address"`
+}
+
+// ImageMigrationStatus is the status of the live migration of an image
+type ImageMigrationStatus struct {
+	SourcePoolName  string `json:"source_pool_name"`
+	SourceImageName string `json:"source_image_name"`
+	DestPoolName    string `json:"dest_pool_name"`
+	DestImageName   string `json:"dest_image_name"`
+	State           string `json:"state"`
+	StateDesc       string `json:"state_description"`
+}
+
+// maxListedPoolObjects is the maximum number of objects of a pool listed to check whether it
+// stores RBD images
+const maxListedPoolObjects = 1000
+
+// rbdImageObjectPrefixes are the prefixes of the objects of the metadata and the data of RBD images
+var rbdImageObjectPrefixes = []string{"rbd_data.", "rbd_header.", "rbd_id.", "rbd_object_map."}
+
+type rbdNamespace struct {
+	Name string `json:"name"`
+}
+
+// GetImageStatus returns the status of an image. The image spec is in the form
+// <pool>[/<namespace>]/<image>.
+func GetImageStatus(context *clusterd.Context, clusterInfo *ClusterInfo, imageSpec string) (*ImageStatus, error) {
+	args := []string{"status", imageSpec}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve status of image %q. %s", imageSpec, string(buf))
+	}
+
+	var status ImageStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal status of image %q", imageSpec)
+	}
+
+	return &status, nil
+}
+
+// ListImagesByDataPool returns the specs of the RBD images of the given pools and of their rados
+// namespaces, keyed by the name of the pool where their data is stored. The data of an image
+// created without a data pool is stored in the pool of the image.
+func ListImagesByDataPool(context *clusterd.Context, clusterInfo *ClusterInfo, poolNames []string) (map[string][]string, error) {
+	images := map[string][]string{}
+	for _, pool := range poolNames {
+		namespaces, err := listRBDNamespaces(context, clusterInfo, pool)
+		if err != nil {
+			return nil, err
+		}
+		for _, namespace := range append([]string{""}, namespaces...) {
+			poolName := pool
+			if namespace != "" {
+				poolName = RadosNamespacePoolName(pool, namespace)
+			}
+			poolImages, err := ListImages(context, clusterInfo, poolName)
+			if err != nil {
+				return nil, err
+			}
+			for _, image := range poolImages {
+				imageSpec := getImageSpec(image.Name, poolName)
+				dataPool, err := getImageDataPool(context, clusterInfo, imageSpec)
+				if err != nil {
+					return nil, err
+				}
+				if dataPool == "" {
+					dataPool = pool
+				}
+				images[dataPool] = append(images[dataPool], imageSpec)
+			}
+		}
+	}
+
+	return images, nil
+}
+
+// PoolHasRBDData returns whether a pool stores the metadata or the data of RBD images, including
+// the images of other pools that use it as their data pool
+func PoolHasRBDData(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (bool, error) {
+	stats, err := GetPoolStats(context, clusterInfo)
+	if err != nil {
+		return false, err
+	}
+	objects := -1.0
+	for _, pool := range stats.Pools {
+		if pool.Name == poolName {
+			objects = pool.Stats.Objects
+			break
+		}
+	}
+	if objects < 0 {
+		// the pool does not exist
+		return false, nil
+	}
+	if objects == 0 {
+		return false, nil
+	}
+	// the objects of a pool with many objects are not listed, such a pool is not an empty rbd pool
+	if objects > maxListedPoolObjects {
+		return true, nil
+	}
+
+	cmd := NewRadosCommand(context, clusterInfo, []string{"ls", "--pool", poolName, "--all"})
+	output, err := cmd.RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		return false, errors.Wrapf(err, "failed to list the objects of pool %q. %s", poolName, string(output))
+	}
+	for _, line := range strings.Split(string(output), "\n") {
+		// the objects are listed as "<namespace>\t<object>"
+		fields := strings.Split(line, "\t")
+		object := fields[len(fields)-1]
+		for _, prefix := range rbdImageObjectPrefixes {
+			if strings.HasPrefix(object, prefix) {
+				return true, nil
+			}
+		}
+	}
+
+	return false, nil
+}
+
+// MigrateImageDataPool live-migrates the data of an image to a data pool. The migration is
+// resumed if it was already prepared. The image must not be in use when the migration is prepared,
+// but it can be used again by the clients as soon as the migration is prepared.
+func MigrateImageDataPool(context *clusterd.Context, clusterInfo *ClusterInfo, imageSpec, dataPool string) error {
+	status, err := GetImageStatus(context, clusterInfo, imageSpec)
+	if err != nil {
+		return err
+	}
+
+	state := ImageMigrationPrepared
+	if status.Migration == nil {
+		logger.Infof("preparing the migration of image %q to data pool %q", imageSpec, dataPool)
+		if err := runImageMigrationCommand(context, clusterInfo, "prepare", imageSpec, "--data-pool", dataPool); err != nil {
+			return err
+		}
+	} else {
+		state = status.Migration.State
+	}
+
+	if state != ImageMigrationExecuted {
+		logger.Infof("executing the migration of image %q", imageSpec)
+		if err := runImageMigrationCommand(context, clusterInfo, "execute", imageSpec); err != nil {
+			return err
+		}
+	}
+
+	if err := runImageMigrationCommand(context, clusterInfo, "commit", imageSpec); err != nil {
+		return err
+	}
+	logger.Infof("successfully migrated image %q to data pool %q", imageSpec, dataPool)
+
+	return nil
+}
+
+// RenamePool renames a pool
+func RenamePool(context *clusterd.Context, clusterInfo *ClusterInfo, oldName, newName string) error {
+	logger.Infof("renaming pool %q to %q", oldName, newName)
+	args := []string{"osd", "pool", "rename", oldName, newName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to rename pool %q to %q. %s", oldName, newName, string(output))
+	}
+
+	return nil
+}
+
+func getImageDataPool(context *clusterd.Context, clusterInfo *ClusterInfo, imageSpec string) (string, error) {
+	args := []string{"info", imageSpec}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	buf, err := cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to retrieve info of image %q. %s", imageSpec, string(buf))
+	}
+
+	var info struct {
+		DataPool string `json:"data_pool"`
+	}
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return "", errors.Wrapf(err, "failed to unmarshal info of image %q", imageSpec)
+	}
+
+	return info.DataPool, nil
+}
+
+func listRBDNamespaces(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) ([]string, error) {
+	args := []string{"namespace", "ls", "--pool", poolName}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list rados namespaces of pool %q. %s", poolName, string(buf))
+	}
+
+	var namespaces []rbdNamespace
+	if err := json.Unmarshal(buf, &namespaces); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal rados namespaces of pool %q", poolName)
+	}
+
+	names := make([]string, 0, len(namespaces))
+	for _, namespace := range namespaces {
+		names = append(names, namespace.Name)
+	}
+	return names, nil
+}
+
+func runImageMigrationCommand(context *clusterd.Context, clusterInfo *ClusterInfo, action, imageSpec string, extraArgs ...string) error {
+	args := append([]string{"migration", action, imageSpec}, extraArgs...)
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s the migration of image %q. %s", action, imageSpec, string(output))
+	}
+
+	return nil
+}
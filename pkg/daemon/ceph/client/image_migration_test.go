@@ -0,0 +1,144 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestListImagesByDataPool(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "namespace" && args[1] == "ls":
+			if args[3] == "replicapool" {
+				return `[{"name":"ns1"}]`, nil
+			}
+			return `[]`, nil
+		case args[0] == "ls":
+			switch args[2] {
+			case "replicapool":
+				return `[{"image":"img1"},{"image":"img2"}]`, nil
+			case "replicapool/ns1":
+				return `[{"image":"img3"}]`, nil
+			}
+			return `[]`, nil
+		case args[0] == "info":
+			if args[1] == "replicapool/img1" {
+				return `{"name":"img1"}`, nil
+			}
+			return `{"name":"img","data_pool":"ecpool"}`, nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	images, err := ListImagesByDataPool(context, AdminTestClusterInfo("mycluster"), []string{"replicapool"})
+	assert.NoError(t, err)
+	assert.Equal(t, map[string][]string{
+		"replicapool": {"replicapool/img1"},
+		"ecpool":      {"replicapool/img2", "replicapool/ns1/img3"},
+	}, images)
+}
+
+func TestPoolHasRBDData(t *testing.T) {
+	objects := ""
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "df" && args[1] == "detail" {
+			return `{"pools":[{"name":"emptypool","stats":{"objects":0}},{"name":"ecpool","stats":{"objects":3}},{"name":"bigpool","stats":{"objects":5000}}]}`, nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+	executor.MockExecuteCommandWithTimeout = func(timeout time.Duration, command string, args ...string) (string, error) {
+		if command == "rados" && args[0] == "ls" {
+			assert.Equal(t, "ecpool", args[2])
+			return objects, nil
+		}
+		return "", errors.Errorf("unexpected command %s %v", command, args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	for poolName, expected := range map[string]bool{"emptypool": false, "bigpool": true, "missingpool": false} {
+		hasData, err := PoolHasRBDData(context, clusterInfo, poolName)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, hasData, poolName)
+	}
+
+	objects = "\trbd_info\n\tobj1\n"
+	hasData, err := PoolHasRBDData(context, clusterInfo, "ecpool")
+	assert.NoError(t, err)
+	assert.False(t, hasData)
+
+	objects = "\trbd_info\nns1\trbd_data.1234.0000000000000000\n"
+	hasData, err = PoolHasRBDData(context, clusterInfo, "ecpool")
+	assert.NoError(t, err)
+	assert.True(t, hasData)
+}
+
+func TestMigrateImageDataPool(t *testing.T) {
+	status := `{"watchers":[]}`
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch args[0] {
+		case "status":
+			assert.Equal(t, "replicapool/img1", args[1])
+			return status, nil
+		case "migration":
+			commands = append(commands, strings.Join(args[1:3], " "))
+			if args[1] == "prepare" {
+				assert.Equal(t, []string{"--data-pool", "ecpool-migration"}, args[3:5])
+			}
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	t.Run("new migration", func(t *testing.T) {
+		commands = nil
+		err := MigrateImageDataPool(context, clusterInfo, "replicapool/img1", "ecpool-migration")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"prepare replicapool/img1", "execute replicapool/img1", "commit replicapool/img1"}, commands)
+	})
+
+	t.Run("resume a prepared migration", func(t *testing.T) {
+		commands = nil
+		status = `{"watchers":[{"address":"10.0.0.1:0/1"}],"migration":{"source_pool_name":"replicapool","dest_pool_name":"replicapool","state":"prepared"}}`
+		err := MigrateImageDataPool(context, clusterInfo, "replicapool/img1", "ecpool-migration")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"execute replicapool/img1", "commit replicapool/img1"}, commands)
+	})
+
+	t.Run("resume an executed migration", func(t *testing.T) {
+		commands = nil
+		status = `{"watchers":[],"migration":{"state":"executed"}}`
+		err := MigrateImageDataPool(context, clusterInfo, "replicapool/img1", "ecpool-migration")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"commit replicapool/img1"}, commands)
+	})
+}
@@ -18,16 +18,51 @@ package client
 
 import (
 	"encoding/json"
+	"regexp"
+	"slices"
 	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
 )
 
+const (
+	// BalancerReadModeMinClientRelease is the oldest release of the clients supporting the read modes of the balancer
+	BalancerReadModeMinClientRelease = "reef"
+)
+
 var (
+	// cephReleases are the releases reported by 'ceph features', from the oldest
+	cephReleases = []string{"hammer", "infernalis", "jewel", "kraken", "luminous", "mimic", "nautilus", "octopus", "pacific", "quincy", "reef", "squid", "tentacle"}
+
 	moduleEnableWaitTime = 5 * time.Second
+	balancerScoreRegex   = regexp.MustCompile(`score ([0-9.]+)`)
 )
 
+// BalancerStatus is the status of the balancer module as returned by 'ceph balancer status'
+type BalancerStatus struct {
+	Active               bool   `json:"active"`
+	Mode                 string `json:"mode"`
+	LastOptimizeStarted  string `json:"last_optimize_started"`
+	LastOptimizeDuration string `json:"last_optimize_duration"`
+	OptimizeResult       string `json:"optimize_result"`
+	NoOptimizationNeeded bool   `json:"no_optimization_needed"`
+}
+
+// MgrModules are the mgr modules as returned by 'ceph mgr module ls'
+type MgrModules struct {
+	AlwaysOnModules []string        `json:"always_on_modules"`
+	EnabledModules  []string        `json:"enabled_modules"`
+	DisabledModules []MgrModuleInfo `json:"disabled_modules"`
+}
+
+// MgrModuleInfo is a disabled mgr module and whether it can run
+type MgrModuleInfo struct {
+	Name        string `json:"name"`
+	CanRun      bool   `json:"can_run"`
+	ErrorString string `json:"error_string"`
+}
+
 func CephMgrMap(context *clusterd.Context, clusterInfo *ClusterInfo) (*MgrMap, error) {
 	args := []string{"mgr", "dump"}
 	buf, err := NewCephCommand(context, clusterInfo, args).Run()
@@ -64,6 +99,21 @@ func CephMgrStat(context *clusterd.Context, clusterInfo *ClusterInfo) (*MgrStat,
 	return &mgrStat, nil
 }
 
+// MgrListModules lists the mgr modules
+func MgrListModules(context *clusterd.Context, clusterInfo *ClusterInfo) (*MgrModules, error) {
+	args := []string{"mgr", "module", "ls"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list mgr modules. %s", string(buf))
+	}
+
+	var modules MgrModules
+	if err := json.Unmarshal(buf, &modules); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal mgr modules")
+	}
+	return &modules, nil
+}
+
 // MgrEnableModule enables a mgr module
 func MgrEnableModule(context *clusterd.Context, clusterInfo *ClusterInfo, name string, force bool) error {
 	retryCount := 5
@@ -132,12 +182,12 @@ func setBalancerMode(context *clusterd.Context, clusterInfo *ClusterInfo, mode s
 	return nil
 }
 
-// setMinCompatClientLuminous set the minimum compatibility for clients to Luminous
-func setMinCompatClientLuminous(context *clusterd.Context, clusterInfo *ClusterInfo) error {
-	args := []string{"osd", "set-require-min-compat-client", "luminous", "--yes-i-really-mean-it"}
+// setMinCompatClient set the minimum compatibility for clients to the given release
+func setMinCompatClient(context *clusterd.Context, clusterInfo *ClusterInfo, release string) error {
+	args := []string{"osd", "set-require-min-compat-client", release, "--yes-i-really-mean-it"}
 	_, err := NewCephCommand(context, clusterInfo, args).Run()
 	if err != nil {
-		return errors.Wrap(err, "failed to set set-require-min-compat-client to luminous")
+		return errors.Wrapf(err, "failed to set set-require-min-compat-client to %s", release)
 	}
 
 	return nil
@@ -165,8 +215,13 @@ func mgrSetBalancerMode(context *clusterd.Context, clusterInfo *ClusterInfo, bal
 
 // ConfigureBalancerModule configures the balancer module
 func ConfigureBalancerModule(context *clusterd.Context, clusterInfo *ClusterInfo, balancerModuleMode string) error {
-	// Set min compat client to luminous before enabling the balancer mode "upmap"
-	err := setMinCompatClientLuminous(context, clusterInfo)
+	// Set min compat client to luminous before enabling the balancer mode "upmap", the read modes
+	// require the pg-upmap-primary mappings of reef
+	release := "luminous"
+	if IsBalancerReadMode(balancerModuleMode) {
+		release = BalancerReadModeMinClientRelease
+	}
+	err := setMinCompatClient(context, clusterInfo, release)
 	if err != nil {
 		return errors.Wrap(err, "failed to set minimum compatibility client")
 	}
@@ -179,3 +234,78 @@ func ConfigureBalancerModule(context *clusterd.Context, clusterInfo *ClusterInfo
 
 	return nil
 }
+
+// GetClientReleasesOlderThan returns the releases of the connected clients that are older than the
+// given release, as reported by 'ceph features'
+func GetClientReleasesOlderThan(context *clusterd.Context, clusterInfo *ClusterInfo, release string) ([]string, error) {
+	args := []string{"features"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get the features of the daemons and clients. %s", string(buf))
+	}
+
+	var features map[string][]struct {
+		Release string `json:"release"`
+		Num     int    `json:"num"`
+	}
+	if err := json.Unmarshal(buf, &features); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal the features")
+	}
+
+	minRelease := slices.Index(cephReleases, release)
+	older := []string{}
+	for _, group := range features["client"] {
+		// the releases newer than the known releases are not older
+		index := slices.Index(cephReleases, group.Release)
+		if index >= 0 && index < minRelease && !slices.Contains(older, group.Release) {
+			older = append(older, group.Release)
+		}
+	}
+	return older, nil
+}
+
+// IsBalancerReadMode returns whether the balancer mode balances the primary OSDs of the PGs
+func IsBalancerReadMode(mode string) bool {
+	return mode == "read" || mode == "upmap-read"
+}
+
+// SetBalancerActive turns on or off the automatic balancing
+func SetBalancerActive(context *clusterd.Context, clusterInfo *ClusterInfo, active bool) error {
+	if active {
+		return enableDisableBalancerModule(context, clusterInfo, "on")
+	}
+	return enableDisableBalancerModule(context, clusterInfo, "off")
+}
+
+// GetBalancerStatus returns the status of the balancer module
+func GetBalancerStatus(context *clusterd.Context, clusterInfo *ClusterInfo) (*BalancerStatus, error) {
+	args := []string{"balancer", "status"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get balancer status. %s", string(buf))
+	}
+
+	var status BalancerStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal balancer status")
+	}
+	return &status, nil
+}
+
+// GetBalancerScore returns the score of the current data distribution, lower is better
+func GetBalancerScore(context *clusterd.Context, clusterInfo *ClusterInfo) (string, error) {
+	args := []string{"balancer", "eval"}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	buf, err := cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to evaluate the balancer score. %s", string(buf))
+	}
+
+	// the output is in the form "current cluster score 0.012345 (lower is better)"
+	match := balancerScoreRegex.FindStringSubmatch(string(buf))
+	if len(match) != 2 {
+		return "", errors.Errorf("failed to parse the balancer score %q", string(buf))
+	}
+	return match[1], nil
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package client
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/pkg/errors"
@@ -135,3 +136,99 @@ func TestSetBalancerMode(t *testing.T) {
 	err := setBalancerMode(&clusterd.Context{Executor: executor}, AdminTestClusterInfo("mycluster"), "upmap")
 	assert.NoError(t, err)
 }
+
+func TestConfigureBalancerModule(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		commands = append(commands, strings.Join(args[:3], " "))
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := ConfigureBalancerModule(context, AdminTestClusterInfo("mycluster"), "upmap")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"osd set-require-min-compat-client luminous", "balancer mode upmap"}, commands)
+
+	commands = nil
+	err = ConfigureBalancerModule(context, AdminTestClusterInfo("mycluster"), "upmap-read")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"osd set-require-min-compat-client reef", "balancer mode upmap-read"}, commands)
+}
+
+func TestGetBalancerStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "balancer" && args[1] == "status" {
+			return `{"active":true,"last_optimize_duration":"0:00:00.000345","last_optimize_started":"Mon Oct 19 08:00:00 2026",` +
+				`"mode":"upmap","no_optimization_needed":true,"optimize_result":"Unable to find further optimization","plans":[]}`, nil
+		}
+		if args[0] == "balancer" && args[1] == "eval" {
+			return "current cluster score 0.012345 (lower is better)\n", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	status, err := GetBalancerStatus(context, clusterInfo)
+	assert.NoError(t, err)
+	assert.True(t, status.Active)
+	assert.Equal(t, "upmap", status.Mode)
+	assert.Equal(t, "Mon Oct 19 08:00:00 2026", status.LastOptimizeStarted)
+	assert.Equal(t, "Unable to find further optimization", status.OptimizeResult)
+
+	score, err := GetBalancerScore(context, clusterInfo)
+	assert.NoError(t, err)
+	assert.Equal(t, "0.012345", score)
+}
+
+func TestGetClientReleasesOlderThan(t *testing.T) {
+	features := `{"mon":[{"features":"0x3f01cfbffffdffff","release":"squid","num":3}],` +
+		`"client":[{"features":"0x2f018fb86aa42ada","release":"luminous","num":2},{"features":"0x3f01cfbffffdffff","release":"squid","num":4},` +
+		`{"features":"0x2f018fb86aa42ada","release":"luminous","num":1},{"features":"0x3f01cfbffffdffff","release":"umbrella","num":1}]}`
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "features" {
+			return features, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	releases, err := GetClientReleasesOlderThan(context, clusterInfo, BalancerReadModeMinClientRelease)
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"luminous"}, releases)
+
+	releases, err = GetClientReleasesOlderThan(context, clusterInfo, "jewel")
+	assert.NoError(t, err)
+	assert.Empty(t, releases)
+
+	features = `{"mon":[{"release":"squid","num":3}]}`
+	releases, err = GetClientReleasesOlderThan(context, clusterInfo, BalancerReadModeMinClientRelease)
+	assert.NoError(t, err)
+	assert.Empty(t, releases)
+}
+
+func TestMgrListModules(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mgr" && args[1] == "module" && args[2] == "ls" {
+			return `{"always_on_modules":["balancer","crash"],"enabled_modules":["pg_autoscaler","prometheus"],` +
+				`"disabled_modules":[{"name":"diskprediction_local","can_run":false,"error_string":"No module named 'sklearn'","module_options":{}},` +
+				`{"name":"insights","can_run":true,"error_string":"","module_options":{}}]}`, nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	modules, err := MgrListModules(context, AdminTestClusterInfo("mycluster"))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"balancer", "crash"}, modules.AlwaysOnModules)
+	assert.Equal(t, []string{"pg_autoscaler", "prometheus"}, modules.EnabledModules)
+	assert.Equal(t, []MgrModuleInfo{
+		{Name: "diskprediction_local", CanRun: false, ErrorString: "No module named 'sklearn'"},
+		{Name: "insights", CanRun: true},
+	}, modules.DisabledModules)
+}
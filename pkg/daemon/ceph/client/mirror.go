@@ -26,6 +26,7 @@ import (
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"k8s.io/apimachinery/pkg/util/sets"
 )
 
@@ -42,6 +43,10 @@ type PeerToken struct {
 var (
 	rbdMirrorPeerCaps      = []string{"mon", "profile rbd-mirror-peer", "osd", "profile rbd"}
 	rbdMirrorPeerKeyringID = "rbd-mirror-peer"
+
+	// RemoteNamespaceMirroringVersion is the minimum ceph version to mirror a rados namespace to
+	// a namespace with a different name on the peer cluster
+	RemoteNamespaceMirroringVersion = cephver.CephVersion{Major: 19, Minor: 2}
 )
 
 // ImportRBDMirrorBootstrapPeer add a mirror peer in the rbd-mirror configuration
@@ -102,12 +107,20 @@ func CreateRBDMirrorBootstrapPeer(context *clusterd.Context, clusterInfo *Cluste
 	return output, nil
 }
 
-// enablePoolMirroring turns on mirroring on that pool by specifying the mirroring type
-func enablePoolMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, pool cephv1.NamedPoolSpec) error {
+// enablePoolMirroring turns on mirroring on that pool by specifying the mirroring type. The pool
+// name can also be a rados namespace of the pool in the form <pool>/<namespace>, the remote
+// namespace is then the namespace it is mirrored to on the peer cluster.
+func enablePoolMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, pool cephv1.NamedPoolSpec, remoteNamespace *string) error {
 	logger.Infof("enabling mirroring type %q for pool %q", pool.Mirroring.Mode, pool.Name)
 
 	// Build command
 	args := []string{"mirror", "pool", "enable", pool.Name, pool.Mirroring.Mode}
+	if remoteNamespace != nil {
+		if !clusterInfo.CephVersion.IsAtLeast(RemoteNamespaceMirroringVersion) {
+			return errors.Errorf("mirroring pool %q to a remote namespace requires ceph version %q", pool.Name, RemoteNamespaceMirroringVersion.String())
+		}
+		args = append(args, "--remote-namespace", *remoteNamespace)
+	}
 	cmd := NewRBDCommand(context, clusterInfo, args)
 
 	// Run command
@@ -136,6 +149,60 @@ func disablePoolMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, p
 	return nil
 }
 
+// EnableRBDRadosNamespaceMirroring turns on mirroring on a rados namespace of a pool and configures
+// its snapshot schedules
+func EnableRBDRadosNamespaceMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, radosNamespace string, mirroring cephv1.RadosNamespaceMirroring) error {
+	pool := cephv1.NamedPoolSpec{
+		Name: RadosNamespacePoolName(poolName, radosNamespace),
+		PoolSpec: cephv1.PoolSpec{
+			Mirroring: cephv1.MirroringSpec{
+				Enabled:           true,
+				Mode:              string(mirroring.Mode),
+				SnapshotSchedules: mirroring.SnapshotSchedules,
+			},
+		},
+	}
+
+	err := enablePoolMirroring(context, clusterInfo, pool, mirroring.RemoteNamespace)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable mirroring for rados namespace %q", pool.Name)
+	}
+
+	// Always reset the schedules so that the ones removed from the spec are removed too
+	err = enableSnapshotSchedules(context, clusterInfo, pool)
+	if err != nil {
+		return errors.Wrapf(err, "failed to enable snapshot scheduling for rados namespace %q", pool.Name)
+	}
+
+	logger.Infof("successfully enabled mirroring type %q for rados namespace %q", mirroring.Mode, pool.Name)
+	return nil
+}
+
+// DisableRBDRadosNamespaceMirroring removes the snapshot schedules of a rados namespace of a pool
+// and turns off its mirroring
+func DisableRBDRadosNamespaceMirroring(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, radosNamespace string) error {
+	pool := cephv1.NamedPoolSpec{Name: RadosNamespacePoolName(poolName, radosNamespace)}
+
+	err := removeSnapshotSchedules(context, clusterInfo, pool)
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove snapshot schedules of rados namespace %q", pool.Name)
+	}
+
+	err = disablePoolMirroring(context, clusterInfo, pool.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to disable mirroring for rados namespace %q", pool.Name)
+	}
+
+	logger.Infof("successfully disabled mirroring for rados namespace %q", pool.Name)
+	return nil
+}
+
+// RadosNamespacePoolName returns the <pool>/<namespace> spec of a rados namespace understood by the
+// rbd mirror commands
+func RadosNamespacePoolName(poolName, radosNamespace string) string {
+	return fmt.Sprintf("%s/%s", poolName, radosNamespace)
+}
+
 func removeClusterPeer(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, peerUUID string) error {
 	logger.Infof("removing cluster peer with UUID %q for the pool %q", peerUUID, poolName)
 
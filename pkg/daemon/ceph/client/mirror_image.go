@@ -0,0 +1,301 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+// maxReportedMirroredImages is the maximum number of images reported in the mirroring role status
+const maxReportedMirroredImages = 50
+
+// PoolMirroredImagesStatus is the verbose mirroring status of a pool returned by 'rbd mirror pool status --verbose'
+type PoolMirroredImagesStatus struct {
+	Summary *cephv1.PoolMirroringStatusSummarySpec `json:"summary,omitempty"`
+	Images  []MirroredImageStatus                  `json:"images"`
+}
+
+// MirroredImageStatus is the mirroring status of an image returned by 'rbd mirror pool status --verbose'
+type MirroredImageStatus struct {
+	Name        string                    `json:"name"`
+	GlobalID    string                    `json:"global_id"`
+	State       string                    `json:"state"`
+	Description string                    `json:"description"`
+	LastUpdate  string                    `json:"last_update"`
+	PeerSites   []MirroredImagePeerStatus `json:"peer_sites"`
+}
+
+// MirroredImagePeerStatus is the mirroring status of an image on a peer site
+type MirroredImagePeerStatus struct {
+	SiteName    string `json:"site_name"`
+	State       string `json:"state"`
+	Description string `json:"description"`
+	LastUpdate  string `json:"last_update"`
+}
+
+// MirroredImageReplayStatus is the replay status embedded in the description of a replaying image,
+// e.g. 'replaying, {"bytes_per_second":0.0,...}'
+type MirroredImageReplayStatus struct {
+	ReplayState              string  `json:"replay_state"`
+	BytesPerSecond           float64 `json:"bytes_per_second"`
+	BytesPerSnapshot         float64 `json:"bytes_per_snapshot"`
+	LocalSnapshotTimestamp   int64   `json:"local_snapshot_timestamp"`
+	RemoteSnapshotTimestamp  int64   `json:"remote_snapshot_timestamp"`
+	SyncingSnapshotTimestamp int64   `json:"syncing_snapshot_timestamp"`
+	SyncingPercent           float64 `json:"syncing_percent"`
+	EntriesBehindPrimary     int64   `json:"entries_behind_primary"`
+}
+
+// ReplayStatus returns the replay status of the image. The replay status is reported by the
+// non-primary image, so it is taken from the peer sites when the local image is primary. It returns
+// nil if the image is not replaying.
+func (s *MirroredImageStatus) ReplayStatus() *MirroredImageReplayStatus {
+	if status := parseReplayStatus(s.Description); status != nil {
+		return status
+	}
+	for _, peer := range s.PeerSites {
+		if status := parseReplayStatus(peer.Description); status != nil {
+			return status
+		}
+	}
+	return nil
+}
+
+// ReplicationState returns the mirroring state of the image, or of its peer when the image is primary
+func (s *MirroredImageStatus) ReplicationState() string {
+	if strings.HasSuffix(s.State, "+stopped") && len(s.PeerSites) > 0 {
+		return s.PeerSites[0].State
+	}
+	return s.State
+}
+
+func parseReplayStatus(description string) *MirroredImageReplayStatus {
+	start := strings.Index(description, "{")
+	if start < 0 {
+		return nil
+	}
+	var status MirroredImageReplayStatus
+	if err := json.Unmarshal([]byte(description[start:]), &status); err != nil {
+		logger.Debugf("failed to parse replay status %q. %v", description, err)
+		return nil
+	}
+	return &status
+}
+
+// ImageMirroringInfo is the mirroring section returned by 'rbd info'
+type ImageMirroringInfo struct {
+	Mode     string `json:"mode"`
+	State    string `json:"state"`
+	GlobalID string `json:"global_id"`
+	Primary  bool   `json:"primary"`
+}
+
+// GetPoolMirroredImagesStatus returns the mirroring status of each mirrored image of a pool. The
+// pool name can also be a rados namespace of the pool in the form <pool>/<namespace>.
+func GetPoolMirroredImagesStatus(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (*PoolMirroredImagesStatus, error) {
+	logger.Debugf("retrieving mirroring status of the images of pool %q", poolName)
+
+	// Build command
+	args := []string{"mirror", "pool", "status", poolName, "--verbose"}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	// Run command
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve mirroring status of the images of pool %q. %s", poolName, string(buf))
+	}
+
+	var status PoolMirroredImagesStatus
+	if err := json.Unmarshal(buf, &status); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal mirror pool verbose status response")
+	}
+
+	return &status, nil
+}
+
+// GetImageMirroringInfo returns the mirroring info of an image
+func GetImageMirroringInfo(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName string) (*ImageMirroringInfo, error) {
+	imageSpec := getImageSpec(imageName, poolName)
+
+	// Build command
+	args := []string{"info", imageSpec}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+
+	// Run command
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to retrieve info of image %q. %s", imageSpec, string(buf))
+	}
+
+	var info struct {
+		Mirroring ImageMirroringInfo `json:"mirroring"`
+	}
+	if err := json.Unmarshal(buf, &info); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal info of image %q", imageSpec)
+	}
+
+	return &info.Mirroring, nil
+}
+
+// DemoteImage demotes a mirrored image to non-primary
+func DemoteImage(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName string) error {
+	return runImageMirrorCommand(context, clusterInfo, "demote", poolName, imageName)
+}
+
+// PromoteImage promotes a mirrored image to primary. A forced promotion succeeds even if the image
+// is still primary on the peer cluster, typically when the peer cluster is unreachable.
+func PromoteImage(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName string, force bool) error {
+	if force {
+		return runImageMirrorCommand(context, clusterInfo, "promote", poolName, imageName, "--force")
+	}
+	return runImageMirrorCommand(context, clusterInfo, "promote", poolName, imageName)
+}
+
+// ResyncImage flags a non-primary mirrored image for a resync from the primary image, typically
+// after a split-brain caused by a forced promotion
+func ResyncImage(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, imageName string) error {
+	return runImageMirrorCommand(context, clusterInfo, "resync", poolName, imageName)
+}
+
+func runImageMirrorCommand(context *clusterd.Context, clusterInfo *ClusterInfo, action, poolName, imageName string, extraArgs ...string) error {
+	imageSpec := getImageSpec(imageName, poolName)
+	logger.Infof("running mirror image %s on image %q", action, imageSpec)
+
+	// Build command
+	args := append([]string{"mirror", "image", action, imageSpec}, extraArgs...)
+	cmd := NewRBDCommand(context, clusterInfo, args)
+
+	// Run command
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to %s image %q. %s", action, imageSpec, string(output))
+	}
+
+	return nil
+}
+
+// ApplyMirroringRole promotes or demotes the mirrored images of a pool, or of a rados namespace of
+// the pool in the form <pool>/<namespace>, to the given role. Secondary images in split-brain after
+// a forced promotion on the peer are only resynced if resyncSplitBrain is set, since the resync
+// discards the local data of the images. The images have converged once they all have the role and
+// the mirroring status of the pool confirms their image health.
+func ApplyMirroringRole(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, role cephv1.MirroringRole, force, resyncSplitBrain bool) (*cephv1.MirroringRoleStatusSpec, error) {
+	poolStatus, err := GetPoolMirroredImagesStatus(context, clusterInfo, poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &cephv1.MirroringRoleStatusSpec{Role: role, Phase: cephv1.ConditionProgressing}
+	failures := 0
+	for _, image := range poolStatus.Images {
+		status.ImageCount++
+		imageStatus := cephv1.MirroredImageRoleStatus{Name: image.Name, State: image.State, Description: image.Description}
+		converged, err := applyImageMirroringRole(context, clusterInfo, poolName, image, role, force, resyncSplitBrain, &imageStatus)
+		if imageStatus.SplitBrain {
+			status.SplitBrainCount++
+		}
+		if err != nil {
+			logger.Warningf("%v", err)
+			imageStatus.Error = err.Error()
+			failures++
+		}
+		if converged {
+			status.ConvergedCount++
+			continue
+		}
+		if len(status.Images) < maxReportedMirroredImages {
+			status.Images = append(status.Images, imageStatus)
+		}
+	}
+	status.LastChecked = time.Now().UTC().Format(time.RFC3339)
+
+	imageHealth := ""
+	if poolStatus.Summary != nil {
+		imageHealth = poolStatus.Summary.ImageHealth
+	}
+	switch {
+	case failures > 0:
+		status.Phase = cephv1.ConditionFailure
+		status.Details = fmt.Sprintf("%d images failed to converge to %s", failures, role)
+	case status.SplitBrainCount > 0 && !resyncSplitBrain:
+		status.Phase = cephv1.ConditionFailure
+		status.Details = fmt.Sprintf("%d images are in split-brain and must be resynced from the peer, which discards their local data", status.SplitBrainCount)
+	case status.ConvergedCount < status.ImageCount:
+		status.Details = fmt.Sprintf("%d/%d images converged to %s", status.ConvergedCount, status.ImageCount, role)
+	// the peer of a forced promotion is typically unreachable, the image health is not expected to recover
+	case imageHealth == "OK" || imageHealth == "" || (role == cephv1.MirroringRolePrimary && force):
+		status.Phase = cephv1.ConditionReady
+	default:
+		status.Details = fmt.Sprintf("waiting for the image health %q to be OK", imageHealth)
+	}
+
+	return status, nil
+}
+
+// applyImageMirroringRole promotes, demotes or resyncs the image and returns whether the image has converged to the role
+func applyImageMirroringRole(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, image MirroredImageStatus, role cephv1.MirroringRole, force, resyncSplitBrain bool, imageStatus *cephv1.MirroredImageRoleStatus) (bool, error) {
+	info, err := GetImageMirroringInfo(context, clusterInfo, poolName, image.Name)
+	if err != nil {
+		return false, err
+	}
+	imageStatus.Primary = info.Primary
+
+	switch role {
+	case cephv1.MirroringRolePrimary:
+		if info.Primary {
+			return true, nil
+		}
+		// without force, the promotion fails until the image is demoted on the peer
+		if err := PromoteImage(context, clusterInfo, poolName, image.Name, force); err != nil {
+			return false, err
+		}
+		imageStatus.Primary = true
+		return true, nil
+
+	case cephv1.MirroringRoleSecondary:
+		if info.Primary {
+			if err := DemoteImage(context, clusterInfo, poolName, image.Name); err != nil {
+				return false, err
+			}
+			// the demoted image starts replaying from the peer
+			imageStatus.Primary = false
+			return false, nil
+		}
+		if strings.Contains(image.Description, "split-brain") {
+			imageStatus.SplitBrain = true
+			if !resyncSplitBrain {
+				return false, nil
+			}
+			if err := ResyncImage(context, clusterInfo, poolName, image.Name); err != nil {
+				return false, err
+			}
+			return false, nil
+		}
+		return !strings.HasSuffix(image.State, "+error"), nil
+	}
+
+	return false, errors.Errorf("invalid mirroring role %q", role)
+}
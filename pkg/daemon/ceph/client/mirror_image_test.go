@@ -0,0 +1,184 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+var mirrorStatusVerbose = `{"summary":{"health":"WARNING","daemon_health":"OK","image_health":"WARNING","states":{"replaying":1,"error":1}},
+"images":[
+{"name":"img1","global_id":"2a3b","state":"up+replaying","description":"replaying","last_update":"2024-03-01 10:00:00","peer_sites":[{"site_name":"site-b","state":"up+stopped","description":"local image is primary","last_update":"2024-03-01 10:00:00"}]},
+{"name":"img2","global_id":"4c5d","state":"up+error","description":"split-brain","last_update":"2024-03-01 10:00:00"}]}`
+
+func TestGetPoolMirroredImagesStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			assert.Equal(t, []string{"mirror", "pool", "status", "pool-test/namespace-a", "--verbose"}, args[:5])
+			return mirrorStatusVerbose, nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	status, err := GetPoolMirroredImagesStatus(context, AdminTestClusterInfo("mycluster"), "pool-test/namespace-a")
+	assert.NoError(t, err)
+	assert.Equal(t, "WARNING", status.Summary.ImageHealth)
+	assert.Equal(t, 2, len(status.Images))
+	assert.Equal(t, "img1", status.Images[0].Name)
+	assert.Equal(t, "up+replaying", status.Images[0].State)
+	assert.Equal(t, "site-b", status.Images[0].PeerSites[0].SiteName)
+	assert.Equal(t, "split-brain", status.Images[1].Description)
+}
+
+func TestApplyMirroringRole(t *testing.T) {
+	poolStatus := mirrorStatusVerbose
+	primary := map[string]bool{}
+	promoteErr := error(nil)
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "mirror" && args[1] == "pool":
+			return poolStatus, nil
+		case args[0] == "info":
+			image := args[1][len("pool-test/"):]
+			return fmt.Sprintf(`{"name":%q,"mirroring":{"mode":"snapshot","state":"enabled","global_id":"2a3b","primary":%t}}`, image, primary[image]), nil
+		case args[0] == "mirror" && args[1] == "image":
+			commands = append(commands, args[2]+" "+args[3])
+			if args[2] == "promote" && promoteErr != nil {
+				return "", promoteErr
+			}
+			return "", nil
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	t.Run("demote the primary images", func(t *testing.T) {
+		commands = nil
+		primary = map[string]bool{"img1": true, "img2": true}
+		status, err := ApplyMirroringRole(context, clusterInfo, "pool-test", cephv1.MirroringRoleSecondary, false, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"demote pool-test/img1", "demote pool-test/img2"}, commands)
+		assert.Equal(t, cephv1.ConditionProgressing, status.Phase)
+		assert.Equal(t, 2, status.ImageCount)
+		assert.Equal(t, 0, status.ConvergedCount)
+		assert.Equal(t, 2, len(status.Images))
+		assert.False(t, status.Images[0].Primary)
+	})
+
+	t.Run("report the images in split-brain", func(t *testing.T) {
+		commands = nil
+		primary = map[string]bool{}
+		status, err := ApplyMirroringRole(context, clusterInfo, "pool-test", cephv1.MirroringRoleSecondary, false, false)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+		assert.Equal(t, cephv1.ConditionFailure, status.Phase)
+		assert.Contains(t, status.Details, "1 images are in split-brain")
+		assert.Equal(t, 1, status.ConvergedCount)
+		assert.Equal(t, 1, status.SplitBrainCount)
+		assert.Equal(t, "img2", status.Images[0].Name)
+		assert.True(t, status.Images[0].SplitBrain)
+	})
+
+	t.Run("resync the images in split-brain", func(t *testing.T) {
+		commands = nil
+		primary = map[string]bool{}
+		status, err := ApplyMirroringRole(context, clusterInfo, "pool-test", cephv1.MirroringRoleSecondary, false, true)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"resync pool-test/img2"}, commands)
+		assert.Equal(t, cephv1.ConditionProgressing, status.Phase)
+		assert.Equal(t, 1, status.ConvergedCount)
+		assert.Equal(t, 1, status.SplitBrainCount)
+		assert.Equal(t, "img2", status.Images[0].Name)
+	})
+
+	t.Run("secondary converged once the image health is ok", func(t *testing.T) {
+		commands = nil
+		poolStatus = `{"summary":{"health":"OK","image_health":"OK"},"images":[{"name":"img1","state":"up+replaying"}]}`
+		status, err := ApplyMirroringRole(context, clusterInfo, "pool-test", cephv1.MirroringRoleSecondary, false, false)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+		assert.Equal(t, cephv1.ConditionReady, status.Phase)
+		assert.Equal(t, 1, status.ConvergedCount)
+		assert.Empty(t, status.Images)
+	})
+
+	t.Run("promotion fails while the peer is primary", func(t *testing.T) {
+		commands = nil
+		promoteErr = errors.New("image is still primary within a remote cluster")
+		status, err := ApplyMirroringRole(context, clusterInfo, "pool-test", cephv1.MirroringRolePrimary, false, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promote pool-test/img1"}, commands)
+		assert.Equal(t, cephv1.ConditionFailure, status.Phase)
+		assert.Contains(t, status.Images[0].Error, "failed to promote image")
+	})
+
+	t.Run("forced promotion", func(t *testing.T) {
+		commands = nil
+		promoteErr = nil
+		poolStatus = `{"summary":{"health":"WARNING","image_health":"WARNING"},"images":[{"name":"img1","state":"down+unknown"}]}`
+		status, err := ApplyMirroringRole(context, clusterInfo, "pool-test", cephv1.MirroringRolePrimary, true, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"promote pool-test/img1"}, commands)
+		assert.Equal(t, cephv1.ConditionReady, status.Phase)
+		assert.Equal(t, 1, status.ConvergedCount)
+	})
+}
+
+func TestMirroredImageReplayStatus(t *testing.T) {
+	t.Run("primary image with a replaying peer", func(t *testing.T) {
+		image := MirroredImageStatus{
+			Name:        "img1",
+			State:       "up+stopped",
+			Description: "local image is primary",
+			PeerSites: []MirroredImagePeerStatus{{
+				SiteName:    "site-b",
+				State:       "up+replaying",
+				Description: `replaying, {"bytes_per_second":0.0,"bytes_per_snapshot":2048.0,"local_snapshot_timestamp":1709287200,"remote_snapshot_timestamp":1709287260,"replay_state":"syncing","syncing_percent":50,"syncing_snapshot_timestamp":1709287260}`,
+			}},
+		}
+		assert.Equal(t, "up+replaying", image.ReplicationState())
+		replay := image.ReplayStatus()
+		assert.NotNil(t, replay)
+		assert.Equal(t, "syncing", replay.ReplayState)
+		assert.Equal(t, int64(1709287200), replay.LocalSnapshotTimestamp)
+		assert.Equal(t, int64(1709287260), replay.RemoteSnapshotTimestamp)
+		assert.Equal(t, 2048.0, replay.BytesPerSnapshot)
+	})
+
+	t.Run("image not replaying", func(t *testing.T) {
+		image := MirroredImageStatus{Name: "img2", State: "up+error", Description: "split-brain"}
+		assert.Equal(t, "up+error", image.ReplicationState())
+		assert.Nil(t, image.ReplayStatus())
+	})
+
+	t.Run("invalid replay status", func(t *testing.T) {
+		image := MirroredImageStatus{Name: "img3", State: "up+replaying", Description: "replaying, {invalid"}
+		assert.Nil(t, image.ReplayStatus())
+	})
+}
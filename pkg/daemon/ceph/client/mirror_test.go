@@ -22,6 +22,7 @@ import (
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -77,10 +78,101 @@ func TestEnablePoolMirroring(t *testing.T) {
 	}
 	context := &clusterd.Context{Executor: executor}
 
-	err := enablePoolMirroring(context, AdminTestClusterInfo("mycluster"), pool)
+	err := enablePoolMirroring(context, AdminTestClusterInfo("mycluster"), pool, nil)
 	assert.NoError(t, err)
 }
 
+func TestEnableRBDRadosNamespaceMirroring(t *testing.T) {
+	remoteNamespace := "namespace-b"
+	mirroring := cephv1.RadosNamespaceMirroring{
+		Mode:              cephv1.RadosNamespaceMirroringModeImage,
+		SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "1h"}},
+	}
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			commands = append(commands, args[2]+" "+args[3])
+			switch {
+			case args[1] == "pool" && args[2] == "enable":
+				assert.Equal(t, "pool-test/namespace-a", args[3])
+				assert.Equal(t, "image", args[4])
+				if mirroring.RemoteNamespace != nil {
+					assert.Equal(t, "--remote-namespace", args[5])
+					assert.Equal(t, remoteNamespace, args[6])
+				} else {
+					assert.NotContains(t, args, "--remote-namespace")
+				}
+				return "", nil
+			case args[2] == "schedule" && args[3] == "ls":
+				assert.Equal(t, "pool-test/namespace-a", args[5])
+				return "[]", nil
+			case args[2] == "schedule" && args[3] == "add":
+				assert.Equal(t, "pool-test/namespace-a", args[5])
+				assert.Equal(t, "1h", args[6])
+				return "", nil
+			}
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	t.Run("same namespace on the peer", func(t *testing.T) {
+		commands = nil
+		err := EnableRBDRadosNamespaceMirroring(context, AdminTestClusterInfo("mycluster"), "pool-test", "namespace-a", mirroring)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"enable pool-test/namespace-a", "schedule ls", "schedule add"}, commands)
+	})
+
+	t.Run("remote namespace", func(t *testing.T) {
+		commands = nil
+		mirroring.RemoteNamespace = &remoteNamespace
+		clusterInfo := AdminTestClusterInfo("mycluster")
+		clusterInfo.CephVersion = cephver.CephVersion{Major: 19, Minor: 2, Extra: 0}
+		err := EnableRBDRadosNamespaceMirroring(context, clusterInfo, "pool-test", "namespace-a", mirroring)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"enable pool-test/namespace-a", "schedule ls", "schedule add"}, commands)
+	})
+
+	t.Run("remote namespace before squid 19.2", func(t *testing.T) {
+		commands = nil
+		mirroring.RemoteNamespace = &remoteNamespace
+		clusterInfo := AdminTestClusterInfo("mycluster")
+		clusterInfo.CephVersion = cephver.CephVersion{Major: 19, Minor: 1, Extra: 2}
+		err := EnableRBDRadosNamespaceMirroring(context, clusterInfo, "pool-test", "namespace-a", mirroring)
+		assert.Error(t, err)
+		assert.Empty(t, commands)
+	})
+}
+
+func TestDisableRBDRadosNamespaceMirroring(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "mirror" {
+			commands = append(commands, args[2]+" "+args[3])
+			switch {
+			case args[1] == "pool" && args[2] == "disable":
+				assert.Equal(t, "pool-test/namespace-a", args[3])
+				return "", nil
+			case args[2] == "schedule" && args[3] == "ls":
+				assert.Equal(t, "pool-test/namespace-a", args[5])
+				return `[{"interval":"1h","start_time":""}]`, nil
+			case args[2] == "schedule" && args[3] == "remove":
+				assert.Equal(t, "pool-test/namespace-a", args[5])
+				assert.Equal(t, "1h", args[6])
+				return "", nil
+			}
+		}
+		return "", errors.New("unknown command")
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	err := DisableRBDRadosNamespaceMirroring(context, AdminTestClusterInfo("mycluster"), "pool-test", "namespace-a")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"schedule ls", "schedule remove", "disable pool-test/namespace-a"}, commands)
+}
+
 func TestGetPoolMirroringStatus(t *testing.T) {
 	pool := "pool-test"
 	executor := &exectest.MockExecutor{}
@@ -17,8 +17,10 @@ package client
 
 import (
 	"encoding/json"
+	"fmt"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -28,6 +30,7 @@ import (
 
 const (
 	defaultStretchCrushRuleName = "default_stretch_cluster_rule"
+	monCompactTimeout           = 5 * time.Minute
 )
 
 // MonStatusResponse represents the response from a quorum_status mon_command (subset of all available fields, only
@@ -164,3 +167,30 @@ func SetNewTiebreaker(context *clusterd.Context, clusterInfo *ClusterInfo, monNa
 	logger.Infof("successfully set new mon tiebreaker %q in arbiter zone", monName)
 	return nil
 }
+
+// DisableStretchMode disables the ceph stretch mode, the pools are moved back to the default replicated
+// crush rule
+func DisableStretchMode(context *clusterd.Context, clusterInfo *ClusterInfo) error {
+	logger.Info("disabling stretch mode")
+	args := []string{"mon", "disable_stretch_mode", "--yes-i-really-mean-it"}
+	buf, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to disable stretch mode. %s", string(buf))
+	}
+	logger.Info("successfully disabled stretch mode")
+	return nil
+}
+
+// CompactMonStore compacts the store of a mon
+func CompactMonStore(context *clusterd.Context, clusterInfo *ClusterInfo, monName string) error {
+	logger.Infof("compacting the store of mon %q", monName)
+	cmd := NewCephCommand(context, clusterInfo, []string{"tell", fmt.Sprintf("mon.%s", monName), "compact"})
+	cmd.JsonOutput = false
+	// the compaction of a large store takes longer than the usual ceph commands
+	buf, err := cmd.RunWithTimeout(monCompactTimeout)
+	if err != nil {
+		return errors.Wrapf(err, "failed to compact the store of mon %q. %s", monName, string(buf))
+	}
+	logger.Infof("compacted the store of mon %q. %s", monName, strings.TrimSpace(string(buf)))
+	return nil
+}
@@ -85,6 +85,23 @@ func TestStretchElectionStrategy(t *testing.T) {
 	assert.NoError(t, err)
 }
 
+func TestDisableStretchMode(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "mon" && args[1] == "disable_stretch_mode" {
+			assert.Equal(t, "--yes-i-really-mean-it", args[2])
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	err := DisableStretchMode(context, clusterInfo)
+	assert.NoError(t, err)
+}
+
 func TestStretchClusterMonTiebreaker(t *testing.T) {
 	monName := "a"
 	failureDomain := "rack"
@@ -152,3 +169,22 @@ func TestMonDump(t *testing.T) {
 	assert.Equal(t, 3, len(dump.Mons))
 	assert.Equal(t, 3, len(dump.Quorum))
 }
+
+func TestCompactMonStore(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithTimeout = func(timeout time.Duration, command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "tell" && args[1] == "mon.a" && args[2] == "compact" {
+			return "compacted rocksdb in 1.2 seconds", nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	err := CompactMonStore(context, clusterInfo, "a")
+	assert.NoError(t, err)
+
+	err = CompactMonStore(context, clusterInfo, "b")
+	assert.Error(t, err)
+}
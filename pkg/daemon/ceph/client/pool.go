@@ -23,10 +23,13 @@ import (
 	"path/filepath"
 	"strconv"
 	"strings"
+	"syscall"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/util/exec"
 	"k8s.io/apimachinery/pkg/api/resource"
 )
 
@@ -48,6 +51,7 @@ type CephStoragePoolDetails struct {
 	Name                   string  `json:"pool"`
 	Number                 int     `json:"pool_id"`
 	Size                   uint    `json:"size"`
+	MinSize                uint    `json:"min_size"`
 	ErasureCodeProfile     string  `json:"erasure_code_profile"`
 	CrushRoot              string  `json:"crushRoot"`
 	DeviceClass            string  `json:"deviceClass"`
@@ -62,19 +66,32 @@ type CephStoragePoolStats struct {
 		Name  string `json:"name"`
 		ID    int    `json:"id"`
 		Stats struct {
-			BytesUsed    float64 `json:"bytes_used"`
-			RawBytesUsed float64 `json:"raw_bytes_used"`
-			MaxAvail     float64 `json:"max_avail"`
-			Objects      float64 `json:"objects"`
-			DirtyObjects float64 `json:"dirty"`
-			ReadIO       float64 `json:"rd"`
-			ReadBytes    float64 `json:"rd_bytes"`
-			WriteIO      float64 `json:"wr"`
-			WriteBytes   float64 `json:"wr_bytes"`
+			Stored             float64 `json:"stored"`
+			BytesUsed          float64 `json:"bytes_used"`
+			RawBytesUsed       float64 `json:"raw_bytes_used"`
+			MaxAvail           float64 `json:"max_avail"`
+			Objects            float64 `json:"objects"`
+			DirtyObjects       float64 `json:"dirty"`
+			ReadIO             float64 `json:"rd"`
+			ReadBytes          float64 `json:"rd_bytes"`
+			WriteIO            float64 `json:"wr"`
+			WriteBytes         float64 `json:"wr_bytes"`
+			QuotaBytes         float64 `json:"quota_bytes"`
+			QuotaObjects       float64 `json:"quota_objects"`
+			CompressBytesUsed  float64 `json:"compress_bytes_used"`
+			CompressUnderBytes float64 `json:"compress_under_bytes"`
 		} `json:"stats"`
 	} `json:"pools"`
 }
 
+// PoolAutoscaleStatus is the status of the PG autoscaler for a pool returned by 'ceph osd pool autoscale-status'
+type PoolAutoscaleStatus struct {
+	Name          string `json:"pool_name"`
+	AutoscaleMode string `json:"pg_autoscale_mode"`
+	PGNumTarget   int    `json:"pg_num_target"`
+	PGNumFinal    int    `json:"pg_num_final"`
+}
+
 type PoolStatistics struct {
 	Images struct {
 		Count            int `json:"count"`
@@ -320,7 +337,7 @@ func setCommonPoolProperties(context *clusterd.Context, clusterInfo *ClusterInfo
 	// If the pool is mirrored, let's enable mirroring
 	// we don't need to check if the pool is erasure coded or not, mirroring will still work, it will simply be slow
 	if pool.Mirroring.Enabled {
-		err := enablePoolMirroring(context, clusterInfo, pool)
+		err := enablePoolMirroring(context, clusterInfo, pool, nil)
 		if err != nil {
 			return errors.Wrapf(err, "failed to enable mirroring for pool %q", pool.Name)
 		}
@@ -398,11 +415,22 @@ func GetErasureCodeProfileForPool(baseName string) string {
 
 func createECPoolForApp(context *clusterd.Context, clusterInfo *ClusterInfo, ecProfileName string, pool cephv1.NamedPoolSpec, pgCount string, enableECOverwrite bool) error {
 	args := []string{"osd", "pool", "create", pool.Name, pgCount, "erasure", ecProfileName}
+	if pool.CrushRule != "" {
+		// The custom crush rule is created by the CephCrushRule controller
+		args = append(args, pool.CrushRule)
+	}
 	output, err := NewCephCommand(context, clusterInfo, args).Run()
 	if err != nil {
 		return errors.Wrapf(err, "failed to create EC pool %s. %s", pool.Name, string(output))
 	}
 
+	if pool.CrushRule != "" {
+		// the crush rule is not changed when the pool already exists
+		if err := setPoolCustomCrushRule(context, clusterInfo, pool.Name, pool.CrushRule); err != nil {
+			return err
+		}
+	}
+
 	if enableECOverwrite {
 		if err = SetPoolProperty(context, clusterInfo, pool.Name, "allow_ec_overwrites", "true"); err != nil {
 			return errors.Wrapf(err, "failed to allow EC overwrite for pool %s", pool.Name)
@@ -423,11 +451,14 @@ func createReplicatedPoolForApp(context *clusterd.Context, clusterInfo *ClusterI
 
 	// The crush rule name is the same as the pool unless we have a stretch cluster.
 	crushRuleName := pool.Name
-	if clusterSpec.IsStretchCluster() {
+	if clusterSpec.StretchModeEnabled() {
 		// A stretch cluster enforces using the same crush rule for all pools.
 		// The stretch cluster rule is created initially by the operator when the stretch cluster is configured
 		// so there is no need to create a new crush rule for the pools here.
 		crushRuleName = defaultStretchCrushRuleName
+	} else if pool.CrushRule != "" {
+		// The custom crush rule is created by the CephCrushRule controller
+		crushRuleName = pool.CrushRule
 	} else if pool.IsHybridStoragePool() {
 		// Create hybrid crush rule
 		err := createHybridCrushRule(context, clusterInfo, clusterSpec, crushRuleName, pool.PoolSpec)
@@ -464,12 +495,18 @@ func createReplicatedPoolForApp(context *clusterd.Context, clusterInfo *ClusterI
 		}
 	} else {
 		// If the pool is type replicated, set the size for the pool if it changed
-		if !clusterSpec.IsStretchCluster() && pool.IsReplicated() && poolDetails.Size != pool.Replicated.Size {
+		if !clusterSpec.StretchModeEnabled() && pool.IsReplicated() && poolDetails.Size != pool.Replicated.Size {
 			logger.Infof("pool size is changed from %d to %d", poolDetails.Size, pool.Replicated.Size)
 			if err := SetPoolReplicatedSizeProperty(context, clusterInfo, pool.Name, strconv.FormatUint(uint64(pool.Replicated.Size), 10)); err != nil {
 				return errors.Wrapf(err, "failed to set size property to replicated pool %q to %d", pool.Name, pool.Replicated.Size)
 			}
 		}
+		if pool.CrushRule != "" && poolDetails.CrushRule != pool.CrushRule {
+			logger.Infof("updating crush rule of pool %q from %q to %q", pool.Name, poolDetails.CrushRule, pool.CrushRule)
+			if err := setCrushRule(context, clusterInfo, pool.Name, pool.CrushRule); err != nil {
+				return err
+			}
+		}
 	}
 
 	// update the common pool properties
@@ -479,14 +516,127 @@ func createReplicatedPoolForApp(context *clusterd.Context, clusterInfo *ClusterI
 
 	logger.Infof("reconciling replicated pool %s succeeded", pool.Name)
 
-	if checkFailureDomain || pool.PoolSpec.DeviceClass != "" {
+	if pool.CrushRule == "" && (checkFailureDomain || pool.PoolSpec.DeviceClass != "") {
 		if err = updatePoolCrushRule(context, clusterInfo, clusterSpec, pool); err != nil {
 			return nil
 		}
 	}
+
+	// the stretch settings refer to the crush rule and the size of the pool, which are set above
+	if err := reconcilePoolStretch(context, clusterInfo, clusterSpec, pool); err != nil {
+		return err
+	}
+	return nil
+}
+
+// PoolStretchDetails are the stretch settings of a pool
+type PoolStretchDetails struct {
+	IsStretchPool             bool   `json:"is_stretch_pool"`
+	PeeringCrushBucketCount   int    `json:"peering_crush_bucket_count"`
+	PeeringCrushBucketTarget  int    `json:"peering_crush_bucket_target"`
+	PeeringCrushBucketBarrier string `json:"peering_crush_bucket_barrier"`
+	CrushRule                 string `json:"crush_rule"`
+	Size                      uint   `json:"size"`
+	MinSize                   uint   `json:"min_size"`
+}
+
+// GetPoolStretch returns the stretch settings of a pool. A pool that is not stretched returns
+// empty settings.
+func GetPoolStretch(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (PoolStretchDetails, error) {
+	args := []string{"osd", "pool", "stretch", "show", poolName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		if code, ok := exec.ExitStatus(err); ok && code == int(syscall.ENOENT) {
+			// ENOENT is returned when the pool is not a stretch pool
+			return PoolStretchDetails{}, nil
+		}
+		return PoolStretchDetails{}, errors.Wrapf(err, "failed to get the stretch settings of pool %q. %s", poolName, string(output))
+	}
+	var details PoolStretchDetails
+	if err := json.Unmarshal(output, &details); err != nil {
+		return PoolStretchDetails{}, errors.Wrapf(err, "failed to unmarshal the stretch settings of pool %q. %s", poolName, string(output))
+	}
+	return details, nil
+}
+
+// SetPoolStretch stretches a pool across the crush buckets of the barrier type
+func SetPoolStretch(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, stretch PoolStretchDetails) error {
+	logger.Infof("setting the stretch settings of pool %q to %d/%d %q buckets", poolName, stretch.PeeringCrushBucketCount, stretch.PeeringCrushBucketTarget, stretch.PeeringCrushBucketBarrier)
+	args := []string{"osd", "pool", "stretch", "set", poolName,
+		strconv.Itoa(stretch.PeeringCrushBucketCount), strconv.Itoa(stretch.PeeringCrushBucketTarget), stretch.PeeringCrushBucketBarrier,
+		stretch.CrushRule, strconv.FormatUint(uint64(stretch.Size), 10), strconv.FormatUint(uint64(stretch.MinSize), 10),
+		confirmFlag}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set the stretch settings of pool %q. %s", poolName, string(output))
+	}
 	return nil
 }
 
+// UnsetPoolStretch removes the stretch settings of a pool
+func UnsetPoolStretch(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) error {
+	logger.Infof("removing the stretch settings of pool %q", poolName)
+	args := []string{"osd", "pool", "stretch", "unset", poolName}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove the stretch settings of pool %q. %s", poolName, string(output))
+	}
+	return nil
+}
+
+// reconcilePoolStretch applies the stretch settings of a pool, or removes them when the pool is not
+// stretched anymore. Stretch pools are only supported outside of the ceph stretch mode.
+func reconcilePoolStretch(context *clusterd.Context, clusterInfo *ClusterInfo, clusterSpec *cephv1.ClusterSpec, pool cephv1.NamedPoolSpec) error {
+	if clusterSpec.StretchModeEnabled() || !clusterInfo.CephVersion.IsAtLeast(cephver.Squid) {
+		return nil
+	}
+	current, err := GetPoolStretch(context, clusterInfo, pool.Name)
+	if err != nil {
+		if pool.Stretch == nil {
+			logger.Debugf("skipping the stretch settings of pool %q. %v", pool.Name, err)
+			return nil
+		}
+		return err
+	}
+	if pool.Stretch == nil {
+		if !current.IsStretchPool {
+			return nil
+		}
+		return UnsetPoolStretch(context, clusterInfo, pool.Name)
+	}
+
+	details, err := GetPoolDetails(context, clusterInfo, pool.Name)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get pool %q details", pool.Name)
+	}
+	desired := PoolStretchDetails{
+		IsStretchPool:             true,
+		PeeringCrushBucketCount:   pool.Stretch.PeeringCrushBucketCount,
+		PeeringCrushBucketTarget:  pool.Stretch.PeeringCrushBucketTarget,
+		PeeringCrushBucketBarrier: pool.Stretch.PeeringCrushBucketBarrier,
+		CrushRule:                 details.CrushRule,
+		Size:                      details.Size,
+		MinSize:                   details.MinSize,
+	}
+	if desired.PeeringCrushBucketTarget == 0 {
+		desired.PeeringCrushBucketTarget = desired.PeeringCrushBucketCount
+	}
+	if desired.PeeringCrushBucketBarrier == "" {
+		desired.PeeringCrushBucketBarrier = "zone"
+		if clusterSpec.IsStretchCluster() && clusterSpec.Mon.StretchCluster.FailureDomainLabel != "" {
+			// the failure domain label is a node label, the crush bucket type is the name of the label
+			// without its prefix such as "zone" for "topology.kubernetes.io/zone"
+			label := clusterSpec.Mon.StretchCluster.FailureDomainLabel
+			desired.PeeringCrushBucketBarrier = label[strings.LastIndex(label, "/")+1:]
+		}
+	}
+	if current == desired {
+		logger.Debugf("pool %q stretch settings are already set", pool.Name)
+		return nil
+	}
+	return SetPoolStretch(context, clusterInfo, pool.Name, desired)
+}
+
 func updatePoolCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, clusterSpec *cephv1.ClusterSpec, pool cephv1.NamedPoolSpec) error {
 	if pool.FailureDomain == "" && pool.DeviceClass == "" {
 		logger.Debugf("skipping check for failure domain and deviceClass on pool %q as it is not specified", pool.Name)
@@ -575,6 +725,19 @@ func setCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, poolName,
 	return nil
 }
 
+func setPoolCustomCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, crushRule string) error {
+	details, err := GetPoolDetails(context, clusterInfo, poolName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get pool %q details", poolName)
+	}
+	if details.CrushRule == crushRule {
+		return nil
+	}
+
+	logger.Infof("updating crush rule of pool %q from %q to %q", poolName, details.CrushRule, crushRule)
+	return setCrushRule(context, clusterInfo, poolName, crushRule)
+}
+
 func createStretchCrushRule(context *clusterd.Context, clusterInfo *ClusterInfo, clusterSpec *cephv1.ClusterSpec, ruleName string, pool cephv1.PoolSpec) error {
 	// set the crush root to the default if not already specified
 	if pool.CrushRoot == "" {
@@ -782,6 +945,22 @@ func GetPoolStats(context *clusterd.Context, clusterInfo *ClusterInfo) (*CephSto
 	return &poolStats, nil
 }
 
+// GetPoolAutoscaleStatus returns the status of the PG autoscaler for all the pools
+func GetPoolAutoscaleStatus(context *clusterd.Context, clusterInfo *ClusterInfo) ([]PoolAutoscaleStatus, error) {
+	args := []string{"osd", "pool", "autoscale-status"}
+	output, err := NewCephCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to get pool autoscale status")
+	}
+
+	var status []PoolAutoscaleStatus
+	if err := json.Unmarshal(output, &status); err != nil {
+		return nil, errors.Wrap(err, "failed to unmarshal pool autoscale status response")
+	}
+
+	return status, nil
+}
+
 func GetPoolStatistics(context *clusterd.Context, clusterInfo *ClusterInfo, name string) (*PoolStatistics, error) {
 	args := []string{"pool", "stats", name}
 	cmd := NewRBDCommand(context, clusterInfo, args)
@@ -20,6 +20,8 @@ import (
 	"os/exec"
 	"reflect"
 	"strconv"
+	"strings"
+	"syscall"
 	"testing"
 
 	"golang.org/x/exp/slices"
@@ -27,6 +29,7 @@ import (
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 )
@@ -605,3 +608,155 @@ func hasCrushtool() bool {
 	_, err := exec.LookPath("crushtool")
 	return err == nil
 }
+
+func TestCreatePoolWithCustomCrushRule(t *testing.T) {
+	poolExists := false
+	currentCrushRule := "other-rule"
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[1] == "pool" {
+			switch args[2] {
+			case "get":
+				if !poolExists {
+					return "", errors.New("pool not found")
+				}
+				return fmt.Sprintf(`{"pool":"mypool","size":3}{"pool":"mypool","crush_rule":%q}`, currentCrushRule), nil
+			case "application":
+				if args[3] == "get" {
+					return `{"rbd":{}}`, nil
+				}
+			case "create", "set":
+				command := strings.Join(args[2:], " ")
+				commands = append(commands, command[:strings.Index(command, " --connect-timeout")])
+				return "", nil
+			}
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	clusterSpec := &cephv1.ClusterSpec{}
+	p := cephv1.NamedPoolSpec{
+		Name: "mypool",
+		PoolSpec: cephv1.PoolSpec{
+			FailureDomain: "host", CrushRule: "my-rule", Application: "rbd",
+			Replicated: cephv1.ReplicatedSpec{Size: 3},
+		},
+	}
+
+	t.Run("new replicated pool", func(t *testing.T) {
+		commands = nil
+		err := createReplicatedPoolForApp(context, AdminTestClusterInfo("mycluster"), clusterSpec, p, DefaultPGCount)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"create mypool 0 replicated my-rule --size 3"}, commands)
+	})
+
+	t.Run("existing replicated pool", func(t *testing.T) {
+		commands = nil
+		poolExists = true
+		err := createReplicatedPoolForApp(context, AdminTestClusterInfo("mycluster"), clusterSpec, p, DefaultPGCount)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"set mypool crush_rule my-rule"}, commands)
+
+		commands = nil
+		currentCrushRule = "my-rule"
+		err = createReplicatedPoolForApp(context, AdminTestClusterInfo("mycluster"), clusterSpec, p, DefaultPGCount)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("erasure coded pool", func(t *testing.T) {
+		commands = nil
+		currentCrushRule = "other-rule"
+		err := createECPoolForApp(context, AdminTestClusterInfo("mycluster"), "mypoolprofile", p, DefaultPGCount, false)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"create mypool 0 erasure mypoolprofile my-rule", "set mypool crush_rule my-rule"}, commands)
+	})
+}
+
+func TestReconcilePoolStretch(t *testing.T) {
+	var stretchArgs []string
+	stretchShow := ""
+	executor := &exectest.MockExecutor{}
+	context := &clusterd.Context{Executor: executor}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		logger.Infof("Command: %s %v", command, args)
+		if args[0] == "osd" && args[1] == "pool" {
+			if args[2] == "stretch" {
+				if args[3] == "show" {
+					if stretchShow == "" {
+						return "Error ENOENT: pool mypool is not a stretch pool", syscall.ENOENT
+					}
+					return stretchShow, nil
+				}
+				// drop the connection flags appended to all the ceph commands
+				stretchArgs = args[3:slices.IndexFunc(args, func(arg string) bool { return strings.HasPrefix(arg, "--connect-timeout") })]
+				return "", nil
+			}
+			if args[2] == "get" {
+				return `{"pool":"mypool","size":4}{"pool":"mypool","min_size":2}{"pool":"mypool","crush_rule":"stretch_rule"}`, nil
+			}
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+	clusterInfo.CephVersion = cephver.Squid
+	clusterSpec := &cephv1.ClusterSpec{}
+	pool := cephv1.NamedPoolSpec{Name: "mypool", PoolSpec: cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 4}}}
+
+	t.Run("pool not stretched", func(t *testing.T) {
+		assert.NoError(t, reconcilePoolStretch(context, clusterInfo, clusterSpec, pool))
+		assert.Nil(t, stretchArgs)
+	})
+
+	t.Run("pool stretched with the default target and barrier", func(t *testing.T) {
+		pool.Stretch = &cephv1.StretchPoolSpec{PeeringCrushBucketCount: 2}
+		assert.NoError(t, reconcilePoolStretch(context, clusterInfo, clusterSpec, pool))
+		assert.Equal(t, []string{"set", "mypool", "2", "2", "zone", "stretch_rule", "4", "2", "--yes-i-really-mean-it"}, stretchArgs)
+	})
+
+	t.Run("stretch settings unchanged", func(t *testing.T) {
+		stretchArgs = nil
+		stretchShow = `{"pool":"mypool","is_stretch_pool":true,"peering_crush_bucket_count":2,"peering_crush_bucket_target":2,"peering_crush_bucket_barrier":"zone","crush_rule":"stretch_rule","size":4,"min_size":2}`
+		assert.NoError(t, reconcilePoolStretch(context, clusterInfo, clusterSpec, pool))
+		assert.Nil(t, stretchArgs)
+	})
+
+	t.Run("barrier from the stretch cluster failure domain", func(t *testing.T) {
+		stretchClusterSpec := &cephv1.ClusterSpec{Mon: cephv1.MonSpec{StretchCluster: &cephv1.StretchClusterSpec{
+			Mode:               cephv1.StretchModePools,
+			FailureDomainLabel: "datacenter",
+			Zones:              []cephv1.MonZoneSpec{{Name: "a"}, {Name: "b"}, {Name: "c"}},
+		}}}
+		assert.NoError(t, reconcilePoolStretch(context, clusterInfo, stretchClusterSpec, pool))
+		assert.Equal(t, []string{"set", "mypool", "2", "2", "datacenter", "stretch_rule", "4", "2", "--yes-i-really-mean-it"}, stretchArgs)
+
+		// the crush bucket type is the name of the node label without its prefix
+		stretchArgs = nil
+		stretchShow = `{"pool":"mypool","is_stretch_pool":true,"peering_crush_bucket_count":2,"peering_crush_bucket_target":2,"peering_crush_bucket_barrier":"datacenter","crush_rule":"stretch_rule","size":4,"min_size":2}`
+		stretchClusterSpec.Mon.StretchCluster.FailureDomainLabel = "topology.kubernetes.io/zone"
+		assert.NoError(t, reconcilePoolStretch(context, clusterInfo, stretchClusterSpec, pool))
+		assert.Equal(t, []string{"set", "mypool", "2", "2", "zone", "stretch_rule", "4", "2", "--yes-i-really-mean-it"}, stretchArgs)
+
+		// pools are not stretched individually in the ceph stretch mode
+		stretchArgs = nil
+		stretchClusterSpec.Mon.StretchCluster.Mode = cephv1.StretchModeCluster
+		assert.NoError(t, reconcilePoolStretch(context, clusterInfo, stretchClusterSpec, pool))
+		assert.Nil(t, stretchArgs)
+	})
+
+	t.Run("stretch settings removed", func(t *testing.T) {
+		pool.Stretch = nil
+		assert.NoError(t, reconcilePoolStretch(context, clusterInfo, clusterSpec, pool))
+		assert.Equal(t, []string{"unset", "mypool"}, stretchArgs)
+	})
+
+	t.Run("not supported before squid", func(t *testing.T) {
+		stretchArgs = nil
+		clusterInfo.CephVersion = cephver.Reef
+		pool.Stretch = &cephv1.StretchPoolSpec{PeeringCrushBucketCount: 3}
+		assert.NoError(t, reconcilePoolStretch(context, clusterInfo, clusterSpec, pool))
+		assert.Nil(t, stretchArgs)
+	})
+}
@@ -0,0 +1,194 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"strconv"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	rbdQoSOptionPrefix = "rbd_qos_"
+	// the source of the options overridden at the pool level, which includes the rados namespaces
+	rbdConfigSourcePool = "pool"
+)
+
+// rbdConfigOption is a configuration option returned by 'rbd config pool list'
+type rbdConfigOption struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Source string `json:"source"`
+}
+
+// RBDQoSOptions converts the QoS spec to the values of the rbd_qos options. An option is 0 when the
+// limit is not set.
+func RBDQoSOptions(qos *cephv1.RBDQoSSpec) (map[string]uint64, error) {
+	options := map[string]uint64{
+		"rbd_qos_iops_limit":       qos.IOPSLimit,
+		"rbd_qos_iops_burst":       qos.IOPSBurst,
+		"rbd_qos_read_iops_limit":  qos.ReadIOPSLimit,
+		"rbd_qos_read_iops_burst":  qos.ReadIOPSBurst,
+		"rbd_qos_write_iops_limit": qos.WriteIOPSLimit,
+		"rbd_qos_write_iops_burst": qos.WriteIOPSBurst,
+	}
+	bps := map[string]string{
+		"rbd_qos_bps_limit":       qos.BPSLimit,
+		"rbd_qos_bps_burst":       qos.BPSBurst,
+		"rbd_qos_read_bps_limit":  qos.ReadBPSLimit,
+		"rbd_qos_read_bps_burst":  qos.ReadBPSBurst,
+		"rbd_qos_write_bps_limit": qos.WriteBPSLimit,
+		"rbd_qos_write_bps_burst": qos.WriteBPSBurst,
+	}
+	for option, value := range bps {
+		if value == "" {
+			options[option] = 0
+			continue
+		}
+		quantity, err := resource.ParseQuantity(value)
+		if err != nil {
+			return nil, errors.Wrapf(err, "invalid value %q of %s", value, option)
+		}
+		options[option] = uint64(quantity.Value())
+	}
+
+	return options, nil
+}
+
+// ApplyRBDQoS sets the QoS options of the RBD images of a pool or a rados namespace and returns the
+// effective limits. poolName is either a pool or a rados namespace in the form pool/namespace. The
+// options are set at the pool level and the options of the limits that are not set are removed.
+func ApplyRBDQoS(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string, qos *cephv1.RBDQoSSpec) (map[string]string, error) {
+	desired, err := RBDQoSOptions(qos)
+	if err != nil {
+		return nil, err
+	}
+	current, err := getRBDPoolConfig(context, clusterInfo, poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	changed := false
+	for option, value := range desired {
+		setting, ok := current[option]
+		overridden := ok && setting.Source == rbdConfigSourcePool
+		if value == 0 {
+			if overridden {
+				if err := removeRBDPoolConfig(context, clusterInfo, poolName, option); err != nil {
+					return nil, err
+				}
+				changed = true
+			}
+			continue
+		}
+		if overridden && setting.Value == strconv.FormatUint(value, 10) {
+			continue
+		}
+		if err := setRBDPoolConfig(context, clusterInfo, poolName, option, strconv.FormatUint(value, 10)); err != nil {
+			return nil, err
+		}
+		changed = true
+	}
+	if changed {
+		logger.Infof("successfully applied the qos of the rbd images of pool %q", poolName)
+		if current, err = getRBDPoolConfig(context, clusterInfo, poolName); err != nil {
+			return nil, err
+		}
+	}
+
+	return effectiveRBDQoS(current), nil
+}
+
+// RemoveRBDQoS removes the QoS options set at the level of a pool or a rados namespace, so the RBD
+// images fall back to the limits of the cluster configuration
+func RemoveRBDQoS(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) error {
+	if _, err := ApplyRBDQoS(context, clusterInfo, poolName, &cephv1.RBDQoSSpec{}); err != nil {
+		return errors.Wrapf(err, "failed to remove the qos of pool %q", poolName)
+	}
+	return nil
+}
+
+// GetRBDQoS returns the effective QoS limits of the RBD images of a pool or a rados namespace
+func GetRBDQoS(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (map[string]string, error) {
+	current, err := getRBDPoolConfig(context, clusterInfo, poolName)
+	if err != nil {
+		return nil, err
+	}
+	return effectiveRBDQoS(current), nil
+}
+
+// effectiveRBDQoS returns the rbd_qos limits and bursts that are not unlimited
+func effectiveRBDQoS(config map[string]rbdConfigOption) map[string]string {
+	limits := map[string]string{}
+	for name, option := range config {
+		if !strings.HasSuffix(name, "_limit") && !strings.HasSuffix(name, "_burst") {
+			continue
+		}
+		if option.Value != "" && option.Value != "0" {
+			limits[name] = option.Value
+		}
+	}
+	return limits
+}
+
+// getRBDPoolConfig returns the rbd_qos options of a pool or a rados namespace
+func getRBDPoolConfig(context *clusterd.Context, clusterInfo *ClusterInfo, poolName string) (map[string]rbdConfigOption, error) {
+	args := []string{"config", "pool", "list", poolName}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the rbd config of pool %q. %s", poolName, string(buf))
+	}
+
+	var options []rbdConfigOption
+	if err := json.Unmarshal(buf, &options); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the rbd config of pool %q", poolName)
+	}
+	config := map[string]rbdConfigOption{}
+	for _, option := range options {
+		if strings.HasPrefix(option.Name, rbdQoSOptionPrefix) {
+			config[option.Name] = option
+		}
+	}
+	return config, nil
+}
+
+func setRBDPoolConfig(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, option, value string) error {
+	logger.Debugf("setting rbd config %s=%s on pool %q", option, value, poolName)
+	args := []string{"config", "pool", "set", poolName, option, value}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to set rbd config %s=%s on pool %q. %s", option, value, poolName, string(buf))
+	}
+	return nil
+}
+
+func removeRBDPoolConfig(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, option string) error {
+	logger.Debugf("removing rbd config %s from pool %q", option, poolName)
+	args := []string{"config", "pool", "remove", poolName, option}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove rbd config %s from pool %q. %s", option, poolName, string(buf))
+	}
+	return nil
+}
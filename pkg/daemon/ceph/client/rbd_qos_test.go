@@ -0,0 +1,111 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRBDQoSOptions(t *testing.T) {
+	options, err := RBDQoSOptions(&cephv1.RBDQoSSpec{IOPSLimit: 500, WriteIOPSBurst: 1000, BPSLimit: "100Mi", ReadBPSBurst: "1G"})
+	assert.NoError(t, err)
+	assert.Equal(t, 12, len(options))
+	assert.Equal(t, uint64(500), options["rbd_qos_iops_limit"])
+	assert.Equal(t, uint64(1000), options["rbd_qos_write_iops_burst"])
+	assert.Equal(t, uint64(100*1024*1024), options["rbd_qos_bps_limit"])
+	assert.Equal(t, uint64(1000000000), options["rbd_qos_read_bps_burst"])
+	assert.Equal(t, uint64(0), options["rbd_qos_write_bps_limit"])
+
+	_, err = RBDQoSOptions(&cephv1.RBDQoSSpec{BPSLimit: "fast"})
+	assert.ErrorContains(t, err, `invalid value "fast" of rbd_qos_bps_limit`)
+}
+
+func TestApplyRBDQoS(t *testing.T) {
+	// the pool level config starts with an iops limit and a bps limit that is no longer desired
+	config := map[string]rbdConfigOption{
+		"rbd_qos_iops_limit":         {Name: "rbd_qos_iops_limit", Value: "100", Source: "pool"},
+		"rbd_qos_bps_limit":          {Name: "rbd_qos_bps_limit", Value: "1024", Source: "pool"},
+		"rbd_qos_read_iops_limit":    {Name: "rbd_qos_read_iops_limit", Value: "50", Source: "config"},
+		"rbd_qos_write_iops_limit":   {Name: "rbd_qos_write_iops_limit", Value: "0", Source: "config"},
+		"rbd_qos_iops_burst_seconds": {Name: "rbd_qos_iops_burst_seconds", Value: "1", Source: "config"},
+		"rbd_cache":                  {Name: "rbd_cache", Value: "true", Source: "config"},
+	}
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] != "config" || args[1] != "pool" {
+			return "", errors.New("unknown command")
+		}
+		assert.Equal(t, "replicapool/ns-a", args[3])
+		switch args[2] {
+		case "list":
+			options := []rbdConfigOption{}
+			for _, option := range config {
+				options = append(options, option)
+			}
+			b, err := json.Marshal(options)
+			return string(b), err
+		case "set":
+			config[args[4]] = rbdConfigOption{Name: args[4], Value: args[5], Source: "pool"}
+		case "remove":
+			config[args[4]] = rbdConfigOption{Name: args[4], Value: "0", Source: "config"}
+		}
+		commands = append(commands, strings.Split(strings.Join(args[2:], " "), " --cluster")[0])
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+	qos := &cephv1.RBDQoSSpec{IOPSLimit: 200, WriteIOPSLimit: 20}
+
+	limits, err := ApplyRBDQoS(context, clusterInfo, "replicapool/ns-a", qos)
+	assert.NoError(t, err)
+	sort.Strings(commands)
+	assert.Equal(t, []string{
+		"remove replicapool/ns-a rbd_qos_bps_limit",
+		"set replicapool/ns-a rbd_qos_iops_limit 200",
+		"set replicapool/ns-a rbd_qos_write_iops_limit 20",
+	}, commands)
+	assert.Equal(t, map[string]string{
+		"rbd_qos_iops_limit":       "200",
+		"rbd_qos_read_iops_limit":  "50",
+		"rbd_qos_write_iops_limit": "20",
+	}, limits)
+
+	// nothing is changed once the limits are applied
+	commands = nil
+	limits, err = ApplyRBDQoS(context, clusterInfo, "replicapool/ns-a", qos)
+	assert.NoError(t, err)
+	assert.Empty(t, commands)
+	assert.Equal(t, 3, len(limits))
+	// only the options overridden at the pool level are removed
+	commands = nil
+	assert.NoError(t, RemoveRBDQoS(context, clusterInfo, "replicapool/ns-a"))
+	sort.Strings(commands)
+	assert.Equal(t, []string{
+		"remove replicapool/ns-a rbd_qos_iops_limit",
+		"remove replicapool/ns-a rbd_qos_write_iops_limit",
+	}, commands)
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+)
+
+const (
+	// ScheduledSnapshotPrefix is the prefix of the names of the snapshots taken by the schedule
+	ScheduledSnapshotPrefix = "rook-scheduled-"
+	// the timestamp of the scheduled snapshot names, which sorts in chronological order
+	scheduledSnapshotTimeFormat = "20060102-150405"
+)
+
+// RBDSnapshot is a snapshot of an RBD image as returned by 'rbd snap ls'
+type RBDSnapshot struct {
+	ID        uint64 `json:"id"`
+	Name      string `json:"name"`
+	Size      uint64 `json:"size"`
+	Protected string `json:"protected"`
+	Timestamp string `json:"timestamp"`
+}
+
+// ScheduledSnapshotName returns the name of the scheduled snapshots taken at the given time
+func ScheduledSnapshotName(t time.Time) string {
+	return ScheduledSnapshotPrefix + t.UTC().Format(scheduledSnapshotTimeFormat)
+}
+
+// ListImageSnapshots lists the user snapshots of an image. imageSpec is in the form
+// pool[/namespace]/image.
+func ListImageSnapshots(context *clusterd.Context, clusterInfo *ClusterInfo, imageSpec string) ([]RBDSnapshot, error) {
+	args := []string{"snap", "ls", imageSpec}
+	cmd := NewRBDCommand(context, clusterInfo, args)
+	cmd.JsonOutput = true
+	buf, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to list the snapshots of image %q. %s", imageSpec, string(buf))
+	}
+
+	var snapshots []RBDSnapshot
+	if err := json.Unmarshal(buf, &snapshots); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal the snapshots of image %q", imageSpec)
+	}
+	return snapshots, nil
+}
+
+// CreateImageSnapshot creates a snapshot of an image
+func CreateImageSnapshot(context *clusterd.Context, clusterInfo *ClusterInfo, imageSpec, snapName string) error {
+	snapSpec := fmt.Sprintf("%s@%s", imageSpec, snapName)
+	args := []string{"snap", "create", snapSpec}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to create snapshot %q. %s", snapSpec, string(buf))
+	}
+	logger.Debugf("created snapshot %q", snapSpec)
+	return nil
+}
+
+// RemoveImageSnapshot removes a snapshot of an image
+func RemoveImageSnapshot(context *clusterd.Context, clusterInfo *ClusterInfo, imageSpec, snapName string) error {
+	snapSpec := fmt.Sprintf("%s@%s", imageSpec, snapName)
+	args := []string{"snap", "rm", snapSpec}
+	buf, err := NewRBDCommand(context, clusterInfo, args).Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove snapshot %q. %s", snapSpec, string(buf))
+	}
+	logger.Debugf("removed snapshot %q", snapSpec)
+	return nil
+}
+
+// CreateScheduledSnapshots takes a snapshot named snapName of every image of a pool or a rados
+// namespace and removes the oldest scheduled snapshots of each image beyond the retention.
+// poolName is either a pool or a rados namespace in the form pool/namespace. A failure on an image
+// does not prevent the snapshots of the other images, the failures are returned together with the
+// status of the run.
+func CreateScheduledSnapshots(context *clusterd.Context, clusterInfo *ClusterInfo, poolName, snapName string, retention int) (*cephv1.RBDScheduledSnapshotsStatus, error) {
+	images, err := ListImages(context, clusterInfo, poolName)
+	if err != nil {
+		return nil, err
+	}
+
+	status := &cephv1.RBDScheduledSnapshotsStatus{LastSnapshotName: snapName}
+	var failures []string
+	seen := map[string]bool{}
+	for _, image := range images {
+		// the long listing has an entry for each snapshot of an image
+		if seen[image.Name] {
+			continue
+		}
+		seen[image.Name] = true
+		status.Images++
+
+		imageSpec := getImageSpec(image.Name, poolName)
+		if err := CreateImageSnapshot(context, clusterInfo, imageSpec, snapName); err != nil {
+			failures = append(failures, err.Error())
+			continue
+		}
+		status.SnapshotsCreated++
+
+		removed, err := pruneScheduledSnapshots(context, clusterInfo, imageSpec, retention)
+		status.SnapshotsRemoved += removed
+		if err != nil {
+			failures = append(failures, err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return status, errors.Errorf("failed to take the scheduled snapshots of %d images of pool %q. %s", len(failures), poolName, strings.Join(failures, "; "))
+	}
+	logger.Infof("took scheduled snapshot %q of %d images of pool %q", snapName, status.SnapshotsCreated, poolName)
+	return status, nil
+}
+
+// pruneScheduledSnapshots removes the oldest scheduled snapshots of an image beyond the retention
+// and returns the number of removed snapshots
+func pruneScheduledSnapshots(context *clusterd.Context, clusterInfo *ClusterInfo, imageSpec string, retention int) (int, error) {
+	snapshots, err := ListImageSnapshots(context, clusterInfo, imageSpec)
+	if err != nil {
+		return 0, err
+	}
+	var scheduled []string
+	for _, snapshot := range snapshots {
+		if strings.HasPrefix(snapshot.Name, ScheduledSnapshotPrefix) {
+			scheduled = append(scheduled, snapshot.Name)
+		}
+	}
+	if len(scheduled) <= retention {
+		return 0, nil
+	}
+
+	sort.Strings(scheduled)
+	removed := 0
+	for _, snapName := range scheduled[:len(scheduled)-retention] {
+		if err := RemoveImageSnapshot(context, clusterInfo, imageSpec, snapName); err != nil {
+			return removed, err
+		}
+		removed++
+	}
+	return removed, nil
+}
@@ -0,0 +1,98 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledSnapshotName(t *testing.T) {
+	now := time.Date(2024, 3, 1, 14, 5, 9, 0, time.UTC)
+	assert.Equal(t, "rook-scheduled-20240301-140509", ScheduledSnapshotName(now))
+}
+
+func TestCreateScheduledSnapshots(t *testing.T) {
+	snapshots := map[string][]string{
+		"replicapool/ns-a/img1": {"rook-scheduled-20240101-000000", "manual", "rook-scheduled-20240102-000000"},
+		"replicapool/ns-a/img2": {},
+		"replicapool/ns-a/img3": {},
+	}
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "ls" {
+			assert.Equal(t, "replicapool/ns-a", args[2])
+			// the long listing has an entry for each snapshot
+			return `[{"image":"img1","size":1024,"format":2},{"image":"img1","snapshot":"manual","size":1024,"format":2},` +
+				`{"image":"img2","size":1024,"format":2},{"image":"img3","size":1024,"format":2}]`, nil
+		}
+		if args[0] != "snap" {
+			return "", errors.New("unknown command")
+		}
+		switch args[1] {
+		case "ls":
+			var list []RBDSnapshot
+			for i, name := range snapshots[args[2]] {
+				list = append(list, RBDSnapshot{ID: uint64(i), Name: name})
+			}
+			b, err := json.Marshal(list)
+			return string(b), err
+		case "create":
+			spec := strings.Split(args[2], "@")
+			if spec[0] == "replicapool/ns-a/img3" {
+				return "", errors.New("image is being removed")
+			}
+			snapshots[spec[0]] = append(snapshots[spec[0]], spec[1])
+		case "rm":
+			spec := strings.Split(args[2], "@")
+			var kept []string
+			for _, name := range snapshots[spec[0]] {
+				if name != spec[1] {
+					kept = append(kept, name)
+				}
+			}
+			snapshots[spec[0]] = kept
+		}
+		commands = append(commands, strings.Split(strings.Join(args[1:], " "), " --cluster")[0])
+		return "", nil
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := AdminTestClusterInfo("mycluster")
+
+	status, err := CreateScheduledSnapshots(context, clusterInfo, "replicapool/ns-a", "rook-scheduled-20240103-000000", 2)
+	assert.ErrorContains(t, err, `failed to take the scheduled snapshots of 1 images of pool "replicapool/ns-a"`)
+	assert.ErrorContains(t, err, "image is being removed")
+	assert.Equal(t, 3, status.Images)
+	assert.Equal(t, 2, status.SnapshotsCreated)
+	assert.Equal(t, 1, status.SnapshotsRemoved)
+	assert.Equal(t, "rook-scheduled-20240103-000000", status.LastSnapshotName)
+	assert.Equal(t, []string{
+		"create replicapool/ns-a/img1@rook-scheduled-20240103-000000",
+		"rm replicapool/ns-a/img1@rook-scheduled-20240101-000000",
+		"create replicapool/ns-a/img2@rook-scheduled-20240103-000000",
+	}, commands)
+	// the snapshots that are not scheduled are kept
+	assert.Equal(t, []string{"manual", "rook-scheduled-20240102-000000", "rook-scheduled-20240103-000000"}, snapshots["replicapool/ns-a/img1"])
+}
@@ -17,8 +17,11 @@ limitations under the License.
 package client
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"strconv"
+	"strings"
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -28,10 +31,22 @@ import (
 
 // CreateCephFSSubVolumeGroup create a CephFS subvolume group.
 // volName is the name of the Ceph FS volume, the same as the CephFilesystem CR name.
-func CreateCephFSSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, volName, groupName string) error {
+// svgSpec is optional and carries the size, pool layout and mode of the subvolume group.
+func CreateCephFSSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, volName, groupName string, svgSpec *cephv1.CephFilesystemSubVolumeGroupSpec) error {
 	logger.Infof("creating cephfs %q subvolume group %q", volName, groupName)
-	//  [--pool_layout <data_pool_name>] [--uid <uid>] [--gid <gid>] [--mode <octal_mode>]
+	//  [--size <size_in_bytes>] [--pool_layout <data_pool_name>] [--uid <uid>] [--gid <gid>] [--mode <octal_mode>]
 	args := []string{"fs", "subvolumegroup", "create", volName, groupName}
+	if svgSpec != nil {
+		if svgSpec.Quota != nil {
+			args = append(args, "--size", strconv.FormatInt(svgSpec.Quota.Value(), 10))
+		}
+		if svgSpec.DataPoolName != "" {
+			args = append(args, "--pool_layout", svgSpec.DataPoolName)
+		}
+		if svgSpec.Mode != "" {
+			args = append(args, "--mode", svgSpec.Mode)
+		}
+	}
 	cmd := NewCephCommand(context, clusterInfo, args)
 	cmd.JsonOutput = false
 	output, err := cmd.Run()
@@ -43,6 +58,78 @@ func CreateCephFSSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterI
 	return nil
 }
 
+// SubVolumeGroupUnlimitedSize is the size of a CephFS subvolume group that is not limited by a quota
+const SubVolumeGroupUnlimitedSize = "infinite"
+
+// ResizeCephFSSubVolumeGroup sets the quota of a CephFS subvolume group to the given size, either
+// in bytes or SubVolumeGroupUnlimitedSize to remove the quota.
+func ResizeCephFSSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, volName, groupName, size string) error {
+	logger.Infof("resizing cephfs %q subvolume group %q to %s", volName, groupName, size)
+	args := []string{"fs", "subvolumegroup", "resize", volName, groupName, size}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	output, err := cmd.Run()
+	if err != nil {
+		return errors.Wrapf(err, "failed to resize subvolume group %q. %s", groupName, output)
+	}
+
+	logger.Infof("successfully resized cephfs %q subvolume group %q", volName, groupName)
+	return nil
+}
+
+// GetCephFSSubVolumeGroupPath returns the path of a CephFS subvolume group in the filesystem, e.g. "/volumes/csi".
+func GetCephFSSubVolumeGroupPath(context *clusterd.Context, clusterInfo *ClusterInfo, volName, groupName string) (string, error) {
+	args := []string{"fs", "subvolumegroup", "getpath", volName, groupName}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	cmd.JsonOutput = false
+	output, err := cmd.Run()
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get path of subvolume group %q. %s", groupName, output)
+	}
+
+	return strings.TrimSpace(string(output)), nil
+}
+
+// SubvolumeGroupInfo is the representation Ceph returns when getting the info of a subvolume group.
+type SubvolumeGroupInfo struct {
+	BytesUsed uint64 `json:"bytes_used"`
+	// BytesQuota is either the quota in bytes or "infinite" if the group size is not limited
+	BytesQuota   interface{} `json:"bytes_quota"`
+	BytesPercent string      `json:"bytes_pcent"`
+	DataPool     string      `json:"data_pool"`
+}
+
+// GetCephFSSubVolumeGroupInfo returns the info of a CephFS subvolume group.
+func GetCephFSSubVolumeGroupInfo(context *clusterd.Context, clusterInfo *ClusterInfo, volName, groupName string) (*SubvolumeGroupInfo, error) {
+	args := []string{"fs", "subvolumegroup", "info", volName, groupName}
+	cmd := NewCephCommand(context, clusterInfo, args)
+	output, err := cmd.Run()
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get info of subvolume group %q. %s", groupName, output)
+	}
+
+	var info SubvolumeGroupInfo
+	// decode numbers as json.Number so that large quotas don't lose precision
+	decoder := json.NewDecoder(bytes.NewReader(output))
+	decoder.UseNumber()
+	if err := decoder.Decode(&info); err != nil {
+		return nil, errors.Wrapf(err, "failed to unmarshal info of subvolume group %q", groupName)
+	}
+	return &info, nil
+}
+
+// Quota returns the quota of the subvolume group in bytes and whether the group size is limited.
+func (i *SubvolumeGroupInfo) Quota() (uint64, bool) {
+	number, ok := i.BytesQuota.(json.Number)
+	if !ok {
+		return 0, false
+	}
+	quota, err := strconv.ParseUint(number.String(), 10, 64)
+	if err != nil {
+		return 0, false
+	}
+	return quota, true
+}
+
 // DeleteCephFSSubVolumeGroup delete a CephFS subvolume group.
 func DeleteCephFSSubVolumeGroup(context *clusterd.Context, clusterInfo *ClusterInfo, volName, groupName string) error {
 	logger.Infof("deleting cephfs %q subvolume group %q", volName, groupName)
@@ -18,9 +18,13 @@ package client
 
 import (
 	"testing"
+	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/api/resource"
 )
 
 func TestValidatePinningValues(t *testing.T) {
@@ -79,3 +83,66 @@ func TestValidatePinningValues(t *testing.T) {
 	err = validatePinningValues(testData1)
 	assert.NoError(t, err)
 }
+
+func TestCreateCephFSSubVolumeGroup(t *testing.T) {
+	var createArgs []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			createArgs = args
+			return "", nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			createArgs = args
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	t.Run("no options", func(t *testing.T) {
+		err := CreateCephFSSubVolumeGroup(context, AdminTestClusterInfo("mycluster"), "myfs", "csi", nil)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fs", "subvolumegroup", "create", "myfs", "csi"}, createArgs[:5])
+		assert.NotContains(t, createArgs, "--size")
+	})
+
+	t.Run("quota, pool layout and mode", func(t *testing.T) {
+		quota := resource.MustParse("10Gi")
+		svgSpec := &cephv1.CephFilesystemSubVolumeGroupSpec{Quota: &quota, DataPoolName: "myfs-replicated", Mode: "755"}
+		err := CreateCephFSSubVolumeGroup(context, AdminTestClusterInfo("mycluster"), "myfs", "group-a", svgSpec)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fs", "subvolumegroup", "create", "myfs", "group-a", "--size", "10737418240", "--pool_layout", "myfs-replicated", "--mode", "755"}, createArgs[:11])
+	})
+}
+
+func TestGetCephFSSubVolumeGroupInfo(t *testing.T) {
+	output := ""
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			return output, nil
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return output, nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+
+	t.Run("unlimited size", func(t *testing.T) {
+		output = `{"bytes_used": 4096, "bytes_quota": "infinite", "bytes_pcent": "undefined", "data_pool": "myfs-data0"}`
+		info, err := GetCephFSSubVolumeGroupInfo(context, AdminTestClusterInfo("mycluster"), "myfs", "csi")
+		assert.NoError(t, err)
+		assert.Equal(t, uint64(4096), info.BytesUsed)
+		assert.Equal(t, "myfs-data0", info.DataPool)
+		_, limited := info.Quota()
+		assert.False(t, limited)
+	})
+
+	t.Run("limited size", func(t *testing.T) {
+		output = `{"bytes_used": 4096, "bytes_quota": 18014398509481984, "bytes_pcent": "0.00", "data_pool": "myfs-data0"}`
+		info, err := GetCephFSSubVolumeGroupInfo(context, AdminTestClusterInfo("mycluster"), "myfs", "csi")
+		assert.NoError(t, err)
+		quota, limited := info.Quota()
+		assert.True(t, limited)
+		assert.Equal(t, uint64(18014398509481984), quota)
+		assert.Equal(t, "0.00", info.BytesPercent)
+	})
+}
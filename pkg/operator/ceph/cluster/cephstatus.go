@@ -42,6 +42,9 @@ import (
 var (
 	// defaultStatusCheckInterval is the interval to check the status of the ceph cluster
 	defaultStatusCheckInterval = 60 * time.Second
+	// balancerScoreInterval is the interval to evaluate the balancer score, which computes the
+	// distribution of all the PGs and is too expensive to run at every status check
+	balancerScoreInterval = time.Hour
 )
 
 // cephStatusChecker aggregates the mon/cluster info needed to check the health of the monitors
@@ -51,6 +54,8 @@ type cephStatusChecker struct {
 	interval    *time.Duration
 	client      client.Client
 	isExternal  bool
+	// balancerScoreChecked is the last time the balancer score was evaluated
+	balancerScoreChecked time.Time
 }
 
 // newCephStatusChecker creates a new HealthChecker object
@@ -198,11 +203,94 @@ func (c *cephStatusChecker) updateCephStatus(status *cephclient.CephStatus, cond
 		cephCluster.Status.CephStatus.Versions = versions
 	}
 
+	// the balancer status is reported when the balancer is configured in the cluster spec
+	if cephCluster.Spec.Balancer != nil && !c.isExternal {
+		cephCluster.Status.CephStatus.Balancer = c.getBalancerStatus(cephCluster.Status, conditionStatus == v1.ConditionTrue)
+	}
+
+	// the status of the mgr modules of the spec is reported with the reason they cannot run
+	if len(cephCluster.Spec.Mgr.Modules) > 0 && !c.isExternal {
+		cephCluster.Status.CephStatus.MgrModules = c.getMgrModulesStatus(cephCluster.Spec.Mgr.Modules, cephCluster.Status, conditionStatus == v1.ConditionTrue)
+	}
+
 	// Update condition
 	logger.Debugf("updating ceph cluster %q status and condition to %+v, %v, %s, %s", clusterName.Namespace, status, conditionStatus, reason, message)
 	opcontroller.UpdateClusterCondition(c.context, cephCluster, c.clusterInfo.NamespacedName(), k8sutil.ObservedGenerationNotAvailable, condition, conditionStatus, reason, message, true)
 }
 
+// getBalancerStatus returns the status of the balancer module. The last known status is kept when
+// the ceph status could not be checked.
+func (c *cephStatusChecker) getBalancerStatus(currentStatus cephv1.ClusterStatus, cephAvailable bool) *cephv1.BalancerStatus {
+	status := &cephv1.BalancerStatus{}
+	if currentStatus.CephStatus != nil && currentStatus.CephStatus.Balancer != nil {
+		status = currentStatus.CephStatus.Balancer.DeepCopy()
+	}
+	if !cephAvailable {
+		return status
+	}
+
+	status.LastChecked = formatTime(time.Now().UTC())
+	status.Details = ""
+	balancer, err := cephclient.GetBalancerStatus(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Errorf("failed to get balancer status. %v", err)
+		status.Details = err.Error()
+		return status
+	}
+	status.Active = balancer.Active
+	status.Mode = balancer.Mode
+	status.LastOptimizationStarted = balancer.LastOptimizeStarted
+	status.LastOptimizationDuration = balancer.LastOptimizeDuration
+	status.OptimizationResult = balancer.OptimizeResult
+
+	if status.Score != "" && time.Since(c.balancerScoreChecked) < balancerScoreInterval {
+		return status
+	}
+	score, err := cephclient.GetBalancerScore(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Errorf("failed to get balancer score. %v", err)
+		status.Details = err.Error()
+		return status
+	}
+	status.Score = score
+	c.balancerScoreChecked = time.Now()
+	return status
+}
+
+// getMgrModulesStatus returns the status of the mgr modules of the spec. The last known status is
+// kept when the ceph status could not be checked.
+func (c *cephStatusChecker) getMgrModulesStatus(modules []cephv1.Module, currentStatus cephv1.ClusterStatus, cephAvailable bool) []cephv1.MgrModuleStatus {
+	var current []cephv1.MgrModuleStatus
+	if currentStatus.CephStatus != nil {
+		current = currentStatus.CephStatus.MgrModules
+	}
+	if !cephAvailable {
+		return current
+	}
+
+	mgrModules, err := cephclient.MgrListModules(c.context, c.clusterInfo)
+	if err != nil {
+		logger.Errorf("failed to list mgr modules. %v", err)
+		return current
+	}
+	enabled := map[string]bool{}
+	for _, name := range append(mgrModules.AlwaysOnModules, mgrModules.EnabledModules...) {
+		enabled[name] = true
+	}
+	moduleErrors := map[string]string{}
+	for _, module := range mgrModules.DisabledModules {
+		if !module.CanRun {
+			moduleErrors[module.Name] = module.ErrorString
+		}
+	}
+
+	status := make([]cephv1.MgrModuleStatus, 0, len(modules))
+	for _, module := range modules {
+		status = append(status, cephv1.MgrModuleStatus{Name: module.Name, Enabled: enabled[module.Name], Error: moduleErrors[module.Name]})
+	}
+	return status
+}
+
 // toCustomResourceStatus converts the ceph status to the struct expected for the CephCluster CR status
 func toCustomResourceStatus(currentStatus cephv1.ClusterStatus, newStatus *cephclient.CephStatus) *cephv1.CephStatus {
 	s := &cephv1.CephStatus{
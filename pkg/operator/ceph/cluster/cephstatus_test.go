@@ -144,9 +144,9 @@ func TestNewCephStatusChecker(t *testing.T) {
 		args args
 		want *cephStatusChecker
 	}{
-		{"default-interval", args{c, clusterInfo, &cephv1.ClusterSpec{}}, &cephStatusChecker{c, clusterInfo, &defaultStatusCheckInterval, c.Client, false}},
-		{"10s-interval", args{c, clusterInfo, &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, false}},
-		{"10s-interval-external", args{c, clusterInfo, &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}, HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, true}},
+		{"default-interval", args{c, clusterInfo, &cephv1.ClusterSpec{}}, &cephStatusChecker{c, clusterInfo, &defaultStatusCheckInterval, c.Client, false, time.Time{}}},
+		{"10s-interval", args{c, clusterInfo, &cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, false, time.Time{}}},
+		{"10s-interval-external", args{c, clusterInfo, &cephv1.ClusterSpec{External: cephv1.ExternalSpec{Enable: true}, HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Status: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: time10s}}}}}}, &cephStatusChecker{c, clusterInfo, &time10s, c.Client, true, time.Time{}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -381,3 +381,81 @@ func TestGetRookPodsOnNode(t *testing.T) {
 	sort.Strings(podNames)
 	assert.Equal(t, expectedPodNames, podNames)
 }
+
+func TestGetBalancerStatus(t *testing.T) {
+	failure := false
+	evaluations := 0
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if failure {
+				return "", errors.New("timed out")
+			}
+			if args[0] == "balancer" && args[1] == "status" {
+				return `{"active":true,"last_optimize_started":"Mon Oct 19 08:00:00 2026","mode":"upmap","optimize_result":"Optimization plan created successfully"}`, nil
+			}
+			if args[0] == "balancer" && args[1] == "eval" {
+				evaluations++
+				return "current cluster score 0.021 (lower is better)", nil
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+	c := newCephStatusChecker(&clusterd.Context{Executor: executor}, cephclient.AdminTestClusterInfo("mycluster"), &cephv1.ClusterSpec{})
+
+	status := c.getBalancerStatus(cephv1.ClusterStatus{}, true)
+	assert.True(t, status.Active)
+	assert.Equal(t, "upmap", status.Mode)
+	assert.Equal(t, "Mon Oct 19 08:00:00 2026", status.LastOptimizationStarted)
+	assert.Equal(t, "Optimization plan created successfully", status.OptimizationResult)
+	assert.Equal(t, "0.021", status.Score)
+	assert.NotEmpty(t, status.LastChecked)
+	assert.Empty(t, status.Details)
+
+	// the last known status is kept when the ceph status is not available
+	current := cephv1.ClusterStatus{CephStatus: &cephv1.CephStatus{Balancer: status}}
+	assert.Equal(t, status, c.getBalancerStatus(current, false))
+
+	// the score is not evaluated again until the interval elapsed
+	c.getBalancerStatus(current, true)
+	assert.Equal(t, 1, evaluations)
+	c.balancerScoreChecked = time.Now().Add(-balancerScoreInterval)
+	c.getBalancerStatus(current, true)
+	assert.Equal(t, 2, evaluations)
+	c.balancerScoreChecked = time.Time{}
+
+	failure = true
+	status = c.getBalancerStatus(current, true)
+	assert.Equal(t, "0.021", status.Score)
+	assert.Contains(t, status.Details, "timed out")
+}
+
+func TestGetMgrModulesStatus(t *testing.T) {
+	failure := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if failure {
+				return "", errors.New("timed out")
+			}
+			if args[0] == "mgr" && args[1] == "module" && args[2] == "ls" {
+				return `{"always_on_modules":["balancer"],"enabled_modules":["pg_autoscaler"],` +
+					`"disabled_modules":[{"name":"diskprediction_local","can_run":false,"error_string":"No module named 'sklearn'"},{"name":"insights","can_run":true}]}`, nil
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+	c := newCephStatusChecker(&clusterd.Context{Executor: executor}, cephclient.AdminTestClusterInfo("mycluster"), &cephv1.ClusterSpec{})
+
+	modules := []cephv1.Module{{Name: "pg_autoscaler", Enabled: true}, {Name: "diskprediction_local", Enabled: true}, {Name: "insights"}}
+	status := c.getMgrModulesStatus(modules, cephv1.ClusterStatus{}, true)
+	assert.Equal(t, []cephv1.MgrModuleStatus{
+		{Name: "pg_autoscaler", Enabled: true},
+		{Name: "diskprediction_local", Error: "No module named 'sklearn'"},
+		{Name: "insights"},
+	}, status)
+
+	// the last known status is kept when the modules cannot be listed
+	current := cephv1.ClusterStatus{CephStatus: &cephv1.CephStatus{MgrModules: status}}
+	assert.Equal(t, status, c.getMgrModulesStatus(modules, current, false))
+	failure = true
+	assert.Equal(t, status, c.getMgrModulesStatus(modules, current, true))
+}
@@ -43,14 +43,20 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/csi"
 	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/tracing"
 	rookversion "github.com/rook/rook/pkg/version"
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	crclient "sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 const (
 	detectVersionName = "rook-ceph-detect-version"
+	// the reason of the event recorded while the stretch mode is not disabled for lack of confirmation
+	stretchModeNotDisabledReason = "StretchModeNotDisabled"
 )
 
 var telemetryMutex sync.Mutex
@@ -63,6 +69,7 @@ type cluster struct {
 	clusterMetadata    metav1.ObjectMeta
 	namespacedName     types.NamespacedName
 	mons               *mon.Cluster
+	recorder           record.EventRecorder
 	ownerInfo          *k8sutil.OwnerInfo
 	isUpgrade          bool
 	monitoringRoutines map[string]*controller.ClusterHealth
@@ -152,10 +159,14 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 	}
 
 	// If a stretch cluster, enable the arbiter after the OSDs are created with the CRUSH map
-	if c.Spec.IsStretchCluster() {
+	if c.Spec.StretchModeEnabled() {
 		if err := c.mons.ConfigureArbiter(); err != nil {
 			return errors.Wrap(err, "failed to configure stretch arbiter")
 		}
+	} else if c.Spec.IsStretchCluster() {
+		if err := c.disableStretchMode(); err != nil {
+			return errors.Wrap(err, "failed to disable stretch mode")
+		}
 	}
 
 	logger.Infof("done reconciling ceph cluster in namespace %q", c.Namespace)
@@ -171,7 +182,12 @@ func (c *cluster) reconcileCephDaemons(rookImage string, cephVersion cephver.Cep
 	return nil
 }
 
-func (c *ClusterController) initializeCluster(cluster *cluster) error {
+// initializeCluster runs the orchestration of the cluster. The ceph commands of the orchestration
+// are traced as part of the span of the reconcile context.
+func (c *ClusterController) initializeCluster(reconcileContext context.Context, cluster *cluster) error {
+	ctx := tracing.ContextWithSpan(c.OpManagerCtx, reconcileContext)
+	logger := util.NewContextLogger(reconcileContext, logger)
+
 	// Check if the dataDirHostPath is located in the disallowed paths list
 	cleanDataDirHostPath := path.Clean(cluster.Spec.DataDirHostPath)
 	for _, b := range disallowedHostDirectories {
@@ -183,13 +199,13 @@ func (c *ClusterController) initializeCluster(cluster *cluster) error {
 
 	// Depending on the cluster type choose the correct orchestration
 	if cluster.Spec.External.Enable {
-		err := c.configureExternalCephCluster(cluster)
+		err := c.configureExternalCephCluster(ctx, cluster)
 		if err != nil {
 			controller.UpdateCondition(c.OpManagerCtx, c.context, c.namespacedName, k8sutil.ObservedGenerationNotAvailable, cephv1.ConditionProgressing, v1.ConditionFalse, cephv1.ClusterProgressingReason, err.Error())
 			return errors.Wrap(err, "failed to configure external ceph cluster")
 		}
 	} else {
-		clusterInfo, _, _, err := controller.LoadClusterInfo(c.context, c.OpManagerCtx, cluster.Namespace, cluster.Spec)
+		clusterInfo, _, _, err := controller.LoadClusterInfo(c.context, ctx, cluster.Namespace, cluster.Spec)
 		if err != nil {
 			if errors.Is(err, controller.ClusterInfoNoClusterNoSecret) {
 				logger.Info("clusterInfo not yet found, must be a new cluster.")
@@ -210,7 +226,7 @@ func (c *ClusterController) initializeCluster(cluster *cluster) error {
 			c.configureCephMonitoring(cluster, clusterInfo)
 		}
 
-		err = c.configureLocalCephCluster(cluster)
+		err = c.configureLocalCephCluster(ctx, cluster)
 		if err != nil {
 			controller.UpdateCondition(c.OpManagerCtx, c.context, c.namespacedName, k8sutil.ObservedGenerationNotAvailable, cephv1.ConditionProgressing, v1.ConditionFalse, cephv1.ClusterProgressingReason, err.Error())
 			return errors.Wrap(err, "failed to configure local ceph cluster")
@@ -235,7 +251,7 @@ func (c *ClusterController) initializeCluster(cluster *cluster) error {
 	return nil
 }
 
-func (c *ClusterController) configureLocalCephCluster(cluster *cluster) error {
+func (c *ClusterController) configureLocalCephCluster(ctx context.Context, cluster *cluster) error {
 	// Cluster Spec validation
 	err := preClusterStartValidation(cluster)
 	if err != nil {
@@ -267,7 +283,7 @@ func (c *ClusterController) configureLocalCephCluster(cluster *cluster) error {
 
 	controller.UpdateCondition(c.OpManagerCtx, c.context, c.namespacedName, k8sutil.ObservedGenerationNotAvailable, cephv1.ConditionProgressing, v1.ConditionTrue, cephv1.ClusterProgressingReason, "Configuring the Ceph cluster")
 
-	cluster.ClusterInfo.Context = c.OpManagerCtx
+	cluster.ClusterInfo.Context = ctx
 	// Run the orchestration
 	err = cluster.reconcileCephDaemons(c.rookImage, *cephVersion)
 	if err != nil {
@@ -317,6 +333,9 @@ func validateStretchCluster(cluster *cluster) error {
 	if !cluster.Spec.IsStretchCluster() {
 		return nil
 	}
+	if !cluster.Spec.StretchModeEnabled() {
+		return validateStretchPoolsCluster(cluster)
+	}
 	if len(cluster.Spec.Mon.StretchCluster.Zones) != 3 {
 		return errors.Errorf("expecting exactly three zones for the stretch cluster, but found %d", len(cluster.Spec.Mon.StretchCluster.Zones))
 	}
@@ -338,6 +357,64 @@ func validateStretchCluster(cluster *cluster) error {
 	return nil
 }
 
+// validateStretchPoolsCluster validates a stretch cluster without the ceph stretch mode, where the
+// mons are spread across any number of zones
+func validateStretchPoolsCluster(cluster *cluster) error {
+	zones := cluster.Spec.Mon.StretchCluster.Zones
+	if len(zones) < 3 {
+		return errors.Errorf("expecting at least three zones for the stretch cluster, but found %d", len(zones))
+	}
+	if cluster.Spec.Mon.Count%2 == 0 || cluster.Spec.Mon.Count < len(zones) {
+		return errors.Errorf("invalid number of mons %d for a stretch cluster with %d zones, expecting an odd number of mons of at least the number of zones", cluster.Spec.Mon.Count, len(zones))
+	}
+	arbitersFound := 0
+	for _, zone := range zones {
+		if zone.Arbiter {
+			arbitersFound++
+		}
+		if zone.Name == "" {
+			return errors.New("missing zone name for the stretch cluster")
+		}
+	}
+	if arbitersFound > 1 {
+		return errors.Errorf("expecting to find at most one arbiter zone, but found %d", arbitersFound)
+	}
+	return nil
+}
+
+// disableStretchMode disables the ceph stretch mode when a stretch cluster is changed to only stretch
+// the pools with stretch settings. Since all the pools are moved off the stretch rule, the stretch
+// mode is only disabled once the cluster is annotated to confirm it.
+func (c *cluster) disableStretchMode() error {
+	monDump, err := client.GetMonDump(c.context, c.ClusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get mon dump")
+	}
+	if !monDump.StretchMode {
+		return nil
+	}
+	if !c.ClusterInfo.CephVersion.IsAtLeast(cephver.Squid) {
+		logger.Warningf("cannot disable the stretch mode before ceph version %q, the stretch mode remains enabled", cephver.Squid.String())
+		return nil
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to get cluster %q", c.namespacedName.Name)
+	}
+	if _, ok := cephCluster.GetAnnotations()[cephv1.DisableStretchModeAnnotation]; !ok {
+		message := fmt.Sprintf("the stretch mode remains enabled until the cluster is annotated with %q to confirm moving all the pools off the stretch rule", cephv1.DisableStretchModeAnnotation)
+		logger.Warning(message)
+		c.recorder.Event(cephCluster, v1.EventTypeWarning, stretchModeNotDisabledReason, message)
+		return nil
+	}
+
+	if err := client.DisableStretchMode(c.context, c.ClusterInfo); err != nil {
+		return err
+	}
+	return c.removeClusterAnnotation(cephCluster, cephv1.DisableStretchModeAnnotation)
+}
+
 func extractExitCode(err error) (int, bool) {
 	exitErr, ok := err.(*exec.ExitError)
 	if ok {
@@ -450,6 +527,41 @@ func (c *cluster) replaceDefaultCrushMap(newRoot string) (err error) {
 
 // preMonStartupActions is a collection of actions to run before the monitors are reconciled.
 func (c *cluster) preMonStartupActions(cephVersion cephver.CephVersion) error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.namespacedName, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to get cluster %q", c.namespacedName.Name)
+	}
+
+	// restore the quorum first so the addresses are only remapped for the surviving mon
+	if survivor, ok := cephCluster.GetAnnotations()[cephv1.RestoreMonQuorumAnnotation]; ok {
+		logger.Infof("cluster %q is annotated with %q, restoring the mon quorum", c.namespacedName.Name, cephv1.RestoreMonQuorumAnnotation)
+		if err := c.mons.RestoreQuorum(c.ClusterInfo, cephVersion, *c.Spec, survivor, c.recorder); err != nil {
+			return errors.Wrap(err, "failed to restore mon quorum")
+		}
+		if err := c.removeClusterAnnotation(cephCluster, cephv1.RestoreMonQuorumAnnotation); err != nil {
+			return err
+		}
+	}
+
+	if _, ok := cephCluster.GetAnnotations()[cephv1.RemapMonAddressesAnnotation]; ok {
+		logger.Infof("cluster %q is annotated with %q, remapping the mon addresses", c.namespacedName.Name, cephv1.RemapMonAddressesAnnotation)
+		if err := c.mons.RemapAddresses(c.ClusterInfo, cephVersion, *c.Spec); err != nil {
+			return errors.Wrap(err, "failed to remap mon addresses")
+		}
+		if err := c.removeClusterAnnotation(cephCluster, cephv1.RemapMonAddressesAnnotation); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// removeClusterAnnotation removes an annotation requesting an operation once it is done
+func (c *cluster) removeClusterAnnotation(cephCluster *cephv1.CephCluster, annotation string) error {
+	patch := crclient.MergeFrom(cephCluster.DeepCopy())
+	delete(cephCluster.Annotations, annotation)
+	if err := c.context.Client.Patch(c.ClusterInfo.Context, cephCluster, patch); err != nil {
+		return errors.Wrapf(err, "failed to remove annotation %q from cluster %q", annotation, c.namespacedName.Name)
+	}
 	return nil
 }
 
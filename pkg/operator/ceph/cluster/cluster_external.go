@@ -39,7 +39,7 @@ import (
 	"k8s.io/client-go/kubernetes"
 )
 
-func (c *ClusterController) configureExternalCephCluster(cluster *cluster) error {
+func (c *ClusterController) configureExternalCephCluster(ctx context.Context, cluster *cluster) error {
 	// Make sure the spec contains all the information we need
 	err := validateExternalClusterSpec(cluster)
 	if err != nil {
@@ -55,7 +55,7 @@ func (c *ClusterController) configureExternalCephCluster(cluster *cluster) error
 		return errors.Wrap(err, "failed to populate external cluster info")
 	}
 	cluster.ClusterInfo.SetName(c.namespacedName.Name)
-	cluster.ClusterInfo.Context = c.OpManagerCtx
+	cluster.ClusterInfo.Context = ctx
 
 	if !client.IsKeyringBase64Encoded(cluster.ClusterInfo.CephCred.Secret) {
 		return errors.Errorf("invalid user health checker key for user %q", cluster.ClusterInfo.CephCred.Username)
@@ -167,6 +167,12 @@ func (c *ClusterController) configureExternalCephCluster(cluster *cluster) error
 		if err != nil {
 			return errors.Wrap(err, "failed to configure external cluster monitoring")
 		}
+	} else {
+		// delete the prometheus rule left behind when the monitoring was enabled
+		manager := mgr.New(c.context, cluster.ClusterInfo, *cluster.Spec, "")
+		if err := manager.ReconcilePrometheusRule(); err != nil {
+			logger.Errorf("failed to delete external prometheus rule. %v", err)
+		}
 	}
 
 	// We don't update the connection status since it is done by the health go routine
@@ -258,5 +264,9 @@ func (c *ClusterController) configureExternalClusterMonitoring(context *clusterd
 	} else {
 		logger.Info("external service monitor created")
 	}
+
+	if err := manager.ReconcilePrometheusRule(); err != nil {
+		logger.Errorf("failed to reconcile external prometheus rule. %v", err)
+	}
 	return nil
 }
@@ -26,6 +26,7 @@ import (
 
 	"github.com/pkg/errors"
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/cluster/mon"
@@ -38,6 +39,9 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"gopkg.in/ini.v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestPreClusterStartValidation(t *testing.T) {
@@ -74,6 +78,34 @@ func TestPreClusterStartValidation(t *testing.T) {
 			{Name: "b"},
 			{Name: "c"},
 		}}}}}}, true},
+		{"stretch mode with four zones", args{&cluster{ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"), context: &clusterd.Context{Clientset: testop.New(t, 5)}, Spec: &cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 5, StretchCluster: &cephv1.StretchClusterSpec{Zones: []cephv1.MonZoneSpec{
+			{Name: "a", Arbiter: true},
+			{Name: "b"},
+			{Name: "c"},
+			{Name: "d"},
+		}}}}}}, true},
+		{"valid stretch pools cluster with four zones", args{&cluster{ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"), context: &clusterd.Context{Clientset: testop.New(t, 5)}, Spec: &cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 5, StretchCluster: &cephv1.StretchClusterSpec{Mode: cephv1.StretchModePools, Zones: []cephv1.MonZoneSpec{
+			{Name: "a", Arbiter: true},
+			{Name: "b"},
+			{Name: "c"},
+			{Name: "d"},
+		}}}}}}, false},
+		{"valid stretch pools cluster without arbiter", args{&cluster{ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"), context: &clusterd.Context{Clientset: testop.New(t, 3)}, Spec: &cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 3, StretchCluster: &cephv1.StretchClusterSpec{Mode: cephv1.StretchModePools, Zones: []cephv1.MonZoneSpec{
+			{Name: "a"},
+			{Name: "b"},
+			{Name: "c"},
+		}}}}}}, false},
+		{"stretch pools cluster with fewer mons than zones", args{&cluster{ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"), context: &clusterd.Context{Clientset: testop.New(t, 5)}, Spec: &cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 3, StretchCluster: &cephv1.StretchClusterSpec{Mode: cephv1.StretchModePools, Zones: []cephv1.MonZoneSpec{
+			{Name: "a", Arbiter: true},
+			{Name: "b"},
+			{Name: "c"},
+			{Name: "d"},
+		}}}}}}, true},
+		{"stretch pools cluster with two arbiters", args{&cluster{ClusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"), context: &clusterd.Context{Clientset: testop.New(t, 3)}, Spec: &cephv1.ClusterSpec{Mon: cephv1.MonSpec{Count: 3, StretchCluster: &cephv1.StretchClusterSpec{Mode: cephv1.StretchModePools, Zones: []cephv1.MonZoneSpec{
+			{Name: "a", Arbiter: true},
+			{Name: "b", Arbiter: true},
+			{Name: "c"},
+		}}}}}}, true},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -335,3 +367,42 @@ func TestTelemetry(t *testing.T) {
 		c.reportTelemetry()
 	})
 }
+
+func TestDisableStretchMode(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mon" && args[1] == "dump" {
+				return `{"epoch":3,"stretch_mode":true}`, nil
+			}
+			commands = append(commands, strings.Join(args[:2], " "))
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	clusterInfo.CephVersion = cephver.Squid
+	namespacedName := clusterInfo.NamespacedName()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: namespacedName.Name, Namespace: namespacedName.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephCluster).Build()
+	recorder := record.NewFakeRecorder(10)
+	c := cluster{
+		context:        &clusterd.Context{Executor: executor, Client: cl},
+		ClusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		recorder:       recorder,
+	}
+
+	// the stretch mode is not disabled without confirmation
+	assert.NoError(t, c.disableStretchMode())
+	assert.Empty(t, commands)
+	assert.Contains(t, <-recorder.Events, cephv1.DisableStretchModeAnnotation)
+
+	// the annotation is removed once the stretch mode is disabled
+	assert.NoError(t, cl.Get(clusterInfo.Context, namespacedName, cephCluster))
+	cephCluster.Annotations = map[string]string{cephv1.DisableStretchModeAnnotation: ""}
+	assert.NoError(t, cl.Update(clusterInfo.Context, cephCluster))
+	assert.NoError(t, c.disableStretchMode())
+	assert.Equal(t, []string{"mon disable_stretch_mode"}, commands)
+	assert.NoError(t, cl.Get(clusterInfo.Context, namespacedName, cephCluster))
+	assert.NotContains(t, cephCluster.Annotations, cephv1.DisableStretchModeAnnotation)
+}
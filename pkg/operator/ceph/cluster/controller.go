@@ -34,6 +34,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/csi"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -125,7 +126,7 @@ func newReconciler(mgr manager.Manager, ctx *clusterd.Context, clusterController
 
 func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reconciler, context *clusterd.Context) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -187,6 +188,22 @@ func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reco
 		return err
 	}
 
+	// Watch for the creation and the renewal of the dashboard tls secrets
+	secretKind := source.Kind(mgr.GetCache(),
+		&corev1.Secret{
+			TypeMeta: metav1.TypeMeta{
+				Kind:       "Secret",
+				APIVersion: corev1.SchemeGroupVersion.String(),
+			},
+		})
+	err = c.Watch(
+		secretKind,
+		handler.EnqueueRequestsFromMapFunc(handlerFunc),
+		predicateForDashboardTLSSecretWatcher(opManagerContext, mgr.GetClient()))
+	if err != nil {
+		return err
+	}
+
 	// Watch for changes on the hotplug config map
 	// TODO: to improve, can we run this against the operator namespace only?
 	disableVal, err := k8sutil.GetOperatorSetting(opManagerContext, context.Clientset, opcontroller.OperatorSettingConfigMapName, disableHotplugEnv, "false")
@@ -222,13 +239,14 @@ func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reco
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileCephCluster) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
-	reconcileResponse, cephCluster, err := r.reconcile(request)
+	reconcileResponse, cephCluster, err := r.reconcile(context, request)
 
 	return reporting.ReportReconcileResult(logger, r.clusterController.recorder, request,
 		&cephCluster, reconcileResponse, err)
 }
 
-func (r *ReconcileCephCluster) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephCluster, error) {
+func (r *ReconcileCephCluster) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, cephv1.CephCluster, error) {
+	logger := util.NewContextLogger(ctx, logger)
 	// Pass the client context to the ClusterController
 	r.clusterController.client = r.client
 
@@ -263,7 +281,7 @@ func (r *ReconcileCephCluster) reconcile(request reconcile.Request) (reconcile.R
 
 	// Do reconcile here!
 	ownerInfo := k8sutil.NewOwnerInfo(cephCluster, r.scheme)
-	if err := r.clusterController.reconcileCephCluster(cephCluster, ownerInfo); err != nil {
+	if err := r.clusterController.reconcileCephCluster(ctx, cephCluster, ownerInfo); err != nil {
 		// If the error has a context cancelled let's return a success result so that the controller can
 		// exit gracefully and the goroutine (the one the manager runs in) won't block retrying even if the parent context has been
 		// cancelled.
@@ -351,7 +369,8 @@ func NewClusterController(context *clusterd.Context, rookImage string) *ClusterC
 	}
 }
 
-func (c *ClusterController) reconcileCephCluster(clusterObj *cephv1.CephCluster, ownerInfo *k8sutil.OwnerInfo) error {
+func (c *ClusterController) reconcileCephCluster(ctx context.Context, clusterObj *cephv1.CephCluster, ownerInfo *k8sutil.OwnerInfo) error {
+	logger := util.NewContextLogger(ctx, logger)
 	if clusterObj.Spec.CleanupPolicy.HasDataDirCleanPolicy() {
 		logger.Infof("skipping orchestration for cluster object %q in namespace %q because its cleanup policy is set", clusterObj.Name, clusterObj.Namespace)
 		return nil
@@ -369,6 +388,7 @@ func (c *ClusterController) reconcileCephCluster(clusterObj *cephv1.CephCluster,
 	// Pass down the client to interact with Kubernetes objects
 	// This will be used later down by spec code to create objects like deployment, services etc
 	cluster.context.Client = c.client
+	cluster.recorder = c.recorder
 
 	// Set the spec
 	cluster.Spec = &clusterObj.Spec
@@ -377,7 +397,7 @@ func (c *ClusterController) reconcileCephCluster(clusterObj *cephv1.CephCluster,
 	logger.Infof("reconciling ceph cluster in namespace %q", cluster.Namespace)
 
 	// Start the main ceph cluster orchestration
-	return c.initializeCluster(cluster)
+	return c.initializeCluster(ctx, cluster)
 }
 
 func (c *ClusterController) requestClusterDelete(cluster *cephv1.CephCluster) (reconcile.Result, error) {
@@ -0,0 +1,158 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"strconv"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+)
+
+const (
+	balancerMaxMisplacedRatioOption = "target_max_misplaced_ratio"
+	balancerUpmapMaxDeviationOption = "mgr/balancer/upmap_max_deviation"
+	balancerBeginTimeOption         = "mgr/balancer/begin_time"
+	balancerEndTimeOption           = "mgr/balancer/end_time"
+	balancerBeginWeekdayOption      = "mgr/balancer/begin_weekday"
+	balancerEndWeekdayOption        = "mgr/balancer/end_weekday"
+)
+
+// balancerOptions are the mgr options managed by the balancer spec
+var balancerOptions = []string{
+	balancerMaxMisplacedRatioOption,
+	balancerUpmapMaxDeviationOption,
+	balancerBeginTimeOption,
+	balancerEndTimeOption,
+	balancerBeginWeekdayOption,
+	balancerEndWeekdayOption,
+}
+
+// configureBalancer applies the balancer spec. If the spec is not set, the balancer options are reset
+// to the ceph defaults and the balancer is turned on.
+func (c *Cluster) configureBalancer() error {
+	spec := c.spec.Balancer
+	if spec == nil {
+		if err := c.removeBalancerOptions(nil); err != nil {
+			return err
+		}
+		return c.enableBalancerModule()
+	}
+
+	mode := spec.Mode
+	if mode == "" {
+		mode = balancerModuleMode
+	}
+	if cephclient.IsBalancerReadMode(mode) {
+		if !c.clusterInfo.CephVersion.IsAtLeast(cephver.Squid) {
+			return errors.Errorf("balancer mode %q requires at least Ceph version %q", mode, cephver.Squid.String())
+		}
+		// the read modes require all the clients to understand the pg-upmap-primary mappings
+		olderClients, err := cephclient.GetClientReleasesOlderThan(c.context, c.clusterInfo, cephclient.BalancerReadModeMinClientRelease)
+		if err != nil {
+			return errors.Wrap(err, "failed to check the release of the clients")
+		}
+		if len(olderClients) > 0 {
+			return errors.Errorf("balancer mode %q requires all the clients to be at least %q, but clients of release %v are connected", mode, cephclient.BalancerReadModeMinClientRelease, olderClients)
+		}
+	}
+	if err := cephclient.ConfigureBalancerModule(c.context, c.clusterInfo, mode); err != nil {
+		return errors.Wrapf(err, "failed to configure mgr %q module", balancerModuleName)
+	}
+
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	settings := balancerSettings(spec)
+	for _, option := range balancerOptions {
+		value, ok := settings[option]
+		if !ok {
+			continue
+		}
+		if _, err := monStore.SetIfChanged("mgr", option, value); err != nil {
+			return errors.Wrapf(err, "failed to set balancer option %q", option)
+		}
+	}
+
+	if err := c.removeBalancerOptions(settings); err != nil {
+		return err
+	}
+
+	active := spec.Active == nil || *spec.Active
+	if err := cephclient.SetBalancerActive(c.context, c.clusterInfo, active); err != nil {
+		return errors.Wrapf(err, "failed to set the balancer active %t", active)
+	}
+	return nil
+}
+
+// balancerSettings returns the mgr options of the balancer spec, the settings that are not set in
+// the spec are omitted
+func balancerSettings(spec *cephv1.BalancerSpec) map[string]string {
+	settings := map[string]string{}
+	if spec.MaxMisplacedRatio != nil {
+		settings[balancerMaxMisplacedRatioOption] = strconv.FormatFloat(*spec.MaxMisplacedRatio, 'f', -1, 64)
+	}
+	if spec.UpmapMaxDeviation > 0 {
+		settings[balancerUpmapMaxDeviationOption] = strconv.Itoa(spec.UpmapMaxDeviation)
+	}
+	if spec.BeginTime != "" {
+		settings[balancerBeginTimeOption] = spec.BeginTime
+	}
+	if spec.EndTime != "" {
+		settings[balancerEndTimeOption] = spec.EndTime
+	}
+	if spec.BeginWeekday != nil {
+		settings[balancerBeginWeekdayOption] = strconv.Itoa(*spec.BeginWeekday)
+	}
+	if spec.EndWeekday != nil {
+		settings[balancerEndWeekdayOption] = strconv.Itoa(*spec.EndWeekday)
+	}
+	return settings
+}
+
+// removeBalancerOptions removes the balancer options of the mgr that are not in the settings of the
+// spec. The options set in the cephConfig of the cluster spec are left to the cephConfig.
+func (c *Cluster) removeBalancerOptions(settings map[string]string) error {
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	current, err := monStore.GetDaemon("mgr")
+	if err != nil {
+		return errors.Wrap(err, "failed to get the mgr options")
+	}
+	for _, option := range current {
+		if _, ok := settings[option.Option]; ok || !isBalancerOption(option.Option) {
+			continue
+		}
+		if _, ok := c.spec.CephConfig["mgr"][option.Option]; ok {
+			continue
+		}
+		logger.Infof("removing balancer option %q that is no longer in the balancer spec", option.Option)
+		if err := monStore.Delete("mgr", option.Option); err != nil {
+			return errors.Wrapf(err, "failed to remove balancer option %q", option.Option)
+		}
+	}
+	return nil
+}
+
+func isBalancerOption(option string) bool {
+	for _, balancerOption := range balancerOptions {
+		if option == balancerOption {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,125 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBalancerSettings(t *testing.T) {
+	assert.Empty(t, balancerSettings(&cephv1.BalancerSpec{}))
+
+	ratio := 0.07
+	sunday := 0
+	settings := balancerSettings(&cephv1.BalancerSpec{MaxMisplacedRatio: &ratio, UpmapMaxDeviation: 2, BeginTime: "0100", EndTime: "0600", BeginWeekday: &sunday})
+	assert.Equal(t, map[string]string{
+		"target_max_misplaced_ratio":       "0.07",
+		"mgr/balancer/upmap_max_deviation": "2",
+		"mgr/balancer/begin_time":          "0100",
+		"mgr/balancer/end_time":            "0600",
+		"mgr/balancer/begin_weekday":       "0",
+	}, settings)
+}
+
+func TestConfigureBalancer(t *testing.T) {
+	var commands []string
+	features := ""
+	mgrConfig := map[string]string{"mgr/balancer/end_time": "0600", "mgr/dashboard/ssl": "true"}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args[:2], " "))
+			if args[0] == "features" {
+				return features, nil
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] != "config" {
+				return "", nil
+			}
+			switch {
+			case args[1] == "get" && strings.HasPrefix(args[3], "--"):
+				config := map[string]interface{}{}
+				for option, value := range mgrConfig {
+					config[option] = map[string]interface{}{"section": "mgr", "value": value}
+				}
+				b, err := json.Marshal(config)
+				return string(b), err
+			case args[1] == "get":
+				return mgrConfig[args[3]], nil
+			case args[1] == "set":
+				mgrConfig[args[3]] = args[4]
+			case args[1] == "rm":
+				delete(mgrConfig, args[3])
+			}
+			commands = append(commands, strings.Join(args[:4], " "))
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	clusterInfo.CephVersion = cephver.Reef
+	c := &Cluster{context: &clusterd.Context{Executor: executor}, clusterInfo: clusterInfo}
+
+	// the balancer options are removed without a balancer spec, unless they are set by the cephConfig
+	mgrConfig["mgr/balancer/begin_time"] = "0100"
+	c.spec.CephConfig = map[string]map[string]string{"mgr": {"mgr/balancer/begin_time": "0100"}}
+	assert.NoError(t, c.configureBalancer())
+	assert.Equal(t, []string{"config rm mgr mgr/balancer/end_time"}, commands)
+	assert.Equal(t, map[string]string{"mgr/balancer/begin_time": "0100", "mgr/dashboard/ssl": "true"}, mgrConfig)
+	c.spec.CephConfig = nil
+	delete(mgrConfig, "mgr/balancer/begin_time")
+	mgrConfig["mgr/balancer/end_time"] = "0600"
+	commands = nil
+
+	active := false
+	c.spec.Balancer = &cephv1.BalancerSpec{Mode: "crush-compat", Active: &active, UpmapMaxDeviation: 3}
+	assert.NoError(t, c.configureBalancer())
+	assert.Equal(t, []string{
+		"osd set-require-min-compat-client",
+		"balancer mode",
+		"config set mgr mgr/balancer/upmap_max_deviation",
+		"config rm mgr mgr/balancer/end_time",
+		"balancer off",
+	}, commands)
+	assert.Equal(t, map[string]string{"mgr/balancer/upmap_max_deviation": "3", "mgr/dashboard/ssl": "true"}, mgrConfig)
+
+	// the read modes require squid
+	c.spec.Balancer.Mode = "upmap-read"
+	assert.EqualError(t, c.configureBalancer(), `balancer mode "upmap-read" requires at least Ceph version "19.0.0-0 squid"`)
+
+	// the read modes require all the clients to be at least reef
+	clusterInfo.CephVersion = cephver.Squid
+	features = `{"mon":[{"release":"squid","num":3}],"client":[{"release":"luminous","num":2},{"release":"squid","num":5}]}`
+	commands = nil
+	assert.EqualError(t, c.configureBalancer(), `balancer mode "upmap-read" requires all the clients to be at least "reef", but clients of release [luminous] are connected`)
+	assert.Equal(t, []string{"features --connect-timeout=15"}, commands)
+
+	features = `{"client":[{"release":"reef","num":2},{"release":"squid","num":5}]}`
+	commands = nil
+	assert.NoError(t, c.configureBalancer())
+	assert.Equal(t, "osd set-require-min-compat-client", commands[1])
+}
@@ -94,6 +94,10 @@ func (c *Cluster) configureDashboardModules() error {
 		return errors.Wrap(err, "failed to initialize dashboard")
 	}
 
+	if err := c.configureDashboardSSO(); err != nil {
+		return errors.Wrap(err, "failed to configure dashboard sso")
+	}
+
 	hasChanged, err := c.configureDashboardModuleSettings()
 	if err != nil {
 		return err
@@ -208,7 +212,17 @@ func (c *Cluster) initializeSecureDashboard() error {
 		return errors.Wrap(err, "failed to generate a password for the ceph dashboard")
 	}
 
-	if c.spec.Dashboard.SSL {
+	if c.spec.Dashboard.SSL && c.spec.Dashboard.TLS != nil {
+		changed, err := c.configureDashboardCert()
+		if err != nil {
+			return errors.Wrap(err, "failed to configure the certificate of the ceph dashboard")
+		}
+		if changed {
+			if err := c.restartMgrModule(dashboardModuleName); err != nil {
+				logger.Warningf("failed to restart dashboard after applying the ssl cert. %v", err)
+			}
+		}
+	} else if c.spec.Dashboard.SSL {
 		alreadyCreated, err := c.createSelfSignedCert()
 		if err != nil {
 			return errors.Wrap(err, "failed to create a self signed cert for the ceph dashboard")
@@ -0,0 +1,59 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+)
+
+const (
+	dashboardSSOProtocolSAML2  = "saml2"
+	dashboardSSOProtocolOAuth2 = "oauth2"
+)
+
+// configureDashboardSSO applies the single sign-on settings of the dashboard, or disables the
+// single sign-on if it is not set
+func (c *Cluster) configureDashboardSSO() error {
+	sso := c.spec.Dashboard.SSO
+	if sso == nil {
+		return client.DisableDashboardSSO(c.context, c.clusterInfo)
+	}
+
+	switch sso.Protocol {
+	case dashboardSSOProtocolSAML2:
+		saml2 := sso.SAML2
+		if saml2 == nil {
+			return errors.New("dashboard sso protocol \"saml2\" requires the saml2 settings")
+		}
+		if err := client.SetupDashboardSAML2(c.context, c.clusterInfo, saml2.BaseURL, saml2.IDPMetadata, saml2.IDPUsernameAttribute, saml2.IDPEntityID); err != nil {
+			return err
+		}
+	case dashboardSSOProtocolOAuth2:
+		// the dashboard trusts the identity in the headers sent by an oauth2-proxy, which must be in
+		// front of the dashboard service to not let anyone in
+		return errors.Errorf("dashboard sso protocol %q is not supported since it relies on an oauth2-proxy in front of the dashboard that Rook does not deploy", sso.Protocol)
+	default:
+		return errors.Errorf("invalid dashboard sso protocol %q", sso.Protocol)
+	}
+
+	if err := client.EnableDashboardSSO(c.context, c.clusterInfo, sso.Protocol); err != nil {
+		return err
+	}
+	logger.Infof("dashboard %s sso configured", sso.Protocol)
+	return nil
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestConfigureDashboardSSO(t *testing.T) {
+	var commands []string
+	ssoStatus := `SSO is "enabled" with "SAML2" protocol.`
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "dashboard" && args[1] == "sso" && args[2] == "status" {
+				return ssoStatus, nil
+			}
+			var cmd []string
+			for _, arg := range args {
+				if strings.HasPrefix(arg, "--") {
+					break
+				}
+				cmd = append(cmd, arg)
+			}
+			commands = append(commands, strings.Join(cmd, " "))
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	clusterInfo.CephVersion = cephver.Reef
+	c := &Cluster{context: &clusterd.Context{Executor: executor}, clusterInfo: clusterInfo}
+
+	t.Run("sso disabled without settings", func(t *testing.T) {
+		assert.NoError(t, c.configureDashboardSSO())
+		assert.Equal(t, []string{"dashboard sso disable"}, commands)
+
+		commands = nil
+		ssoStatus = `SSO is "disabled".`
+		assert.NoError(t, c.configureDashboardSSO())
+		assert.Empty(t, commands)
+	})
+
+	t.Run("saml2", func(t *testing.T) {
+		commands = nil
+		c.spec.Dashboard.SSO = &cephv1.DashboardSSOSpec{
+			Protocol: "saml2",
+			SAML2:    &cephv1.DashboardSAML2Spec{BaseURL: "https://dashboard.example.com", IDPMetadata: "https://idp.example.com/metadata", IDPEntityID: "ceph"},
+		}
+		assert.NoError(t, c.configureDashboardSSO())
+		assert.Equal(t, []string{
+			"dashboard sso setup saml2 https://dashboard.example.com https://idp.example.com/metadata uid ceph",
+			"dashboard sso enable saml2",
+		}, commands)
+
+		c.spec.Dashboard.SSO.SAML2 = nil
+		assert.EqualError(t, c.configureDashboardSSO(), `dashboard sso protocol "saml2" requires the saml2 settings`)
+	})
+
+	t.Run("oauth2", func(t *testing.T) {
+		commands = nil
+		c.spec.Dashboard.SSO = &cephv1.DashboardSSOSpec{Protocol: "oauth2"}
+		c.clusterInfo.CephVersion = cephver.Squid
+		assert.EqualError(t, c.configureDashboardSSO(), `dashboard sso protocol "oauth2" is not supported since it relies on an oauth2-proxy in front of the dashboard that Rook does not deploy`)
+		assert.Empty(t, commands)
+	})
+}
@@ -0,0 +1,201 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/exec"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// the config-key entries where the dashboard stores its certificate and key
+	dashboardCertKey    = "mgr/dashboard/crt"
+	dashboardCertKeyKey = "mgr/dashboard/key"
+)
+
+var certificateGVK = schema.GroupVersionKind{Group: "cert-manager.io", Version: "v1", Kind: "Certificate"}
+
+// dashboardCertificateName is the name of the cert-manager Certificate of the dashboard
+func dashboardCertificateName() string {
+	return fmt.Sprintf("%s-dashboard", AppName)
+}
+
+// configureDashboardCert applies the certificate of the TLS secret to the dashboard. It returns
+// whether the certificate changed, in which case the dashboard must be restarted.
+func (c *Cluster) configureDashboardCert() (bool, error) {
+	tls := c.spec.Dashboard.TLS
+	if tls.CertManager != nil {
+		if err := c.reconcileDashboardCertificate(tls); err != nil {
+			return false, err
+		}
+	}
+
+	secret, err := c.context.Clientset.CoreV1().Secrets(c.clusterInfo.Namespace).Get(c.clusterInfo.Context, tls.SecretName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) && tls.CertManager != nil {
+			return false, errors.Errorf("waiting for cert-manager to issue the dashboard tls secret %q", tls.SecretName)
+		}
+		return false, errors.Wrapf(err, "failed to get dashboard tls secret %q", tls.SecretName)
+	}
+	cert, ok := secret.Data[v1.TLSCertKey]
+	if !ok || len(cert) == 0 {
+		return false, errors.Errorf("dashboard tls secret %q has no %q", tls.SecretName, v1.TLSCertKey)
+	}
+	key, ok := secret.Data[v1.TLSPrivateKeyKey]
+	if !ok || len(key) == 0 {
+		return false, errors.Errorf("dashboard tls secret %q has no %q", tls.SecretName, v1.TLSPrivateKeyKey)
+	}
+
+	// the certificate is only applied when it differs from the one of the dashboard, e.g. after a renewal
+	if c.getDashboardConfigKey(dashboardCertKey) == strings.TrimSpace(string(cert)) &&
+		c.getDashboardConfigKey(dashboardCertKeyKey) == strings.TrimSpace(string(key)) {
+		logger.Debugf("dashboard certificate of secret %q is already applied", tls.SecretName)
+		return false, nil
+	}
+
+	if err := c.setDashboardTLSFile("set-ssl-certificate", string(cert)); err != nil {
+		return false, err
+	}
+	if err := c.setDashboardTLSFile("set-ssl-certificate-key", string(key)); err != nil {
+		return false, err
+	}
+	logger.Infof("applied the dashboard certificate of secret %q", tls.SecretName)
+	return true, nil
+}
+
+// getDashboardConfigKey returns the value of a config-key of the dashboard, or an empty string if it is not set
+func (c *Cluster) getDashboardConfigKey(key string) string {
+	cmd := client.NewCephCommand(c.context, c.clusterInfo, []string{"config-key", "get", key})
+	cmd.JsonOutput = false
+	out, err := cmd.RunWithTimeout(exec.CephCommandsTimeout)
+	if err != nil {
+		logger.Debugf("failed to get config-key %q. %v", key, err)
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// setDashboardTLSFile sets the certificate or the key of the dashboard with the given command. The
+// content is passed in a temp file so it is not logged.
+func (c *Cluster) setDashboardTLSFile(command, content string) error {
+	file, err := util.CreateTempFile(content)
+	if err != nil {
+		return errors.Wrapf(err, "failed to create a temporary file for dashboard %s", command)
+	}
+	defer func() {
+		if err := os.Remove(file.Name()); err != nil {
+			logger.Errorf("failed to clean up dashboard tls file %q. %v", file.Name(), err)
+		}
+	}()
+
+	args := []string{"dashboard", command, "-i", file.Name()}
+	_, err = client.ExecuteCephCommandWithRetry(func() (string, []byte, error) {
+		output, err := client.NewCephCommand(c.context, c.clusterInfo, args).RunWithTimeout(exec.CephCommandsTimeout)
+		return "dashboard " + command, output, err
+	}, 5, dashboardInitWaitTime)
+	if err != nil {
+		return errors.Wrapf(err, "failed to run dashboard %s", command)
+	}
+	return nil
+}
+
+// reconcileDashboardCertificate creates or updates the cert-manager Certificate that issues the
+// dashboard tls secret
+func (c *Cluster) reconcileDashboardCertificate(tls *cephv1.DashboardTLSSpec) error {
+	desired := c.makeDashboardCertificate(tls)
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(desired); err != nil {
+		return errors.Wrapf(err, "failed to set owner reference on certificate %q", desired.GetName())
+	}
+
+	current := &unstructured.Unstructured{}
+	current.SetGroupVersionKind(certificateGVK)
+	err := c.context.Client.Get(c.clusterInfo.Context, types.NamespacedName{Namespace: desired.GetNamespace(), Name: desired.GetName()}, current)
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get certificate %q, is cert-manager installed?", desired.GetName())
+		}
+		if err := c.context.Client.Create(c.clusterInfo.Context, desired); err != nil {
+			return errors.Wrapf(err, "failed to create certificate %q", desired.GetName())
+		}
+		logger.Infof("created cert-manager certificate %q for the dashboard", desired.GetName())
+		return nil
+	}
+
+	current.Object["spec"] = desired.Object["spec"]
+	if err := c.context.Client.Update(c.clusterInfo.Context, current); err != nil {
+		return errors.Wrapf(err, "failed to update certificate %q", desired.GetName())
+	}
+	return nil
+}
+
+// makeDashboardCertificate builds the cert-manager Certificate of the dashboard
+func (c *Cluster) makeDashboardCertificate(tls *cephv1.DashboardTLSSpec) *unstructured.Unstructured {
+	certManager := tls.CertManager
+	serviceName := fmt.Sprintf("%s-dashboard", AppName)
+	dnsNames := []interface{}{
+		serviceName,
+		fmt.Sprintf("%s.%s", serviceName, c.clusterInfo.Namespace),
+		fmt.Sprintf("%s.%s.svc", serviceName, c.clusterInfo.Namespace),
+	}
+	for _, name := range certManager.DNSNames {
+		dnsNames = append(dnsNames, name)
+	}
+
+	kind := certManager.IssuerRef.Kind
+	if kind == "" {
+		kind = "Issuer"
+	}
+	group := certManager.IssuerRef.Group
+	if group == "" {
+		group = certificateGVK.Group
+	}
+	spec := map[string]interface{}{
+		"secretName": tls.SecretName,
+		"commonName": serviceName,
+		"dnsNames":   dnsNames,
+		"issuerRef": map[string]interface{}{
+			"name":  certManager.IssuerRef.Name,
+			"kind":  kind,
+			"group": group,
+		},
+	}
+	if certManager.Duration != nil {
+		spec["duration"] = certManager.Duration.Duration.String()
+	}
+	if certManager.RenewBefore != nil {
+		spec["renewBefore"] = certManager.RenewBefore.Duration.String()
+	}
+
+	certificate := &unstructured.Unstructured{Object: map[string]interface{}{"spec": spec}}
+	certificate.SetGroupVersionKind(certificateGVK)
+	certificate.SetName(dashboardCertificateName())
+	certificate.SetNamespace(c.clusterInfo.Namespace)
+	return certificate
+}
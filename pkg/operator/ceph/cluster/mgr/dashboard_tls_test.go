@@ -0,0 +1,141 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigureDashboardCert(t *testing.T) {
+	configKeys := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "config-key" && args[1] == "get" {
+				if value, ok := configKeys[args[2]]; ok {
+					return value, nil
+				}
+				return "", errors.New("ENOENT")
+			}
+			if args[0] == "dashboard" && (args[1] == "set-ssl-certificate" || args[1] == "set-ssl-certificate-key") {
+				content, err := os.ReadFile(args[3])
+				assert.NoError(t, err)
+				key := dashboardCertKey
+				if args[1] == "set-ssl-certificate-key" {
+					key = dashboardCertKeyKey
+				}
+				configKeys[key] = string(content)
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected command %v", args)
+		},
+	}
+	clientset := test.New(t, 1)
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "rook-ceph", OwnerInfo: cephclient.NewMinimumOwnerInfoWithOwnerRef(), Context: context.TODO()}
+	c := &Cluster{
+		context:     &clusterd.Context{Executor: executor, Clientset: clientset, Client: fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()},
+		clusterInfo: clusterInfo,
+	}
+	c.spec.Dashboard = cephv1.DashboardSpec{Enabled: true, SSL: true, TLS: &cephv1.DashboardTLSSpec{SecretName: "dashboard-tls"}}
+
+	// the user provided secret does not exist
+	_, err := c.configureDashboardCert()
+	assert.ErrorContains(t, err, `failed to get dashboard tls secret "dashboard-tls"`)
+
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "dashboard-tls", Namespace: "rook-ceph"},
+		Type:       v1.SecretTypeTLS,
+		Data:       map[string][]byte{v1.TLSCertKey: []byte("cert-1"), v1.TLSPrivateKeyKey: []byte("key-1")},
+	}
+	_, err = clientset.CoreV1().Secrets("rook-ceph").Create(context.TODO(), secret, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	changed, err := c.configureDashboardCert()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, map[string]string{dashboardCertKey: "cert-1", dashboardCertKeyKey: "key-1"}, configKeys)
+
+	// the certificate is applied only once
+	changed, err = c.configureDashboardCert()
+	assert.NoError(t, err)
+	assert.False(t, changed)
+
+	// the renewed certificate is applied
+	secret.Data[v1.TLSCertKey] = []byte("cert-2")
+	_, err = clientset.CoreV1().Secrets("rook-ceph").Update(context.TODO(), secret, metav1.UpdateOptions{})
+	assert.NoError(t, err)
+	changed, err = c.configureDashboardCert()
+	assert.NoError(t, err)
+	assert.True(t, changed)
+	assert.Equal(t, "cert-2", configKeys[dashboardCertKey])
+}
+
+func TestReconcileDashboardCertificate(t *testing.T) {
+	clusterInfo := &cephclient.ClusterInfo{Namespace: "rook-ceph", OwnerInfo: cephclient.NewMinimumOwnerInfoWithOwnerRef(), Context: context.TODO()}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).Build()
+	c := &Cluster{
+		context:     &clusterd.Context{Clientset: test.New(t, 1), Client: cl},
+		clusterInfo: clusterInfo,
+	}
+	tls := &cephv1.DashboardTLSSpec{
+		SecretName: "dashboard-tls",
+		CertManager: &cephv1.DashboardCertManagerSpec{
+			IssuerRef: cephv1.CertManagerIssuerReference{Name: "letsencrypt", Kind: "ClusterIssuer"},
+			DNSNames:  []string{"dashboard.example.com"},
+			Duration:  &metav1.Duration{Duration: 2160 * time.Hour},
+		},
+	}
+	c.spec.Dashboard = cephv1.DashboardSpec{Enabled: true, SSL: true, TLS: tls}
+
+	// the secret is not issued yet
+	_, err := c.configureDashboardCert()
+	assert.EqualError(t, err, `waiting for cert-manager to issue the dashboard tls secret "dashboard-tls"`)
+
+	certificate := &unstructured.Unstructured{}
+	certificate.SetGroupVersionKind(certificateGVK)
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "rook-ceph", Name: "rook-ceph-mgr-dashboard"}, certificate))
+	assert.Equal(t, "dashboard-tls", certificate.Object["spec"].(map[string]interface{})["secretName"])
+	dnsNames, _, _ := unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	assert.Equal(t, []string{"rook-ceph-mgr-dashboard", "rook-ceph-mgr-dashboard.rook-ceph", "rook-ceph-mgr-dashboard.rook-ceph.svc", "dashboard.example.com"}, dnsNames)
+	issuer, _, _ := unstructured.NestedStringMap(certificate.Object, "spec", "issuerRef")
+	assert.Equal(t, map[string]string{"name": "letsencrypt", "kind": "ClusterIssuer", "group": "cert-manager.io"}, issuer)
+	duration, _, _ := unstructured.NestedString(certificate.Object, "spec", "duration")
+	assert.Equal(t, "2160h0m0s", duration)
+	assert.Equal(t, 1, len(certificate.GetOwnerReferences()))
+
+	// the certificate is updated with the spec
+	tls.CertManager.DNSNames = nil
+	assert.NoError(t, c.reconcileDashboardCertificate(tls))
+	assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "rook-ceph", Name: "rook-ceph-mgr-dashboard"}, certificate))
+	dnsNames, _, _ = unstructured.NestedStringSlice(certificate.Object, "spec", "dnsNames")
+	assert.Equal(t, 3, len(dnsNames))
+}
@@ -327,6 +327,11 @@ func (c *Cluster) reconcileServices() error {
 		}
 	}
 
+	// create, update or delete the prometheus rule, also when the monitoring is disabled
+	if err := c.ReconcilePrometheusRule(); err != nil {
+		return errors.Wrap(err, "failed to reconcile prometheus rule")
+	}
+
 	c.updateServiceSelectors()
 	return nil
 }
@@ -381,7 +386,7 @@ func (c *Cluster) configureModules(daemonIDs []string) {
 
 	// It is a bit confusing but modules that are in the "always_on_modules" list
 	// are "just" enabled, but still they must be configured to work properly
-	startModuleConfiguration("balancer", c.enableBalancerModule)
+	startModuleConfiguration("balancer", c.configureBalancer)
 	startModuleConfiguration("mgr module(s) from the spec", c.configureMgrModules)
 }
 
@@ -465,22 +470,37 @@ func (c *Cluster) enableBalancerModule() error {
 }
 
 func (c *Cluster) configureMgrModules() error {
-	// Enable mgr modules from the spec
 	for _, module := range c.spec.Mgr.Modules {
 		if module.Name == "" {
 			return errors.New("name not specified for the mgr module configuration")
 		}
 		if wellKnownModule(module.Name) {
-			return errors.Errorf("cannot configure mgr module %q that is configured with other cluster settings", module.Name)
+			// the well known modules are enabled from other cluster settings, only their settings
+			// can be configured
+			if module.Settings == nil {
+				return errors.Errorf("cannot configure mgr module %q that is configured with other cluster settings", module.Name)
+			}
+			continue
 		}
 		minVersion, versionOK := c.moduleMeetsMinVersion(module.Name)
 		if !versionOK {
 			return errors.Errorf("module %q cannot be configured because it requires at least Ceph version %q", module.Name, minVersion.String())
 		}
+	}
+
+	// Apply the module settings before enabling the modules
+	if err := c.configureModuleSettings(); err != nil {
+		return err
+	}
 
+	// Enable mgr modules from the spec
+	for _, module := range c.spec.Mgr.Modules {
+		if wellKnownModule(module.Name) {
+			continue
+		}
 		if module.Enabled {
-			if module.Name == balancerModuleName {
-				// Configure balancer module mode
+			if module.Name == balancerModuleName && c.spec.Balancer == nil {
+				// Configure balancer module mode, unless the balancer section of the spec configures it
 				err := cephclient.ConfigureBalancerModule(c.context, c.clusterInfo, balancerModuleMode)
 				if err != nil {
 					return errors.Wrapf(err, "failed to configure module %q", module.Name)
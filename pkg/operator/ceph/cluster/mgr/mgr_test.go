@@ -42,6 +42,7 @@ import (
 	policyv1beta1 "k8s.io/api/policy/v1beta1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/rest"
 	"sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
@@ -74,6 +75,8 @@ func createNewCluster(t *testing.T) *Cluster {
 		ConfigDir: configDir,
 		Clientset: clientset,
 		Client:    cl,
+		// the prometheus rule is deleted with the monitoring disabled
+		KubeConfig: &rest.Config{},
 	}
 	ownerInfo := cephclient.NewMinimumOwnerInfo(t)
 	clusterInfo := &cephclient.ClusterInfo{Namespace: "ns", FSID: "myfsid", OwnerInfo: ownerInfo, CephVersion: cephver.CephVersion{Major: 16, Minor: 2, Build: 5}, Context: context.TODO()}
@@ -317,8 +320,10 @@ func TestConfigureModules(t *testing.T) {
 	}
 
 	clientset := testop.New(t, 3)
-	context := &clusterd.Context{Executor: executor, Clientset: clientset}
 	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterInfo.NamespacedName().Name, Namespace: clusterInfo.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+	context := &clusterd.Context{Executor: executor, Clientset: clientset, Client: cl}
 	c := &Cluster{
 		context:     context,
 		clusterInfo: clusterInfo,
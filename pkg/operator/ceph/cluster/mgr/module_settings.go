@@ -0,0 +1,152 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+// configureModuleSettings applies the settings of the mgr modules of the spec, and removes the
+// settings applied before that are no longer in the spec. The settings applied are recorded in the
+// status so that the settings configured outside of the spec are never removed.
+func (c *Cluster) configureModuleSettings() error {
+	desired := map[string][]string{}
+	for _, module := range c.spec.Mgr.Modules {
+		for key := range module.Settings {
+			if c.isManagedModuleSetting(module.Name, key) {
+				return errors.Errorf("cannot set mgr module %q setting %q that is configured with other cluster settings", module.Name, key)
+			}
+			desired[module.Name] = append(desired[module.Name], key)
+		}
+		sort.Strings(desired[module.Name])
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.clusterInfo.Context, c.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve ceph cluster %q to configure the mgr module settings", c.clusterInfo.NamespacedName().Name)
+	}
+	applied := cephCluster.Status.MgrModuleSettings
+
+	// the settings are recorded before they are applied so that they are removed later even if the
+	// status update fails after they are applied
+	if err := c.updateModuleSettingsStatus(cephCluster, mergeModuleSettings(applied, desired)); err != nil {
+		return err
+	}
+
+	monStore := config.GetMonStore(c.context, c.clusterInfo)
+	for _, module := range c.spec.Mgr.Modules {
+		for _, key := range desired[module.Name] {
+			if _, err := monStore.SetIfChanged(config.MgrType, moduleSettingPrefix(module.Name)+key, module.Settings[key]); err != nil {
+				return errors.Wrapf(err, "failed to set mgr module %q setting %q", module.Name, key)
+			}
+		}
+	}
+
+	// remove the settings that are no longer in the spec, unless they are now configured with other
+	// cluster settings
+	modules := make([]string, 0, len(applied))
+	for module := range applied {
+		modules = append(modules, module)
+	}
+	sort.Strings(modules)
+	for _, module := range modules {
+		for _, key := range applied[module] {
+			if slices.Contains(desired[module], key) || c.isManagedModuleSetting(module, key) {
+				continue
+			}
+			logger.Infof("removing mgr module %q setting %q that is no longer in the spec", module, key)
+			if err := monStore.Delete(config.MgrType, moduleSettingPrefix(module)+key); err != nil {
+				return errors.Wrapf(err, "failed to remove mgr module %q setting %q", module, key)
+			}
+		}
+	}
+
+	return c.updateModuleSettingsStatus(cephCluster, desired)
+}
+
+// mergeModuleSettings returns the settings of both lists by module
+func mergeModuleSettings(a, b map[string][]string) map[string][]string {
+	merged := map[string][]string{}
+	for _, settings := range []map[string][]string{a, b} {
+		for module, keys := range settings {
+			for _, key := range keys {
+				if !slices.Contains(merged[module], key) {
+					merged[module] = append(merged[module], key)
+				}
+			}
+			sort.Strings(merged[module])
+		}
+	}
+	return merged
+}
+
+func (c *Cluster) updateModuleSettingsStatus(cephCluster *cephv1.CephCluster, settings map[string][]string) error {
+	if len(settings) == 0 {
+		settings = nil
+	}
+	if reflect.DeepEqual(cephCluster.Status.MgrModuleSettings, settings) {
+		return nil
+	}
+	cephCluster.Status.MgrModuleSettings = settings
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to update cluster %q mgr module settings status", cephCluster.Name)
+	}
+	return nil
+}
+
+// isManagedModuleSetting returns whether a module setting is configured from other cluster settings
+func (c *Cluster) isManagedModuleSetting(module, key string) bool {
+	var managed []string
+	switch module {
+	case PrometheusModuleName:
+		// the rbd stats pools are configured from the pools with the rbd stats enabled
+		managed = []string{"server_port", "scrape_interval", "rbd_stats_pools"}
+	case dashboardModuleName:
+		managed = []string{"url_prefix", "ssl", "server_port", "ssl_server_port", "PROMETHEUS_API_HOST", "PROMETHEUS_API_SSL_VERIFY"}
+	case balancerModuleName:
+		if c.spec.Balancer == nil {
+			return false
+		}
+		for _, option := range balancerOptions {
+			managed = append(managed, strings.TrimPrefix(option, moduleSettingPrefix(balancerModuleName)))
+		}
+	}
+	for _, managedKey := range managed {
+		if key == managedKey {
+			return true
+		}
+	}
+	return false
+}
+
+func moduleSettingPrefix(module string) string {
+	return fmt.Sprintf("mgr/%s/", module)
+}
@@ -0,0 +1,165 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestConfigureModuleSettings(t *testing.T) {
+	var commands []string
+	mgrConfig := map[string]string{}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			commands = append(commands, strings.Join(args[:4], " "))
+			return "", nil
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			switch {
+			case args[1] == "get" && strings.HasPrefix(args[3], "--"):
+				config := map[string]interface{}{}
+				for option, value := range mgrConfig {
+					config[option] = map[string]interface{}{"section": "mgr", "value": value}
+				}
+				b, err := json.Marshal(config)
+				return string(b), err
+			case args[1] == "get":
+				return mgrConfig[args[3]], nil
+			case args[1] == "set":
+				mgrConfig[args[3]] = args[4]
+			case args[1] == "rm":
+				delete(mgrConfig, args[3])
+			}
+			commands = append(commands, strings.Join(args[:4], " "))
+			return "", nil
+		},
+	}
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterInfo.NamespacedName().Name, Namespace: clusterInfo.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+	c := &Cluster{context: &clusterd.Context{Executor: executor, Client: cl}, clusterInfo: clusterInfo}
+	appliedSettings := func() map[string][]string {
+		updated := &cephv1.CephCluster{}
+		assert.NoError(t, cl.Get(clusterInfo.Context, clusterInfo.NamespacedName(), updated))
+		return updated.Status.MgrModuleSettings
+	}
+
+	t.Run("settings applied before enabling the module", func(t *testing.T) {
+		c.spec.Mgr.Modules = []cephv1.Module{
+			{Name: "telemetry", Enabled: true, Settings: map[string]string{"channel_crash": "false", "contact": "storage@example.com"}},
+		}
+		assert.NoError(t, c.configureMgrModules())
+		assert.Equal(t, []string{
+			"config set mgr mgr/telemetry/channel_crash",
+			"config set mgr mgr/telemetry/contact",
+			"mgr module enable telemetry",
+		}, commands)
+		assert.Equal(t, map[string][]string{"telemetry": {"channel_crash", "contact"}}, appliedSettings())
+	})
+
+	t.Run("settings removed from the spec", func(t *testing.T) {
+		commands = nil
+		mgrConfig["mgr/insights/manual"] = "true"
+		mgrConfig["mgr/telemetry/description"] = "set manually"
+		c.spec.Mgr.Modules[0].Settings = map[string]string{"contact": "storage@example.com"}
+		assert.NoError(t, c.configureMgrModules())
+		assert.Equal(t, []string{
+			"config rm mgr mgr/telemetry/channel_crash",
+			"mgr module enable telemetry",
+		}, commands)
+		// the settings that were not applied from the spec are kept
+		assert.Equal(t, map[string]string{
+			"mgr/telemetry/contact":     "storage@example.com",
+			"mgr/telemetry/description": "set manually",
+			"mgr/insights/manual":       "true",
+		}, mgrConfig)
+		assert.Equal(t, map[string][]string{"telemetry": {"contact"}}, appliedSettings())
+	})
+
+	t.Run("settings removed with the settings of the module", func(t *testing.T) {
+		commands = nil
+		c.spec.Mgr.Modules[0].Settings = nil
+		assert.NoError(t, c.configureMgrModules())
+		assert.Equal(t, []string{"config rm mgr mgr/telemetry/contact", "mgr module enable telemetry"}, commands)
+		assert.NotContains(t, mgrConfig, "mgr/telemetry/contact")
+		assert.Nil(t, appliedSettings())
+	})
+
+	t.Run("settings removed with the module", func(t *testing.T) {
+		c.spec.Mgr.Modules[0].Settings = map[string]string{"contact": "storage@example.com"}
+		assert.NoError(t, c.configureMgrModules())
+		assert.Equal(t, "storage@example.com", mgrConfig["mgr/telemetry/contact"])
+
+		commands = nil
+		c.spec.Mgr.Modules = nil
+		assert.NoError(t, c.configureMgrModules())
+		assert.Equal(t, []string{"config rm mgr mgr/telemetry/contact"}, commands)
+		assert.Equal(t, map[string]string{"mgr/telemetry/description": "set manually", "mgr/insights/manual": "true"}, mgrConfig)
+		assert.Nil(t, appliedSettings())
+	})
+
+	t.Run("settings of a well known module", func(t *testing.T) {
+		commands = nil
+		mgrConfig["mgr/prometheus/server_port"] = "9283"
+		mgrConfig["mgr/prometheus/rbd_stats_pools"] = "replicapool"
+		c.spec.Mgr.Modules = []cephv1.Module{
+			{Name: "prometheus", Settings: map[string]string{"stale_cache_strategy": "fail"}},
+		}
+		assert.NoError(t, c.configureMgrModules())
+		// the module is not enabled nor disabled, and the settings configured by rook are kept
+		assert.Equal(t, []string{"config set mgr mgr/prometheus/stale_cache_strategy"}, commands)
+		assert.Equal(t, "9283", mgrConfig["mgr/prometheus/server_port"])
+		assert.Equal(t, "replicapool", mgrConfig["mgr/prometheus/rbd_stats_pools"])
+
+		c.spec.Mgr.Modules[0].Settings = map[string]string{"server_port": "9000"}
+		assert.EqualError(t, c.configureMgrModules(), `cannot set mgr module "prometheus" setting "server_port" that is configured with other cluster settings`)
+
+		// the rbd stats pools are configured from the pools
+		c.spec.Mgr.Modules[0].Settings = map[string]string{"rbd_stats_pools": "otherpool"}
+		assert.EqualError(t, c.configureMgrModules(), `cannot set mgr module "prometheus" setting "rbd_stats_pools" that is configured with other cluster settings`)
+
+		c.spec.Mgr.Modules[0].Settings = nil
+		assert.EqualError(t, c.configureMgrModules(), `cannot configure mgr module "prometheus" that is configured with other cluster settings`)
+	})
+
+	t.Run("balancer settings of the balancer spec", func(t *testing.T) {
+		settings := map[string]string{"upmap_max_deviation": "1"}
+		c.spec.Mgr.Modules = []cephv1.Module{{Name: "balancer", Enabled: true, Settings: settings}}
+		assert.NoError(t, c.configureModuleSettings())
+
+		// the setting applied from the module is not removed once the balancer spec configures it
+		commands = nil
+		c.spec.Balancer = &cephv1.BalancerSpec{}
+		assert.EqualError(t, c.configureModuleSettings(), `cannot set mgr module "balancer" setting "upmap_max_deviation" that is configured with other cluster settings`)
+		c.spec.Mgr.Modules = nil
+		assert.NoError(t, c.configureModuleSettings())
+		assert.Empty(t, commands)
+		assert.Equal(t, "1", mgrConfig["mgr/balancer/upmap_max_deviation"])
+	})
+}
@@ -0,0 +1,169 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	_ "embed"
+	"regexp"
+	"sort"
+
+	"github.com/pkg/errors"
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/intstr"
+	"sigs.k8s.io/yaml"
+)
+
+// PrometheusRuleName is the name of the PrometheusRule with the ceph alerts managed by the operator
+const PrometheusRuleName = "rook-ceph-prometheus-rules"
+
+var (
+	// the alerts of the ceph cluster, kept identical to the alerts of the rook-ceph-cluster chart
+	//go:embed prometheus/localrules.yaml
+	localRules []byte
+	// the alerts of an external ceph cluster, kept identical to the alerts of the rook-ceph-cluster chart
+	//go:embed prometheus/externalrules.yaml
+	externalRules []byte
+	// the alerts on the metrics exported by the operator
+	//go:embed prometheus/rookrules.yaml
+	rookRules []byte
+
+	// alertThresholdRegex matches the threshold at the end of an alert expression
+	alertThresholdRegex = regexp.MustCompile(`(>=|<=|!=|>|<)(\s*)-?[0-9]+(\.[0-9]+)?(\s*)$`)
+)
+
+// ReconcilePrometheusRule creates or updates the PrometheusRule with the ceph alerts when enabled,
+// or deletes it when the rule or the monitoring is disabled
+func (c *Cluster) ReconcilePrometheusRule() error {
+	if !c.spec.Monitoring.Enabled || c.spec.Monitoring.PrometheusRule == nil || !c.spec.Monitoring.PrometheusRule.Enabled {
+		if err := k8sutil.DeletePrometheusRule(c.context, c.clusterInfo.Context, c.clusterInfo.Namespace, PrometheusRuleName); err != nil {
+			return errors.Wrap(err, "failed to delete prometheus rule")
+		}
+		return nil
+	}
+
+	rule, err := c.makePrometheusRule()
+	if err != nil {
+		return err
+	}
+	if _, err := k8sutil.CreateOrUpdatePrometheusRule(c.context, c.clusterInfo.Context, rule); err != nil {
+		return errors.Wrap(err, "prometheus rule could not be enabled")
+	}
+	return nil
+}
+
+// makePrometheusRule returns the PrometheusRule with the ceph alerts and the overrides of the spec.
+// The version label is updated with the operator so that the alerts are updated on upgrades.
+func (c *Cluster) makePrometheusRule() (*monitoringv1.PrometheusRule, error) {
+	rules := [][]byte{localRules, rookRules}
+	if c.spec.External.Enable {
+		rules = [][]byte{externalRules}
+	}
+	spec, err := buildPrometheusRuleSpec(rules, c.spec.Monitoring.PrometheusRule.Alerts)
+	if err != nil {
+		return nil, err
+	}
+
+	rule := &monitoringv1.PrometheusRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PrometheusRuleName,
+			Namespace: c.clusterInfo.Namespace,
+			Labels: map[string]string{
+				"prometheus": "rook-prometheus",
+				"role":       "alert-rules",
+			},
+		},
+		Spec: spec,
+	}
+	cephv1.GetMonitoringLabels(c.spec.Labels).OverwriteApplyToObjectMeta(&rule.ObjectMeta)
+	k8sutil.AddRookVersionLabelToObjectMeta(&rule.ObjectMeta)
+	if err := c.clusterInfo.OwnerInfo.SetControllerReference(rule); err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference to prometheus rule %q", rule.Name)
+	}
+	return rule, nil
+}
+
+// buildPrometheusRuleSpec merges the groups of the rules and applies the overrides of the alerts.
+// The overrides of unknown alerts and the unsupported thresholds are skipped with a warning so that
+// a typo in the spec does not fail the reconcile of the cluster.
+func buildPrometheusRuleSpec(rules [][]byte, alerts map[string]cephv1.PrometheusAlertSpec) (monitoringv1.PrometheusRuleSpec, error) {
+	spec := monitoringv1.PrometheusRuleSpec{}
+	for _, r := range rules {
+		var ruleSpec monitoringv1.PrometheusRuleSpec
+		if err := yaml.Unmarshal(r, &ruleSpec); err != nil {
+			return monitoringv1.PrometheusRuleSpec{}, errors.Wrap(err, "failed to parse prometheus rules")
+		}
+		spec.Groups = append(spec.Groups, ruleSpec.Groups...)
+	}
+
+	found := map[string]bool{}
+	for i := range spec.Groups {
+		group := &spec.Groups[i]
+		groupRules := make([]monitoringv1.Rule, 0, len(group.Rules))
+		for _, rule := range group.Rules {
+			override, ok := alerts[rule.Alert]
+			if !ok {
+				groupRules = append(groupRules, rule)
+				continue
+			}
+			found[rule.Alert] = true
+			if override.Disabled {
+				continue
+			}
+			applyAlertOverride(&rule, override)
+			groupRules = append(groupRules, rule)
+		}
+		group.Rules = groupRules
+	}
+
+	var unknown []string
+	for name := range alerts {
+		if !found[name] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		logger.Warningf("skipping the overrides of unknown alerts %v", unknown)
+	}
+	return spec, nil
+}
+
+func applyAlertOverride(rule *monitoringv1.Rule, override cephv1.PrometheusAlertSpec) {
+	if override.Threshold != "" {
+		expr := rule.Expr.String()
+		if alertThresholdRegex.MatchString(expr) {
+			rule.Expr = intstr.FromString(alertThresholdRegex.ReplaceAllString(expr, "${1}${2}"+override.Threshold+"${4}"))
+		} else {
+			logger.Warningf("skipping the threshold override of alert %q, its expression does not end with an inequality to a number", rule.Alert)
+		}
+	}
+	if override.For != "" {
+		duration := monitoringv1.Duration(override.For)
+		rule.For = &duration
+	}
+	if override.Severity != "" {
+		labels := make(map[string]string, len(rule.Labels)+1)
+		for k, v := range rule.Labels {
+			labels[k] = v
+		}
+		labels["severity"] = override.Severity
+		rule.Labels = labels
+	}
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mgr
+
+import (
+	"os"
+	"testing"
+
+	monitoringv1 "github.com/prometheus-operator/prometheus-operator/pkg/apis/monitoring/v1"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrometheusRulesInSyncWithChart(t *testing.T) {
+	// the rules of the operator must be updated with the rules of the chart
+	chartRules, err := os.ReadFile("../../../../../deploy/charts/rook-ceph-cluster/prometheus/localrules.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, string(chartRules), string(localRules))
+
+	chartRules, err = os.ReadFile("../../../../../deploy/charts/rook-ceph-cluster/prometheus/externalrules.yaml")
+	assert.NoError(t, err)
+	assert.Equal(t, string(chartRules), string(externalRules))
+}
+
+func findAlert(spec monitoringv1.PrometheusRuleSpec, name string) *monitoringv1.Rule {
+	for _, group := range spec.Groups {
+		for i := range group.Rules {
+			if group.Rules[i].Alert == name {
+				return &group.Rules[i]
+			}
+		}
+	}
+	return nil
+}
+
+func TestMakePrometheusRule(t *testing.T) {
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	c := &Cluster{clusterInfo: clusterInfo, spec: cephv1.ClusterSpec{
+		Labels: cephv1.LabelsSpec{cephv1.KeyMonitoring: map[string]string{"release": "prometheus"}},
+		Monitoring: cephv1.MonitoringSpec{
+			Enabled:        true,
+			PrometheusRule: &cephv1.PrometheusRuleSpec{Enabled: true},
+		},
+	}}
+
+	t.Run("default alerts", func(t *testing.T) {
+		rule, err := c.makePrometheusRule()
+		assert.NoError(t, err)
+		assert.Equal(t, PrometheusRuleName, rule.Name)
+		assert.Equal(t, "rook-ceph", rule.Namespace)
+		assert.Equal(t, "prometheus", rule.Labels["release"])
+		assert.Equal(t, "alert-rules", rule.Labels["role"])
+		assert.Contains(t, rule.Labels, k8sutil.RookVersionLabelKey)
+		assert.Len(t, rule.OwnerReferences, 1)
+		for _, name := range []string{"CephHealthError", "CephOSDDown", "CephOSDNearFull", "CephPGsInactive", "CephMonDownQuorumAtRisk", "CephRBDMirrorImageLagHigh"} {
+			assert.NotNil(t, findAlert(rule.Spec, name), name)
+		}
+		assert.Nil(t, findAlert(rule.Spec, "PersistentVolumeUsageNearFull"))
+	})
+
+	t.Run("overridden alerts", func(t *testing.T) {
+		c.spec.Monitoring.PrometheusRule.Alerts = map[string]cephv1.PrometheusAlertSpec{
+			"CephHealthWarning":         {Disabled: true},
+			"CephOSDDownHigh":           {Threshold: "20", For: "10m"},
+			"CephPGImbalance":           {Threshold: "0.5"},
+			"CephRBDMirrorImageLagHigh": {Threshold: "600", Severity: "critical"},
+		}
+		rule, err := c.makePrometheusRule()
+		assert.NoError(t, err)
+		assert.Nil(t, findAlert(rule.Spec, "CephHealthWarning"))
+		assert.NotNil(t, findAlert(rule.Spec, "CephHealthError"))
+
+		osdDownHigh := findAlert(rule.Spec, "CephOSDDownHigh")
+		assert.Equal(t, "count(ceph_osd_up == 0) / count(ceph_osd_up) * 100 >= 20", osdDownHigh.Expr.String())
+		assert.Equal(t, monitoringv1.Duration("10m"), *osdDownHigh.For)
+		assert.Contains(t, findAlert(rule.Spec, "CephPGImbalance").Expr.String(), "> 0.5")
+
+		mirrorLag := findAlert(rule.Spec, "CephRBDMirrorImageLagHigh")
+		assert.Equal(t, "rook_ceph_rbd_mirror_image_lag_seconds > 600", mirrorLag.Expr.String())
+		assert.Equal(t, "critical", mirrorLag.Labels["severity"])
+		assert.Equal(t, "rook_default", mirrorLag.Labels["type"])
+	})
+
+	t.Run("threshold not supported", func(t *testing.T) {
+		c.spec.Monitoring.PrometheusRule.Alerts = map[string]cephv1.PrometheusAlertSpec{"CephHealthError": {Threshold: "3", Severity: "warning"}}
+		rule, err := c.makePrometheusRule()
+		assert.NoError(t, err)
+		healthError := findAlert(rule.Spec, "CephHealthError")
+		assert.NotNil(t, healthError)
+		assert.Equal(t, "ceph_health_status == 2", healthError.Expr.String())
+		assert.Equal(t, "warning", healthError.Labels["severity"])
+	})
+
+	t.Run("unknown alert", func(t *testing.T) {
+		c.spec.Monitoring.PrometheusRule.Alerts = map[string]cephv1.PrometheusAlertSpec{"CephUnknown": {Disabled: true}, "CephOSDDown": {For: "1m"}}
+		rule, err := c.makePrometheusRule()
+		assert.NoError(t, err)
+		osdDown := findAlert(rule.Spec, "CephOSDDown")
+		assert.NotNil(t, osdDown)
+		assert.Equal(t, monitoringv1.Duration("1m"), *osdDown.For)
+	})
+
+	t.Run("external cluster", func(t *testing.T) {
+		c.spec.Monitoring.PrometheusRule.Alerts = nil
+		c.spec.External.Enable = true
+		rule, err := c.makePrometheusRule()
+		assert.NoError(t, err)
+		assert.NotNil(t, findAlert(rule.Spec, "PersistentVolumeUsageNearFull"))
+		assert.Nil(t, findAlert(rule.Spec, "CephHealthError"))
+	})
+}
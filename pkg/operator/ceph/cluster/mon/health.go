@@ -35,6 +35,7 @@ import (
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/apimachinery/pkg/util/sets"
+	"k8s.io/client-go/tools/record"
 )
 
 var (
@@ -55,6 +56,7 @@ var (
 type HealthChecker struct {
 	monCluster *Cluster
 	interval   time.Duration
+	recorder   record.EventRecorder
 }
 
 func updateMonTimeout(monCluster *Cluster) {
@@ -105,10 +107,11 @@ func updateMonInterval(monCluster *Cluster, h *HealthChecker) {
 }
 
 // NewHealthChecker creates a new HealthChecker object
-func NewHealthChecker(monCluster *Cluster) *HealthChecker {
+func NewHealthChecker(monCluster *Cluster, recorder record.EventRecorder) *HealthChecker {
 	h := &HealthChecker{
 		monCluster: monCluster,
 		interval:   HealthCheckInterval,
+		recorder:   recorder,
 	}
 	return h
 }
@@ -147,6 +150,9 @@ func (hc *HealthChecker) Check(monitoringRoutines map[string]*controller.Cluster
 			if err != nil {
 				logger.Warningf("failed to check mon health. %v", err)
 			}
+			if err := hc.monCluster.checkMonStores(hc.recorder); err != nil {
+				logger.Warningf("failed to check mon stores. %v", err)
+			}
 		}
 	}
 }
@@ -367,6 +373,16 @@ func (c *Cluster) checkHealth(ctx context.Context) error {
 		}
 	}
 
+	// migrate the storage of the mons that do not match the spec, one mon at a time
+	if c.migrateMonStorage(allMonsInQuorum && len(quorumStatus.MonMap.Mons) == desiredMonCount, desiredMonCount) {
+		return nil
+	}
+
+	// relocate the mons in zones removed from the stretch cluster, one mon at a time
+	if c.relocateStretchMon(allMonsInQuorum && len(quorumStatus.MonMap.Mons) == desiredMonCount, desiredMonCount) {
+		return nil
+	}
+
 	// failover any mons present in the mon fail over list
 	for _, mon := range c.ClusterInfo.Monitors {
 		if c.monsToFailover.Has(mon.Name) {
@@ -468,6 +484,12 @@ func (c *Cluster) determineExtraMonToRemove() string {
 }
 
 func (c *Cluster) findExtraMonToRemoveFromStretchCluster(mons []*monConfig) string {
+	// Remove the mons in zones that are not in the stretch cluster anymore first
+	if names := c.monsInRemovedStretchZones(mons); len(names) > 0 {
+		logger.Infof("removing extra mon %q in a zone removed from the stretch cluster", names[0])
+		return names[0]
+	}
+
 	// Build the count of current mons per zone
 	zoneCount := map[string]int{}
 	monInZones := map[string]string{}
@@ -482,6 +504,7 @@ func (c *Cluster) findExtraMonToRemoveFromStretchCluster(mons []*monConfig) stri
 	}
 
 	// Find a zone that has too many mons
+	mostMonsZone := ""
 	for _, zone := range c.spec.Mon.StretchCluster.Zones {
 		count, ok := zoneCount[zone.Name]
 		if !ok {
@@ -493,13 +516,21 @@ func (c *Cluster) findExtraMonToRemoveFromStretchCluster(mons []*monConfig) stri
 				logger.Infof("removing extra mon %q in arbiter zone %q", monInZones[zone.Name], zone.Name)
 				return monInZones[zone.Name]
 			}
-		} else {
+		} else if c.spec.StretchModeEnabled() {
 			if count > 2 {
 				logger.Infof("removing extra mon %q in zone %q", monInZones[zone.Name], zone.Name)
 				return monInZones[zone.Name]
 			}
+		} else if mostMonsZone == "" || count > zoneCount[mostMonsZone] {
+			mostMonsZone = zone.Name
 		}
 	}
+
+	// Without the ceph stretch mode, the mons are balanced across any number of data zones
+	if mostMonsZone != "" {
+		logger.Infof("removing extra mon %q in zone %q with the most mons", monInZones[mostMonsZone], mostMonsZone)
+		return monInZones[mostMonsZone]
+	}
 	return ""
 }
 
@@ -543,8 +574,8 @@ func (c *Cluster) failMon(monCount, desiredMonCount int, name string) bool {
 }
 
 func (c *Cluster) allowFailover(name string) error {
-	if !c.spec.IsStretchCluster() {
-		// always failover if not a stretch cluster
+	if !c.spec.StretchModeEnabled() {
+		// always failover if not in the ceph stretch mode
 		return nil
 	}
 	if name != c.arbiterMon {
@@ -709,7 +740,7 @@ func (c *Cluster) failoverMon(name string) error {
 	}
 
 	// Assign to a zone if a stretch cluster
-	if c.spec.IsStretchCluster() {
+	if c.spec.StretchModeEnabled() {
 		// Update the arbiter mon for the stretch cluster if it changed
 		if err := c.ConfigureArbiter(); err != nil {
 			return errors.Wrap(err, "failed to configure stretch arbiter")
@@ -736,9 +767,36 @@ func (c *Cluster) removeMonWithOptionalQuorum(daemonName string, shouldRemoveFro
 	}
 	logger.Infof("ensuring removal of unhealthy monitor %s", daemonName)
 
-	resourceName := resourceName(daemonName)
+	c.deleteMonDeployment(daemonName)
+
+	// Remove the bad monitor from quorum
+	if shouldRemoveFromQuorum {
+		if err := c.removeMonitorFromQuorum(daemonName); err != nil {
+			logger.Errorf("failed to remove mon %q from quorum. %v", daemonName, err)
+		}
+	}
+	delete(c.ClusterInfo.Monitors, daemonName)
+
+	delete(c.mapping.Schedule, daemonName)
+
+	c.deleteMonServiceAndPVC(daemonName)
+
+	if err := c.saveMonConfig(); err != nil {
+		return errors.Wrapf(err, "failed to save mon config after failing over mon %s", daemonName)
+	}
+
+	// Update cluster-wide RBD bootstrap peer token since Monitors have changed
+	_, err := controller.CreateBootstrapPeerSecret(c.context, c.ClusterInfo, &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: c.ClusterInfo.NamespacedName().Name, Namespace: c.Namespace}}, c.ownerInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to update cluster rbd bootstrap peer token")
+	}
+
+	return nil
+}
 
-	// Remove the mon pod if it is still there
+// deleteMonDeployment removes the deployment of a mon if it is still there
+func (c *Cluster) deleteMonDeployment(daemonName string) {
+	resourceName := resourceName(daemonName)
 	var gracePeriod int64
 	propagation := metav1.DeletePropagationForeground
 	options := &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod, PropagationPolicy: &propagation}
@@ -749,18 +807,14 @@ func (c *Cluster) removeMonWithOptionalQuorum(daemonName string, shouldRemoveFro
 			logger.Errorf("failed to remove dead mon deployment %q. %v", resourceName, err)
 		}
 	}
+}
 
-	// Remove the bad monitor from quorum
-	if shouldRemoveFromQuorum {
-		if err := c.removeMonitorFromQuorum(daemonName); err != nil {
-			logger.Errorf("failed to remove mon %q from quorum. %v", daemonName, err)
-		}
-	}
-	delete(c.ClusterInfo.Monitors, daemonName)
-
-	delete(c.mapping.Schedule, daemonName)
-
-	// Remove the service endpoint
+// deleteMonServiceAndPVC removes the service endpoint of a mon and the PVC backing the mon if it existed
+func (c *Cluster) deleteMonServiceAndPVC(daemonName string) {
+	resourceName := resourceName(daemonName)
+	var gracePeriod int64
+	propagation := metav1.DeletePropagationForeground
+	options := &metav1.DeleteOptions{GracePeriodSeconds: &gracePeriod, PropagationPolicy: &propagation}
 	if err := c.context.Clientset.CoreV1().Services(c.Namespace).Delete(c.ClusterInfo.Context, resourceName, *options); err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Infof("dead mon service %s was already gone", resourceName)
@@ -769,7 +823,6 @@ func (c *Cluster) removeMonWithOptionalQuorum(daemonName string, shouldRemoveFro
 		}
 	}
 
-	// Remove the PVC backing the mon if it existed
 	if err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Delete(c.ClusterInfo.Context, resourceName, metav1.DeleteOptions{}); err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Infof("mon pvc did not exist %q", resourceName)
@@ -777,18 +830,6 @@ func (c *Cluster) removeMonWithOptionalQuorum(daemonName string, shouldRemoveFro
 			logger.Errorf("failed to remove dead mon pvc %q. %v", resourceName, err)
 		}
 	}
-
-	if err := c.saveMonConfig(); err != nil {
-		return errors.Wrapf(err, "failed to save mon config after failing over mon %s", daemonName)
-	}
-
-	// Update cluster-wide RBD bootstrap peer token since Monitors have changed
-	_, err := controller.CreateBootstrapPeerSecret(c.context, c.ClusterInfo, &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: c.ClusterInfo.NamespacedName().Name, Namespace: c.Namespace}}, c.ownerInfo)
-	if err != nil {
-		return errors.Wrap(err, "failed to update cluster rbd bootstrap peer token")
-	}
-
-	return nil
 }
 
 func (c *Cluster) removeMonitorFromQuorum(name string) error {
@@ -25,6 +25,7 @@ import (
 	"time"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
@@ -40,6 +41,7 @@ import (
 	v1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
 )
 
 func TestCheckHealth(t *testing.T) {
@@ -594,10 +596,10 @@ func TestNewHealthChecker(t *testing.T) {
 		args args
 		want *HealthChecker
 	}{
-		"default-interval", args{c}, &HealthChecker{c, HealthCheckInterval},
+		"default-interval", args{c}, &HealthChecker{c, HealthCheckInterval, nil},
 	}
 	t.Run(tests.name, func(t *testing.T) {
-		if got := NewHealthChecker(tests.args.monCluster); !reflect.DeepEqual(got, tests.want) {
+		if got := NewHealthChecker(tests.args.monCluster, nil); !reflect.DeepEqual(got, tests.want) {
 			t.Errorf("NewHealthChecker() = %v, want %v", got, tests.want)
 		}
 	})
@@ -625,14 +627,14 @@ func TestUpdateMonTimeout(t *testing.T) {
 func TestUpdateMonInterval(t *testing.T) {
 	t.Run("using default mon interval", func(t *testing.T) {
 		m := &Cluster{}
-		h := &HealthChecker{m, HealthCheckInterval}
+		h := &HealthChecker{m, HealthCheckInterval, nil}
 		updateMonInterval(m, h)
 		assert.Equal(t, time.Second*45, HealthCheckInterval)
 	})
 	t.Run("using env var mon timeout", func(t *testing.T) {
 		t.Setenv("ROOK_MON_HEALTHCHECK_INTERVAL", "10s")
 		m := &Cluster{}
-		h := &HealthChecker{m, HealthCheckInterval}
+		h := &HealthChecker{m, HealthCheckInterval, nil}
 		updateMonInterval(m, h)
 		assert.Equal(t, time.Second*10, h.interval)
 	})
@@ -640,8 +642,88 @@ func TestUpdateMonInterval(t *testing.T) {
 		tm, err := time.ParseDuration("1m")
 		assert.NoError(t, err)
 		m := &Cluster{spec: cephv1.ClusterSpec{HealthCheck: cephv1.CephClusterHealthCheckSpec{DaemonHealth: cephv1.DaemonHealthSpec{Monitor: cephv1.HealthCheckSpec{Interval: &metav1.Duration{Duration: tm}}}}}}
-		h := &HealthChecker{m, HealthCheckInterval}
+		h := &HealthChecker{m, HealthCheckInterval, nil}
 		updateMonInterval(m, h)
 		assert.Equal(t, time.Minute, h.interval)
 	})
 }
+
+func TestMigrateMonStorage(t *testing.T) {
+	ctx := context.TODO()
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("executing command: %s %+v", command, args)
+			return "{\"key\":\"mysecurekey\"}", nil
+		},
+	}
+	clusterInfo := clienttest.CreateTestClusterInfo(2)
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterInfo.NamespacedName().Name, Namespace: clusterInfo.Namespace}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+	context := &clusterd.Context{Clientset: test.New(t, 1), Client: client, ConfigDir: t.TempDir(), Executor: executor}
+	c := New(ctx, context, clusterInfo.Namespace, cephv1.ClusterSpec{}, cephclient.NewMinimumOwnerInfoWithOwnerRef())
+	c.ClusterInfo = clusterInfo
+
+	getCondition := func() *cephv1.Condition {
+		updated := &cephv1.CephCluster{}
+		assert.NoError(t, client.Get(ctx, clusterInfo.NamespacedName(), updated))
+		return cephv1.FindStatusCondition(updated.Status.Conditions, cephv1.ConditionMonMigration)
+	}
+
+	t.Run("nothing to migrate", func(t *testing.T) {
+		assert.False(t, c.migrateMonStorage(true, 1))
+		assert.Nil(t, getCondition())
+	})
+
+	c.monsToMigrate["a"] = "host path to pvc"
+	c.monsToMigrate["z"] = "host path to pvc"
+
+	t.Run("waiting for quorum", func(t *testing.T) {
+		assert.False(t, c.migrateMonStorage(false, 1))
+		// mons that no longer exist are not migrated
+		assert.Equal(t, map[string]string{"a": "host path to pvc"}, c.monsToMigrate)
+		condition := getCondition()
+		assert.Equal(t, v1.ConditionTrue, condition.Status)
+		assert.Equal(t, cephv1.MonMigrationInProgressReason, condition.Reason)
+		assert.Contains(t, condition.Message, "waiting for all mons to be in quorum")
+	})
+
+	t.Run("mon migrated", func(t *testing.T) {
+		// the mon is removed instead of failed over since there is an extra mon
+		assert.True(t, c.migrateMonStorage(true, 1))
+		assert.Empty(t, c.monsToMigrate)
+		assert.NotContains(t, c.ClusterInfo.Monitors, "a")
+		condition := getCondition()
+		assert.Equal(t, v1.ConditionFalse, condition.Status)
+		assert.Equal(t, cephv1.MonMigrationCompletedReason, condition.Reason)
+	})
+}
+
+func TestRemoveExtraMonFromStretchPoolsCluster(t *testing.T) {
+	endpoint := "1.2.3.4:6789"
+	c := &Cluster{mapping: &opcontroller.Mapping{}}
+	c.spec.Mon.StretchCluster = &cephv1.StretchClusterSpec{
+		Mode: cephv1.StretchModePools,
+		Zones: []cephv1.MonZoneSpec{
+			{Name: "w", Arbiter: true},
+			{Name: "x"},
+			{Name: "y"},
+			{Name: "z"},
+		},
+	}
+	c.ClusterInfo = &cephclient.ClusterInfo{Monitors: map[string]*cephclient.MonInfo{}}
+	c.mapping.Schedule = map[string]*opcontroller.MonScheduleInfo{}
+	for name, zone := range map[string]string{"a": "w", "b": "x", "c": "y", "d": "y", "e": "z", "f": "v"} {
+		c.ClusterInfo.Monitors[name] = &cephclient.MonInfo{Name: name, Endpoint: endpoint}
+		c.mapping.Schedule[name] = &opcontroller.MonScheduleInfo{Name: "node-" + name, Zone: zone}
+	}
+
+	// Remove the mon in the zone removed from the stretch cluster first
+	assert.Equal(t, "f", c.determineExtraMonToRemove())
+
+	// Remove a mon from the data zone with the most mons
+	delete(c.ClusterInfo.Monitors, "f")
+	removedMon := c.determineExtraMonToRemove()
+	if removedMon != "c" && removedMon != "d" {
+		assert.Fail(t, fmt.Sprintf("removed mon %q instead of c or d from the zone with the most mons", removedMon))
+	}
+}
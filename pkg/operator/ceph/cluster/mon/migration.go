@@ -0,0 +1,143 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	apps "k8s.io/api/apps/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// monStorageMigration returns the migration required for the storage of an existing mon to match
+// the volume claim template, or an empty string if the mon storage is already as desired
+func (c *Cluster) monStorageMigration(d *apps.Deployment, claim *v1.PersistentVolumeClaim) (string, error) {
+	pvcName := d.Labels["pvc_name"]
+	if pvcName == "" {
+		if claim != nil {
+			return "host path to pvc", nil
+		}
+		return "", nil
+	}
+	if claim == nil {
+		return "pvc to host path", nil
+	}
+
+	// the storage class can only be compared when the template sets it explicitly
+	desiredClass := claim.Spec.StorageClassName
+	if desiredClass == nil || *desiredClass == "" {
+		return "", nil
+	}
+	pvc, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Get(c.ClusterInfo.Context, pvcName, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return "", nil
+		}
+		return "", errors.Wrapf(err, "failed to get mon pvc %q", pvcName)
+	}
+	currentClass := ""
+	if pvc.Spec.StorageClassName != nil {
+		currentClass = *pvc.Spec.StorageClassName
+	}
+	if currentClass != *desiredClass {
+		return fmt.Sprintf("storage class %q to %q", currentClass, *desiredClass), nil
+	}
+	return "", nil
+}
+
+// migrateMonStorage fails over the next mon whose storage does not match the spec so that the new
+// mon is created with the desired storage. Only one mon is migrated per health check, and only
+// when all the mons are in quorum. Returns whether a mon was failed over.
+func (c *Cluster) migrateMonStorage(allMonsInQuorum bool, desiredMonCount int) bool {
+	for name := range c.monsToMigrate {
+		if _, ok := c.ClusterInfo.Monitors[name]; !ok {
+			delete(c.monsToMigrate, name)
+		}
+	}
+	if len(c.monsToMigrate) == 0 {
+		if c.monMigrationInProgress {
+			c.completeMonMigration()
+		}
+		return false
+	}
+
+	names := make([]string, 0, len(c.monsToMigrate))
+	for name := range c.monsToMigrate {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if !allMonsInQuorum {
+		c.updateMonMigrationCondition(v1.ConditionTrue, cephv1.MonMigrationInProgressReason,
+			fmt.Sprintf("waiting for all mons to be in quorum to migrate the storage of mons %v", names))
+		return false
+	}
+
+	name := names[0]
+	logger.Infof("migrating the storage of mon %q from %s", name, c.monsToMigrate[name])
+	c.updateMonMigrationCondition(v1.ConditionTrue, cephv1.MonMigrationInProgressReason,
+		fmt.Sprintf("migrating the storage of mon %q from %s, %d mon(s) left to migrate", name, c.monsToMigrate[name], len(names)))
+
+	c.failMon(len(c.ClusterInfo.Monitors), desiredMonCount, name)
+	if _, ok := c.ClusterInfo.Monitors[name]; ok {
+		logger.Warningf("storage of mon %q not migrated, retrying at the next mon health check", name)
+		return true
+	}
+
+	delete(c.monsToMigrate, name)
+	logger.Infof("migrated the storage of mon %q", name)
+	if len(c.monsToMigrate) == 0 {
+		c.completeMonMigration()
+	}
+	return true
+}
+
+func (c *Cluster) completeMonMigration() {
+	logger.Info("the storage of all the mons matches the cluster spec")
+	c.updateMonMigrationCondition(v1.ConditionFalse, cephv1.MonMigrationCompletedReason, "the storage of all the mons matches the cluster spec")
+}
+
+// updateMonMigrationCondition reports the progress of the mon storage migration in the cluster
+// conditions without changing the phase of the cluster
+func (c *Cluster) updateMonMigrationCondition(status v1.ConditionStatus, reason cephv1.ConditionReason, message string) {
+	c.monMigrationInProgress = status == v1.ConditionTrue
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster); err != nil {
+		logger.Errorf("failed to get cluster %q to update the mon migration condition. %v", c.ClusterInfo.NamespacedName().Name, err)
+		return
+	}
+	current := cephv1.FindStatusCondition(cephCluster.Status.Conditions, cephv1.ConditionMonMigration)
+	if current != nil && current.Status == status && current.Reason == reason && current.Message == message {
+		return
+	}
+	cephv1.SetStatusCondition(&cephCluster.Status.Conditions, cephv1.Condition{
+		Type:    cephv1.ConditionMonMigration,
+		Status:  status,
+		Reason:  reason,
+		Message: message,
+	})
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		logger.Errorf("failed to update the mon migration condition. %v", err)
+	}
+}
@@ -119,6 +119,11 @@ type Cluster struct {
 	arbiterMon         string
 	// list of mons to be failed over
 	monsToFailover sets.Set[string]
+	// mons to be failed over to migrate their storage, with the migration required
+	monsToMigrate          map[string]string
+	monMigrationInProgress bool
+	// held while a mon store is compacted in the background
+	monStoreCompactionMutex sync.Mutex
 }
 
 // monConfig for a single monitor
@@ -167,6 +172,7 @@ func New(ctx context.Context, clusterdContext *clusterd.Context, namespace strin
 			Context: ctx,
 		},
 		monsToFailover: sets.New[string](),
+		monsToMigrate:  map[string]string{},
 	}
 }
 
@@ -323,6 +329,11 @@ func (c *Cluster) configureStretchCluster(mons []*monConfig) error {
 		return errors.Wrap(err, "failed to enable stretch cluster")
 	}
 
+	// Without the ceph stretch mode, only the pools with stretch settings are stretched across the zones
+	if !c.spec.StretchModeEnabled() {
+		return nil
+	}
+
 	// Create the default crush rule for stretch clusters, that by default will also apply to all pools
 	if err := cephclient.CreateDefaultStretchCrushRule(c.context, c.ClusterInfo, &c.spec, c.getFailureDomainName()); err != nil {
 		return errors.Wrap(err, "failed to create default stretch rule")
@@ -352,11 +363,16 @@ func (c *Cluster) isArbiterZone(zone string) bool {
 }
 
 func (c *Cluster) ConfigureArbiter() error {
+	monDump, err := cephclient.GetMonDump(c.context, c.ClusterInfo)
 	if c.arbiterMon == "" {
+		if err == nil && monDump.StretchMode {
+			// the arbiter zone was replaced, the tiebreaker is updated when a mon is relocated to the new zone
+			logger.Infof("waiting for a mon in the arbiter zone %q to replace tiebreaker mon %q", c.getArbiterZone(), monDump.TiebreakerMon)
+			return nil
+		}
 		return errors.New("arbiter not specified for the stretch cluster")
 	}
 
-	monDump, err := cephclient.GetMonDump(c.context, c.ClusterInfo)
 	if err != nil {
 		logger.Warningf("attempting to enable arbiter after failed to detect if already enabled. %v", err)
 	} else if monDump.StretchMode {
@@ -631,16 +647,25 @@ func (c *Cluster) findAvailableZone(mons []*monConfig) (string, error) {
 	}
 
 	// Find a zone in the stretch cluster that still needs an assignment
+	leastMonsZone := ""
 	for _, zone := range zones {
 		count, ok := zoneCount[zone.Name]
 		if !ok {
 			// The zone isn't currently assigned to any mon, so return it
 			return zone.Name, nil
 		}
-		if c.spec.IsStretchCluster() && c.spec.Mon.Count == 5 && count == 1 && !zone.Arbiter {
+		if c.spec.StretchModeEnabled() && c.spec.Mon.Count == 5 && count == 1 && !zone.Arbiter {
 			// The zone only has 1 mon assigned, but needs 2 mons since it is not the arbiter
 			return zone.Name, nil
 		}
+		if !zone.Arbiter && (leastMonsZone == "" || count < zoneCount[leastMonsZone]) {
+			leastMonsZone = zone.Name
+		}
+	}
+
+	// Without the ceph stretch mode, the mons are balanced across any number of data zones
+	if c.spec.IsStretchCluster() && !c.spec.StretchModeEnabled() && leastMonsZone != "" {
+		return leastMonsZone, nil
 	}
 	return "", errors.New("A zone is not available to assign a new mon")
 }
@@ -1345,11 +1370,18 @@ func (c *Cluster) startMon(m *monConfig, schedule *controller.MonScheduleInfo) e
 
 	p.ApplyToPodSpec(&d.Spec.Template.Spec)
 	if deploymentExists {
-		// skip update if mon path has changed
-		if hasMonPathChanged(existingDeployment, c.spec.Mon.VolumeClaimTemplate.ToPVC()) {
-			c.monsToFailover.Insert(m.DaemonName)
+		// skip update if the mon storage must be migrated, the mon is failed over by the health
+		// check to create a new mon with the desired storage
+		migration, err := c.monStorageMigration(existingDeployment, c.monVolumeClaimTemplate(m))
+		if err != nil {
+			return errors.Wrapf(err, "failed to check the storage of mon %q", m.DaemonName)
+		}
+		if migration != "" {
+			logger.Infof("skipping update for mon %q whose storage must be migrated from %s", m.DaemonName, migration)
+			c.monsToMigrate[m.DaemonName] = migration
 			return nil
 		}
+		delete(c.monsToMigrate, m.DaemonName)
 
 		// skip update if mon fail over is required due to change in hostnetwork settings
 		if isMonIPUpdateRequiredForHostNetwork(m.DaemonName, m.UseHostNetwork, &c.spec.Network) {
@@ -1435,18 +1467,6 @@ func isMonIPUpdateRequiredForHostNetwork(mon string, isMonUsingHostNetwork bool,
 	return false
 }
 
-func hasMonPathChanged(d *apps.Deployment, claim *v1.PersistentVolumeClaim) bool {
-	if d.Labels["pvc_name"] == "" && claim != nil {
-		logger.Infof("skipping update for mon %q where path has changed from hostPath to pvc", d.Name)
-		return true
-	} else if d.Labels["pvc_name"] != "" && claim == nil {
-		logger.Infof("skipping update for mon %q where path has changed from pvc to hostPath", d.Name)
-		return true
-	}
-
-	return false
-}
-
 func waitForQuorumWithMons(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, mons []string, sleepTime int, requireAllInQuorum bool) error {
 	logger.Infof("waiting for mon quorum with %v", mons)
 
@@ -123,6 +123,7 @@ func newCluster(context *clusterd.Context, namespace string, allowMultiplePerNod
 		},
 		ownerInfo:      ownerInfo,
 		monsToFailover: sets.New[string](),
+		monsToMigrate:  map[string]string{},
 	}
 }
 
@@ -528,6 +529,13 @@ func TestConfigureArbiter(t *testing.T) {
 		assert.NoError(t, err)
 		assert.True(t, setNewTiebreaker)
 	})
+	t.Run("waiting for a mon in the replaced arbiter zone", func(t *testing.T) {
+		setNewTiebreaker = false
+		c.arbiterMon = ""
+		err := c.ConfigureArbiter()
+		assert.NoError(t, err)
+		assert.False(t, setNewTiebreaker)
+	})
 }
 
 func TestFindAvailableZoneMon(t *testing.T) {
@@ -644,6 +652,45 @@ func TestFindAvailableZoneForStretchedMon(t *testing.T) {
 	assert.Equal(t, "a", availableZone)
 }
 
+func TestFindAvailableZoneForStretchedPoolsMon(t *testing.T) {
+	c := &Cluster{spec: cephv1.ClusterSpec{
+		Mon: cephv1.MonSpec{
+			Count: 5,
+			StretchCluster: &cephv1.StretchClusterSpec{
+				Mode: cephv1.StretchModePools,
+				Zones: []cephv1.MonZoneSpec{
+					{Name: "a", Arbiter: true},
+					{Name: "b"},
+					{Name: "c"},
+					{Name: "d"},
+				},
+			},
+		},
+	}}
+
+	// The zones without a mon are assigned first
+	existingMons := []*monConfig{
+		{ResourceName: "w", Zone: "b"},
+		{ResourceName: "x", Zone: "c"},
+		{ResourceName: "y", Zone: "d"},
+	}
+	availableZone, err := c.findAvailableZone(existingMons)
+	assert.NoError(t, err)
+	assert.Equal(t, "a", availableZone)
+
+	// The data zone with the fewest mons is assigned, never a second mon in the arbiter zone
+	existingMons = []*monConfig{
+		{ResourceName: "v", Zone: "a"},
+		{ResourceName: "w", Zone: "b"},
+		{ResourceName: "x", Zone: "b"},
+		{ResourceName: "y", Zone: "c"},
+		{ResourceName: "z", Zone: "d"},
+	}
+	availableZone, err = c.findAvailableZone(existingMons)
+	assert.NoError(t, err)
+	assert.Equal(t, "c", availableZone)
+}
+
 func TestMonVolumeClaimTemplate(t *testing.T) {
 	generalSC := "generalSC"
 	zoneSC := "zoneSC"
@@ -887,7 +934,19 @@ func TestSkipReconcile(t *testing.T) {
 	assert.Equal(t, 1, result.Len())
 }
 
-func TestHasMonPathChanged(t *testing.T) {
+func TestMonStorageMigration(t *testing.T) {
+	ctx := context.TODO()
+	clientset := test.New(t, 1)
+	c := New(ctx, &clusterd.Context{Clientset: clientset}, "test", cephv1.ClusterSpec{}, nil)
+	c.ClusterInfo = clienttest.CreateTestClusterInfo(1)
+
+	currentClass := "current"
+	_, err := clientset.CoreV1().PersistentVolumeClaims("test").Create(ctx, &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "test-pvc", Namespace: "test"},
+		Spec:       v1.PersistentVolumeClaimSpec{StorageClassName: &currentClass},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
 	monDeployment := &apps.Deployment{
 		ObjectMeta: metav1.ObjectMeta{
 			Name:      "rook-ceph-mon-a",
@@ -903,20 +962,44 @@ func TestHasMonPathChanged(t *testing.T) {
 		},
 	}
 	t.Run("mon path changed from pv to hostpath", func(t *testing.T) {
-		assert.True(t, hasMonPathChanged(monDeployment, nil))
+		migration, err := c.monStorageMigration(monDeployment, nil)
+		assert.NoError(t, err)
+		assert.Equal(t, "pvc to host path", migration)
 	})
 
 	t.Run("mon path not changed from pv to hostpath", func(t *testing.T) {
-		assert.False(t, hasMonPathChanged(monDeployment, pvcTemplate))
+		migration, err := c.monStorageMigration(monDeployment, pvcTemplate)
+		assert.NoError(t, err)
+		assert.Empty(t, migration)
+	})
+
+	t.Run("mon storage class not changed", func(t *testing.T) {
+		pvcTemplate.Spec.StorageClassName = &currentClass
+		migration, err := c.monStorageMigration(monDeployment, pvcTemplate)
+		assert.NoError(t, err)
+		assert.Empty(t, migration)
 	})
+
+	t.Run("mon storage class changed", func(t *testing.T) {
+		desiredClass := "desired"
+		pvcTemplate.Spec.StorageClassName = &desiredClass
+		migration, err := c.monStorageMigration(monDeployment, pvcTemplate)
+		assert.NoError(t, err)
+		assert.Equal(t, `storage class "current" to "desired"`, migration)
+	})
+
 	t.Run("mon path changed from hostPath to pvc", func(t *testing.T) {
 		delete(monDeployment.Labels, "pvc_name")
-		assert.True(t, hasMonPathChanged(monDeployment, pvcTemplate))
+		migration, err := c.monStorageMigration(monDeployment, pvcTemplate)
+		assert.NoError(t, err)
+		assert.Equal(t, "host path to pvc", migration)
 	})
 
 	t.Run("mon path not changed from hostPath to pvc", func(t *testing.T) {
 		delete(monDeployment.Labels, "pvc_name")
-		assert.False(t, hasMonPathChanged(monDeployment, nil))
+		migration, err := c.monStorageMigration(monDeployment, nil)
+		assert.NoError(t, err)
+		assert.Empty(t, migration)
 	})
 }
 
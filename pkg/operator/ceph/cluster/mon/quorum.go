@@ -0,0 +1,199 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	monQuorumRestoreReason       = "MonQuorumRestore"
+	monQuorumRestoredReason      = "MonQuorumRestored"
+	monQuorumRestoreFailedReason = "MonQuorumRestoreFailed"
+)
+
+// monDaemonStatus is the mon_status reported by the admin socket of a mon, which is available
+// even when the mons are not in quorum
+type monDaemonStatus struct {
+	Name   string `json:"name"`
+	State  string `json:"state"`
+	MonMap struct {
+		Epoch int `json:"epoch"`
+	} `json:"monmap"`
+}
+
+// RestoreQuorum restores the mon quorum from a single surviving mon when the other mons are lost.
+// Nothing is done while any mon reports on its admin socket that it is in quorum. If the surviving
+// mon is not given, the mon responding on its admin socket with the most recent monmap is picked. All the mons are stopped, the other mons are removed from the monmap of the
+// surviving mon, then the resources of the other mons are removed and the mon endpoints are saved
+// with the surviving mon only. The next mon reconcile starts the surviving mon, which forms quorum
+// on its own, and grows the quorum back to the desired mon count. Each step is recorded as an event
+// on the CephCluster.
+func (c *Cluster) RestoreQuorum(clusterInfo *cephclient.ClusterInfo, cephVersion cephver.CephVersion, spec cephv1.ClusterSpec, survivor string, recorder record.EventRecorder) error {
+	c.acquireOrchestrationLock()
+	defer c.releaseOrchestrationLock()
+
+	clusterInfo.OwnerInfo = c.ownerInfo
+	c.ClusterInfo = clusterInfo
+	c.spec = spec
+
+	if err := c.initClusterInfo(cephVersion, c.ClusterInfo.NamespacedName().Name); err != nil {
+		return errors.Wrap(err, "failed to initialize ceph cluster info")
+	}
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to get cluster %q", c.ClusterInfo.NamespacedName().Name)
+	}
+	recordEvent := func(eventType, reason, message string) {
+		logger.Info(message)
+		recorder.Event(cephCluster, eventType, reason, message)
+	}
+	fail := func(err error) error {
+		recorder.Event(cephCluster, v1.EventTypeWarning, monQuorumRestoreFailedReason, err.Error())
+		return err
+	}
+
+	if survivor != "" {
+		if _, ok := c.ClusterInfo.Monitors[survivor]; !ok {
+			return fail(errors.Errorf("failed to restore the mon quorum, mon %q to restore the quorum from is not an existing mon", survivor))
+		}
+	}
+
+	// the quorum is only restored when no mon is in quorum, even if the mon to restore the quorum
+	// from is given, since restoring the quorum removes all the other mons
+	healthiest, inQuorum, err := c.findSurvivingMon()
+	if len(inQuorum) > 0 {
+		recorder.Event(cephCluster, v1.EventTypeWarning, monQuorumRestoreFailedReason,
+			fmt.Sprintf("not restoring the mon quorum since mons %v are in quorum", inQuorum))
+		return nil
+	}
+	if survivor == "" {
+		if err != nil {
+			return fail(err)
+		}
+		survivor = healthiest
+	}
+
+	var deadMons []string
+	for name := range c.ClusterInfo.Monitors {
+		if name != survivor {
+			deadMons = append(deadMons, name)
+		}
+	}
+	sort.Strings(deadMons)
+	if len(deadMons) == 0 {
+		recordEvent(v1.EventTypeNormal, monQuorumRestoredReason, fmt.Sprintf("mon %q is the only mon, no mon to remove to restore the quorum", survivor))
+		return nil
+	}
+	recordEvent(v1.EventTypeNormal, monQuorumRestoreReason, fmt.Sprintf("restoring the mon quorum from mon %q, removing mons %v", survivor, deadMons))
+
+	// the lost mons may still be running on reachable nodes, stop them so they do not join again
+	// with the old monmap
+	for _, name := range deadMons {
+		if err := c.updateMonDeploymentReplica(name, false); err != nil {
+			logger.Warningf("failed to stop mon %q. %v", name, err)
+		}
+	}
+	if err := c.updateMonDeploymentReplica(survivor, false); err != nil {
+		return fail(errors.Wrapf(err, "failed to stop mon %q to restore the mon quorum", survivor))
+	}
+	if err := c.waitForMonPodsToStop(survivor); err != nil {
+		return fail(err)
+	}
+	recordEvent(v1.EventTypeNormal, monQuorumRestoreReason, fmt.Sprintf("stopped the mons to remove mons %v from the monmap of mon %q", deadMons, survivor))
+
+	job, err := c.makeMonMaintenanceJob(survivor, "restore", restoreQuorumMonmapScript(survivor))
+	if err != nil {
+		return fail(errors.Wrapf(err, "failed to make the job to restore the mon quorum from mon %q", survivor))
+	}
+	if err := runMonMaintenanceJob(c.ClusterInfo.Context, c.context, job); err != nil {
+		return fail(errors.Wrapf(err, "failed to remove mons %v from the monmap of mon %q", deadMons, survivor))
+	}
+	recordEvent(v1.EventTypeNormal, monQuorumRestoreReason, fmt.Sprintf("removed mons %v from the monmap of mon %q", deadMons, survivor))
+
+	// the mons cannot be removed from quorum with ceph commands while the surviving mon is stopped,
+	// they are already removed from its monmap
+	for _, name := range deadMons {
+		c.deleteMonDeployment(name)
+		delete(c.ClusterInfo.Monitors, name)
+		delete(c.mapping.Schedule, name)
+		c.deleteMonServiceAndPVC(name)
+	}
+	c.ClusterInfo.Monitors[survivor].OutOfQuorum = false
+	if err := c.saveMonConfig(); err != nil {
+		return fail(errors.Wrapf(err, "failed to save the mon config with the surviving mon %q", survivor))
+	}
+	recordEvent(v1.EventTypeNormal, monQuorumRestoredReason,
+		fmt.Sprintf("restored the mon quorum from mon %q, the quorum is grown back to %d mons by the mon reconcile", survivor, c.spec.Mon.Count))
+	return nil
+}
+
+// findSurvivingMon returns the mon with the most recent monmap among the mons responding on their
+// admin socket, or the mons in quorum if the quorum is not lost
+func (c *Cluster) findSurvivingMon() (string, []string, error) {
+	names := make([]string, 0, len(c.ClusterInfo.Monitors))
+	for name := range c.ClusterInfo.Monitors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	survivor := ""
+	survivorEpoch := -1
+	var inQuorum []string
+	for _, name := range names {
+		// the admin socket is in the run dir of the mon container
+		socket := fmt.Sprintf("/run/ceph/ceph-mon.%s.asok", name)
+		output, err := execInMonPod(c.ClusterInfo.Context, c.context, c.Namespace, name, "env", "-i", "ceph", "--admin-daemon", socket, "mon_status")
+		if err != nil {
+			logger.Infof("mon %q is not responding, considering it lost. %v", name, err)
+			continue
+		}
+		var status monDaemonStatus
+		if err := json.Unmarshal([]byte(output), &status); err != nil {
+			logger.Warningf("failed to parse the status of mon %q, considering it lost. %v", name, err)
+			continue
+		}
+		logger.Infof("mon %q is in state %q with monmap epoch %d", name, status.State, status.MonMap.Epoch)
+		if status.State == "leader" || status.State == "peon" {
+			inQuorum = append(inQuorum, name)
+		}
+		if status.MonMap.Epoch > survivorEpoch {
+			survivor = name
+			survivorEpoch = status.MonMap.Epoch
+		}
+	}
+	if survivor == "" {
+		return "", nil, errors.Errorf("failed to restore the mon quorum, none of the mons %v is responding. set the name of the mon to restore the quorum from in the %q annotation", names, cephv1.RestoreMonQuorumAnnotation)
+	}
+	return survivor, inQuorum, nil
+}
+
+// restoreQuorumMonmapScript returns the script removing all the mons but the surviving mon from the
+// monmap of the surviving mon
+func restoreQuorumMonmapScript(survivor string) string {
+	return monmapScript(fmt.Sprintf("for mon in $(%s); do if [ \"$mon\" != %q ]; then monmaptool --rm \"$mon\" %s; fi; done\n",
+		listMonmapMonsCommand, survivor, monmapPath))
+}
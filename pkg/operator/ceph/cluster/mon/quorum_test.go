@@ -0,0 +1,189 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/stretchr/testify/assert"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestRestoreQuorum(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+
+	newTestCluster := func(t *testing.T) *Cluster {
+		c, _ := newTestMonMaintenanceCluster(t)
+		cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "default", Namespace: namespace}}
+		c.context.Client = clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephCluster).Build()
+		for _, name := range []string{"a", "b", "c"} {
+			_, err := c.context.Clientset.CoreV1().Services(namespace).Create(ctx, &v1.Service{ObjectMeta: metav1.ObjectMeta{Name: resourceName(name), Namespace: namespace}}, metav1.CreateOptions{})
+			assert.NoError(t, err)
+		}
+		return c
+	}
+
+	// mon a is lost, mon b has the most recent monmap and mon c is behind
+	monStatus := map[string]string{
+		"b": `{"name":"b","rank":1,"state":"probing","monmap":{"epoch":4}}`,
+		"c": `{"name":"c","rank":2,"state":"probing","monmap":{"epoch":3}}`,
+	}
+	execInMonPod = func(ctx context.Context, clusterdContext *clusterd.Context, namespace, monName string, command ...string) (string, error) {
+		assert.Equal(t, []string{"env", "-i", "ceph", "--admin-daemon", "/run/ceph/ceph-mon." + monName + ".asok", "mon_status"}, command)
+		if status, ok := monStatus[monName]; ok {
+			return status, nil
+		}
+		return "", errors.Errorf("no running pod found for mon %q", monName)
+	}
+	defer func() { execInMonPod = realExecInMonPod }()
+
+	var jobs []*batch.Job
+	var jobErr error
+	runMonMaintenanceJob = func(ctx context.Context, clusterdContext *clusterd.Context, job *batch.Job) error {
+		jobs = append(jobs, job)
+		return jobErr
+	}
+	defer func() { runMonMaintenanceJob = realRunMonMaintenanceJob }()
+
+	deploymentExists := func(c *Cluster, name string) bool {
+		_, err := c.context.Clientset.AppsV1().Deployments(namespace).Get(ctx, resourceName(name), metav1.GetOptions{})
+		if err != nil {
+			assert.True(t, kerrors.IsNotFound(err))
+			return false
+		}
+		return true
+	}
+	getEndpoints := func(c *Cluster) string {
+		cm, err := c.context.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, EndpointConfigMapName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return cm.Data[EndpointDataKey]
+	}
+
+	t.Run("quorum restored from the healthiest mon", func(t *testing.T) {
+		jobs = nil
+		c := newTestCluster(t)
+		recorder := record.NewFakeRecorder(10)
+		assert.NoError(t, c.RestoreQuorum(c.ClusterInfo, cephver.Quincy, c.spec, "", recorder))
+
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, "rook-ceph-mon-b-restore", jobs[0].Name)
+		script := jobs[0].Spec.Template.Spec.Containers[0].Command[2]
+		assert.Contains(t, script, "ceph-mon \"$@\" --extract-monmap /tmp/monmap")
+		assert.Contains(t, script, `if [ "$mon" != "b" ]; then monmaptool --rm "$mon" /tmp/monmap; fi`)
+		assert.Contains(t, script, "ceph-mon \"$@\" --inject-monmap /tmp/monmap")
+
+		assert.False(t, deploymentExists(c, "a"))
+		assert.True(t, deploymentExists(c, "b"))
+		assert.False(t, deploymentExists(c, "c"))
+		_, err := c.context.Clientset.CoreV1().Services(namespace).Get(ctx, resourceName("c"), metav1.GetOptions{})
+		assert.True(t, kerrors.IsNotFound(err))
+		assert.Equal(t, "b=1.2.3.2:3300", getEndpoints(c))
+		assert.Len(t, c.ClusterInfo.Monitors, 1)
+		assert.Len(t, c.mapping.Schedule, 1)
+
+		assert.Len(t, recorder.Events, 4)
+		assert.Contains(t, <-recorder.Events, `restoring the mon quorum from mon "b", removing mons [a c]`)
+		assert.Contains(t, <-recorder.Events, "stopped the mons")
+		assert.Contains(t, <-recorder.Events, `removed mons [a c] from the monmap of mon "b"`)
+		assert.Contains(t, <-recorder.Events, monQuorumRestoredReason)
+	})
+
+	t.Run("quorum restored from the requested mon", func(t *testing.T) {
+		jobs = nil
+		c := newTestCluster(t)
+		recorder := record.NewFakeRecorder(10)
+		assert.NoError(t, c.RestoreQuorum(c.ClusterInfo, cephver.Quincy, c.spec, "a", recorder))
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, "rook-ceph-mon-a-restore", jobs[0].Name)
+		assert.Equal(t, "a=1.2.3.1:3300", getEndpoints(c))
+	})
+
+	t.Run("unknown requested mon", func(t *testing.T) {
+		jobs = nil
+		c := newTestCluster(t)
+		recorder := record.NewFakeRecorder(10)
+		assert.Error(t, c.RestoreQuorum(c.ClusterInfo, cephver.Quincy, c.spec, "z", recorder))
+		assert.Empty(t, jobs)
+		assert.Contains(t, <-recorder.Events, monQuorumRestoreFailedReason)
+	})
+
+	t.Run("failed job keeps the mons", func(t *testing.T) {
+		jobs = nil
+		jobErr = errors.New("failed job")
+		defer func() { jobErr = nil }()
+		c := newTestCluster(t)
+		recorder := record.NewFakeRecorder(10)
+		assert.Error(t, c.RestoreQuorum(c.ClusterInfo, cephver.Quincy, c.spec, "", recorder))
+		assert.True(t, deploymentExists(c, "a"))
+		assert.True(t, deploymentExists(c, "c"))
+		assert.Contains(t, getEndpoints(c), "c=1.2.3.3:3300")
+	})
+
+	t.Run("quorum not lost", func(t *testing.T) {
+		jobs = nil
+		monStatus["c"] = `{"name":"c","rank":2,"state":"leader","monmap":{"epoch":4}}`
+		c := newTestCluster(t)
+		recorder := record.NewFakeRecorder(10)
+		assert.NoError(t, c.RestoreQuorum(c.ClusterInfo, cephver.Quincy, c.spec, "", recorder))
+		assert.Empty(t, jobs)
+		assert.True(t, deploymentExists(c, "a"))
+		assert.Contains(t, <-recorder.Events, "not restoring the mon quorum since mons [c] are in quorum")
+	})
+
+	t.Run("quorum not lost with the requested mon", func(t *testing.T) {
+		jobs = nil
+		c := newTestCluster(t)
+		recorder := record.NewFakeRecorder(10)
+		assert.NoError(t, c.RestoreQuorum(c.ClusterInfo, cephver.Quincy, c.spec, "b", recorder))
+		assert.Empty(t, jobs)
+		assert.True(t, deploymentExists(c, "a"))
+		assert.True(t, deploymentExists(c, "c"))
+		assert.Contains(t, <-recorder.Events, "not restoring the mon quorum since mons [c] are in quorum")
+	})
+
+	t.Run("no mon responding", func(t *testing.T) {
+		jobs = nil
+		monStatus = map[string]string{}
+		c := newTestCluster(t)
+		recorder := record.NewFakeRecorder(10)
+		err := c.RestoreQuorum(c.ClusterInfo, cephver.Quincy, c.spec, "", recorder)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), cephv1.RestoreMonQuorumAnnotation)
+		assert.Empty(t, jobs)
+	})
+
+	t.Run("no mon responding with the requested mon", func(t *testing.T) {
+		jobs = nil
+		c := newTestCluster(t)
+		recorder := record.NewFakeRecorder(10)
+		assert.NoError(t, c.RestoreQuorum(c.ClusterInfo, cephver.Quincy, c.spec, "c", recorder))
+		assert.Len(t, jobs, 1)
+		assert.Equal(t, "c=1.2.3.3:3300", getEndpoints(c))
+	})
+}
@@ -0,0 +1,287 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephutil "github.com/rook/rook/pkg/daemon/ceph/util"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/wait"
+)
+
+const (
+	monMaintenanceJobTimeout = 15 * time.Minute
+	monPodStopTimeout        = 5 * time.Minute
+	monmapPath               = "/tmp/monmap"
+)
+
+// listMonmapMonsCommand prints the names of the mons in the extracted monmap
+var listMonmapMonsCommand = fmt.Sprintf("monmaptool --print %s | sed -n 's/.* mon\\.\\(.*\\)$/\\1/p'", monmapPath)
+
+// runMonMaintenanceJob is overridden in the unit tests since the jobs never complete in a fake clientset
+var runMonMaintenanceJob = realRunMonMaintenanceJob
+
+// RemapAddresses rewrites the monmap of all the mons of a host network cluster with the current IP
+// of the nodes where the mons are scheduled. This is needed when the nodes are renumbered, for
+// example after a datacenter re-IP, since the mons cannot form quorum with the addresses saved in
+// their monmap. All the mons are stopped, the monmap is extracted from the store of each mon, its
+// addresses are rewritten and the monmap is injected back. The mon endpoints configmap and the csi
+// config are updated with the new addresses, and the mons are restarted with the new addresses at
+// the next mon reconcile.
+func (c *Cluster) RemapAddresses(clusterInfo *cephclient.ClusterInfo, cephVersion cephver.CephVersion, spec cephv1.ClusterSpec) error {
+	c.acquireOrchestrationLock()
+	defer c.releaseOrchestrationLock()
+
+	clusterInfo.OwnerInfo = c.ownerInfo
+	c.ClusterInfo = clusterInfo
+	c.spec = spec
+
+	if !c.spec.Network.IsHost() {
+		logger.Warningf("skipping remap of the mon addresses since the cluster is not using host networking, the mons keep the ip of their service")
+		return nil
+	}
+
+	if err := c.initClusterInfo(cephVersion, c.ClusterInfo.NamespacedName().Name); err != nil {
+		return errors.Wrap(err, "failed to initialize ceph cluster info")
+	}
+	if len(c.ClusterInfo.Monitors) == 0 {
+		logger.Info("skipping remap of the mon addresses since no mons exist yet")
+		return nil
+	}
+
+	newAddresses, err := c.currentMonNodeAddresses()
+	if err != nil {
+		return errors.Wrap(err, "failed to get the ip of the mon nodes")
+	}
+	changed := []string{}
+	for name, address := range newAddresses {
+		if cephutil.GetIPFromEndpoint(c.ClusterInfo.Monitors[name].Endpoint) != address {
+			changed = append(changed, name)
+		}
+	}
+	if len(changed) == 0 {
+		logger.Info("the mon addresses already match the ip of the mon nodes, nothing to remap")
+		return nil
+	}
+	sort.Strings(changed)
+	logger.Infof("remapping the addresses of mons %v to the ip of their node", changed)
+
+	// the mon endpoints are only saved once all the monmaps are rewritten, so that an interrupted
+	// remap is detected and started again at the next reconcile
+	names := make([]string, 0, len(newAddresses))
+	for name, address := range newAddresses {
+		names = append(names, name)
+		port := cephutil.GetPortFromEndpoint(c.ClusterInfo.Monitors[name].Endpoint)
+		logger.Infof("mon %q address changes from %q to %q", name, c.ClusterInfo.Monitors[name].Endpoint, address)
+		c.ClusterInfo.Monitors[name] = cephclient.NewMonInfo(name, address, port)
+		c.mapping.Schedule[name].Address = address
+	}
+	sort.Strings(names)
+
+	// the monmap can only be modified while the mons are stopped
+	for _, name := range names {
+		if err := c.updateMonDeploymentReplica(name, false); err != nil {
+			return errors.Wrapf(err, "failed to stop mon %q", name)
+		}
+	}
+	for _, name := range names {
+		if err := c.waitForMonPodsToStop(name); err != nil {
+			return err
+		}
+	}
+
+	script := c.remapMonmapScript(names)
+	for _, name := range names {
+		logger.Infof("rewriting the monmap of mon %q", name)
+		job, err := c.makeMonMaintenanceJob(name, "remap", script)
+		if err != nil {
+			return errors.Wrapf(err, "failed to make the job to remap the monmap of mon %q", name)
+		}
+		if err := runMonMaintenanceJob(c.ClusterInfo.Context, c.context, job); err != nil {
+			return errors.Wrapf(err, "failed to remap the monmap of mon %q", name)
+		}
+	}
+
+	if err := c.saveMonConfig(); err != nil {
+		return errors.Wrap(err, "failed to save the remapped mon endpoints")
+	}
+	logger.Infof("remapped the mon addresses to %s, the mons will be restarted with the new addresses", flattenMonEndpoints(c.ClusterInfo.Monitors))
+	return nil
+}
+
+// currentMonNodeAddresses returns the current ip of the node where each mon is scheduled
+func (c *Cluster) currentMonNodeAddresses() (map[string]string, error) {
+	addresses := map[string]string{}
+	for name := range c.ClusterInfo.Monitors {
+		schedule, ok := c.mapping.Schedule[name]
+		if !ok || schedule == nil || schedule.Name == "" {
+			return nil, errors.Errorf("node of mon %q not found in the mon mapping", name)
+		}
+		node, err := c.context.Clientset.CoreV1().Nodes().Get(c.ClusterInfo.Context, schedule.Name, metav1.GetOptions{})
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get node %q of mon %q", schedule.Name, name)
+		}
+		nodeInfo, err := getNodeInfoFromNode(*node)
+		if err != nil {
+			return nil, errors.Wrapf(err, "failed to get the ip of node %q of mon %q", schedule.Name, name)
+		}
+		addresses[name] = nodeInfo.Address
+	}
+	return addresses, nil
+}
+
+// remapMonmapScript returns the script rewriting the monmap of a mon with the mon endpoints
+func (c *Cluster) remapMonmapScript(names []string) string {
+	var edit strings.Builder
+	// remove all the mons from the monmap, including the mons unknown to the operator
+	fmt.Fprintf(&edit, "for mon in $(%s); do monmaptool --rm \"$mon\" %s; done\n", listMonmapMonsCommand, monmapPath)
+	for _, name := range names {
+		fmt.Fprintf(&edit, "monmaptool --addv %s %s %s\n", name, monAddrVec(c.ClusterInfo.Monitors[name].Endpoint), monmapPath)
+	}
+	return monmapScript(edit.String())
+}
+
+// monmapScript returns a script extracting the monmap from the store of a mon, editing it and
+// injecting it back. The script runs with the arguments of the mon daemon.
+func monmapScript(edit string) string {
+	var script strings.Builder
+	script.WriteString("set -xe\n")
+	fmt.Fprintf(&script, "ceph-mon \"$@\" --extract-monmap %s\n", monmapPath)
+	fmt.Fprintf(&script, "monmaptool --print %s\n", monmapPath)
+	script.WriteString(edit)
+	fmt.Fprintf(&script, "monmaptool --print %s\n", monmapPath)
+	fmt.Fprintf(&script, "ceph-mon \"$@\" --inject-monmap %s\n", monmapPath)
+	return script.String()
+}
+
+// monAddrVec returns the address vector of a mon in the monmap for the given mon endpoint
+func monAddrVec(endpoint string) string {
+	ip := cephutil.GetIPFromEndpoint(endpoint)
+	port := cephutil.GetPortFromEndpoint(endpoint)
+	msgr2Endpoint := net.JoinHostPort(ip, strconv.Itoa(int(DefaultMsgr2Port)))
+	if port == DefaultMsgr2Port {
+		return "[v2:" + msgr2Endpoint + "]"
+	}
+	return "[v2:" + msgr2Endpoint + ",v1:" + net.JoinHostPort(ip, strconv.Itoa(int(port))) + "]"
+}
+
+// makeMonMaintenanceJob returns a job running a script against the store of a stopped mon. The
+// job runs on the node and with the volumes of the mon deployment, and the script receives the
+// arguments of the mon daemon so it can call ceph-mon with them.
+func (c *Cluster) makeMonMaintenanceJob(name, action, script string) (*batch.Job, error) {
+	d, err := c.context.Clientset.AppsV1().Deployments(c.Namespace).Get(c.ClusterInfo.Context, resourceName(name), metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get mon %q deployment", name)
+	}
+
+	var monContainer *v1.Container
+	for i := range d.Spec.Template.Spec.Containers {
+		if d.Spec.Template.Spec.Containers[i].Name == "mon" {
+			monContainer = d.Spec.Template.Spec.Containers[i].DeepCopy()
+			break
+		}
+	}
+	if monContainer == nil {
+		return nil, errors.Errorf("mon container not found in deployment %q", d.Name)
+	}
+	monContainer.Name = action
+	monContainer.Command = []string{"/bin/bash", "-c", script, "--"}
+	monContainer.Ports = nil
+	monContainer.LivenessProbe = nil
+	monContainer.ReadinessProbe = nil
+	monContainer.StartupProbe = nil
+	monContainer.Lifecycle = nil
+
+	podSpec := d.Spec.Template.Spec.DeepCopy()
+	podSpec.InitContainers = nil
+	podSpec.Containers = []v1.Container{*monContainer}
+	podSpec.RestartPolicy = v1.RestartPolicyNever
+	podSpec.ShareProcessNamespace = nil
+
+	labels := controller.AppLabels(AppName, c.Namespace)
+	labels[controller.DaemonIDLabel] = name
+	labels["job"] = action
+	backoffLimit := int32(0)
+	job := &batch.Job{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      fmt.Sprintf("%s-%s", resourceName(name), action),
+			Namespace: c.Namespace,
+			Labels:    labels,
+		},
+		Spec: batch.JobSpec{
+			BackoffLimit: &backoffLimit,
+			Template: v1.PodTemplateSpec{
+				ObjectMeta: metav1.ObjectMeta{Labels: labels},
+				Spec:       *podSpec,
+			},
+		},
+	}
+	cephv1.GetMonAnnotations(c.spec.Annotations).ApplyToObjectMeta(&job.Spec.Template.ObjectMeta)
+	k8sutil.AddRookVersionLabelToJob(job)
+	if err := c.ownerInfo.SetControllerReference(job); err != nil {
+		return nil, errors.Wrapf(err, "failed to set owner reference to job %q", job.Name)
+	}
+	return job, nil
+}
+
+// waitForMonPodsToStop waits until no pod of a mon is left, so that the mon store is not in use
+func (c *Cluster) waitForMonPodsToStop(name string) error {
+	selector := fmt.Sprintf("%s=%s,%s=%s", k8sutil.AppAttr, AppName, controller.DaemonIDLabel, name)
+	err := wait.PollUntilContextTimeout(c.ClusterInfo.Context, 5*time.Second, monPodStopTimeout, true, func(ctx context.Context) (bool, error) {
+		pods, err := c.context.Clientset.CoreV1().Pods(c.Namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			logger.Warningf("failed to list the pods of mon %q. %v", name, err)
+			return false, nil
+		}
+		if len(pods.Items) > 0 {
+			logger.Infof("waiting for the %d pod(s) of mon %q to stop", len(pods.Items), name)
+			return false, nil
+		}
+		return true, nil
+	})
+	return errors.Wrapf(err, "failed to wait for the pods of mon %q to stop", name)
+}
+
+// realRunMonMaintenanceJob runs a mon maintenance job until it completes and removes it
+func realRunMonMaintenanceJob(ctx context.Context, clusterdContext *clusterd.Context, job *batch.Job) error {
+	if err := k8sutil.RunReplaceableJob(ctx, clusterdContext.Clientset, job, true); err != nil {
+		return errors.Wrapf(err, "failed to run job %q", job.Name)
+	}
+	if err := k8sutil.WaitForJobCompletion(ctx, clusterdContext.Clientset, job, monMaintenanceJobTimeout); err != nil {
+		return errors.Wrapf(err, "failed to complete job %q, check the logs of the job pod", job.Name)
+	}
+	if err := k8sutil.DeleteBatchJob(ctx, clusterdContext.Clientset, job.Namespace, job.Name, false); err != nil {
+		logger.Warningf("failed to delete job %q. %v", job.Name, err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/clusterd"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	"github.com/stretchr/testify/assert"
+	batch "k8s.io/api/batch/v1"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// newTestMonMaintenanceCluster returns a cluster with the deployments of mons a, b and c running
+// with host networking on node0, node1 and node2
+func newTestMonMaintenanceCluster(t *testing.T) (*Cluster, func(node, ip string)) {
+	ctx := context.TODO()
+	namespace := "ns"
+	clusterdContext, err := newTestStartCluster(t, namespace)
+	assert.NoError(t, err)
+	c := newCluster(clusterdContext, namespace, false, v1.ResourceRequirements{})
+	setCommonMonProperties(c, 3, c.spec.Mon, "myversion")
+	c.spec.Network.HostNetwork = true
+
+	setNodeIP := func(node, ip string) {
+		n, err := clusterdContext.Clientset.CoreV1().Nodes().Get(ctx, node, metav1.GetOptions{})
+		assert.NoError(t, err)
+		n.Status.Addresses = []v1.NodeAddress{{Type: v1.NodeInternalIP, Address: ip}}
+		_, err = clusterdContext.Clientset.CoreV1().Nodes().UpdateStatus(ctx, n, metav1.UpdateOptions{})
+		assert.NoError(t, err)
+	}
+	for i, name := range []string{"a", "b", "c"} {
+		node := fmt.Sprintf("node%d", i)
+		ip := fmt.Sprintf("1.2.3.%d", i+1)
+		setNodeIP(node, ip)
+		c.mapping.Schedule[name] = &opcontroller.MonScheduleInfo{Name: node, Hostname: node, Address: ip}
+	}
+	assert.NoError(t, c.persistExpectedMonDaemons())
+	for _, m := range c.clusterInfoToMonConfig() {
+		d, err := c.makeDeployment(m, false)
+		assert.NoError(t, err)
+		_, err = clusterdContext.Clientset.AppsV1().Deployments(namespace).Create(ctx, d, metav1.CreateOptions{})
+		assert.NoError(t, err)
+	}
+	return c, setNodeIP
+}
+
+func TestRemapAddresses(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "ns"
+	c, setNodeIP := newTestMonMaintenanceCluster(t)
+	clusterdContext := c.context
+
+	var jobs []*batch.Job
+	var jobErr error
+	runMonMaintenanceJob = func(ctx context.Context, clusterdContext *clusterd.Context, job *batch.Job) error {
+		jobs = append(jobs, job)
+		return jobErr
+	}
+	defer func() { runMonMaintenanceJob = realRunMonMaintenanceJob }()
+
+	getReplicas := func(name string) int32 {
+		d, err := clusterdContext.Clientset.AppsV1().Deployments(namespace).Get(ctx, resourceName(name), metav1.GetOptions{})
+		assert.NoError(t, err)
+		return *d.Spec.Replicas
+	}
+	getEndpoints := func() string {
+		cm, err := clusterdContext.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, EndpointConfigMapName, metav1.GetOptions{})
+		assert.NoError(t, err)
+		return cm.Data[EndpointDataKey]
+	}
+
+	t.Run("addresses unchanged", func(t *testing.T) {
+		assert.NoError(t, c.RemapAddresses(c.ClusterInfo, cephver.Quincy, c.spec))
+		assert.Empty(t, jobs)
+		assert.Equal(t, int32(1), getReplicas("a"))
+	})
+
+	t.Run("remap skipped without host networking", func(t *testing.T) {
+		setNodeIP("node1", "10.0.0.2")
+		spec := c.spec
+		spec.Network.HostNetwork = false
+		assert.NoError(t, c.RemapAddresses(c.ClusterInfo, cephver.Quincy, spec))
+		assert.Empty(t, jobs)
+		assert.Equal(t, int32(1), getReplicas("b"))
+		c.spec.Network.HostNetwork = true
+	})
+
+	t.Run("failed job leaves the endpoints unchanged", func(t *testing.T) {
+		jobErr = errors.New("failed job")
+		assert.Error(t, c.RemapAddresses(c.ClusterInfo, cephver.Quincy, c.spec))
+		assert.Len(t, jobs, 1)
+		assert.Contains(t, getEndpoints(), "b=1.2.3.2:3300")
+		jobs = nil
+		jobErr = nil
+	})
+
+	t.Run("monmap of all the mons remapped", func(t *testing.T) {
+		assert.NoError(t, c.RemapAddresses(c.ClusterInfo, cephver.Quincy, c.spec))
+		assert.Len(t, jobs, 3)
+		for i, name := range []string{"a", "b", "c"} {
+			assert.Equal(t, int32(0), getReplicas(name))
+
+			job := jobs[i]
+			assert.Equal(t, "rook-ceph-mon-"+name+"-remap", job.Name)
+			podSpec := job.Spec.Template.Spec
+			assert.Equal(t, v1.RestartPolicyNever, podSpec.RestartPolicy)
+			assert.Empty(t, podSpec.InitContainers)
+			assert.Len(t, podSpec.Containers, 1)
+			assert.True(t, podSpec.HostNetwork)
+			container := podSpec.Containers[0]
+			assert.Equal(t, "/bin/bash", container.Command[0])
+			assert.Contains(t, container.Command[2], "ceph-mon \"$@\" --extract-monmap /tmp/monmap")
+			assert.Contains(t, container.Command[2], "monmaptool --addv a [v2:1.2.3.1:3300] /tmp/monmap")
+			assert.Contains(t, container.Command[2], "monmaptool --addv b [v2:10.0.0.2:3300] /tmp/monmap")
+			assert.Contains(t, container.Command[2], "ceph-mon \"$@\" --inject-monmap /tmp/monmap")
+			assert.Contains(t, container.Args, "--id="+name)
+			assert.Nil(t, container.LivenessProbe)
+		}
+
+		assert.Equal(t, "10.0.0.2:3300", c.ClusterInfo.Monitors["b"].Endpoint)
+		assert.Equal(t, "10.0.0.2", c.mapping.Schedule["b"].Address)
+		assert.Contains(t, getEndpoints(), "b=10.0.0.2:3300")
+		assert.Contains(t, getEndpoints(), "a=1.2.3.1:3300")
+
+		// the mons run on the remapped ip with host networking after the remap
+		for _, m := range c.clusterInfoToMonConfig() {
+			assert.True(t, m.UseHostNetwork)
+		}
+	})
+}
+
+func TestMonAddrVec(t *testing.T) {
+	assert.Equal(t, "[v2:10.0.0.1:3300]", monAddrVec("10.0.0.1:3300"))
+	assert.Equal(t, "[v2:10.0.0.1:3300,v1:10.0.0.1:6789]", monAddrVec("10.0.0.1:6789"))
+	assert.Equal(t, "[v2:[fd00::1]:3300,v1:[fd00::1]:6789]", monAddrVec("[fd00::1]:6789"))
+}
@@ -0,0 +1,357 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/exec"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+const (
+	monStoreCompactedReason        = "MonStoreCompacted"
+	monStoreCompactionFailedReason = "MonStoreCompactionFailed"
+	monPVCExpandedReason           = "MonPVCExpanded"
+	monPVCExpansionFailedReason    = "MonPVCExpansionFailed"
+	monDiskLowReason               = "MonDiskLow"
+
+	defaultMonUsedPercentThreshold = 80
+	defaultMonPVCGrowthPercent     = 20
+	// a store that stays above the compaction threshold is not compacted more often than this
+	minMonStoreCompactionInterval = time.Hour
+)
+
+var (
+	// hook for tests to override
+	execInMonPod = realExecInMonPod
+)
+
+// checkMonStores reports the size of the mon stores in the cluster status, compacts the stores on
+// a schedule or past a threshold, and expands the mon PVCs before the disks are full. The mon
+// orchestration is not blocked while the stores are measured and compacted, the orchestration lock
+// is only held to read the mons and to expand a PVC.
+func (c *Cluster) checkMonStores(recorder record.EventRecorder) error {
+	if err := c.ClusterInfo.IsInitialized(); err != nil {
+		return errors.Wrap(err, "skipping mon store check since cluster details are not initialized")
+	}
+
+	cephCluster := &cephv1.CephCluster{}
+	if err := c.context.Client.Get(c.ClusterInfo.Context, c.ClusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve ceph cluster %q to check the mon stores", c.ClusterInfo.NamespacedName().Name)
+	}
+
+	storeSpec := c.spec.Mon.Store
+	if storeSpec == nil || c.spec.External.Enable {
+		if cephCluster.Status.MonStores == nil {
+			return nil
+		}
+		return c.updateMonStoresStatus(cephCluster, nil)
+	}
+
+	// a mon store is compacted only when all the mons are in quorum since the mon is unresponsive
+	// during the compaction
+	quorumStatus, err := cephclient.GetMonQuorumStatus(c.context, c.ClusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to get mon quorum status")
+	}
+	canCompact := len(quorumStatus.Quorum) == len(quorumStatus.MonMap.Mons)
+
+	currentStatus := map[string]cephv1.MonStoreStatus{}
+	for _, status := range cephCluster.Status.MonStores {
+		currentStatus[status.Name] = status
+	}
+
+	c.acquireOrchestrationLock()
+	monNames := make([]string, 0, len(c.ClusterInfo.Monitors))
+	for name := range c.ClusterInfo.Monitors {
+		monNames = append(monNames, name)
+	}
+	c.releaseOrchestrationLock()
+	sort.Strings(monNames)
+
+	now := time.Now().UTC()
+	statuses := make([]cephv1.MonStoreStatus, 0, len(monNames))
+	for _, name := range monNames {
+		status, ok := currentStatus[name]
+		if !ok {
+			status = cephv1.MonStoreStatus{Name: name}
+		}
+		status.LastChecked = now.Format(time.RFC3339)
+		status.Details = ""
+
+		if err := c.checkMonStore(cephCluster, recorder, storeSpec, &status); err != nil {
+			logger.Warningf("%v", err)
+			status.Details = err.Error()
+		} else if canCompact && monStoreCompactionDue(storeSpec, &status, now) {
+			// only one mon store is compacted at a time to keep the quorum responsive
+			canCompact = false
+			if c.monStoreCompactionMutex.TryLock() {
+				status.LastCompaction = now.Format(time.RFC3339)
+				go c.compactMonStore(cephCluster.DeepCopy(), recorder, name, status.StoreBytes)
+			} else {
+				logger.Debugf("waiting for the compaction of a mon store to complete before compacting the store of mon %q", name)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+
+	return c.updateMonStoresStatus(cephCluster, statuses)
+}
+
+// compactMonStore compacts the store of a mon in the background since the compaction can take
+// minutes. A failed compaction is retried when the compaction is due again.
+func (c *Cluster) compactMonStore(cephCluster *cephv1.CephCluster, recorder record.EventRecorder, name string, storeBytes uint64) {
+	defer c.monStoreCompactionMutex.Unlock()
+
+	if err := cephclient.CompactMonStore(c.context, c.ClusterInfo, name); err != nil {
+		logger.Warningf("%v", err)
+		recorder.Event(cephCluster, v1.EventTypeWarning, monStoreCompactionFailedReason, err.Error())
+		return
+	}
+	recorder.Event(cephCluster, v1.EventTypeNormal, monStoreCompactedReason,
+		fmt.Sprintf("compacted the store of mon %q of size %s", name, formatBytes(storeBytes)))
+}
+
+// checkMonStore measures the store of a mon and the space of its data directory, then expands the
+// mon PVC if the disk is almost full
+func (c *Cluster) checkMonStore(cephCluster *cephv1.CephCluster, recorder record.EventRecorder, storeSpec *cephv1.MonStoreSpec, status *cephv1.MonStoreStatus) error {
+	dataDir := config.NewStatefulDaemonDataPathMap(c.spec.DataDirHostPath, dataDirRelativeHostPath(status.Name), config.MonType, status.Name, c.Namespace).ContainerDataDir
+
+	output, err := execInMonPod(c.ClusterInfo.Context, c.context, c.Namespace, status.Name, "du", "-sb", path.Join(dataDir, "store.db"))
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the store size of mon %q", status.Name)
+	}
+	if status.StoreBytes, err = parseDiskUsage(output); err != nil {
+		return errors.Wrapf(err, "failed to parse the store size of mon %q", status.Name)
+	}
+
+	output, err = execInMonPod(c.ClusterInfo.Context, c.context, c.Namespace, status.Name, "df", "-B1", "--output=size,avail", dataDir)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get the disk space of mon %q", status.Name)
+	}
+	if status.TotalBytes, status.AvailableBytes, err = parseDiskFree(output); err != nil {
+		return errors.Wrapf(err, "failed to parse the disk space of mon %q", status.Name)
+	}
+
+	threshold := defaultMonUsedPercentThreshold
+	if storeSpec.PVCExpansion != nil && storeSpec.PVCExpansion.UsedPercentThreshold > 0 {
+		threshold = storeSpec.PVCExpansion.UsedPercentThreshold
+	}
+	usedPercent := usedPercent(status.TotalBytes, status.AvailableBytes)
+
+	pvc, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(c.Namespace).Get(c.ClusterInfo.Context, resourceName(status.Name), metav1.GetOptions{})
+	if err != nil {
+		if !kerrors.IsNotFound(err) {
+			return errors.Wrapf(err, "failed to get the pvc of mon %q", status.Name)
+		}
+		// the mon runs on the host path
+		status.PVCSize = ""
+		if usedPercent >= threshold {
+			recorder.Event(cephCluster, v1.EventTypeWarning, monDiskLowReason,
+				fmt.Sprintf("the data directory of mon %q is %d%% full", status.Name, usedPercent))
+		}
+		return nil
+	}
+
+	size := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	status.PVCSize = size.String()
+	if usedPercent < threshold {
+		return nil
+	}
+	if storeSpec.PVCExpansion == nil {
+		recorder.Event(cephCluster, v1.EventTypeWarning, monDiskLowReason,
+			fmt.Sprintf("the pvc %q of mon %q is %d%% full", pvc.Name, status.Name, usedPercent))
+		return nil
+	}
+
+	// the mon PVCs are also updated by the mon orchestration
+	c.acquireOrchestrationLock()
+	newSize, err := c.expandMonPVC(pvc, storeSpec.PVCExpansion)
+	c.releaseOrchestrationLock()
+	if err != nil {
+		recorder.Event(cephCluster, v1.EventTypeWarning, monPVCExpansionFailedReason, err.Error())
+		return err
+	}
+	if newSize != nil {
+		status.PVCSize = newSize.String()
+		recorder.Event(cephCluster, v1.EventTypeNormal, monPVCExpandedReason,
+			fmt.Sprintf("expanded the pvc %q of mon %q from %s to %s since it is %d%% full", pvc.Name, status.Name, size.String(), newSize.String(), usedPercent))
+	}
+	return nil
+}
+
+// expandMonPVC grows the mon PVC by the growth percentage of the spec, up to the max size. The new
+// size is returned, or nil if the PVC is already being expanded.
+func (c *Cluster) expandMonPVC(pvc *v1.PersistentVolumeClaim, expansion *cephv1.MonPVCExpansionSpec) (*resource.Quantity, error) {
+	size, ok := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+	if !ok {
+		return nil, errors.Errorf("cannot expand pvc %q since its size is not specified", pvc.Name)
+	}
+	if capacity, ok := pvc.Status.Capacity[v1.ResourceStorage]; ok && capacity.Cmp(size) < 0 {
+		logger.Infof("waiting for the expansion of the pvc %q to %s to complete", pvc.Name, size.String())
+		return nil, nil
+	}
+
+	growthPercent := int64(defaultMonPVCGrowthPercent)
+	if expansion.GrowthPercent > 0 {
+		growthPercent = int64(expansion.GrowthPercent)
+	}
+	newBytes := size.Value() + size.Value()*growthPercent/100
+	if expansion.MaxSize != nil {
+		if size.Cmp(*expansion.MaxSize) >= 0 {
+			return nil, errors.Errorf("cannot expand pvc %q of size %s beyond the max size %s", pvc.Name, size.String(), expansion.MaxSize.String())
+		}
+		newBytes = min(newBytes, expansion.MaxSize.Value())
+	}
+	newSize := resource.NewQuantity(newBytes, resource.BinarySI)
+
+	if pvc.Spec.StorageClassName == nil || *pvc.Spec.StorageClassName == "" {
+		return nil, errors.Errorf("cannot expand pvc %q since its storage class is not provided", pvc.Name)
+	}
+	storageClass, err := c.context.Clientset.StorageV1().StorageClasses().Get(c.ClusterInfo.Context, *pvc.Spec.StorageClassName, metav1.GetOptions{})
+	if err != nil {
+		return nil, errors.Wrapf(err, "failed to get storage class %q of pvc %q", *pvc.Spec.StorageClassName, pvc.Name)
+	}
+	if storageClass.AllowVolumeExpansion == nil || !*storageClass.AllowVolumeExpansion {
+		return nil, errors.Errorf("cannot expand pvc %q since storage class %q does not allow volume expansion", pvc.Name, storageClass.Name)
+	}
+
+	logger.Infof("expanding pvc %q from %s to %s", pvc.Name, size.String(), newSize.String())
+	pvc.Spec.Resources.Requests[v1.ResourceStorage] = *newSize
+	if _, err := c.context.Clientset.CoreV1().PersistentVolumeClaims(pvc.Namespace).Update(c.ClusterInfo.Context, pvc, metav1.UpdateOptions{}); err != nil {
+		return nil, errors.Wrapf(err, "failed to expand pvc %q", pvc.Name)
+	}
+	return newSize, nil
+}
+
+func (c *Cluster) updateMonStoresStatus(cephCluster *cephv1.CephCluster, statuses []cephv1.MonStoreStatus) error {
+	cephCluster.Status.MonStores = statuses
+	if err := reporting.UpdateStatus(c.context.Client, cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to update cluster %q mon stores status", cephCluster.Name)
+	}
+	return nil
+}
+
+// monStoreCompactionDue returns whether the mon store is due for compaction, either on the
+// schedule or because it is above the size threshold
+func monStoreCompactionDue(storeSpec *cephv1.MonStoreSpec, status *cephv1.MonStoreStatus, now time.Time) bool {
+	sinceLastCompaction := time.Duration(-1)
+	if last, err := time.Parse(time.RFC3339, status.LastCompaction); err == nil {
+		sinceLastCompaction = now.Sub(last)
+	}
+	neverCompacted := sinceLastCompaction < 0
+
+	if storeSpec.CompactionInterval != nil && storeSpec.CompactionInterval.Duration > 0 {
+		if neverCompacted || sinceLastCompaction >= storeSpec.CompactionInterval.Duration {
+			return true
+		}
+	}
+	if storeSpec.CompactionThreshold != nil && status.StoreBytes > uint64(storeSpec.CompactionThreshold.Value()) {
+		if neverCompacted || sinceLastCompaction >= minMonStoreCompactionInterval {
+			return true
+		}
+	}
+	return false
+}
+
+// parseDiskUsage parses the size in bytes of 'du -sb' output, e.g. "123456	/var/lib/ceph/mon/ceph-a/store.db"
+func parseDiskUsage(output string) (uint64, error) {
+	fields := strings.Fields(output)
+	if len(fields) == 0 {
+		return 0, errors.Errorf("unexpected du output %q", output)
+	}
+	return strconv.ParseUint(fields[0], 10, 64)
+}
+
+// parseDiskFree parses the total and the available bytes of 'df -B1 --output=size,avail' output
+func parseDiskFree(output string) (uint64, uint64, error) {
+	lines := strings.Split(strings.TrimSpace(output), "\n")
+	fields := strings.Fields(lines[len(lines)-1])
+	if len(lines) < 2 || len(fields) != 2 {
+		return 0, 0, errors.Errorf("unexpected df output %q", output)
+	}
+	total, err := strconv.ParseUint(fields[0], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	available, err := strconv.ParseUint(fields[1], 10, 64)
+	if err != nil {
+		return 0, 0, err
+	}
+	return total, available, nil
+}
+
+func usedPercent(total, available uint64) int {
+	if total == 0 || available > total {
+		return 0
+	}
+	return int((total - available) * 100 / total)
+}
+
+func formatBytes(bytes uint64) string {
+	return resource.NewQuantity(int64(bytes), resource.BinarySI).String()
+}
+
+// realExecInMonPod runs a command in the mon container of the running pod of a mon
+func realExecInMonPod(ctx context.Context, clusterdContext *clusterd.Context, namespace, monName string, command ...string) (string, error) {
+	selector := fmt.Sprintf("%s=%s,%s=%s", k8sutil.AppAttr, AppName, controller.DaemonIDLabel, monName)
+	pods, err := clusterdContext.Clientset.CoreV1().Pods(namespace).List(ctx, metav1.ListOptions{LabelSelector: selector})
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to list the pods of mon %q", monName)
+	}
+	for _, pod := range pods.Items {
+		if pod.Status.Phase != v1.PodRunning {
+			continue
+		}
+		stdout, stderr, err := clusterdContext.RemoteExecutor.ExecWithOptions(ctx, exec.ExecOptions{
+			Command:       command,
+			Namespace:     namespace,
+			PodName:       pod.Name,
+			ContainerName: "mon",
+			CaptureStdout: true,
+			CaptureStderr: true,
+		})
+		if err != nil {
+			return "", errors.Wrapf(err, "failed to run %v in pod %q. %s", command, pod.Name, stderr)
+		}
+		return stdout, nil
+	}
+	return "", errors.Errorf("no running pod found for mon %q", monName)
+}
@@ -0,0 +1,220 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	storagev1 "k8s.io/api/storage/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestCheckMonStores(t *testing.T) {
+	ctx := context.TODO()
+	var compacted []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "tell" && args[2] == "compact" {
+				compacted = append(compacted, args[1])
+				return "", nil
+			}
+			return "", errors.Errorf("unexpected ceph command %q", args)
+		},
+	}
+	clusterInfo := clienttest.CreateTestClusterInfo(2)
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		if args[0] == "quorum_status" {
+			return clienttest.MonInQuorumResponseFromMons(clusterInfo.Monitors), nil
+		}
+		return "", errors.Errorf("unexpected ceph command %q", args)
+	}
+
+	// mon "a" runs on a pvc that is 85% full, mon "b" runs on the host path and is 50% full
+	execInMonPod = func(ctx context.Context, clusterdContext *clusterd.Context, namespace, monName string, command ...string) (string, error) {
+		switch {
+		case command[0] == "du":
+			return "2147483648\t/var/lib/ceph/mon/ceph-" + monName + "/store.db", nil
+		case command[0] == "df" && monName == "a":
+			return "    1B-blocks     Avail\n10737418240 1610612736", nil
+		case command[0] == "df":
+			return "    1B-blocks     Avail\n10737418240 5368709120", nil
+		}
+		return "", errors.Errorf("unexpected command %v", command)
+	}
+	defer func() { execInMonPod = realExecInMonPod }()
+
+	clientset := test.New(t, 1)
+	storageClassName := "expandable"
+	allowExpansion := true
+	_, err := clientset.StorageV1().StorageClasses().Create(ctx, &storagev1.StorageClass{
+		ObjectMeta:           metav1.ObjectMeta{Name: storageClassName},
+		AllowVolumeExpansion: &allowExpansion,
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+	_, err = clientset.CoreV1().PersistentVolumeClaims(clusterInfo.Namespace).Create(ctx, &v1.PersistentVolumeClaim{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon-a", Namespace: clusterInfo.Namespace},
+		Spec: v1.PersistentVolumeClaimSpec{
+			StorageClassName: &storageClassName,
+			Resources: v1.VolumeResourceRequirements{
+				Requests: v1.ResourceList{v1.ResourceStorage: resource.MustParse("10Gi")},
+			},
+		},
+	}, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: clusterInfo.NamespacedName().Name, Namespace: clusterInfo.Namespace}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+	c := New(ctx, &clusterd.Context{Clientset: clientset, Client: client, Executor: executor}, clusterInfo.Namespace, cephv1.ClusterSpec{}, nil)
+	c.ClusterInfo = clusterInfo
+	recorder := record.NewFakeRecorder(10)
+
+	getStatus := func() []cephv1.MonStoreStatus {
+		updated := &cephv1.CephCluster{}
+		assert.NoError(t, client.Get(ctx, clusterInfo.NamespacedName(), updated))
+		return updated.Status.MonStores
+	}
+
+	t.Run("store settings not set", func(t *testing.T) {
+		assert.NoError(t, c.checkMonStores(recorder))
+		assert.Nil(t, getStatus())
+		assert.Empty(t, compacted)
+	})
+
+	threshold := resource.MustParse("1Gi")
+	c.spec.Mon.Store = &cephv1.MonStoreSpec{
+		CompactionInterval:  &metav1.Duration{Duration: 24 * time.Hour},
+		CompactionThreshold: &threshold,
+		PVCExpansion:        &cephv1.MonPVCExpansionSpec{UsedPercentThreshold: 80, GrowthPercent: 20},
+	}
+
+	// waitForCompaction waits for the compaction in the background to complete
+	waitForCompaction := func() {
+		c.monStoreCompactionMutex.Lock()
+		defer c.monStoreCompactionMutex.Unlock()
+	}
+
+	t.Run("stores compacted one at a time and full pvc expanded", func(t *testing.T) {
+		assert.NoError(t, c.checkMonStores(recorder))
+		waitForCompaction()
+		assert.Equal(t, []string{"mon.a"}, compacted)
+
+		status := getStatus()
+		assert.Len(t, status, 2)
+		assert.Equal(t, "a", status[0].Name)
+		assert.Equal(t, uint64(2147483648), status[0].StoreBytes)
+		assert.Equal(t, uint64(10737418240), status[0].TotalBytes)
+		assert.Equal(t, uint64(1610612736), status[0].AvailableBytes)
+		assert.Equal(t, "12Gi", status[0].PVCSize)
+		assert.NotEmpty(t, status[0].LastCompaction)
+		assert.Equal(t, "b", status[1].Name)
+		assert.Empty(t, status[1].PVCSize)
+		assert.Empty(t, status[1].LastCompaction)
+
+		pvc, err := clientset.CoreV1().PersistentVolumeClaims(clusterInfo.Namespace).Get(ctx, "rook-ceph-mon-a", metav1.GetOptions{})
+		assert.NoError(t, err)
+		size := pvc.Spec.Resources.Requests[v1.ResourceStorage]
+		assert.Equal(t, "12Gi", size.String())
+		assert.Contains(t, <-recorder.Events, monPVCExpandedReason)
+		assert.Contains(t, <-recorder.Events, monStoreCompactedReason)
+	})
+
+	t.Run("next store compacted", func(t *testing.T) {
+		compacted = nil
+		assert.NoError(t, c.checkMonStores(recorder))
+		waitForCompaction()
+		assert.Equal(t, []string{"mon.b"}, compacted)
+		status := getStatus()
+		assert.NotEmpty(t, status[1].LastCompaction)
+	})
+
+	t.Run("no compaction while a compaction is running", func(t *testing.T) {
+		compacted = nil
+		status := getStatus()
+		status[0].LastCompaction = ""
+		updated := &cephv1.CephCluster{}
+		assert.NoError(t, client.Get(ctx, clusterInfo.NamespacedName(), updated))
+		updated.Status.MonStores = status
+		assert.NoError(t, client.Status().Update(ctx, updated))
+
+		c.monStoreCompactionMutex.Lock()
+		assert.NoError(t, c.checkMonStores(recorder))
+		c.monStoreCompactionMutex.Unlock()
+		assert.Empty(t, compacted)
+		assert.Empty(t, getStatus()[0].LastCompaction)
+	})
+
+	t.Run("store settings removed", func(t *testing.T) {
+		c.spec.Mon.Store = nil
+		assert.NoError(t, c.checkMonStores(recorder))
+		assert.Nil(t, getStatus())
+	})
+}
+
+func TestMonStoreCompactionDue(t *testing.T) {
+	now := time.Now().UTC()
+	threshold := resource.MustParse("1Gi")
+	status := &cephv1.MonStoreStatus{StoreBytes: 512 * 1024 * 1024}
+
+	// nothing to do without any compaction setting
+	assert.False(t, monStoreCompactionDue(&cephv1.MonStoreSpec{}, status, now))
+
+	// scheduled compaction
+	storeSpec := &cephv1.MonStoreSpec{CompactionInterval: &metav1.Duration{Duration: 24 * time.Hour}}
+	assert.True(t, monStoreCompactionDue(storeSpec, status, now))
+	status.LastCompaction = now.Add(-time.Hour).Format(time.RFC3339)
+	assert.False(t, monStoreCompactionDue(storeSpec, status, now))
+	status.LastCompaction = now.Add(-25 * time.Hour).Format(time.RFC3339)
+	assert.True(t, monStoreCompactionDue(storeSpec, status, now))
+
+	// compaction past the threshold, not more often than the min interval
+	storeSpec = &cephv1.MonStoreSpec{CompactionThreshold: &threshold}
+	assert.False(t, monStoreCompactionDue(storeSpec, status, now))
+	status.StoreBytes = 2 * 1024 * 1024 * 1024
+	assert.True(t, monStoreCompactionDue(storeSpec, status, now))
+	status.LastCompaction = now.Add(-10 * time.Minute).Format(time.RFC3339)
+	assert.False(t, monStoreCompactionDue(storeSpec, status, now))
+}
+
+func TestParseMonDiskSpace(t *testing.T) {
+	size, err := parseDiskUsage("123456\t/var/lib/ceph/mon/ceph-a/store.db\n")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(123456), size)
+	_, err = parseDiskUsage("")
+	assert.Error(t, err)
+
+	total, available, err := parseDiskFree("    1B-blocks     Avail\n10737418240 1610612736\n")
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(10737418240), total)
+	assert.Equal(t, uint64(1610612736), available)
+	assert.Equal(t, 85, usedPercent(total, available))
+	_, _, err = parseDiskFree("10737418240 1610612736")
+	assert.Error(t, err)
+}
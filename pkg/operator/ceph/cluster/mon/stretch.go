@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"sort"
+)
+
+// stretchZoneMonCount returns the number of mons in each zone of the stretch cluster spec
+func (c *Cluster) stretchZoneMonCount(mons []*monConfig) map[string]int {
+	zoneCount := map[string]int{}
+	for _, zone := range c.spec.Mon.StretchCluster.Zones {
+		zoneCount[zone.Name] = 0
+	}
+	for _, m := range mons {
+		if _, ok := zoneCount[m.Zone]; ok {
+			zoneCount[m.Zone]++
+		}
+	}
+	return zoneCount
+}
+
+// monsInRemovedStretchZones returns the mons assigned to a zone that is not in the stretch cluster
+// spec anymore, sorted by name
+func (c *Cluster) monsInRemovedStretchZones(mons []*monConfig) []string {
+	zoneCount := c.stretchZoneMonCount(mons)
+	var names []string
+	for _, m := range mons {
+		if m.Zone == "" {
+			continue
+		}
+		if _, ok := zoneCount[m.Zone]; !ok {
+			names = append(names, m.DaemonName)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// relocateStretchMon fails over the next mon in a zone removed from the stretch cluster spec so that
+// the new mon is created in one of the zones of the spec. This is how the arbiter zone is replaced:
+// the mon of the previous arbiter zone is replaced by a mon in the new arbiter zone, which becomes
+// the tiebreaker mon. In the ceph stretch mode only the arbiter can be relocated, the data zones
+// cannot change. Only one mon is relocated per health check, and only when all the mons are in
+// quorum. Returns whether a mon was failed over.
+func (c *Cluster) relocateStretchMon(allMonsInQuorum bool, desiredMonCount int) bool {
+	if !c.spec.IsStretchCluster() || !allMonsInQuorum {
+		return false
+	}
+
+	mons := c.clusterInfoToMonConfig()
+	names := c.monsInRemovedStretchZones(mons)
+	if len(names) == 0 {
+		return false
+	}
+	if c.spec.StretchModeEnabled() {
+		if arbiterZone := c.getArbiterZone(); c.stretchZoneMonCount(mons)[arbiterZone] > 0 {
+			logger.Warningf("not relocating mons %v in zones removed from the stretch cluster, only the arbiter zone can be replaced in the ceph stretch mode", names)
+			return false
+		}
+	}
+
+	name := names[0]
+	logger.Infof("relocating mon %q from zone %q that is not in the stretch cluster anymore", name, c.mapping.Schedule[name].Zone)
+	c.failMon(len(c.ClusterInfo.Monitors), desiredMonCount, name)
+	if _, ok := c.ClusterInfo.Monitors[name]; ok {
+		logger.Warningf("mon %q not relocated, retrying at the next mon health check", name)
+	}
+	return true
+}
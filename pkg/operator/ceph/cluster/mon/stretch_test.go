@@ -0,0 +1,89 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mon
+
+import (
+	"context"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	clienttest "github.com/rook/rook/pkg/daemon/ceph/client/test"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRelocateStretchMon(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			logger.Infof("executing command: %s %+v", command, args)
+			return "{\"key\":\"mysecurekey\"}", nil
+		},
+	}
+	clusterInfo := clienttest.CreateTestClusterInfo(4)
+	clusterdContext := &clusterd.Context{Clientset: test.New(t, 1), ConfigDir: t.TempDir(), Executor: executor}
+	spec := cephv1.ClusterSpec{Mon: cephv1.MonSpec{StretchCluster: &cephv1.StretchClusterSpec{
+		Zones: []cephv1.MonZoneSpec{
+			{Name: "x"},
+			{Name: "y"},
+			{Name: "new-arbiter", Arbiter: true},
+		},
+	}}}
+	c := New(context.TODO(), clusterdContext, clusterInfo.Namespace, spec, cephclient.NewMinimumOwnerInfoWithOwnerRef())
+	c.ClusterInfo = clusterInfo
+	c.mapping.Schedule = map[string]*opcontroller.MonScheduleInfo{
+		"a": {Name: "node-a", Zone: "old-arbiter"},
+		"b": {Name: "node-b", Zone: "x"},
+		"c": {Name: "node-c", Zone: "y"},
+		"d": {Name: "node-d", Zone: "y"},
+	}
+
+	t.Run("waiting for quorum", func(t *testing.T) {
+		assert.False(t, c.relocateStretchMon(false, 3))
+		assert.Contains(t, c.ClusterInfo.Monitors, "a")
+	})
+
+	t.Run("stretch mode data zone not relocated", func(t *testing.T) {
+		c.mapping.Schedule["a"].Zone = "new-arbiter"
+		c.mapping.Schedule["d"].Zone = "z"
+		assert.False(t, c.relocateStretchMon(true, 3))
+		assert.Contains(t, c.ClusterInfo.Monitors, "d")
+	})
+
+	t.Run("data zone relocated without the stretch mode", func(t *testing.T) {
+		c.spec.Mon.StretchCluster.Mode = cephv1.StretchModePools
+		// the mon is removed instead of failed over since there is an extra mon
+		assert.True(t, c.relocateStretchMon(true, 3))
+		assert.NotContains(t, c.ClusterInfo.Monitors, "d")
+	})
+
+	t.Run("arbiter relocated", func(t *testing.T) {
+		c.spec.Mon.StretchCluster.Mode = cephv1.StretchModeCluster
+		c.mapping.Schedule["a"].Zone = "old-arbiter"
+		c.ClusterInfo.Monitors["d"] = cephclient.NewMonInfo("d", "1.2.3.4", 6789)
+		c.mapping.Schedule["d"] = &opcontroller.MonScheduleInfo{Name: "node-d", Zone: "y"}
+		assert.True(t, c.relocateStretchMon(true, 3))
+		assert.NotContains(t, c.ClusterInfo.Monitors, "a")
+	})
+
+	t.Run("nothing to relocate", func(t *testing.T) {
+		assert.False(t, c.relocateStretchMon(true, 3))
+	})
+}
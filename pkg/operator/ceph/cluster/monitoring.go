@@ -80,7 +80,7 @@ func isMonitoringEnabled(daemon string, clusterSpec *cephv1.ClusterSpec) bool {
 func (c *ClusterController) startMonitoringCheck(cluster *cluster, clusterInfo *cephclient.ClusterInfo, daemon string) {
 	switch daemon {
 	case "mon":
-		healthChecker := mon.NewHealthChecker(cluster.mons)
+		healthChecker := mon.NewHealthChecker(cluster.mons, c.recorder)
 		logger.Infof("enabling ceph %s monitoring goroutine for cluster %q", daemon, cluster.Namespace)
 		go healthChecker.Check(cluster.monitoringRoutines, daemon)
 
@@ -0,0 +1,348 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opconfig "github.com/rook/rook/pkg/operator/ceph/config"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+)
+
+const (
+	// the weights of the crush map dump are 16.16 fixed-point numbers
+	crushWeightScale = 0x10000
+	// crushUpdateOnStartOption is the option of an OSD to update its location in the CRUSH map when it starts
+	crushUpdateOnStartOption = "osd_crush_update_on_start"
+)
+
+type crushDriftKind int
+
+const (
+	crushBucketMissing crushDriftKind = iota
+	crushBucketMisplaced
+	crushOSDMissing
+	crushOSDMisplaced
+	crushOSDWeight
+)
+
+// crushDrift is a difference between the CRUSH map and the crush topology
+type crushDrift struct {
+	kind   crushDriftKind
+	bucket cephv1.CrushBucketSpec
+	osd    cephv1.CrushOSDSpec
+	// current is the current parent of the bucket or the OSD, or the current weight of the OSD
+	current string
+}
+
+func (d crushDrift) String() string {
+	switch d.kind {
+	case crushBucketMissing:
+		return fmt.Sprintf("%s bucket %q does not exist", d.bucket.Type, d.bucket.Name)
+	case crushBucketMisplaced:
+		return fmt.Sprintf("bucket %q is under %q instead of %q", d.bucket.Name, d.current, d.bucket.Parent)
+	case crushOSDMissing:
+		return fmt.Sprintf("osd.%d does not exist in the crush map", d.osd.ID)
+	case crushOSDMisplaced:
+		return fmt.Sprintf("osd.%d is in %q instead of %q", d.osd.ID, d.current, d.osd.Bucket)
+	case crushOSDWeight:
+		return fmt.Sprintf("osd.%d has weight %s instead of %s", d.osd.ID, d.current, d.osd.Weight)
+	}
+	return ""
+}
+
+// crushHierarchy is the parent and the weight of the items of the CRUSH map
+type crushHierarchy struct {
+	bucketIDs   map[string]int
+	bucketTypes map[string]string
+	parents     map[int]string
+	weights     map[int]int
+}
+
+func newCrushHierarchy(crushMap cephclient.CrushMap) crushHierarchy {
+	h := crushHierarchy{
+		bucketIDs:   map[string]int{},
+		bucketTypes: map[string]string{},
+		parents:     map[int]string{},
+		weights:     map[int]int{},
+	}
+	for _, bucket := range crushMap.Buckets {
+		h.bucketIDs[bucket.Name] = bucket.ID
+		h.bucketTypes[bucket.Name] = bucket.TypeName
+		for _, item := range bucket.Items {
+			h.parents[item.ID] = bucket.Name
+			h.weights[item.ID] = item.Weight
+		}
+	}
+	return h
+}
+
+// getCrushTopologyDrift compares the CRUSH map to the crush topology
+func getCrushTopologyDrift(crushMap cephclient.CrushMap, topology *cephv1.CrushTopologySpec) ([]crushDrift, error) {
+	h := newCrushHierarchy(crushMap)
+	drift := []crushDrift{}
+
+	for _, bucket := range topology.Buckets {
+		id, ok := h.bucketIDs[bucket.Name]
+		if !ok {
+			drift = append(drift, crushDrift{kind: crushBucketMissing, bucket: bucket})
+			continue
+		}
+		if bucket.Parent != "" && h.parents[id] != bucket.Parent {
+			drift = append(drift, crushDrift{kind: crushBucketMisplaced, bucket: bucket, current: h.parents[id]})
+		}
+	}
+
+	for _, osd := range topology.OSDs {
+		parent, ok := h.parents[osd.ID]
+		if !ok {
+			drift = append(drift, crushDrift{kind: crushOSDMissing, osd: osd})
+			continue
+		}
+		if osd.Bucket != "" && parent != osd.Bucket {
+			drift = append(drift, crushDrift{kind: crushOSDMisplaced, osd: osd, current: parent})
+		}
+		if osd.Weight != "" {
+			weight, err := strconv.ParseFloat(osd.Weight, 64)
+			if err != nil {
+				return nil, errors.Wrapf(err, "invalid crush weight %q of osd.%d", osd.Weight, osd.ID)
+			}
+			if int(math.Round(weight*crushWeightScale)) != h.weights[osd.ID] {
+				current := strconv.FormatFloat(float64(h.weights[osd.ID])/crushWeightScale, 'f', -1, 64)
+				drift = append(drift, crushDrift{kind: crushOSDWeight, osd: osd, current: current})
+			}
+		}
+	}
+
+	return drift, nil
+}
+
+// applyCrushTopology corrects the drift of the CRUSH map from the crush topology
+func applyCrushTopology(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, crushMap cephclient.CrushMap, topology *cephv1.CrushTopologySpec, drift []crushDrift) error {
+	h := newCrushHierarchy(crushMap)
+	bucketType := func(name string) string {
+		for _, bucket := range topology.Buckets {
+			if bucket.Name == name {
+				return bucket.Type
+			}
+		}
+		return h.bucketTypes[name]
+	}
+
+	// create all the missing buckets first so they can be the parent of the others
+	for _, d := range drift {
+		if d.kind == crushBucketMissing {
+			logger.Infof("creating %s bucket %q in the crush map", d.bucket.Type, d.bucket.Name)
+			if err := cephclient.CreateCrushBucket(context, clusterInfo, d.bucket.Name, d.bucket.Type); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, d := range drift {
+		switch d.kind {
+		case crushBucketMissing, crushBucketMisplaced:
+			if d.bucket.Parent == "" {
+				continue
+			}
+			parentType := bucketType(d.bucket.Parent)
+			if parentType == "" {
+				return errors.Errorf("parent bucket %q of bucket %q does not exist", d.bucket.Parent, d.bucket.Name)
+			}
+			logger.Infof("moving bucket %q under %s %q in the crush map", d.bucket.Name, parentType, d.bucket.Parent)
+			if err := cephclient.MoveCrushBucket(context, clusterInfo, d.bucket.Name, parentType, d.bucket.Parent); err != nil {
+				return err
+			}
+		}
+	}
+
+	for _, d := range drift {
+		switch d.kind {
+		case crushOSDMissing:
+			logger.Warningf("cannot apply the crush topology of osd.%d, it is not in the crush map", d.osd.ID)
+		case crushOSDMisplaced:
+			parentType := bucketType(d.osd.Bucket)
+			if parentType == "" {
+				return errors.Errorf("bucket %q of osd.%d does not exist", d.osd.Bucket, d.osd.ID)
+			}
+			weight := float64(h.weights[d.osd.ID]) / crushWeightScale
+			if d.osd.Weight != "" {
+				weight, _ = strconv.ParseFloat(d.osd.Weight, 64)
+			}
+			logger.Infof("moving osd.%d to %s %q in the crush map", d.osd.ID, parentType, d.osd.Bucket)
+			if err := cephclient.SetOSDCrushLocation(context, clusterInfo, d.osd.ID, weight, parentType, d.osd.Bucket); err != nil {
+				return err
+			}
+		case crushOSDWeight:
+			if d.osd.Bucket != "" && h.parents[d.osd.ID] != d.osd.Bucket {
+				// already weighted when placed in its bucket
+				continue
+			}
+			weight, _ := strconv.ParseFloat(d.osd.Weight, 64)
+			logger.Infof("setting the crush weight of osd.%d to %s", d.osd.ID, d.osd.Weight)
+			if err := cephclient.ReweightOSD(context, clusterInfo, d.osd.ID, weight); err != nil {
+				return err
+			}
+		}
+	}
+
+	return nil
+}
+
+// updatePinnedOSDs disables the crush location update on start of the OSDs placed in a bucket of the
+// topology, since an OSD would move itself back under its host when it restarts. The update is
+// enabled again for the OSDs previously pinned that are no longer placed by the topology. The OSDs
+// pinned by the operator are returned, including the OSDs that failed to be unpinned.
+func updatePinnedOSDs(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, topology *cephv1.CrushTopologySpec, previous []int) ([]int, error) {
+	monStore := opconfig.GetMonStore(context, clusterInfo)
+	pinned := []int{}
+	if topology != nil {
+		for _, osd := range topology.OSDs {
+			if osd.Bucket == "" {
+				continue
+			}
+			if _, err := monStore.SetIfChanged(fmt.Sprintf("osd.%d", osd.ID), crushUpdateOnStartOption, "false"); err != nil {
+				return append(pinned, previous...), errors.Wrapf(err, "failed to disable the crush location update of osd.%d", osd.ID)
+			}
+			pinned = append(pinned, osd.ID)
+		}
+	}
+
+	var err error
+	for _, id := range previous {
+		if slices.Contains(pinned, id) {
+			continue
+		}
+		logger.Infof("enabling the crush location update on start of osd.%d since it is no longer placed by the crush topology", id)
+		if deleteErr := monStore.Delete(fmt.Sprintf("osd.%d", id), crushUpdateOnStartOption); deleteErr != nil {
+			err = errors.Wrapf(deleteErr, "failed to enable the crush location update of osd.%d", id)
+			pinned = append(pinned, id)
+		}
+	}
+
+	return pinned, err
+}
+
+// reconcileCrushTopology reconciles the CRUSH map to the crush topology of the storage spec and
+// reports the drift in the CephCluster status
+func (c *Cluster) reconcileCrushTopology() error {
+	return reconcileCrushTopology(c.context, c.clusterInfo, c.spec.Storage.CrushTopology)
+}
+
+func reconcileCrushTopology(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, topology *cephv1.CrushTopologySpec) error {
+	cephCluster := &cephv1.CephCluster{}
+	err := context.Client.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve ceph cluster %q to reconcile the crush topology", clusterInfo.NamespacedName().Name)
+	}
+	var previous []int
+	if cephCluster.Status.CrushTopology != nil {
+		previous = cephCluster.Status.CrushTopology.PinnedOSDs
+	}
+
+	if topology == nil {
+		if len(previous) > 0 {
+			pinned, err := updatePinnedOSDs(context, clusterInfo, nil, previous)
+			if err != nil {
+				if statusErr := updateCrushTopologyStatus(context, clusterInfo, &cephv1.CrushTopologyStatus{PinnedOSDs: pinned, Message: err.Error()}); statusErr != nil {
+					return statusErr
+				}
+				return err
+			}
+		}
+		return updateCrushTopologyStatus(context, clusterInfo, nil)
+	}
+
+	status := &cephv1.CrushTopologyStatus{PinnedOSDs: previous}
+	drift, err := checkCrushTopology(context, clusterInfo, topology)
+	if err == nil && len(drift) > 0 && !topology.ReportOnly {
+		err = correctCrushTopology(context, clusterInfo, topology, drift)
+		if err == nil {
+			drift, err = checkCrushTopology(context, clusterInfo, topology)
+		}
+	}
+	if err == nil && !topology.ReportOnly {
+		status.PinnedOSDs, err = updatePinnedOSDs(context, clusterInfo, topology, previous)
+	}
+	if err != nil {
+		status.Message = err.Error()
+	}
+	for _, d := range drift {
+		status.Drift = append(status.Drift, d.String())
+	}
+	status.InSync = err == nil && len(drift) == 0
+	status.LastChecked = time.Now().UTC().Format(time.RFC3339)
+
+	if len(drift) > 0 {
+		logger.Infof("crush map drifts from the crush topology: %v", status.Drift)
+	}
+	if statusErr := updateCrushTopologyStatus(context, clusterInfo, status); statusErr != nil {
+		return statusErr
+	}
+	return err
+}
+
+func checkCrushTopology(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, topology *cephv1.CrushTopologySpec) ([]crushDrift, error) {
+	crushMap, err := cephclient.GetCrushMap(context, clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return getCrushTopologyDrift(crushMap, topology)
+}
+
+func correctCrushTopology(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, topology *cephv1.CrushTopologySpec, drift []crushDrift) error {
+	crushMap, err := cephclient.GetCrushMap(context, clusterInfo)
+	if err != nil {
+		return err
+	}
+	return applyCrushTopology(context, clusterInfo, crushMap, topology, drift)
+}
+
+func updateCrushTopologyStatus(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, status *cephv1.CrushTopologyStatus) error {
+	cephCluster := cephv1.CephCluster{}
+	err := context.Client.Get(clusterInfo.Context, clusterInfo.NamespacedName(), &cephCluster)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephCluster resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve ceph cluster %q to update the crush topology status", clusterInfo.NamespacedName().Name)
+	}
+
+	if status == nil && cephCluster.Status.CrushTopology == nil {
+		return nil
+	}
+
+	cephCluster.Status.CrushTopology = status
+	if err := reporting.UpdateStatus(context.Client, &cephCluster); err != nil {
+		return errors.Wrapf(err, "failed to update cluster %q crush topology status", clusterInfo.NamespacedName().Name)
+	}
+	return nil
+}
@@ -0,0 +1,178 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package osd
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	testexec "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientfake "sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+// default > host-a > osd.0 (weight 1), osd.1 (weight 2); row1 is not placed in the hierarchy
+const topologyCrushMap = `{"buckets":[
+{"id":-1,"name":"default","type_name":"root","items":[{"id":-2,"weight":196608}]},
+{"id":-2,"name":"host-a","type_name":"host","items":[{"id":0,"weight":65536},{"id":1,"weight":131072}]},
+{"id":-3,"name":"row1","type_name":"row","items":[]}]}`
+
+func TestGetCrushTopologyDrift(t *testing.T) {
+	var crushMap cephclient.CrushMap
+	assert.NoError(t, json.Unmarshal([]byte(topologyCrushMap), &crushMap))
+
+	t.Run("in sync", func(t *testing.T) {
+		topology := &cephv1.CrushTopologySpec{
+			Buckets: []cephv1.CrushBucketSpec{{Name: "host-a", Type: "host", Parent: "default"}, {Name: "default", Type: "root"}},
+			OSDs:    []cephv1.CrushOSDSpec{{ID: 0, Bucket: "host-a", Weight: "1"}, {ID: 1, Weight: "2.0"}},
+		}
+		drift, err := getCrushTopologyDrift(crushMap, topology)
+		assert.NoError(t, err)
+		assert.Empty(t, drift)
+	})
+
+	t.Run("drift", func(t *testing.T) {
+		topology := &cephv1.CrushTopologySpec{
+			Buckets: []cephv1.CrushBucketSpec{
+				{Name: "row1", Type: "row", Parent: "default"},
+				{Name: "host-a", Type: "host", Parent: "row1"},
+				{Name: "chassis1", Type: "chassis", Parent: "row1"},
+			},
+			OSDs: []cephv1.CrushOSDSpec{{ID: 0, Bucket: "chassis1"}, {ID: 1, Weight: "0.5"}, {ID: 7, Weight: "1"}},
+		}
+		drift, err := getCrushTopologyDrift(crushMap, topology)
+		assert.NoError(t, err)
+		messages := []string{}
+		for _, d := range drift {
+			messages = append(messages, d.String())
+		}
+		assert.Equal(t, []string{
+			`bucket "row1" is under "" instead of "default"`,
+			`bucket "host-a" is under "default" instead of "row1"`,
+			`chassis bucket "chassis1" does not exist`,
+			`osd.0 is in "host-a" instead of "chassis1"`,
+			`osd.1 has weight 2 instead of 0.5`,
+			`osd.7 does not exist in the crush map`,
+		}, messages)
+	})
+}
+
+func TestReconcileCrushTopology(t *testing.T) {
+	clusterInfo := cephclient.AdminTestClusterInfo("fake")
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "osd" && args[1] == "crush" {
+				if args[2] == "dump" {
+					return topologyCrushMap, nil
+				}
+				commands = append(commands, strings.Join(args[:5], " "))
+			}
+			return "", nil
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "config" && args[1] == "set" {
+				commands = append(commands, strings.Join(args[:5], " "))
+			}
+			if args[0] == "config" && args[1] == "rm" {
+				commands = append(commands, strings.Join(args[:4], " "))
+			}
+			return "", nil
+		},
+	}
+
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "testing", Namespace: "fake"}}
+	client := clientfake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephCluster).WithStatusSubresource(cephCluster).Build()
+	context := &clusterd.Context{Executor: executor, Client: client, Clientset: testexec.New(t, 1)}
+
+	spec := cephv1.ClusterSpec{}
+	spec.Storage.CrushTopology = &cephv1.CrushTopologySpec{
+		Buckets: []cephv1.CrushBucketSpec{
+			{Name: "chassis1", Type: "chassis", Parent: "row1"},
+			{Name: "row1", Type: "row", Parent: "default"},
+		},
+		OSDs: []cephv1.CrushOSDSpec{{ID: 0, Bucket: "chassis1", Weight: "1.5"}, {ID: 1, Weight: "0.5"}},
+	}
+	c := New(context, clusterInfo, spec, "myversion")
+
+	t.Run("drift corrected", func(t *testing.T) {
+		assert.NoError(t, c.reconcileCrushTopology())
+		assert.Equal(t, []string{
+			"osd crush add-bucket chassis1 chassis",
+			"osd crush move chassis1 row=row1",
+			"osd crush move row1 root=default",
+			"osd crush set osd.0 1.5",
+			"osd crush reweight osd.1 0.5",
+			"config set osd.0 osd_crush_update_on_start false",
+		}, commands)
+
+		// the mocked crush map is unchanged so the drift is still reported
+		assert.NoError(t, client.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster))
+		assert.False(t, cephCluster.Status.CrushTopology.InSync)
+		assert.Len(t, cephCluster.Status.CrushTopology.Drift, 5)
+		assert.NotEmpty(t, cephCluster.Status.CrushTopology.LastChecked)
+		assert.Equal(t, []int{0}, cephCluster.Status.CrushTopology.PinnedOSDs)
+	})
+
+	t.Run("osd no longer placed in a bucket", func(t *testing.T) {
+		commands = nil
+		c.spec.Storage.CrushTopology.OSDs[0].Bucket = ""
+		assert.NoError(t, c.reconcileCrushTopology())
+		assert.Contains(t, commands, "config rm osd.0 osd_crush_update_on_start")
+		assert.NotContains(t, commands, "config set osd.0 osd_crush_update_on_start false")
+
+		assert.NoError(t, client.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster))
+		assert.Empty(t, cephCluster.Status.CrushTopology.PinnedOSDs)
+		c.spec.Storage.CrushTopology.OSDs[0].Bucket = "chassis1"
+	})
+
+	t.Run("drift checked periodically", func(t *testing.T) {
+		commands = nil
+		assert.NoError(t, client.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster))
+		cephCluster.Spec.Storage.CrushTopology = c.spec.Storage.CrushTopology
+		assert.NoError(t, client.Update(clusterInfo.Context, cephCluster))
+
+		osdMon := NewOSDHealthMonitor(context, clusterInfo, false, cephv1.CephClusterHealthCheckSpec{})
+		assert.NoError(t, osdMon.checkCrushTopology())
+		assert.Contains(t, commands, "osd crush add-bucket chassis1 chassis")
+		assert.Contains(t, commands, "config set osd.0 osd_crush_update_on_start false")
+	})
+
+	t.Run("drift reported only", func(t *testing.T) {
+		commands = nil
+		c.spec.Storage.CrushTopology.ReportOnly = true
+		assert.NoError(t, c.reconcileCrushTopology())
+		assert.Empty(t, commands)
+	})
+
+	t.Run("status cleared", func(t *testing.T) {
+		commands = nil
+		c.spec.Storage.CrushTopology = nil
+		assert.NoError(t, c.reconcileCrushTopology())
+		assert.Equal(t, []string{"config rm osd.0 osd_crush_update_on_start"}, commands)
+		assert.NoError(t, client.Get(clusterInfo.Context, clusterInfo.NamespacedName(), cephCluster))
+		assert.Nil(t, cephCluster.Status.CrushTopology)
+	})
+}
@@ -100,6 +100,28 @@ func (m *OSDHealthMonitor) checkOSDHealth() {
 	if err != nil {
 		logger.Debugf("failed to check OSD Dump. %v", err)
 	}
+
+	err = m.checkCrushTopology()
+	if err != nil {
+		logger.Debugf("failed to check the crush topology. %v", err)
+	}
+}
+
+// checkCrushTopology reconciles the CRUSH map to the crush topology of the CephCluster between the
+// reconciles of the cluster, so the drift is reported and corrected when it happens
+func (m *OSDHealthMonitor) checkCrushTopology() error {
+	cephCluster := &cephv1.CephCluster{}
+	if err := m.context.Client.Get(m.clusterInfo.Context, m.clusterInfo.NamespacedName(), cephCluster); err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to get ceph cluster %q", m.clusterInfo.NamespacedName().Name)
+	}
+	if cephCluster.Spec.Storage.CrushTopology == nil {
+		return nil
+	}
+
+	return reconcileCrushTopology(m.context, m.clusterInfo, cephCluster.Spec.Storage.CrushTopology)
 }
 
 func (m *OSDHealthMonitor) checkOSDDump() error {
@@ -268,6 +268,11 @@ func (c *Cluster) Start() error {
 		return errors.Wrapf(err, "failed to update ceph storage status")
 	}
 
+	// a failure to reconcile the crush topology is reported in the status and should not block the osds
+	if err := c.reconcileCrushTopology(); err != nil {
+		logger.Errorf("failed to reconcile the crush topology. %v", err)
+	}
+
 	if c.spec.Storage.Store.UpdateStore == OSDStoreUpdateConfirmation {
 		if c.replaceOSD != nil {
 			delOpts := &k8sutil.DeleteOptions{MustDelete: true, WaitOptions: k8sutil.WaitOptions{Wait: true}}
@@ -154,6 +154,14 @@ func watchControllerPredicate(ctx context.Context, c client.Client) predicate.Fu
 
 					return false
 
+				} else if annotation := addedMonOperationAnnotation(objOld, objNew); annotation != "" {
+					logger.Infof("CR %q is annotated with %q, cancelling any ongoing orchestration to run the mon operation", objNew.Name, annotation)
+
+					// Stop any ongoing orchestration, which is likely waiting for the mons to be in quorum
+					controller.ReloadManager()
+
+					return false
+
 				} else if !objOld.GetDeletionTimestamp().Equal(objNew.GetDeletionTimestamp()) {
 					logger.Infof("CR %q is going be deleted, cancelling any ongoing orchestration", objNew.Name)
 
@@ -174,3 +182,70 @@ func watchControllerPredicate(ctx context.Context, c client.Client) predicate.Fu
 		},
 	}
 }
+
+// addedMonOperationAnnotation returns the annotation requesting an operation on the mons that was
+// added to the cluster, or an empty string if none was added
+func addedMonOperationAnnotation(objOld, objNew *cephv1.CephCluster) string {
+	for _, annotation := range []string{cephv1.RestoreMonQuorumAnnotation, cephv1.RemapMonAddressesAnnotation} {
+		_, wasRequested := objOld.GetAnnotations()[annotation]
+		_, isRequested := objNew.GetAnnotations()[annotation]
+		if isRequested && !wasRequested {
+			return annotation
+		}
+	}
+	return ""
+}
+
+// predicateForDashboardTLSSecretWatcher is the predicate function to trigger reconcile when the
+// dashboard tls secret of a cluster is created or renewed
+func predicateForDashboardTLSSecretWatcher(ctx context.Context, client client.Client) predicate.Funcs {
+	return predicate.Funcs{
+		CreateFunc: func(e event.CreateEvent) bool {
+			return isDashboardTLSSecret(ctx, client, e.Object)
+		},
+
+		UpdateFunc: func(e event.UpdateEvent) bool {
+			oldSecret, ok := e.ObjectOld.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			newSecret, ok := e.ObjectNew.(*corev1.Secret)
+			if !ok {
+				return false
+			}
+			if cmp.Equal(oldSecret.Data, newSecret.Data) {
+				return false
+			}
+			return isDashboardTLSSecret(ctx, client, e.ObjectNew)
+		},
+
+		DeleteFunc: func(e event.DeleteEvent) bool {
+			return false
+		},
+
+		GenericFunc: func(e event.GenericEvent) bool {
+			return false
+		},
+	}
+}
+
+// isDashboardTLSSecret informs whether the object is the dashboard tls secret of a cluster
+func isDashboardTLSSecret(ctx context.Context, c client.Client, obj client.Object) bool {
+	if _, ok := obj.(*corev1.Secret); !ok {
+		return false
+	}
+
+	clusters := &cephv1.CephClusterList{}
+	if err := c.List(ctx, clusters, client.InNamespace(obj.GetNamespace())); err != nil {
+		logger.Debugf("failed to list ceph clusters in namespace %q. %v", obj.GetNamespace(), err)
+		return false
+	}
+	for _, cluster := range clusters.Items {
+		tls := cluster.Spec.Dashboard.TLS
+		if cluster.Spec.Dashboard.Enabled && tls != nil && tls.SecretName == obj.GetName() {
+			logger.Infof("dashboard tls secret %q of cluster %q changed, reconciling", obj.GetName(), cluster.Name)
+			return true
+		}
+	}
+	return false
+}
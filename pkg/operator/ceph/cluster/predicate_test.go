@@ -17,11 +17,16 @@ limitations under the License.
 package cluster
 
 import (
+	"context"
 	"testing"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/stretchr/testify/assert"
 	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 )
 
 func TestIsHotPlugCM(t *testing.T) {
@@ -40,3 +45,47 @@ func TestIsHotPlugCM(t *testing.T) {
 	cm.Labels["app"] = "rook-discover"
 	assert.True(t, isHotPlugCM(cm))
 }
+
+func TestIsDashboardTLSSecret(t *testing.T) {
+	ctx := context.TODO()
+	cephCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "rook-ceph"}}
+	cephCluster.Spec.Dashboard = cephv1.DashboardSpec{Enabled: true, SSL: true, TLS: &cephv1.DashboardTLSSpec{SecretName: "dashboard-tls"}}
+	c := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithRuntimeObjects(cephCluster).Build()
+
+	secret := &corev1.Secret{ObjectMeta: metav1.ObjectMeta{Name: "dashboard-tls", Namespace: "rook-ceph"}}
+	assert.True(t, isDashboardTLSSecret(ctx, c, secret))
+
+	// the predicate only matches the secret of the cluster namespace
+	secret.Namespace = "other"
+	assert.False(t, isDashboardTLSSecret(ctx, c, secret))
+	secret.Namespace = "rook-ceph"
+	secret.Name = "other"
+	assert.False(t, isDashboardTLSSecret(ctx, c, secret))
+	assert.False(t, isDashboardTLSSecret(ctx, c, &corev1.ConfigMap{ObjectMeta: metav1.ObjectMeta{Name: "dashboard-tls", Namespace: "rook-ceph"}}))
+
+	// the renewal of the secret triggers a reconcile, not an update of its metadata
+	secret.Name = "dashboard-tls"
+	renewed := secret.DeepCopy()
+	renewed.Labels = map[string]string{"foo": "bar"}
+	p := predicateForDashboardTLSSecretWatcher(ctx, c)
+	assert.False(t, p.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: renewed}))
+	renewed.Data = map[string][]byte{corev1.TLSCertKey: []byte("cert")}
+	assert.True(t, p.Update(event.UpdateEvent{ObjectOld: secret, ObjectNew: renewed}))
+}
+
+func TestAddedMonOperationAnnotation(t *testing.T) {
+	oldCluster := &cephv1.CephCluster{ObjectMeta: metav1.ObjectMeta{Name: "my-cluster", Namespace: "rook-ceph"}}
+	newCluster := oldCluster.DeepCopy()
+	assert.Empty(t, addedMonOperationAnnotation(oldCluster, newCluster))
+
+	newCluster.Annotations = map[string]string{cephv1.RemapMonAddressesAnnotation: "true"}
+	assert.Equal(t, cephv1.RemapMonAddressesAnnotation, addedMonOperationAnnotation(oldCluster, newCluster))
+
+	// the removal of the annotation once the operation is done is not a new request
+	assert.Empty(t, addedMonOperationAnnotation(newCluster, oldCluster))
+	assert.Empty(t, addedMonOperationAnnotation(newCluster, newCluster.DeepCopy()))
+
+	restoreCluster := newCluster.DeepCopy()
+	restoreCluster.Annotations[cephv1.RestoreMonQuorumAnnotation] = ""
+	assert.Equal(t, cephv1.RestoreMonQuorumAnnotation, addedMonOperationAnnotation(newCluster, restoreCluster))
+}
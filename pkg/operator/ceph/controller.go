@@ -67,7 +67,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 
 func add(ctx context.Context, context *clusterd.Context, mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -120,8 +120,9 @@ func (r *ReconcileConfig) reconcile(request reconcile.Request) (reconcile.Result
 	// controllers they will receive the update
 	opcontroller.SetCephCommandsTimeout(r.config.Parameters)
 
-	// Reconcile Operator's logging level
+	// Reconcile Operator's logging level and format
 	reconcileOperatorLogLevel(opConfig.Data)
+	reconcileOperatorLogFormat(opConfig.Data)
 
 	// Reconcile discovery daemon
 	err = r.reconcileDiscoveryDaemon(opConfig.Data)
@@ -140,6 +141,11 @@ func reconcileOperatorLogLevel(data map[string]string) {
 	util.SetGlobalLogLevel(rookLogLevel, logger)
 }
 
+func reconcileOperatorLogFormat(data map[string]string) {
+	logFormat := k8sutil.GetValue(data, "ROOK_LOG_FORMAT", util.LogFormatText)
+	util.SetLogFormat(logFormat, logger)
+}
+
 func (r *ReconcileConfig) reconcileDiscoveryDaemon(data map[string]string) error {
 	rookDiscover := discover.New(r.context.Clientset)
 	if opcontroller.DiscoveryDaemonEnabled(r.config.Parameters) {
@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+type tracedReconciler struct {
+	controllerName string
+	reconciler     reconcile.Reconciler
+	logger         *capnslog.PackageLogger
+}
+
+// NewTracedReconciler returns the reconciler of the controller with each reconcile in a span named
+// after the controller, and logged at debug level with the name, namespace and ID of the reconcile.
+// The ceph commands run with a context holding the span of the reconcile are child spans of the reconcile.
+// The reconcile context holds the log fields of the util.ContextLogger of the controllers.
+func NewTracedReconciler(controllerName string, r reconcile.Reconciler) reconcile.Reconciler {
+	return &tracedReconciler{
+		controllerName: controllerName,
+		reconciler:     r,
+		logger:         capnslog.NewPackageLogger("github.com/rook/rook", controllerName),
+	}
+}
+
+func (t *tracedReconciler) Reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	fields := ReconcileLogFields(ctx, t.controllerName, request)
+	ctx, span := tracing.Tracer().Start(ctx, t.controllerName+" reconcile", trace.WithAttributes(
+		attribute.String("rook.controller", t.controllerName),
+		attribute.String("rook.namespace", request.Namespace),
+		attribute.String("rook.name", request.Name),
+		attribute.String("rook.reconcile_id", fields["reconcileID"]),
+	))
+	defer span.End()
+	if span.SpanContext().HasTraceID() {
+		fields["traceID"] = span.SpanContext().TraceID().String()
+	}
+
+	// the logs of the reconcile carry the fields through the context loggers of the controllers. The
+	// fields are copied since the duration and the error are added to the fields of the done log.
+	contextFields := make(util.LogFields, len(fields))
+	for k, v := range fields {
+		contextFields[k] = v
+	}
+	ctx = util.ContextWithLogFields(ctx, contextFields)
+
+	t.logger.Debug(util.StructuredLogEntry{Message: "reconcile started", Fields: fields})
+	start := time.Now()
+	result, err := t.reconciler.Reconcile(ctx, request)
+	fields["duration"] = time.Since(start).String()
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		fields["error"] = err.Error()
+	}
+	t.logger.Debug(util.StructuredLogEntry{Message: "reconcile done", Fields: fields})
+
+	return result, err
+}
+
+// ReconcileLogFields returns the fields of the structured logs of a reconcile
+func ReconcileLogFields(ctx context.Context, controllerName string, request reconcile.Request) util.LogFields {
+	return util.LogFields{
+		"controller":  controllerName,
+		"namespace":   request.Namespace,
+		"name":        request.Name,
+		"reconcileID": string(controller.ReconcileIDFromContext(ctx)),
+	}
+}
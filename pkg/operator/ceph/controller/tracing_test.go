@@ -0,0 +1,74 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package controller
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/exec"
+	"github.com/rook/rook/pkg/util/tracing"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestTracedReconciler(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+
+	opManagerContext, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+	reconcileErr := errors.New("failed to configure pool")
+	var logFields util.LogFields
+	r := NewTracedReconciler("ceph-block-pool-controller", reconcile.Func(func(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+		logFields = util.LogFieldsFromContext(ctx)
+		// the ceph commands run with the operator context holding the span of the reconcile
+		clusterContext := tracing.ContextWithSpan(opManagerContext, ctx)
+		exec.StartCommandSpan(clusterContext, "ceph", "osd", "pool", "create", "replicapool")(nil)
+		return reconcile.Result{}, reconcileErr
+	}))
+
+	request := reconcile.Request{NamespacedName: types.NamespacedName{Namespace: "rook-ceph", Name: "replicapool"}}
+	_, err := r.Reconcile(context.TODO(), request)
+	assert.Equal(t, reconcileErr, err)
+
+	spans := recorder.Ended()
+	assert.Len(t, spans, 2)
+	command, reconcileSpan := spans[0], spans[1]
+	assert.Equal(t, "ceph-block-pool-controller reconcile", reconcileSpan.Name())
+	assert.Contains(t, reconcileSpan.Attributes(), attribute.String("rook.namespace", "rook-ceph"))
+	assert.Contains(t, reconcileSpan.Attributes(), attribute.String("rook.name", "replicapool"))
+	assert.Equal(t, codes.Error, reconcileSpan.Status().Code)
+	assert.Equal(t, "ceph osd pool create", command.Name())
+	assert.Equal(t, reconcileSpan.SpanContext().SpanID(), command.Parent().SpanID())
+	assert.Equal(t, reconcileSpan.SpanContext().TraceID(), command.SpanContext().TraceID())
+
+	// the logs of the reconcile carry the fields of the reconcile, without the result of the reconcile
+	assert.Equal(t, "ceph-block-pool-controller", logFields["controller"])
+	assert.Equal(t, "rook-ceph", logFields["namespace"])
+	assert.Equal(t, "replicapool", logFields["name"])
+	assert.Equal(t, reconcileSpan.SpanContext().TraceID().String(), logFields["traceID"])
+	assert.NotContains(t, logFields, "error")
+}
@@ -18,6 +18,7 @@ package operator
 
 import (
 	"context"
+	"time"
 
 	"github.com/pkg/errors"
 	"github.com/rook/rook/pkg/clusterd"
@@ -27,6 +28,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/cluster/rbd"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/csi"
+	"github.com/rook/rook/pkg/operator/ceph/dashboarduser"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/clusterdisruption"
 	"github.com/rook/rook/pkg/operator/ceph/disruption/controllerconfig"
 	"github.com/rook/rook/pkg/operator/ceph/file"
@@ -43,7 +45,10 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/object/zone"
 	"github.com/rook/rook/pkg/operator/ceph/object/zonegroup"
 	"github.com/rook/rook/pkg/operator/ceph/pool"
+	"github.com/rook/rook/pkg/operator/ceph/pool/crushrule"
 	"github.com/rook/rook/pkg/operator/ceph/pool/radosnamespace"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/tracing"
 	"k8s.io/apimachinery/pkg/runtime"
 
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
@@ -66,6 +71,12 @@ var (
 	EnableMachineDisruptionBudget bool
 )
 
+// tracingShutdownTimeout is the time to flush the remaining spans when the operator stops
+const tracingShutdownTimeout = 5 * time.Second
+
+// defaultMetricsBindAddress is the default bind address of the operator metrics server
+const defaultMetricsBindAddress = ":8080"
+
 // AddToManagerFuncsMaintenance is a list of functions to add all Controllers to the Manager (entrypoint for controller)
 var AddToManagerFuncsMaintenance = []func(manager.Manager, *controllerconfig.Context) error{
 	clusterdisruption.Add,
@@ -92,6 +103,8 @@ var AddToManagerFuncs = []func(manager.Manager, *clusterd.Context, context.Conte
 	notification.Add,
 	subvolumegroup.Add,
 	radosnamespace.Add,
+	crushrule.Add,
+	dashboarduser.Add,
 	cosi.Add,
 }
 
@@ -141,13 +154,25 @@ func (o *Operator) startCRDManager(context context.Context, mgrErrorCh chan erro
 		}
 	}
 
+	// The reconciles of the controllers are traced when the export of the traces is enabled
+	stopTracing := o.startTracing(context)
+	defer stopTracing()
+
+	// The controller runtime metrics server exports the metrics of the controllers, such as the
+	// mirroring status of the images of the block pools. It is disabled with the bind address 0,
+	// for instance if the port is already in use on the host network of the operator.
+	metricsBindAddress, err := k8sutil.GetOperatorSetting(context, o.context.Clientset, opcontroller.OperatorSettingConfigMapName, "ROOK_OPERATOR_METRICS_BIND_ADDRESS", defaultMetricsBindAddress)
+	if err != nil {
+		logger.Warningf("failed to get the metrics bind address, using the default %q. %v", defaultMetricsBindAddress, err)
+		metricsBindAddress = defaultMetricsBindAddress
+	}
+
 	// Set up a manager
 	mgrOpts := manager.Options{
 		LeaderElection: false,
 		Metrics: metricsserver.Options{
-			// BindAddress is the bind address for controller runtime metrics server default is 8080. Since we don't use the
-			// controller runtime metrics server, we need to set the bind address 0 so that port 8080 is available.
-			BindAddress: "0",
+			// BindAddress is the bind address for controller runtime metrics server, "0" disables it
+			BindAddress: metricsBindAddress,
 		},
 		Scheme: scheme,
 	}
@@ -187,3 +212,40 @@ func (o *Operator) startCRDManager(context context.Context, mgrErrorCh chan erro
 
 	logger.Info("successfully started the controller-runtime manager")
 }
+
+// startTracing starts the export of the traces of the reconciles to the OTLP collector of the
+// operator settings, if enabled. Changing the settings requires restarting the operator. The
+// returned function flushes the remaining spans when the manager is stopped.
+func (o *Operator) startTracing(ctx context.Context) func() {
+	noop := func() {}
+	enabled, err := k8sutil.GetOperatorSetting(ctx, o.context.Clientset, opcontroller.OperatorSettingConfigMapName, "ROOK_TRACING_ENABLED", "false")
+	if err != nil {
+		logger.Warningf("failed to get the tracing setting, disabling the export of the traces. %v", err)
+		return noop
+	}
+	if enabled != "true" {
+		return noop
+	}
+	endpoint, err := k8sutil.GetOperatorSetting(ctx, o.context.Clientset, opcontroller.OperatorSettingConfigMapName, "ROOK_TRACING_OTLP_ENDPOINT", "")
+	if err != nil {
+		logger.Warningf("failed to get the otlp endpoint, using the default endpoint. %v", err)
+	}
+	insecure, err := k8sutil.GetOperatorSetting(ctx, o.context.Clientset, opcontroller.OperatorSettingConfigMapName, "ROOK_TRACING_OTLP_INSECURE", "false")
+	if err != nil {
+		logger.Warningf("failed to get the otlp insecure setting, using tls. %v", err)
+	}
+
+	shutdown, err := tracing.Start(ctx, "rook-ceph-operator", tracing.Config{Endpoint: endpoint, Insecure: insecure == "true"})
+	if err != nil {
+		logger.Errorf("failed to start the export of the traces. %v", err)
+		return noop
+	}
+	return func() {
+		// the operator context is done, so the remaining spans are flushed with a new context
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), tracingShutdownTimeout)
+		defer cancel()
+		if err := shutdown(shutdownCtx); err != nil {
+			logger.Warningf("failed to flush the traces. %v", err)
+		}
+	}
+}
@@ -0,0 +1,449 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package dashboarduser to manage the users of the Ceph dashboard
+package dashboarduser
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"strings"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/cluster/mgr"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/tracing"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-dashboard-user-controller"
+	// passwordKeyName is the key of the password in the password secret of a user
+	passwordKeyName = "password"
+	// the length of the password generated for the users that only sign in with single sign-on
+	generatedPasswordLength = 20
+	// the dashboard user managed by the mgr controller
+	dashboardAdminUsername = "admin"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var dashboardUserKind = reflect.TypeOf(cephv1.CephDashboardUser{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       dashboardUserKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephDashboardUser reconciles a CephDashboardUser object
+type ReconcileCephDashboardUser struct {
+	client           client.Client
+	scheme           *runtime.Scheme
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+	recorder         record.EventRecorder
+}
+
+// Add creates a new CephDashboardUser Controller and adds it to the Manager. The Manager will set
+// fields on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephDashboardUser{
+		client:           mgr.GetClient(),
+		scheme:           mgr.GetScheme(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		recorder:         mgr.GetEventRecorderFor("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephDashboardUser CRD object
+	err = c.Watch(source.Kind(mgr.GetCache(), &cephv1.CephDashboardUser{TypeMeta: controllerTypeMeta}), &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	// Watch for changes on the password secrets of the users
+	secretKind := source.Kind(mgr.GetCache(), &corev1.Secret{TypeMeta: metav1.TypeMeta{Kind: "Secret", APIVersion: corev1.SchemeGroupVersion.String()}})
+	err = c.Watch(secretKind, handler.EnqueueRequestsFromMapFunc(usersOfPasswordSecret(mgr.GetClient())))
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// usersOfPasswordSecret returns the requests of the users whose password is in a secret
+func usersOfPasswordSecret(cl client.Client) handler.MapFunc {
+	return func(ctx context.Context, obj client.Object) []reconcile.Request {
+		users := &cephv1.CephDashboardUserList{}
+		if err := cl.List(ctx, users, client.InNamespace(obj.GetNamespace())); err != nil {
+			logger.Debugf("failed to list dashboard users. %v", err)
+			return nil
+		}
+		var requests []reconcile.Request
+		for _, user := range users.Items {
+			if user.Spec.PasswordSecretName == obj.GetName() {
+				requests = append(requests, reconcile.Request{NamespacedName: types.NamespacedName{Namespace: user.Namespace, Name: user.Name}})
+			}
+		}
+		return requests
+	}
+}
+
+// Reconcile reads that state of the cluster for a CephDashboardUser object and makes changes based
+// on the state read and what is in the CephDashboardUser.Spec The Controller will requeue the
+// Request to be processed again if the returned error is non-nil or Result.Requeue is true,
+// otherwise upon completion it will remove the work from the queue.
+func (r *ReconcileCephDashboardUser) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(context, request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %q %v", request.NamespacedName, err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephDashboardUser) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := util.NewContextLogger(ctx, logger)
+	namespacedName := request.NamespacedName
+	// Fetch the CephDashboardUser instance
+	dashboardUser := &cephv1.CephDashboardUser{}
+	err := r.client.Get(r.opManagerContext, namespacedName, dashboardUser)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("cephDashboardUser resource %q not found. Ignoring since object must be deleted.", namespacedName)
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get cephDashboardUser")
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, dashboardUser)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// The CR was just created, initializing status fields
+	if dashboardUser.Status == nil {
+		r.updateStatus(namespacedName, cephv1.ConditionProgressing, 0)
+		dashboardUser.Status = &cephv1.DashboardUserStatus{}
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, namespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the user deletion since everything is gone already
+		//
+		// Also, only remove the finalizer if the CephCluster is gone
+		// If not, we should wait for it to be ready
+		// This handles the case where the operator is not ready to accept Ceph command but the cluster exists
+		if !dashboardUser.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, dashboardUser)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, tracing.ContextWithSpan(r.opManagerContext, ctx), namespacedName.Namespace, &cephCluster.Spec)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	// DELETE: the CR was deleted
+	if !dashboardUser.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("delete cephDashboardUser %q", namespacedName)
+		// The users are kept by the dashboard module when it is disabled, they cannot be deleted
+		if !cephCluster.Spec.Dashboard.Enabled {
+			logger.Warningf("dashboard is disabled, dashboard users %v are not deleted", dashboardUser.Status.Users)
+		} else {
+			err := r.deleteUsers(namespacedName, dashboardUser, nil)
+			if err != nil {
+				if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+					logger.Info(opcontroller.OperatorNotInitializedMessage)
+					return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
+				}
+				return reconcile.Result{}, err
+			}
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, dashboardUser)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	if !cephCluster.Spec.Dashboard.Enabled {
+		logger.Infof("dashboard is disabled, waiting to create dashboard user %q", dashboardUser.GetUsername())
+		r.updateStatus(namespacedName, cephv1.ConditionFailure, 0)
+		return opcontroller.WaitForRequeueIfCephClusterNotReady, nil
+	}
+
+	// Create or update the dashboard users
+	err = r.reconcileUsers(namespacedName, dashboardUser)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			logger.Info(opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
+		}
+		r.updateStatus(namespacedName, cephv1.ConditionFailure, 0)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create or update dashboard users %v", dashboardUser.GetUsernames())
+	}
+
+	r.updateStatus(namespacedName, cephv1.ConditionReady, dashboardUser.Generation)
+	logger.Debugf("done reconciling cephDashboardUser %q", namespacedName)
+
+	// Return and do not requeue
+	return reconcile.Result{}, nil
+}
+
+// reconcileUsers creates or updates the users of the CR, and deletes the users created by the
+// operator that are no longer in the spec, e.g. the members removed from a group
+func (r *ReconcileCephDashboardUser) reconcileUsers(namespacedName types.NamespacedName, dashboardUser *cephv1.CephDashboardUser) error {
+	if err := validateDashboardUser(dashboardUser); err != nil {
+		return err
+	}
+
+	password, err := r.getPassword(dashboardUser)
+	if err != nil {
+		return err
+	}
+
+	usernames := dashboardUser.GetUsernames()
+	for _, username := range usernames {
+		if err := r.createOrUpdateUser(namespacedName, dashboardUser, username, password); err != nil {
+			return err
+		}
+	}
+
+	return r.deleteUsers(namespacedName, dashboardUser, usernames)
+}
+
+// validateDashboardUser checks the usernames of the spec
+func validateDashboardUser(dashboardUser *cephv1.CephDashboardUser) error {
+	if len(dashboardUser.Spec.Members) > 0 {
+		if dashboardUser.Spec.Username != "" {
+			return errors.New("username and members cannot be both set")
+		}
+		if dashboardUser.Spec.PasswordSecretName != "" {
+			return errors.New("the members of a group can only sign in with single sign-on, passwordSecretName cannot be set")
+		}
+	}
+	for _, username := range dashboardUser.GetUsernames() {
+		if username == dashboardAdminUsername {
+			return errors.Errorf("dashboard user %q is managed by the CephCluster", username)
+		}
+	}
+	return nil
+}
+
+// createOrUpdateUser creates the user in the dashboard and applies the roles, the info and the
+// password of the spec. A user that exists without being created by the operator is not taken over.
+func (r *ReconcileCephDashboardUser) createOrUpdateUser(namespacedName types.NamespacedName, dashboardUser *cephv1.CephDashboardUser, username, password string) error {
+	current, err := cephclient.GetDashboardUser(r.context, r.clusterInfo, username)
+	if err != nil {
+		return err
+	}
+	owned := slices.Contains(dashboardUser.Status.Users, username)
+	if current != nil && !owned {
+		return errors.Errorf("dashboard user %q already exists and was not created by the operator, delete the user in the dashboard or choose another username", username)
+	}
+
+	if current == nil {
+		if !owned {
+			// the ownership is recorded before the user is created so that the user is not refused
+			// if the status update fails after the creation
+			if err := r.updateUsers(namespacedName, dashboardUser, append(slices.Clone(dashboardUser.Status.Users), username)); err != nil {
+				return err
+			}
+		}
+		if password == "" {
+			// the user only signs in with single sign-on, but the dashboard requires a password
+			password, err = mgr.GeneratePassword(generatedPasswordLength)
+			if err != nil {
+				return errors.Wrapf(err, "failed to generate a password for dashboard user %q", username)
+			}
+		}
+		if err := cephclient.CreateDashboardUser(r.context, r.clusterInfo, username, password); err != nil {
+			return err
+		}
+		current = &cephclient.DashboardUser{Username: username, Enabled: true}
+	} else if password != "" {
+		if err := cephclient.SetDashboardUserPassword(r.context, r.clusterInfo, username, password); err != nil {
+			return err
+		}
+	}
+
+	if !sameRoles(current.Roles, dashboardUser.Spec.Roles) {
+		if err := cephclient.SetDashboardUserRoles(r.context, r.clusterInfo, username, dashboardUser.Spec.Roles); err != nil {
+			return err
+		}
+	}
+	if current.Name != dashboardUser.Spec.Name || current.Email != dashboardUser.Spec.Email {
+		if err := cephclient.SetDashboardUserInfo(r.context, r.clusterInfo, username, dashboardUser.Spec.Name, dashboardUser.Spec.Email); err != nil {
+			return err
+		}
+	}
+	enabled := dashboardUser.Spec.Enabled == nil || *dashboardUser.Spec.Enabled
+	if current.Enabled != enabled {
+		if err := cephclient.SetDashboardUserEnabled(r.context, r.clusterInfo, username, enabled); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// deleteUsers deletes the users created by the operator for the CR, except the given users
+func (r *ReconcileCephDashboardUser) deleteUsers(namespacedName types.NamespacedName, dashboardUser *cephv1.CephDashboardUser, keep []string) error {
+	users := []string{}
+	for _, username := range dashboardUser.Status.Users {
+		if slices.Contains(keep, username) {
+			users = append(users, username)
+			continue
+		}
+		if err := cephclient.DeleteDashboardUser(r.context, r.clusterInfo, username); err != nil {
+			return err
+		}
+	}
+	if len(users) == len(dashboardUser.Status.Users) {
+		return nil
+	}
+	return r.updateUsers(namespacedName, dashboardUser, users)
+}
+
+// getPassword returns the password of the password secret of the user, or an empty string if the
+// user has no password secret
+func (r *ReconcileCephDashboardUser) getPassword(dashboardUser *cephv1.CephDashboardUser) (string, error) {
+	secretName := dashboardUser.Spec.PasswordSecretName
+	if secretName == "" {
+		return "", nil
+	}
+	secret := &corev1.Secret{}
+	err := r.client.Get(r.opManagerContext, types.NamespacedName{Namespace: dashboardUser.Namespace, Name: secretName}, secret)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get password secret %q", secretName)
+	}
+	password, ok := secret.Data[passwordKeyName]
+	if !ok || len(password) == 0 {
+		return "", errors.Errorf("password secret %q has no %q", secretName, passwordKeyName)
+	}
+	return string(password), nil
+}
+
+// sameRoles returns whether two lists have the same roles in any order
+func sameRoles(current, desired []string) bool {
+	if len(current) != len(desired) {
+		return false
+	}
+	a := append([]string{}, current...)
+	b := append([]string{}, desired...)
+	sort.Strings(a)
+	sort.Strings(b)
+	return reflect.DeepEqual(a, b)
+}
+
+// updateStatus updates an object with a given status
+func (r *ReconcileCephDashboardUser) updateStatus(name types.NamespacedName, status cephv1.ConditionType, observedGeneration int64) {
+	dashboardUser := &cephv1.CephDashboardUser{}
+	if err := r.client.Get(r.opManagerContext, name, dashboardUser); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("CephDashboardUser resource %q not found. Ignoring since object must be deleted.", name)
+			return
+		}
+		logger.Warningf("failed to retrieve dashboard user %q to update status to %q. %v", name, status, err)
+		return
+	}
+	if dashboardUser.Status == nil {
+		dashboardUser.Status = &cephv1.DashboardUserStatus{}
+	}
+
+	dashboardUser.Status.Phase = status
+	if observedGeneration != 0 {
+		dashboardUser.Status.ObservedGeneration = observedGeneration
+	}
+	if err := reporting.UpdateStatus(r.client, dashboardUser); err != nil {
+		logger.Errorf("failed to set dashboard user %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("dashboard user %q status updated to %q", name, status)
+}
+
+// updateUsers records the users created by the operator in the status
+func (r *ReconcileCephDashboardUser) updateUsers(name types.NamespacedName, dashboardUser *cephv1.CephDashboardUser, users []string) error {
+	updated := &cephv1.CephDashboardUser{}
+	if err := r.client.Get(r.opManagerContext, name, updated); err != nil {
+		return errors.Wrapf(err, "failed to retrieve dashboard user %q to update the users", name)
+	}
+	if updated.Status == nil {
+		updated.Status = &cephv1.DashboardUserStatus{}
+	}
+	updated.Status.Users = users
+	if err := reporting.UpdateStatus(r.client, updated); err != nil {
+		return errors.Wrapf(err, "failed to set the users of dashboard user %q", name)
+	}
+	dashboardUser.Status.Users = users
+	return nil
+}
@@ -0,0 +1,293 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package dashboarduser
+
+import (
+	"context"
+	"os"
+	"strings"
+	"syscall"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+func TestCephDashboardUserController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "alice", Namespace: namespace}}
+
+	dashboardUser := &cephv1.CephDashboardUser{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       req.Name,
+			Namespace:  namespace,
+			Generation: 1,
+		},
+		Spec: cephv1.DashboardUserSpec{
+			Username:           "alice@example.com",
+			Roles:              []string{"read-only", "block-manager"},
+			PasswordSecretName: "alice-password",
+		},
+	}
+	passwordSecret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "alice-password", Namespace: namespace},
+		Data:       map[string][]byte{"password": []byte("p@ssw0rd")},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Spec:       cephv1.ClusterSpec{Dashboard: cephv1.DashboardSpec{Enabled: true}},
+		Status: cephv1.ClusterStatus{
+			Phase:       cephv1.ConditionReady,
+			CephVersion: &cephv1.ClusterVersion{Version: "18.2.0-0"},
+			CephStatus:  &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+
+	userShow := ""
+	var commands []string
+	var passwords []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] != "dashboard" {
+				return "", nil
+			}
+			if args[1] == "ac-user-show" {
+				if userShow == "" {
+					return "", syscall.ENOENT
+				}
+				return userShow, nil
+			}
+			var cmd []string
+			for i := 0; i < len(args) && !strings.HasPrefix(args[i], "--"); i++ {
+				if args[i] == "-i" {
+					password, err := os.ReadFile(args[i+1])
+					assert.NoError(t, err)
+					passwords = append(passwords, string(password))
+					break
+				}
+				cmd = append(cmd, args[i])
+			}
+			commands = append(commands, strings.Join(cmd, " "))
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{
+		Executor:      executor,
+		Clientset:     testop.New(t, 1),
+		RookClientset: rookclient.NewSimpleClientset(),
+	}
+
+	// Mock clusterInfo
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"fsid":         []byte("c47cac40-9bee-4d52-823b-ccd803ba5bfe"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	s.AddKnownTypes(v1.SchemeGroupVersion, &v1.Secret{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(dashboardUser, passwordSecret, cephCluster).WithStatusSubresource(dashboardUser).Build()
+	r := &ReconcileCephDashboardUser{
+		client:           cl,
+		scheme:           s,
+		context:          c,
+		opManagerContext: ctx,
+		recorder:         record.NewFakeRecorder(10),
+	}
+
+	getDashboardUser := func() *cephv1.CephDashboardUser {
+		updated := &cephv1.CephDashboardUser{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, updated))
+		return updated
+	}
+
+	t.Run("user created", func(t *testing.T) {
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+		assert.Equal(t, []string{
+			"dashboard ac-user-create alice@example.com",
+			"dashboard ac-user-set-roles alice@example.com read-only block-manager",
+		}, commands)
+		assert.Equal(t, []string{"p@ssw0rd"}, passwords)
+
+		updated := getDashboardUser()
+		assert.Equal(t, cephv1.ConditionReady, updated.Status.Phase)
+		assert.Equal(t, int64(1), updated.Status.ObservedGeneration)
+		assert.Equal(t, []string{"alice@example.com"}, updated.Status.Users)
+	})
+
+	t.Run("user updated", func(t *testing.T) {
+		commands = nil
+		passwords = nil
+		userShow = `{"username": "alice@example.com", "roles": ["block-manager", "read-only"], "name": "", "email": "", "enabled": true}`
+		updated := getDashboardUser()
+		disabled := false
+		updated.Spec.Enabled = &disabled
+		updated.Spec.Email = "alice@example.com"
+		assert.NoError(t, cl.Update(ctx, updated))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"dashboard ac-user-set-password alice@example.com",
+			"dashboard ac-user-set-info alice@example.com  alice@example.com",
+			"dashboard ac-user-disable alice@example.com",
+		}, commands)
+		assert.Equal(t, []string{"p@ssw0rd"}, passwords)
+	})
+
+	t.Run("sso user with a generated password", func(t *testing.T) {
+		commands = nil
+		passwords = nil
+		userShow = ""
+		updated := getDashboardUser()
+		updated.Spec = cephv1.DashboardUserSpec{Roles: []string{"administrator"}}
+		assert.NoError(t, cl.Update(ctx, updated))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		// the user of the previous username is deleted
+		assert.Equal(t, []string{
+			"dashboard ac-user-create alice",
+			"dashboard ac-user-set-roles alice administrator",
+			"dashboard ac-user-delete alice@example.com",
+		}, commands)
+		assert.Len(t, passwords, 1)
+		assert.Len(t, passwords[0], generatedPasswordLength)
+		assert.Equal(t, []string{"alice"}, getDashboardUser().Status.Users)
+	})
+
+	t.Run("existing user is not taken over", func(t *testing.T) {
+		commands = nil
+		userShow = `{"username": "bob", "roles": ["read-only"], "name": "", "email": "", "enabled": true}`
+		updated := getDashboardUser()
+		updated.Spec.Username = "bob"
+		assert.NoError(t, cl.Update(ctx, updated))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, `dashboard user "bob" already exists and was not created by the operator`)
+		assert.Empty(t, commands)
+		updated = getDashboardUser()
+		assert.Equal(t, cephv1.ConditionFailure, updated.Status.Phase)
+		assert.Equal(t, []string{"alice"}, updated.Status.Users)
+
+		updated.Spec.Username = ""
+		assert.NoError(t, cl.Update(ctx, updated))
+	})
+
+	t.Run("group members", func(t *testing.T) {
+		commands = nil
+		passwords = nil
+		userShow = ""
+		updated := getDashboardUser()
+		updated.Spec.Members = []string{"carol", "dave"}
+		assert.NoError(t, cl.Update(ctx, updated))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{
+			"dashboard ac-user-create carol",
+			"dashboard ac-user-set-roles carol administrator",
+			"dashboard ac-user-create dave",
+			"dashboard ac-user-set-roles dave administrator",
+			"dashboard ac-user-delete alice",
+		}, commands)
+		assert.Len(t, passwords, 2)
+		assert.Equal(t, []string{"carol", "dave"}, getDashboardUser().Status.Users)
+
+		// the user of a member removed from the group is deleted
+		commands = nil
+		userShow = `{"username": "carol", "roles": ["administrator"], "name": "", "email": "", "enabled": true}`
+		updated = getDashboardUser()
+		updated.Spec.Members = []string{"carol"}
+		assert.NoError(t, cl.Update(ctx, updated))
+
+		_, err = r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"dashboard ac-user-delete dave"}, commands)
+		assert.Equal(t, []string{"carol"}, getDashboardUser().Status.Users)
+
+		// the members only sign in with single sign-on
+		updated = getDashboardUser()
+		updated.Spec.PasswordSecretName = "alice-password"
+		assert.NoError(t, cl.Update(ctx, updated))
+		_, err = r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, "passwordSecretName cannot be set")
+
+		updated = getDashboardUser()
+		updated.Spec.PasswordSecretName = ""
+		assert.NoError(t, cl.Update(ctx, updated))
+	})
+
+	t.Run("admin user is managed by the cluster", func(t *testing.T) {
+		updated := getDashboardUser()
+		updated.Spec.Members = []string{"carol", "admin"}
+		assert.NoError(t, cl.Update(ctx, updated))
+
+		_, err := r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, `dashboard user "admin" is managed by the CephCluster`)
+		assert.Equal(t, cephv1.ConditionFailure, getDashboardUser().Status.Phase)
+
+		updated = getDashboardUser()
+		updated.Spec.Members = []string{"carol"}
+		assert.NoError(t, cl.Update(ctx, updated))
+	})
+
+	t.Run("user deleted", func(t *testing.T) {
+		commands = nil
+		assert.NoError(t, cl.Delete(ctx, getDashboardUser()))
+
+		// only the users created by the operator are deleted
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"dashboard ac-user-delete carol"}, commands)
+
+		err = cl.Get(ctx, req.NamespacedName, &cephv1.CephDashboardUser{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+}
+
+func TestSameRoles(t *testing.T) {
+	assert.True(t, sameRoles([]string{"read-only", "pool-manager"}, []string{"pool-manager", "read-only"}))
+	assert.True(t, sameRoles(nil, []string{}))
+	assert.False(t, sameRoles([]string{"read-only"}, []string{"read-only", "pool-manager"}))
+	assert.False(t, sameRoles([]string{"read-only"}, []string{"administrator"}))
+}
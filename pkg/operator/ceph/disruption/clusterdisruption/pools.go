@@ -172,7 +172,7 @@ func (r *ReconcileClusterDisruption) reconcileCephObjectStore(cephObjectStoreLis
 }
 
 // Setting naive minAvailable for MDS at: n -1
-// getting n from the cephfilesystem.spec.metadataserver.activecount
+// getting n from the cephfilesystem.spec.metadataserver.activecount, or the mds autoscaler status
 func (r *ReconcileClusterDisruption) reconcileCephFilesystem(cephFilesystemList *cephv1.CephFilesystemList) error {
 	for _, filesystem := range cephFilesystemList.Items {
 		fsName := filesystem.ObjectMeta.Name
@@ -182,7 +182,7 @@ func (r *ReconcileClusterDisruption) reconcileCephFilesystem(cephFilesystemList
 			MatchLabels: map[string]string{"rook_file_system": fsName},
 		}
 
-		activeCount := filesystem.Spec.MetadataServer.ActiveCount
+		activeCount := filesystem.ActiveMDSCount()
 		minAvailable := &intstr.IntOrString{IntVal: activeCount - 1}
 		if filesystem.Spec.MetadataServer.ActiveStandby {
 			minAvailable.IntVal++
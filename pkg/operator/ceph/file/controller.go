@@ -34,6 +34,8 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/file/mirror"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/tracing"
 	appsv1 "k8s.io/api/apps/v1"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
@@ -43,6 +45,7 @@ import (
 	"k8s.io/client-go/tools/record"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/event"
 	"sigs.k8s.io/controller-runtime/pkg/handler"
 	"sigs.k8s.io/controller-runtime/pkg/manager"
 	"sigs.k8s.io/controller-runtime/pkg/reconcile"
@@ -82,36 +85,45 @@ type ReconcileCephFilesystem struct {
 	fsContexts       map[string]*fsHealth
 	opManagerContext context.Context
 	opConfig         opcontroller.OperatorConfig
+	// mdsAutoscalerReconciles receives the filesystems to reconcile when the mds autoscaler changes
+	// their number of active ranks
+	mdsAutoscalerReconciles chan event.GenericEvent
 }
 
 type fsHealth struct {
-	internalCtx    context.Context
-	internalCancel context.CancelFunc
-	started        bool
+	internalCtx                context.Context
+	internalCancel             context.CancelFunc
+	started                    bool
+	snapshotSchedulesMonitored bool
+	pinningMonitored           bool
+	scrubMonitored             bool
+	mdsAutoscalerMonitored     bool
 }
 
 // Add creates a new CephFilesystem Controller and adds it to the Manager. The Manager will set fields on the Controller
 // and Start it when the Manager is Started.
 func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
-	return add(opManagerContext, mgr, newReconciler(mgr, context, opManagerContext, opConfig))
+	r := newReconciler(mgr, context, opManagerContext, opConfig)
+	return add(opManagerContext, mgr, r, r.mdsAutoscalerReconciles)
 }
 
 // newReconciler returns a new reconcile.Reconciler
-func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) reconcile.Reconciler {
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) *ReconcileCephFilesystem {
 	return &ReconcileCephFilesystem{
-		client:           mgr.GetClient(),
-		recorder:         mgr.GetEventRecorderFor("rook-" + controllerName),
-		scheme:           mgr.GetScheme(),
-		context:          context,
-		fsContexts:       make(map[string]*fsHealth),
-		opManagerContext: opManagerContext,
-		opConfig:         opConfig,
+		client:                  mgr.GetClient(),
+		recorder:                mgr.GetEventRecorderFor("rook-" + controllerName),
+		scheme:                  mgr.GetScheme(),
+		context:                 context,
+		fsContexts:              make(map[string]*fsHealth),
+		opManagerContext:        opManagerContext,
+		opConfig:                opConfig,
+		mdsAutoscalerReconciles: make(chan event.GenericEvent),
 	}
 }
 
-func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reconciler) error {
+func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reconciler, mdsAutoscalerReconciles <-chan event.GenericEvent) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -137,6 +149,12 @@ func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reco
 		}
 	}
 
+	// Watch for the filesystems whose number of active mds ranks is changed by the mds autoscaler
+	err = c.Watch(&source.Channel{Source: mdsAutoscalerReconciles}, &handler.EnqueueRequestForObject{})
+	if err != nil {
+		return err
+	}
+
 	// Build Handler function to return the list of ceph filesystems
 	// This is used by the watchers below
 	handlerFunc, err := opcontroller.ObjectToCRMapper(opManagerContext, mgr.GetClient(), &cephv1.CephFilesystemList{}, mgr.GetScheme())
@@ -159,7 +177,7 @@ func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reco
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileCephFilesystem) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
-	reconcileResponse, cephFilesystem, err := r.reconcile(request)
+	reconcileResponse, cephFilesystem, err := r.reconcile(context, request)
 	if err != nil {
 		logger.Errorf("failed to reconcile %v", err)
 	}
@@ -167,7 +185,8 @@ func (r *ReconcileCephFilesystem) Reconcile(context context.Context, request rec
 	return reporting.ReportReconcileResult(logger, r.recorder, request, &cephFilesystem, reconcileResponse, err)
 }
 
-func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephFilesystem, error) {
+func (r *ReconcileCephFilesystem) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, cephv1.CephFilesystem, error) {
+	logger := util.NewContextLogger(ctx, logger)
 	// Fetch the cephFilesystem instance
 	cephFilesystem := &cephv1.CephFilesystem{}
 	err := r.client.Get(r.opManagerContext, request.NamespacedName, cephFilesystem)
@@ -244,7 +263,7 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 
 	// Populate clusterInfo
 	// Always populate it during each reconcile
-	clusterInfo, _, _, err := opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, r.cephClusterSpec)
+	clusterInfo, _, _, err := opcontroller.LoadClusterInfo(r.context, tracing.ContextWithSpan(r.opManagerContext, ctx), request.NamespacedName.Namespace, r.cephClusterSpec)
 	if err != nil {
 		return reconcile.Result{}, *cephFilesystem, errors.Wrap(err, "failed to populate cluster info")
 	}
@@ -257,10 +276,10 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 			return reconcile.Result{}, *cephFilesystem, err
 		}
 		if !deps.Empty() {
-			err := reporting.ReportDeletionBlockedDueToDependents(r.opManagerContext, logger, r.client, cephFilesystem, deps)
+			err := reporting.ReportDeletionBlockedDueToDependents(r.opManagerContext, logger.PackageLogger, r.client, cephFilesystem, deps)
 			return opcontroller.WaitForRequeueIfFinalizerBlocked, *cephFilesystem, err
 		}
-		reporting.ReportDeletionNotBlockedDueToDependents(r.opManagerContext, logger, r.client, r.recorder, cephFilesystem)
+		reporting.ReportDeletionNotBlockedDueToDependents(r.opManagerContext, logger.PackageLogger, r.client, r.recorder, cephFilesystem)
 
 		runningCephVersion, err := cephclient.LeastUptodateDaemonVersion(r.context, clusterInfo, config.MonType)
 		if err != nil {
@@ -330,6 +349,10 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 			errors.Wrapf(err, "invalid object filesystem %q arguments", cephFilesystem.Name)
 	}
 
+	// The mds deployments and max_mds are reconciled with the number of active ranks of the spec once
+	// the autoscaler is disabled
+	r.clearStatusMDSAutoscaler(cephFilesystem, request.NamespacedName)
+
 	// RECONCILE
 	logger.Debug("reconciling ceph filesystem store deployments")
 	reconcileResponse, err = r.reconcileCreateFilesystem(cephFilesystem)
@@ -394,6 +417,45 @@ func (r *ReconcileCephFilesystem) reconcile(request reconcile.Request) (reconcil
 			}
 		}
 	}
+	// Add the snapshot schedules, independently of mirroring
+	err = r.reconcileSnapshotSchedules(cephFilesystem)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, *cephFilesystem,
+			errors.Wrapf(err, "failed to configure snapshot schedules for filesystem %q.", cephFilesystem.Name)
+	}
+	hasSnapshotSchedules := len(cephFilesystem.Spec.SnapshotSchedules) > 0 || (cephFilesystem.Status != nil && len(cephFilesystem.Status.SnapshotSchedules) > 0)
+	if hasSnapshotSchedules && !r.fsContexts[fsChannelKeyName(cephFilesystem)].snapshotSchedulesMonitored {
+		checker := newSnapshotScheduleChecker(r.context, r.client, r.clusterInfo, request.NamespacedName)
+		go checker.checkSnapshotSchedules(r.fsContexts[fsChannelKeyName(cephFilesystem)].internalCtx)
+		r.fsContexts[fsChannelKeyName(cephFilesystem)].snapshotSchedulesMonitored = true
+	}
+
+	// Pin the filesystem directories to the mds ranks
+	r.reconcilePinning(cephFilesystem, request.NamespacedName)
+	hasPinning := len(cephFilesystem.Spec.Pinning) > 0 || (cephFilesystem.Status != nil && cephFilesystem.Status.Pinning != nil)
+	if hasPinning && !r.fsContexts[fsChannelKeyName(cephFilesystem)].pinningMonitored {
+		checker := newPinningChecker(r.context, r.client, r.clusterInfo, request.NamespacedName)
+		go checker.checkPinning(r.fsContexts[fsChannelKeyName(cephFilesystem)].internalCtx)
+		r.fsContexts[fsChannelKeyName(cephFilesystem)].pinningMonitored = true
+	}
+
+	// Schedule the scrubs and report the damages of the filesystem
+	hasScrub := cephFilesystem.Spec.Scrub != nil || (cephFilesystem.Status != nil && cephFilesystem.Status.Scrub != nil)
+	if hasScrub && !r.fsContexts[fsChannelKeyName(cephFilesystem)].scrubMonitored {
+		checker := newScrubChecker(r.context, r.client, r.recorder, r.clusterInfo, request.NamespacedName)
+		go checker.checkScrub(r.fsContexts[fsChannelKeyName(cephFilesystem)].internalCtx)
+		r.fsContexts[fsChannelKeyName(cephFilesystem)].scrubMonitored = true
+	}
+
+	// Check the load of the mds to scale the active ranks, the filesystem is reconciled again when the
+	// number of active ranks changes
+	autoscalerEnabled := cephFilesystem.Spec.MetadataServer.Autoscaler != nil && cephFilesystem.Spec.MetadataServer.Autoscaler.Enabled
+	if autoscalerEnabled && !r.fsContexts[fsChannelKeyName(cephFilesystem)].mdsAutoscalerMonitored {
+		checker := newMDSAutoscalerChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, r.mdsAutoscalerReconciles)
+		go checker.checkMDSLoad(r.fsContexts[fsChannelKeyName(cephFilesystem)].internalCtx)
+		r.fsContexts[fsChannelKeyName(cephFilesystem)].mdsAutoscalerMonitored = true
+	}
+
 	if !statusUpdated {
 		// update ObservedGeneration in status at the end of reconcile
 		// Set Ready status, we are done reconciling$
@@ -59,7 +59,7 @@ func createFilesystem(
 
 	if len(fs.Spec.DataPools) != 0 {
 		f := newFS(fs.Name, fs.Namespace)
-		if err := f.doFilesystemCreate(context, clusterInfo, clusterSpec, fs.Spec); err != nil {
+		if err := f.doFilesystemCreate(context, clusterInfo, clusterSpec, fs.Spec, fs.MaxMDS()); err != nil {
 			return errors.Wrapf(err, "failed to create filesystem %q", fs.Name)
 		}
 	}
@@ -68,13 +68,13 @@ func createFilesystem(
 	}
 
 	// set the number of active mds instances
-	if fs.Spec.MetadataServer.ActiveCount > 1 {
-		if err := cephclient.SetNumMDSRanks(context, clusterInfo, fs.Name, fs.Spec.MetadataServer.ActiveCount); err != nil {
-			logger.Warningf("failed setting active mds count to %d. %v", fs.Spec.MetadataServer.ActiveCount, err)
+	if maxMDS := fs.MaxMDS(); maxMDS > 1 {
+		if err := cephclient.SetNumMDSRanks(context, clusterInfo, fs.Name, maxMDS); err != nil {
+			logger.Warningf("failed setting active mds count to %d. %v", maxMDS, err)
 		}
 	}
 
-	err := cephclient.CreateCephFSSubVolumeGroup(context, clusterInfo, fs.Name, defaultCSISubvolumeGroup)
+	err := cephclient.CreateCephFSSubVolumeGroup(context, clusterInfo, fs.Name, defaultCSISubvolumeGroup, nil)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create subvolume group %q", defaultCSISubvolumeGroup)
 	}
@@ -94,7 +94,7 @@ func deleteFilesystem(
 	c := mds.NewCluster(clusterInfo, context, clusterSpec, fs, ownerInfo, dataDirHostPath)
 
 	// Delete mds CephX keys and configuration in centralized mon database
-	replicas := fs.Spec.MetadataServer.ActiveCount * 2
+	replicas := fs.ActiveMDSCount() * 2
 	for i := 0; i < int(replicas); i++ {
 		daemonLetterID := k8sutil.IndexToName(i)
 		daemonName := fmt.Sprintf("%s-%s", fs.Name, daemonLetterID)
@@ -140,6 +140,9 @@ func validateFilesystem(context *clusterd.Context, clusterInfo *cephclient.Clust
 	if f.Spec.MetadataServer.ActiveCount < 1 {
 		return errors.New("MetadataServer.ActiveCount must be at least 1")
 	}
+	if err := validateMDSAutoscaler(f.Spec.MetadataServer); err != nil {
+		return errors.Wrap(err, "invalid mds autoscaler")
+	}
 	// No data pool means that we expect the fs to exist already
 	if len(f.Spec.DataPools) == 0 {
 		return nil
@@ -190,14 +193,15 @@ func createOrUpdatePools(f *Filesystem, context *clusterd.Context, clusterInfo *
 	return nil
 }
 
-// updateFilesystem ensures that a filesystem which already exists matches the provided spec.
-func (f *Filesystem) updateFilesystem(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, spec cephv1.FilesystemSpec) error {
+// updateFilesystem ensures that a filesystem which already exists matches the provided spec and
+// runs maxMDS active mds ranks.
+func (f *Filesystem) updateFilesystem(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, spec cephv1.FilesystemSpec, maxMDS int32) error {
 	// Even if the fs already exists, the num active mdses may have changed
-	if err := cephclient.SetNumMDSRanks(context, clusterInfo, f.Name, spec.MetadataServer.ActiveCount); err != nil {
+	if err := cephclient.SetNumMDSRanks(context, clusterInfo, f.Name, maxMDS); err != nil {
 		logger.Errorf(
-			fmt.Sprintf("failed to set num mds ranks (max_mds) to %d for filesystem %s, still continuing. ", spec.MetadataServer.ActiveCount, f.Name) +
+			fmt.Sprintf("failed to set num mds ranks (max_mds) to %d for filesystem %s, still continuing. ", maxMDS, f.Name) +
 				"this error is not critical, but mdses may not be as failure tolerant as desired. " +
-				fmt.Sprintf("USER should verify that the number of active mdses is %d with 'ceph fs get %s'", spec.MetadataServer.ActiveCount, f.Name) +
+				fmt.Sprintf("USER should verify that the number of active mdses is %d with 'ceph fs get %s'", maxMDS, f.Name) +
 				fmt.Sprintf(". %v", err),
 		)
 	}
@@ -216,12 +220,12 @@ func (f *Filesystem) updateFilesystem(context *clusterd.Context, clusterInfo *ce
 }
 
 // doFilesystemCreate starts the Ceph file daemons and creates the filesystem in Ceph.
-func (f *Filesystem) doFilesystemCreate(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, spec cephv1.FilesystemSpec) error {
+func (f *Filesystem) doFilesystemCreate(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, spec cephv1.FilesystemSpec, maxMDS int32) error {
 
 	_, err := cephclient.GetFilesystem(context, clusterInfo, f.Name)
 	if err == nil {
 		logger.Infof("filesystem %q already exists", f.Name)
-		return f.updateFilesystem(context, clusterInfo, clusterSpec, spec)
+		return f.updateFilesystem(context, clusterInfo, clusterSpec, spec, maxMDS)
 	}
 	if len(spec.DataPools) == 0 {
 		return errors.New("at least one data pool must be specified")
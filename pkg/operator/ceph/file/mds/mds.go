@@ -119,13 +119,13 @@ func (c *Cluster) Start() error {
 		if fsPreparedForUpgrade {
 			if err := finishedWithDaemonUpgrade(c.context, c.clusterInfo, c.fs); err != nil {
 				logger.Errorf("for filesystem %q, USER should make sure the Ceph fs max_mds property is set to %d. %v",
-					c.fs.Name, c.fs.Spec.MetadataServer.ActiveCount, err)
+					c.fs.Name, c.fs.MaxMDS(), err)
 			}
 		}
 	}()
 
 	// Always create double the number of metadata servers to have standby mdses available
-	replicas := c.fs.Spec.MetadataServer.ActiveCount * 2
+	replicas := c.fs.ActiveMDSCount() * 2
 
 	mdsToSkipReconcile, err := controller.GetDaemonsToSkipReconcile(c.clusterInfo.Context, c.context, c.clusterInfo.Namespace, config.MdsType, AppName)
 	if err != nil {
@@ -149,7 +149,7 @@ func (c *Cluster) Start() error {
 		desiredDeployments[deployment] = true
 	}
 
-	if err := c.scaleDownDeployments(replicas, c.fs.Spec.MetadataServer.ActiveCount, desiredDeployments, true); err != nil {
+	if err := c.scaleDownDeployments(replicas, c.fs.ActiveMDSCount(), desiredDeployments, true); err != nil {
 		return errors.Wrap(err, "failed to scale down mds deployments")
 	}
 
@@ -394,7 +394,7 @@ func (c *Cluster) DeleteMdsCephObjects(mdsID string) error {
 // ideal state following an upgrade of its daemon(s).
 func finishedWithDaemonUpgrade(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, fs cephv1.CephFilesystem) error {
 	fsName := fs.Name
-	activeMDSCount := fs.Spec.MetadataServer.ActiveCount
+	activeMDSCount := fs.MaxMDS()
 	logger.Debugf("restoring filesystem %s from daemon upgrade", fsName)
 	logger.Debugf("bringing num active MDS daemons for fs %s back to %d", fsName, activeMDSCount)
 	// upgrade guide for mds: https://docs.ceph.com/en/latest/cephfs/upgrading/
@@ -0,0 +1,346 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package file manages a CephFS filesystem and the required daemons.
+package file
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+const (
+	// mdsAutoscalerInterval is the interval at which the load of the mds is checked when the autoscaler is enabled
+	mdsAutoscalerInterval            = time.Minute
+	defaultMDSRequestRateThreshold   = 5000
+	defaultMDSCachePressureThreshold = 80
+	defaultMDSScaleUpCooldown        = 5 * time.Minute
+	defaultMDSScaleDownCooldown      = 15 * time.Minute
+	// an active rank is only removed if the load of the remaining ranks stays below this ratio of the
+	// thresholds, this prevents the number of ranks from flapping
+	mdsScaleDownLoadRatio = 0.5
+	// stopping a rank can take a while since its metadata must be exported, the ranks are checked
+	// at this interval until they are stopped, as well as after a failure to scale down
+	mdsScaleDownCheckInterval = 15 * time.Second
+)
+
+var getMDSLoad = cephclient.GetMDSLoad
+
+func validateMDSAutoscaler(spec cephv1.MetadataServerSpec) error {
+	if spec.Autoscaler == nil || !spec.Autoscaler.Enabled {
+		return nil
+	}
+	minCount, maxCount := mdsActiveCountBounds(spec)
+	if minCount > maxCount {
+		return errors.Errorf("minActiveCount %d must not be greater than maxActiveCount %d", minCount, maxCount)
+	}
+	return nil
+}
+
+// mdsActiveCountBounds returns the minimum and maximum number of active ranks allowed by the autoscaler
+func mdsActiveCountBounds(spec cephv1.MetadataServerSpec) (int32, int32) {
+	minCount, maxCount := spec.ActiveCount, spec.ActiveCount
+	if spec.Autoscaler.MinActiveCount > 0 {
+		minCount = spec.Autoscaler.MinActiveCount
+	}
+	if spec.Autoscaler.MaxActiveCount > 0 {
+		maxCount = spec.Autoscaler.MaxActiveCount
+	}
+	return minCount, maxCount
+}
+
+func durationOrDefault(d *metav1.Duration, defaultDuration time.Duration) time.Duration {
+	if d == nil {
+		return defaultDuration
+	}
+	return d.Duration
+}
+
+// desiredMDSActiveCount returns the number of active ranks for the given load of the mds and the
+// reason of the decision. The number of ranks is changed by one rank at a time.
+func desiredMDSActiveCount(spec cephv1.MetadataServerSpec, current int32, load *cephclient.MDSLoad, lastScale, now time.Time) (int32, string) {
+	minCount, maxCount := mdsActiveCountBounds(spec)
+	if current < minCount {
+		return minCount, fmt.Sprintf("active count raised to the minimum of %d", minCount)
+	}
+	if current > maxCount {
+		return maxCount, fmt.Sprintf("active count lowered to the maximum of %d", maxCount)
+	}
+	if load == nil || load.ActiveCount < 1 {
+		return current, "no active mds"
+	}
+
+	requestRateThreshold := float64(defaultMDSRequestRateThreshold)
+	if spec.Autoscaler.RequestRateThreshold > 0 {
+		requestRateThreshold = float64(spec.Autoscaler.RequestRateThreshold)
+	}
+	cachePressureThreshold := float64(defaultMDSCachePressureThreshold)
+	if spec.Autoscaler.CachePressureThreshold > 0 {
+		cachePressureThreshold = float64(spec.Autoscaler.CachePressureThreshold)
+	}
+	ranks := float64(load.ActiveCount)
+	requestRate := load.RequestRate / ranks
+	cachePressure := float64(load.CachePressure)
+
+	if requestRate > requestRateThreshold || cachePressure > cachePressureThreshold {
+		if current >= maxCount {
+			return current, fmt.Sprintf("mds overloaded but already at the maximum of %d active ranks", maxCount)
+		}
+		cooldown := durationOrDefault(spec.Autoscaler.ScaleUpCooldown, defaultMDSScaleUpCooldown)
+		if now.Sub(lastScale) < cooldown {
+			return current, "mds overloaded, waiting for the scale up cooldown"
+		}
+		return current + 1, fmt.Sprintf("mds overloaded with %.0f requests/s and %.0f%% cache pressure per active rank", requestRate, cachePressure)
+	}
+
+	if current <= minCount || load.ActiveCount < 2 {
+		return current, "mds load within thresholds"
+	}
+	// the load of the removed rank is spread across the remaining ranks
+	remainingRequestRate := load.RequestRate / (ranks - 1)
+	remainingCachePressure := cachePressure * ranks / (ranks - 1)
+	if remainingRequestRate < requestRateThreshold*mdsScaleDownLoadRatio && remainingCachePressure < cachePressureThreshold*mdsScaleDownLoadRatio {
+		cooldown := durationOrDefault(spec.Autoscaler.ScaleDownCooldown, defaultMDSScaleDownCooldown)
+		if now.Sub(lastScale) < cooldown {
+			return current, "mds underloaded, waiting for the scale down cooldown"
+		}
+		return current - 1, fmt.Sprintf("mds underloaded with %.0f requests/s and %.0f%% cache pressure per active rank", requestRate, cachePressure)
+	}
+
+	return current, "mds load within thresholds"
+}
+
+type mdsAutoscalerChecker struct {
+	context        *clusterd.Context
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	// reconciles receives the filesystems whose mds deployments and max_mds must be reconciled with
+	// the autoscaled number of active ranks
+	reconciles chan<- event.GenericEvent
+}
+
+// newMDSAutoscalerChecker creates a new checker of the load of the mds of a filesystem
+func newMDSAutoscalerChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, reconciles chan<- event.GenericEvent) *mdsAutoscalerChecker {
+	return &mdsAutoscalerChecker{
+		context:        context,
+		client:         client,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		reconciles:     reconciles,
+	}
+}
+
+// checkMDSLoad periodically checks the load of the mds and scales the number of active ranks
+func (c *mdsAutoscalerChecker) checkMDSLoad(context context.Context) {
+	// check the load immediately before starting the loop
+	interval := c.autoscale(context, time.Now().UTC())
+
+	for {
+		select {
+		case <-context.Done():
+			logger.Infof("stopping monitoring filesystem mds load %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(interval):
+			logger.Debugf("checking filesystem mds load %q", c.namespacedName.Name)
+			interval = c.autoscale(context, time.Now().UTC())
+		}
+	}
+}
+
+// autoscale updates the number of active ranks chosen by the autoscaler in the status of the filesystem,
+// and returns when the load must be checked again, which is sooner while removed ranks are stopping. The
+// filesystem is only reconciled when the number of active ranks changes, so that the mds deployments and
+// max_mds are reconciled with the autoscaled count. When a rank is removed, max_mds is lowered here and the
+// mds deployments are only scaled down once the rank is stopped.
+func (c *mdsAutoscalerChecker) autoscale(ctx context.Context, now time.Time) time.Duration {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return mdsAutoscalerInterval
+		}
+		logger.Warningf("failed to retrieve ceph filesystem %q to autoscale the mds. %v", c.namespacedName.Name, err)
+		return mdsAutoscalerInterval
+	}
+	spec := fs.Spec.MetadataServer
+	if spec.Autoscaler == nil || !spec.Autoscaler.Enabled {
+		// the status is cleared by the reconcile of the filesystem
+		return mdsAutoscalerInterval
+	}
+
+	activeCount := fs.ActiveMDSCount()
+	status, err := c.desiredMDSAutoscalerStatus(fs, now)
+	if err != nil {
+		logger.Errorf("failed to autoscale the mds of filesystem %q. %v", fs.Name, err)
+		return mdsScaleDownCheckInterval
+	}
+	if !c.updateStatusMDSAutoscaler(fs, status) {
+		return mdsAutoscalerInterval
+	}
+
+	if fs.ActiveMDSCount() != activeCount {
+		select {
+		case c.reconciles <- event.GenericEvent{Object: fs}:
+		case <-ctx.Done():
+		}
+	}
+	if status.PendingActiveCount > 0 {
+		return mdsScaleDownCheckInterval
+	}
+	return mdsAutoscalerInterval
+}
+
+// desiredMDSAutoscalerStatus returns the status of the autoscaler with the number of active ranks for the
+// current load of the mds
+func (c *mdsAutoscalerChecker) desiredMDSAutoscalerStatus(fs *cephv1.CephFilesystem, now time.Time) (*cephv1.MDSAutoscalerStatus, error) {
+	status := &cephv1.MDSAutoscalerStatus{}
+	if fs.Status != nil && fs.Status.MDSAutoscaler != nil {
+		status = fs.Status.MDSAutoscaler.DeepCopy()
+	}
+	current := fs.ActiveMDSCount()
+	status.LastChecked = now.Format(time.RFC3339)
+
+	if status.PendingActiveCount > 0 {
+		if err := c.checkMDSScaleDown(fs.Name, status, now); err != nil {
+			return nil, err
+		}
+		return status, nil
+	}
+
+	lastScale := time.Time{}
+	if status.LastScaleTime != "" {
+		if t, err := time.Parse(time.RFC3339, status.LastScaleTime); err == nil {
+			lastScale = t
+		}
+	}
+
+	// the filesystem may not exist yet, the load is then checked again later
+	load, err := getMDSLoad(c.context, c.clusterInfo, fs.Name)
+	if err != nil {
+		logger.Warningf("failed to get the mds load of filesystem %q. %v", fs.Name, err)
+		load = nil
+		status.Details = fmt.Sprintf("failed to get the mds load. %v", err)
+	}
+
+	desired, reason := desiredMDSActiveCount(fs.Spec.MetadataServer, current, load, lastScale, now)
+	if load != nil {
+		status.RequestRate = int64(math.Round(load.RequestRate))
+		status.CachePressure = load.CachePressure
+		status.Details = reason
+	}
+	status.ActiveCount = current
+
+	if desired < current && load != nil {
+		logger.Infof("scaling down filesystem %q from %d to %d active mds ranks. %s", fs.Name, current, desired, reason)
+		if err := cephclient.SetNumMDSRanks(c.context, c.clusterInfo, fs.Name, desired); err != nil {
+			return nil, errors.Wrapf(err, "failed to lower the number of active mds ranks of filesystem %q to %d", fs.Name, desired)
+		}
+		// the deployments are scaled down when the rank is stopped
+		status.PendingActiveCount = desired
+		status.LastScaleTime = now.Format(time.RFC3339)
+	} else if desired != current {
+		logger.Infof("scaling filesystem %q from %d to %d active mds ranks. %s", fs.Name, current, desired, reason)
+		status.ActiveCount = desired
+		status.LastScaleTime = now.Format(time.RFC3339)
+	}
+
+	return status, nil
+}
+
+// checkMDSScaleDown completes the scale down of the active ranks once the removed ranks are stopped
+func (c *mdsAutoscalerChecker) checkMDSScaleDown(fsName string, status *cephv1.MDSAutoscalerStatus, now time.Time) error {
+	fs, err := cephclient.GetFilesystem(c.context, c.clusterInfo, fsName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get filesystem %q to check the stopping mds ranks", fsName)
+	}
+	if fs.MDSMap.MaxMDS != int(status.PendingActiveCount) {
+		// max_mds was changed in the meantime, lower it again
+		if err := cephclient.SetNumMDSRanks(c.context, c.clusterInfo, fsName, status.PendingActiveCount); err != nil {
+			return errors.Wrapf(err, "failed to lower the number of active mds ranks of filesystem %q to %d", fsName, status.PendingActiveCount)
+		}
+	}
+	if len(fs.MDSMap.Up) > int(status.PendingActiveCount) {
+		status.Details = fmt.Sprintf("waiting for %d mds ranks to stop to scale down to %d active ranks", len(fs.MDSMap.Up)-int(status.PendingActiveCount), status.PendingActiveCount)
+		logger.Infof("filesystem %q: %s", fsName, status.Details)
+		return nil
+	}
+
+	logger.Infof("scaled down filesystem %q to %d active mds ranks", fsName, status.PendingActiveCount)
+	status.ActiveCount = status.PendingActiveCount
+	status.PendingActiveCount = 0
+	status.LastScaleTime = now.Format(time.RFC3339)
+	status.Details = fmt.Sprintf("scaled down to %d active ranks", status.ActiveCount)
+	return nil
+}
+
+// updateStatusMDSAutoscaler updates the mds autoscaler status of the filesystem and returns whether it
+// was updated
+func (c *mdsAutoscalerChecker) updateStatusMDSAutoscaler(fs *cephv1.CephFilesystem, status *cephv1.MDSAutoscalerStatus) bool {
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+	fs.Status.MDSAutoscaler = status
+	if err := reporting.UpdateStatus(c.client, fs); err != nil {
+		logger.Errorf("failed to set ceph filesystem %q mds autoscaler status. %v", c.namespacedName.Name, err)
+		return false
+	}
+	logger.Debugf("ceph filesystem %q mds autoscaler status updated", c.namespacedName.Name)
+	return true
+}
+
+// clearStatusMDSAutoscaler removes the mds autoscaler status of the filesystem once the autoscaler is
+// disabled, the mds are then reconciled with the active count of the spec
+func (r *ReconcileCephFilesystem) clearStatusMDSAutoscaler(cephFilesystem *cephv1.CephFilesystem, namespacedName types.NamespacedName) {
+	spec := cephFilesystem.Spec.MetadataServer
+	if (spec.Autoscaler != nil && spec.Autoscaler.Enabled) || cephFilesystem.Status == nil || cephFilesystem.Status.MDSAutoscaler == nil {
+		return
+	}
+	cephFilesystem.Status.MDSAutoscaler = nil
+
+	fs := &cephv1.CephFilesystem{}
+	if err := r.client.Get(r.opManagerContext, namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph filesystem %q to clear the mds autoscaler status. %v", namespacedName.Name, err)
+		return
+	}
+	if fs.Status == nil || fs.Status.MDSAutoscaler == nil {
+		return
+	}
+	fs.Status.MDSAutoscaler = nil
+	if err := reporting.UpdateStatus(r.client, fs); err != nil {
+		logger.Errorf("failed to clear ceph filesystem %q mds autoscaler status. %v", namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("ceph filesystem %q mds autoscaler status cleared", namespacedName.Name)
+}
@@ -0,0 +1,259 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/event"
+)
+
+func TestDesiredMDSActiveCount(t *testing.T) {
+	now := time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)
+	longAgo := now.Add(-time.Hour)
+	spec := cephv1.MetadataServerSpec{
+		ActiveCount: 2,
+		Autoscaler: &cephv1.MDSAutoscalerSpec{
+			Enabled:                true,
+			MinActiveCount:         1,
+			MaxActiveCount:         3,
+			RequestRateThreshold:   1000,
+			CachePressureThreshold: 80,
+		},
+	}
+
+	t.Run("bounds", func(t *testing.T) {
+		count, _ := desiredMDSActiveCount(spec, 5, nil, now, now)
+		assert.Equal(t, int32(3), count)
+		count, _ = desiredMDSActiveCount(spec, 0, nil, now, now)
+		assert.Equal(t, int32(1), count)
+		count, _ = desiredMDSActiveCount(spec, 2, nil, longAgo, now)
+		assert.Equal(t, int32(2), count)
+	})
+
+	t.Run("scale up", func(t *testing.T) {
+		load := &cephclient.MDSLoad{ActiveCount: 2, RequestRate: 2500, CachePressure: 10}
+		count, _ := desiredMDSActiveCount(spec, 2, load, longAgo, now)
+		assert.Equal(t, int32(3), count)
+
+		// cache pressure
+		load = &cephclient.MDSLoad{ActiveCount: 2, RequestRate: 10, CachePressure: 90}
+		count, _ = desiredMDSActiveCount(spec, 2, load, longAgo, now)
+		assert.Equal(t, int32(3), count)
+
+		// cooldown
+		count, reason := desiredMDSActiveCount(spec, 2, load, now.Add(-time.Minute), now)
+		assert.Equal(t, int32(2), count)
+		assert.Contains(t, reason, "cooldown")
+
+		// maximum
+		load.ActiveCount = 3
+		count, _ = desiredMDSActiveCount(spec, 3, load, longAgo, now)
+		assert.Equal(t, int32(3), count)
+	})
+
+	t.Run("scale down", func(t *testing.T) {
+		load := &cephclient.MDSLoad{ActiveCount: 2, RequestRate: 400, CachePressure: 10}
+		count, _ := desiredMDSActiveCount(spec, 2, load, longAgo, now)
+		assert.Equal(t, int32(1), count)
+
+		// the remaining rank would be too loaded
+		load = &cephclient.MDSLoad{ActiveCount: 2, RequestRate: 600, CachePressure: 10}
+		count, _ = desiredMDSActiveCount(spec, 2, load, longAgo, now)
+		assert.Equal(t, int32(2), count)
+
+		// cooldown
+		load = &cephclient.MDSLoad{ActiveCount: 2, RequestRate: 400, CachePressure: 10}
+		specWithCooldown := *spec.DeepCopy()
+		specWithCooldown.Autoscaler.ScaleDownCooldown = &metav1.Duration{Duration: 2 * time.Hour}
+		count, _ = desiredMDSActiveCount(specWithCooldown, 2, load, longAgo, now)
+		assert.Equal(t, int32(2), count)
+
+		// minimum
+		load.ActiveCount = 1
+		count, _ = desiredMDSActiveCount(spec, 1, load, longAgo, now)
+		assert.Equal(t, int32(1), count)
+	})
+}
+
+func TestValidateMDSAutoscaler(t *testing.T) {
+	spec := cephv1.MetadataServerSpec{ActiveCount: 2}
+	assert.NoError(t, validateMDSAutoscaler(spec))
+
+	spec.Autoscaler = &cephv1.MDSAutoscalerSpec{Enabled: true, MaxActiveCount: 4}
+	assert.NoError(t, validateMDSAutoscaler(spec))
+
+	spec.Autoscaler.MinActiveCount = 5
+	assert.Error(t, validateMDSAutoscaler(spec))
+
+	// the default bounds are the active count
+	spec.Autoscaler = &cephv1.MDSAutoscalerSpec{Enabled: true, MaxActiveCount: 1}
+	assert.Error(t, validateMDSAutoscaler(spec))
+}
+
+func TestActiveMDSCount(t *testing.T) {
+	fs := &cephv1.CephFilesystem{Spec: cephv1.FilesystemSpec{MetadataServer: cephv1.MetadataServerSpec{ActiveCount: 2}}}
+	fs.Status = &cephv1.CephFilesystemStatus{MDSAutoscaler: &cephv1.MDSAutoscalerStatus{ActiveCount: 4}}
+	assert.Equal(t, int32(2), fs.ActiveMDSCount())
+
+	fs.Spec.MetadataServer.Autoscaler = &cephv1.MDSAutoscalerSpec{Enabled: true, MaxActiveCount: 4}
+	assert.Equal(t, int32(4), fs.ActiveMDSCount())
+
+	fs.Status.MDSAutoscaler = nil
+	assert.Equal(t, int32(2), fs.ActiveMDSCount())
+}
+
+func TestMaxMDS(t *testing.T) {
+	fs := &cephv1.CephFilesystem{Spec: cephv1.FilesystemSpec{MetadataServer: cephv1.MetadataServerSpec{ActiveCount: 2}}}
+	assert.Equal(t, int32(2), fs.MaxMDS())
+
+	fs.Spec.MetadataServer.Autoscaler = &cephv1.MDSAutoscalerSpec{Enabled: true, MaxActiveCount: 4}
+	assert.Equal(t, int32(2), fs.MaxMDS())
+
+	fs.Status = &cephv1.CephFilesystemStatus{MDSAutoscaler: &cephv1.MDSAutoscalerStatus{ActiveCount: 3}}
+	assert.Equal(t, int32(3), fs.MaxMDS())
+
+	// max_mds is lowered while the deployments are kept until the ranks are stopped
+	fs.Status.MDSAutoscaler.PendingActiveCount = 2
+	assert.Equal(t, int32(2), fs.MaxMDS())
+	assert.Equal(t, int32(3), fs.ActiveMDSCount())
+}
+
+func TestMDSAutoscalerScaleDown(t *testing.T) {
+	defer func() { getMDSLoad = cephclient.GetMDSLoad }()
+	getMDSLoad = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, fsName string) (*cephclient.MDSLoad, error) {
+		return &cephclient.MDSLoad{ActiveCount: 2, RequestRate: 10, CachePressure: 5}, nil
+	}
+	defer func(getFilesystem func(*clusterd.Context, *cephclient.ClusterInfo, string) (*cephclient.CephFilesystemDetails, error)) {
+		cephclient.GetFilesystem = getFilesystem
+	}(cephclient.GetFilesystem)
+	upRanks := map[string]int{"mds_0": 4100, "mds_1": 4101}
+	cephclient.GetFilesystem = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, fsName string) (*cephclient.CephFilesystemDetails, error) {
+		return &cephclient.CephFilesystemDetails{MDSMap: cephclient.MDSMap{MaxMDS: 1, Up: upRanks}}, nil
+	}
+	var maxMDSSet []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "set" && args[3] == "max_mds" {
+				maxMDSSet = append(maxMDSSet, args[4])
+				return "", nil
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+	}
+
+	fs := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "rook-ceph"},
+		Spec: cephv1.FilesystemSpec{MetadataServer: cephv1.MetadataServerSpec{
+			ActiveCount: 1,
+			Autoscaler:  &cephv1.MDSAutoscalerSpec{Enabled: true, MaxActiveCount: 3},
+		}},
+		Status: &cephv1.CephFilesystemStatus{MDSAutoscaler: &cephv1.MDSAutoscalerStatus{ActiveCount: 2}},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephFilesystem{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(fs.DeepCopy()).WithStatusSubresource(fs).Build()
+	namespacedName := types.NamespacedName{Name: "myfs", Namespace: "rook-ceph"}
+	reconciles := make(chan event.GenericEvent, 1)
+	c := newMDSAutoscalerChecker(&clusterd.Context{Executor: executor}, cl, cephclient.AdminTestClusterInfo("rook-ceph"), namespacedName, reconciles)
+	getFilesystem := func() *cephv1.CephFilesystem {
+		updated := &cephv1.CephFilesystem{}
+		assert.NoError(t, cl.Get(context.TODO(), namespacedName, updated))
+		return updated
+	}
+
+	// max_mds is lowered without waiting for the rank to stop, and the filesystem is not reconciled
+	interval := c.autoscale(context.TODO(), time.Now())
+	assert.Equal(t, mdsScaleDownCheckInterval, interval)
+	assert.Equal(t, []string{"1"}, maxMDSSet)
+	fs = getFilesystem()
+	assert.Equal(t, int32(2), fs.Status.MDSAutoscaler.ActiveCount)
+	assert.Equal(t, int32(1), fs.Status.MDSAutoscaler.PendingActiveCount)
+	assert.Equal(t, int32(1), fs.MaxMDS())
+	assert.Empty(t, reconciles)
+	// the spec is not changed
+	assert.Equal(t, int32(1), fs.Spec.MetadataServer.ActiveCount)
+
+	// the rank is still stopping
+	interval = c.autoscale(context.TODO(), time.Now())
+	assert.Equal(t, mdsScaleDownCheckInterval, interval)
+	fs = getFilesystem()
+	assert.Equal(t, int32(2), fs.ActiveMDSCount())
+	assert.Contains(t, fs.Status.MDSAutoscaler.Details, "waiting for 1 mds ranks to stop")
+	assert.Empty(t, reconciles)
+
+	// the rank is stopped, the filesystem is reconciled to scale down the deployments
+	upRanks = map[string]int{"mds_0": 4100}
+	interval = c.autoscale(context.TODO(), time.Now())
+	assert.Equal(t, mdsAutoscalerInterval, interval)
+	fs = getFilesystem()
+	assert.Equal(t, int32(1), fs.ActiveMDSCount())
+	assert.Equal(t, int32(0), fs.Status.MDSAutoscaler.PendingActiveCount)
+	assert.Equal(t, []string{"1"}, maxMDSSet)
+	assert.Len(t, reconciles, 1)
+	assert.Equal(t, "myfs", (<-reconciles).Object.GetName())
+}
+
+func TestMDSAutoscalerScaleUp(t *testing.T) {
+	defer func() { getMDSLoad = cephclient.GetMDSLoad }()
+	load := &cephclient.MDSLoad{ActiveCount: 1, RequestRate: 10000, CachePressure: 5}
+	getMDSLoad = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, fsName string) (*cephclient.MDSLoad, error) {
+		return load, nil
+	}
+
+	fs := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "rook-ceph"},
+		Spec: cephv1.FilesystemSpec{MetadataServer: cephv1.MetadataServerSpec{
+			ActiveCount: 1,
+			Autoscaler:  &cephv1.MDSAutoscalerSpec{Enabled: true, MaxActiveCount: 3},
+		}},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephFilesystem{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(fs.DeepCopy()).WithStatusSubresource(fs).Build()
+	namespacedName := types.NamespacedName{Name: "myfs", Namespace: "rook-ceph"}
+	reconciles := make(chan event.GenericEvent, 1)
+	c := newMDSAutoscalerChecker(&clusterd.Context{}, cl, cephclient.AdminTestClusterInfo("rook-ceph"), namespacedName, reconciles)
+
+	// the filesystem is reconciled to add the rank
+	interval := c.autoscale(context.TODO(), time.Now())
+	assert.Equal(t, mdsAutoscalerInterval, interval)
+	updated := &cephv1.CephFilesystem{}
+	assert.NoError(t, cl.Get(context.TODO(), namespacedName, updated))
+	assert.Equal(t, int32(2), updated.ActiveMDSCount())
+	assert.Len(t, reconciles, 1)
+	<-reconciles
+
+	// the filesystem is not reconciled while the number of ranks is unchanged
+	load = &cephclient.MDSLoad{ActiveCount: 2, RequestRate: 6000, CachePressure: 5}
+	c.autoscale(context.TODO(), time.Now())
+	assert.NoError(t, cl.Get(context.TODO(), namespacedName, updated))
+	assert.Equal(t, int32(2), updated.ActiveMDSCount())
+	assert.Empty(t, reconciles)
+}
@@ -0,0 +1,203 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package file manages a CephFS filesystem and the required daemons.
+package file
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// maxSubtreePathsPerRank is the maximum number of subtree paths reported in the status for each rank
+const maxSubtreePathsPerRank = 20
+
+type pinningChecker struct {
+	context        *clusterd.Context
+	interval       time.Duration
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	// activeMDS are the names of the mds holding each active rank at the last check
+	activeMDS map[int]string
+}
+
+// newPinningChecker creates a new checker of the filesystem directory pinning
+func newPinningChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName) *pinningChecker {
+	return &pinningChecker{
+		context:        context,
+		interval:       defaultHealthCheckInterval,
+		client:         client,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+	}
+}
+
+// checkPinning periodically re-applies the directory pins after an mds failover and updates the
+// distribution of the subtrees across the mds ranks
+func (c *pinningChecker) checkPinning(context context.Context) {
+	// check the pinning immediately before starting the loop
+	c.updatePinning()
+
+	for {
+		select {
+		case <-context.Done():
+			logger.Infof("stopping monitoring filesystem pinning %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debugf("checking filesystem pinning %q", c.namespacedName.Name)
+			c.updatePinning()
+		}
+	}
+}
+
+func (c *pinningChecker) updatePinning() {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph filesystem %q to update pinning status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if fs.Status == nil || fs.Status.Pinning == nil {
+		return
+	}
+	pinning := fs.Status.Pinning.DeepCopy()
+
+	fsStatus, err := cephclient.GetFilesystemStatus(c.context, c.clusterInfo, fs.Name)
+	if err != nil {
+		logger.Warningf("failed to get the mds status of filesystem %q. %v", fs.Name, err)
+		return
+	}
+	activeMDS := map[int]string{}
+	for _, mds := range fsStatus.MDSMap {
+		if mds.State == "active" {
+			activeMDS[mds.Rank] = mds.Name
+		}
+	}
+	failover := c.activeMDS != nil && !reflect.DeepEqual(activeMDS, c.activeMDS)
+	c.activeMDS = activeMDS
+
+	// the pins are also re-applied until they all succeed
+	if failover || pinning.Details != "" {
+		if failover {
+			logger.Infof("mds failover detected on filesystem %q, re-applying the directory pins", fs.Name)
+		}
+		pinning.PinnedPaths, pinning.Details = applyDirectoryPins(c.context, c.clusterInfo, fs)
+	}
+
+	ranks, err := cephclient.GetFilesystemSubtreeDistribution(c.context, c.clusterInfo, fs.Name, maxSubtreePathsPerRank)
+	if err != nil {
+		logger.Warningf("failed to get the subtree distribution of filesystem %q. %v", fs.Name, err)
+	} else {
+		pinning.Ranks = ranks
+	}
+	pinning.LastChecked = time.Now().UTC().Format(time.RFC3339)
+
+	updateStatusPinning(c.client, c.clusterInfo.Context, c.namespacedName, pinning)
+}
+
+// applyDirectoryPins pins the directories of the spec and unpins the directories previously pinned
+// that are no longer in the spec. It returns the pinned paths and the pinning errors, if any.
+func applyDirectoryPins(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, fs *cephv1.CephFilesystem) ([]string, string) {
+	pinnedPaths := []string{}
+	failures := []string{}
+
+	desired := map[string]bool{}
+	for _, pin := range fs.Spec.Pinning {
+		desired[pin.Path] = true
+		if err := cephclient.PinFilesystemDirectory(context, clusterInfo, fs.Name, pin); err != nil {
+			logger.Warningf("%v", err)
+			failures = append(failures, err.Error())
+		}
+		// a pin that failed may have been applied previously, it is kept so it can be removed later
+		pinnedPaths = append(pinnedPaths, pin.Path)
+	}
+
+	if fs.Status != nil && fs.Status.Pinning != nil {
+		for _, path := range fs.Status.Pinning.PinnedPaths {
+			if desired[path] {
+				continue
+			}
+			if err := cephclient.UnpinFilesystemDirectory(context, clusterInfo, fs.Name, path); err != nil {
+				logger.Warningf("%v", err)
+				failures = append(failures, err.Error())
+				pinnedPaths = append(pinnedPaths, path)
+			}
+		}
+	}
+
+	return pinnedPaths, strings.Join(failures, "; ")
+}
+
+// reconcilePinning applies the directory pins of the filesystem. A directory that cannot be pinned,
+// typically because it does not exist yet, does not fail the reconcile and is retried by the
+// pinning checker.
+func (r *ReconcileCephFilesystem) reconcilePinning(cephFilesystem *cephv1.CephFilesystem, namespacedName types.NamespacedName) {
+	var pinning *cephv1.FilesystemPinningStatus
+	if cephFilesystem.Status != nil && cephFilesystem.Status.Pinning != nil {
+		pinning = cephFilesystem.Status.Pinning.DeepCopy()
+	}
+	if len(cephFilesystem.Spec.Pinning) == 0 && pinning == nil {
+		return
+	}
+	if pinning == nil {
+		pinning = &cephv1.FilesystemPinningStatus{}
+	}
+
+	pinning.PinnedPaths, pinning.Details = applyDirectoryPins(r.context, r.clusterInfo, cephFilesystem)
+	if len(pinning.PinnedPaths) == 0 && pinning.Details == "" {
+		// nothing is pinned anymore
+		pinning = nil
+	}
+	updateStatusPinning(r.client, r.opManagerContext, namespacedName, pinning)
+}
+
+// updateStatusPinning updates the pinning status of the filesystem
+func updateStatusPinning(c client.Client, ctx context.Context, namespacedName types.NamespacedName, pinning *cephv1.FilesystemPinningStatus) {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.Get(ctx, namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph filesystem %q to update pinning status. %v", namespacedName.Name, err)
+		return
+	}
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+
+	fs.Status.Pinning = pinning
+	if err := reporting.UpdateStatus(c, fs); err != nil {
+		logger.Errorf("failed to set ceph filesystem %q pinning status. %v", namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("ceph filesystem %q pinning status updated", namespacedName.Name)
+}
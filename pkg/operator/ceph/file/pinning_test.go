@@ -0,0 +1,62 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestApplyDirectoryPins(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			// skip the script, the filesystem and the standard flags
+			if args[3] == "/missing" {
+				return "", errors.New("No such file or directory")
+			}
+			commands = append(commands, strings.Join(args[3:7], " "))
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	export := 1
+
+	fs := &cephv1.CephFilesystem{}
+	fs.Name = "myfs"
+	fs.Spec.Pinning = []cephv1.FilesystemDirectoryPinningSpec{
+		{Path: "/volumes", Export: &export},
+		{Path: "/missing", Export: &export},
+	}
+	fs.Status = &cephv1.CephFilesystemStatus{Pinning: &cephv1.FilesystemPinningStatus{PinnedPaths: []string{"/volumes", "/old"}}}
+
+	pinnedPaths, details := applyDirectoryPins(context, cephclient.AdminTestClusterInfo("mycluster"), fs)
+	assert.Equal(t, []string{"/volumes", "/missing"}, pinnedPaths)
+	assert.Contains(t, details, "/missing")
+	assert.Equal(t, []string{
+		"/volumes ceph.dir.pin=1 ceph.dir.pin.distributed=0 ceph.dir.pin.random=0",
+		"/old ceph.dir.pin=-1 ceph.dir.pin.distributed=0 ceph.dir.pin.random=0",
+	}, commands)
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package file manages a CephFS filesystem and the required daemons.
+package file
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	corev1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxReportedDamages is the maximum number of damages reported in the status
+	maxReportedDamages = 20
+
+	scrubStartedReason      = "ScrubStarted"
+	scrubCompletedReason    = "ScrubCompleted"
+	scrubFailedReason       = "ScrubFailed"
+	filesystemDamagedReason = "FilesystemDamaged"
+)
+
+type scrubChecker struct {
+	context        *clusterd.Context
+	interval       time.Duration
+	client         client.Client
+	recorder       record.EventRecorder
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+}
+
+// newScrubChecker creates a new checker of the filesystem scrubs and damages
+func newScrubChecker(context *clusterd.Context, client client.Client, recorder record.EventRecorder, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName) *scrubChecker {
+	return &scrubChecker{
+		context:        context,
+		interval:       defaultHealthCheckInterval,
+		client:         client,
+		recorder:       recorder,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+	}
+}
+
+// checkScrub periodically starts the scheduled scrubs and reports the scrub status and the damages
+func (c *scrubChecker) checkScrub(context context.Context) {
+	// check the scrubs immediately before starting the loop
+	c.updateScrub(time.Now().UTC())
+
+	for {
+		select {
+		case <-context.Done():
+			logger.Infof("stopping monitoring filesystem scrubs %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debugf("checking filesystem scrubs %q", c.namespacedName.Name)
+			c.updateScrub(time.Now().UTC())
+		}
+	}
+}
+
+func (c *scrubChecker) updateScrub(now time.Time) {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph filesystem %q to update scrub status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+	if fs.Spec.Scrub == nil {
+		if fs.Status.Scrub != nil {
+			c.updateStatusScrub(fs, nil)
+		}
+		return
+	}
+
+	status := &cephv1.FilesystemScrubStatus{}
+	if fs.Status.Scrub != nil {
+		status = fs.Status.Scrub.DeepCopy()
+	}
+	status.Details = ""
+	status.LastChecked = now.Format(time.RFC3339)
+	c.checkScrubStatus(fs, status, now)
+	c.checkDamages(fs, status)
+	c.updateStatusScrub(fs, status)
+}
+
+// checkScrubStatus updates the scrub status and starts the scrub if it is due
+func (c *scrubChecker) checkScrubStatus(fs *cephv1.CephFilesystem, status *cephv1.FilesystemScrubStatus, now time.Time) {
+	scrubStatus, err := cephclient.GetFilesystemScrubStatus(c.context, c.clusterInfo, fs.Name)
+	if err != nil {
+		logger.Warningf("%v", err)
+		status.Details = err.Error()
+		return
+	}
+	wasActive := status.Active
+	status.Active = len(scrubStatus.Scrubs) > 0
+	status.State = scrubStatus.Status
+	if wasActive && !status.Active {
+		c.recorder.Event(fs, corev1.EventTypeNormal, scrubCompletedReason, fmt.Sprintf("scrub of filesystem %q completed", fs.Name))
+	}
+
+	interval, err := scrubInterval(fs.Spec.Scrub.Schedule)
+	if err != nil {
+		status.Details = err.Error()
+		return
+	}
+	status.NextScrub = ""
+	if interval == 0 {
+		return
+	}
+	next := nextScrubTime(status.LastScrubStart, interval, now)
+	if !status.Active && !next.After(now) {
+		path := fs.Spec.Scrub.Path
+		if path == "" {
+			path = "/"
+		}
+		tag, err := cephclient.StartFilesystemScrub(c.context, c.clusterInfo, fs.Name, path, fs.Spec.Scrub.Recursive, fs.Spec.Scrub.Repair)
+		if err != nil {
+			logger.Warningf("%v", err)
+			status.Details = err.Error()
+			c.recorder.Event(fs, corev1.EventTypeWarning, scrubFailedReason, err.Error())
+			return
+		}
+		c.recorder.Event(fs, corev1.EventTypeNormal, scrubStartedReason, fmt.Sprintf("started scrub %q of %q on filesystem %q", tag, path, fs.Name))
+		status.Active = true
+		status.LastScrubTag = tag
+		status.LastScrubStart = now.Format(time.RFC3339)
+		next = now.Add(interval)
+	}
+	status.NextScrub = next.Format(time.RFC3339)
+}
+
+// checkDamages updates the damages of the active mds ranks and emits an event when new damages are found
+func (c *scrubChecker) checkDamages(fs *cephv1.CephFilesystem, status *cephv1.FilesystemScrubStatus) {
+	fsStatus, err := cephclient.GetFilesystemStatus(c.context, c.clusterInfo, fs.Name)
+	if err != nil {
+		logger.Warningf("%v", err)
+		status.Details = appendDetails(status.Details, err.Error())
+		return
+	}
+
+	damages := []cephv1.FilesystemDamage{}
+	for _, mds := range fsStatus.MDSMap {
+		if mds.State != "active" {
+			continue
+		}
+		rankDamages, err := cephclient.ListFilesystemDamage(c.context, c.clusterInfo, fs.Name, mds.Rank)
+		if err != nil {
+			logger.Warningf("%v", err)
+			status.Details = appendDetails(status.Details, err.Error())
+			return
+		}
+		for _, damage := range rankDamages {
+			damages = append(damages, cephv1.FilesystemDamage{
+				Rank:       mds.Rank,
+				ID:         damage.ID.String(),
+				DamageType: damage.DamageType,
+				Ino:        damage.Ino,
+				Path:       damage.Path,
+			})
+		}
+	}
+
+	if len(damages) > status.DamageCount {
+		c.recorder.Event(fs, corev1.EventTypeWarning, filesystemDamagedReason,
+			fmt.Sprintf("filesystem %q has %d damaged metadata entries, see the damages in the filesystem status", fs.Name, len(damages)))
+	}
+	status.DamageCount = len(damages)
+	if len(damages) > maxReportedDamages {
+		damages = damages[:maxReportedDamages]
+	}
+	status.Damages = damages
+}
+
+func (c *scrubChecker) updateStatusScrub(fs *cephv1.CephFilesystem, status *cephv1.FilesystemScrubStatus) {
+	fs.Status.Scrub = status
+	if err := reporting.UpdateStatus(c.client, fs); err != nil {
+		logger.Errorf("failed to set ceph filesystem %q scrub status. %v", c.namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("ceph filesystem %q scrub status updated", c.namespacedName.Name)
+}
+
+func appendDetails(details, detail string) string {
+	if details == "" {
+		return detail
+	}
+	return details + "; " + detail
+}
+
+// scrubInterval returns the interval of a scrub schedule, or zero if no scrub is scheduled
+func scrubInterval(schedule string) (time.Duration, error) {
+	if schedule == "" {
+		return 0, nil
+	}
+	count, err := strconv.Atoi(schedule[:len(schedule)-1])
+	if err != nil || count <= 0 {
+		return 0, errors.Errorf("invalid scrub schedule %q", schedule)
+	}
+	switch schedule[len(schedule)-1] {
+	case 'h':
+		return time.Duration(count) * time.Hour, nil
+	case 'd':
+		return time.Duration(count) * 24 * time.Hour, nil
+	case 'w':
+		return time.Duration(count) * 7 * 24 * time.Hour, nil
+	}
+	return 0, errors.Errorf("invalid scrub schedule %q", schedule)
+}
+
+// nextScrubTime returns the time of the next scrub, the scrub is due immediately if none was started yet
+func nextScrubTime(lastScrubStart string, interval time.Duration, now time.Time) time.Time {
+	last, err := time.Parse(time.RFC3339, lastScrubStart)
+	if err != nil {
+		return now
+	}
+	return last.Add(interval)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestScrubInterval(t *testing.T) {
+	interval, err := scrubInterval("")
+	assert.NoError(t, err)
+	assert.Equal(t, time.Duration(0), interval)
+
+	interval, err = scrubInterval("12h")
+	assert.NoError(t, err)
+	assert.Equal(t, 12*time.Hour, interval)
+
+	interval, err = scrubInterval("2w")
+	assert.NoError(t, err)
+	assert.Equal(t, 14*24*time.Hour, interval)
+
+	_, err = scrubInterval("1y")
+	assert.Error(t, err)
+}
+
+func TestUpdateScrub(t *testing.T) {
+	scrubStarted := 0
+	scrubActive := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "status" {
+				return `{"mdsmap": [{"name": "myfs-a", "rank": 0, "rate": 0, "state": "active"}]}`, nil
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			if args[0] == "tell" && args[2] == "scrub" && args[3] == "start" {
+				scrubStarted++
+				return `{"return_code": 0, "scrub_tag": "mytag", "mode": "asynchronous"}`, nil
+			}
+			if args[0] == "tell" && args[2] == "scrub" && args[3] == "status" {
+				if scrubActive {
+					return `{"status": "scrub active", "scrubs": {"mytag": {"path": "/", "tag": "mytag", "options": "recursive"}}}`, nil
+				}
+				return `{"status": "no active scrubs running", "scrubs": {}}`, nil
+			}
+			if args[0] == "tell" && args[2] == "damage" {
+				return `[{"damage_type": "dir_frag", "id": 1, "ino": 1099511627776}]`, nil
+			}
+			return "", errors.Errorf("unknown command %v", args)
+		},
+	}
+
+	fs := &cephv1.CephFilesystem{
+		ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "rook-ceph"},
+		Spec:       cephv1.FilesystemSpec{Scrub: &cephv1.FilesystemScrubSpec{Schedule: "1d", Recursive: true}},
+	}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephFilesystem{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(fs).Build()
+	recorder := record.NewFakeRecorder(10)
+	clusterInfo := cephclient.AdminTestClusterInfo("rook-ceph")
+	namespacedName := types.NamespacedName{Name: "myfs", Namespace: "rook-ceph"}
+	checker := newScrubChecker(&clusterd.Context{Executor: executor}, cl, recorder, clusterInfo, namespacedName)
+	now := time.Date(2024, 1, 2, 10, 30, 0, 0, time.UTC)
+
+	getStatus := func() *cephv1.FilesystemScrubStatus {
+		updated := &cephv1.CephFilesystem{}
+		assert.NoError(t, cl.Get(context.TODO(), namespacedName, updated))
+		return updated.Status.Scrub
+	}
+
+	// the first scrub is started immediately
+	checker.updateScrub(now)
+	status := getStatus()
+	assert.Equal(t, 1, scrubStarted)
+	assert.True(t, status.Active)
+	assert.Equal(t, "mytag", status.LastScrubTag)
+	assert.Equal(t, "2024-01-02T10:30:00Z", status.LastScrubStart)
+	assert.Equal(t, "2024-01-03T10:30:00Z", status.NextScrub)
+	assert.Equal(t, 1, status.DamageCount)
+	assert.Equal(t, "1", status.Damages[0].ID)
+	assert.Len(t, recorder.Events, 2)
+
+	// the scrub completes, the next one is not due yet
+	checker.updateScrub(now.Add(time.Hour))
+	status = getStatus()
+	assert.Equal(t, 1, scrubStarted)
+	assert.False(t, status.Active)
+	assert.Len(t, recorder.Events, 3)
+
+	// a scrub is running, the next one is not started
+	scrubActive = true
+	checker.updateScrub(now.Add(25 * time.Hour))
+	assert.Equal(t, 1, scrubStarted)
+
+	scrubActive = false
+	checker.updateScrub(now.Add(26 * time.Hour))
+	assert.Equal(t, 2, scrubStarted)
+	assert.Equal(t, "2024-01-03T12:30:00Z", getStatus().LastScrubStart)
+}
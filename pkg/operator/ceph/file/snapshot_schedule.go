@@ -0,0 +1,172 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package file manages a CephFS filesystem and the required daemons.
+package file
+
+import (
+	"context"
+	"reflect"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type snapshotScheduleChecker struct {
+	context        *clusterd.Context
+	interval       time.Duration
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+}
+
+// newSnapshotScheduleChecker creates a new checker of the filesystem snapshot schedules
+func newSnapshotScheduleChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName) *snapshotScheduleChecker {
+	return &snapshotScheduleChecker{
+		context:        context,
+		interval:       defaultHealthCheckInterval,
+		client:         client,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+	}
+}
+
+// checkSnapshotSchedules periodically updates the status of the filesystem snapshot schedules
+func (c *snapshotScheduleChecker) checkSnapshotSchedules(context context.Context) {
+	// check the snapshot schedules immediately before starting the loop
+	c.updateStatusSnapshotSchedules()
+
+	for {
+		select {
+		case <-context.Done():
+			logger.Infof("stopping monitoring filesystem snapshot schedules %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debugf("checking filesystem snapshot schedules %q", c.namespacedName.Name)
+			c.updateStatusSnapshotSchedules()
+		}
+	}
+}
+
+// updateStatusSnapshotSchedules updates the snapshot schedules status of the filesystem from its latest spec
+func (c *snapshotScheduleChecker) updateStatusSnapshotSchedules() {
+	fs := &cephv1.CephFilesystem{}
+	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph filesystem %q to update snapshot schedules status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+	if len(fs.Spec.SnapshotSchedules) == 0 && len(fs.Status.SnapshotSchedules) == 0 {
+		return
+	}
+
+	fs.Status.SnapshotSchedules = nil
+	if len(fs.Spec.SnapshotSchedules) > 0 {
+		fs.Status.SnapshotSchedules = cephclient.GetSnapshotSchedulesStatus(c.context, c.clusterInfo, fs.Name, "/", fs.Spec.SnapshotSchedules, time.Now())
+	}
+	if err := reporting.UpdateStatus(c.client, fs); err != nil {
+		logger.Errorf("failed to set ceph filesystem %q snapshot schedules status. %v", c.namespacedName.Name, err)
+		return
+	}
+
+	logger.Debugf("ceph filesystem %q snapshot schedules status updated", c.namespacedName.Name)
+}
+
+// reconcileSnapshotSchedules applies the snapshot schedules of the filesystem and removes the schedules
+// applied before that are no longer in the spec. The schedules applied are recorded in the status so that
+// the schedules configured outside of the spec are never removed, such as the schedules added manually or
+// the schedules of the subvolumes.
+func (r *ReconcileCephFilesystem) reconcileSnapshotSchedules(cephFilesystem *cephv1.CephFilesystem) error {
+	fs := &cephv1.CephFilesystem{}
+	if err := r.client.Get(r.opManagerContext, types.NamespacedName{Namespace: cephFilesystem.Namespace, Name: cephFilesystem.Name}, fs); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephFilesystem resource not found. Ignoring since object must be deleted.")
+			return nil
+		}
+		return errors.Wrapf(err, "failed to retrieve ceph filesystem %q to configure the snapshot schedules", cephFilesystem.Name)
+	}
+	if fs.Status == nil {
+		fs.Status = &cephv1.CephFilesystemStatus{}
+	}
+	applied := fs.Status.AppliedSnapshotSchedules
+	desired := cephFilesystem.Spec.SnapshotSchedules
+	if len(applied) == 0 && len(desired) == 0 {
+		// the snapshot schedules are not managed by the spec
+		return nil
+	}
+
+	// the schedules are recorded before they are applied so that they are removed later even if the
+	// status update fails after they are applied
+	if err := r.updateAppliedSnapshotSchedules(fs, cephclient.MergeSnapshotSchedules(applied, desired)); err != nil {
+		return err
+	}
+
+	err := cephclient.ApplySnapshotSchedules(r.context, r.clusterInfo, cephFilesystem.Name, "/", desired)
+	if err != nil {
+		return errors.Wrapf(err, "failed to add snapshot schedules on filesystem %q", cephFilesystem.Name)
+	}
+
+	// the schedules of a mirrored filesystem must not be removed
+	kept := append([]cephv1.FilesystemSnapshotScheduleSpec{}, desired...)
+	if cephFilesystem.Spec.Mirroring != nil && cephFilesystem.Spec.Mirroring.Enabled {
+		mirroredRetentions := map[string]string{}
+		for _, retention := range cephFilesystem.Spec.Mirroring.SnapshotRetention {
+			mirroredRetentions[cephclient.SnapshotSchedulePath("/", retention.Path)] = retention.Duration
+		}
+		for _, schedule := range cephFilesystem.Spec.Mirroring.SnapshotSchedules {
+			kept = append(kept, cephv1.FilesystemSnapshotScheduleSpec{
+				Path:      schedule.Path,
+				Interval:  schedule.Interval,
+				Retention: mirroredRetentions[cephclient.SnapshotSchedulePath("/", schedule.Path)],
+			})
+		}
+	}
+
+	err = cephclient.PruneSnapshotSchedules(r.context, r.clusterInfo, cephFilesystem.Name, "/", applied, kept)
+	if err != nil {
+		return errors.Wrapf(err, "failed to remove snapshot schedules on filesystem %q", cephFilesystem.Name)
+	}
+
+	return r.updateAppliedSnapshotSchedules(fs, desired)
+}
+
+func (r *ReconcileCephFilesystem) updateAppliedSnapshotSchedules(fs *cephv1.CephFilesystem, schedules []cephv1.FilesystemSnapshotScheduleSpec) error {
+	if len(schedules) == 0 {
+		schedules = nil
+	}
+	if reflect.DeepEqual(fs.Status.AppliedSnapshotSchedules, schedules) {
+		return nil
+	}
+	fs.Status.AppliedSnapshotSchedules = schedules
+	if err := reporting.UpdateStatus(r.client, fs); err != nil {
+		return errors.Wrapf(err, "failed to update filesystem %q applied snapshot schedules status", fs.Name)
+	}
+	return nil
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package file
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileSnapshotSchedules(t *testing.T) {
+	statusOutputs := map[string]string{
+		"/":     `[{"path": "/", "schedule": "1d", "retention": {"d": 7}}, {"path": "/", "schedule": "1h", "retention": {"d": 7}}]`,
+		"/data": `[{"path": "/data", "schedule": "6h", "retention": {"h": 24}}]`,
+	}
+	var commands []string
+	mockCommand := func(args ...string) (string, error) {
+		if args[0] == "fs" && args[1] == "snap-schedule" && args[2] == "status" {
+			return statusOutputs[args[3]], nil
+		}
+		// only record the command without the connection flags
+		for i, arg := range args {
+			if strings.HasPrefix(arg, "--") {
+				args = args[:i]
+				break
+			}
+		}
+		commands = append(commands, strings.Join(args, " "))
+		return "", nil
+	}
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithTimeout: func(timeout time.Duration, command string, args ...string) (string, error) {
+			return mockCommand(args...)
+		},
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			return mockCommand(args...)
+		},
+	}
+
+	fs := &cephv1.CephFilesystem{ObjectMeta: metav1.ObjectMeta{Name: "myfs", Namespace: "rook-ceph"}}
+	// the schedules applied before, the schedules added manually are not in the status
+	fs.Status = &cephv1.CephFilesystemStatus{AppliedSnapshotSchedules: []cephv1.FilesystemSnapshotScheduleSpec{
+		{Path: "/", Interval: "1d", Retention: "7d"},
+		{Path: "/", Interval: "1h"},
+		{Path: "/data", Interval: "6h", Retention: "24h"},
+		{Path: "/mirrored", Interval: "1h"},
+	}}
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephFilesystem{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithRuntimeObjects(fs.DeepCopy()).WithStatusSubresource(fs).Build()
+	r := &ReconcileCephFilesystem{
+		client:           cl,
+		context:          &clusterd.Context{Executor: executor, Client: cl},
+		clusterInfo:      cephclient.AdminTestClusterInfo("rook-ceph"),
+		opManagerContext: context.TODO(),
+	}
+	getApplied := func() []cephv1.FilesystemSnapshotScheduleSpec {
+		current := &cephv1.CephFilesystem{}
+		assert.NoError(t, cl.Get(context.TODO(), types.NamespacedName{Namespace: "rook-ceph", Name: "myfs"}, current))
+		return current.Status.AppliedSnapshotSchedules
+	}
+
+	fs.Spec.SnapshotSchedules = []cephv1.FilesystemSnapshotScheduleSpec{{Path: "/", Interval: "1d", Retention: "7d"}, {Path: "/data", Interval: "6h"}}
+	fs.Spec.Mirroring = &cephv1.FSMirroringSpec{Enabled: true, SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Path: "/mirrored", Interval: "1h"}}}
+
+	err := r.reconcileSnapshotSchedules(fs)
+	assert.NoError(t, err)
+	assert.Contains(t, commands, "fs snap-schedule add / 1d fs=myfs")
+	assert.Contains(t, commands, "fs snap-schedule add /data 6h fs=myfs")
+	// the applied schedule removed from the spec is removed
+	assert.Contains(t, commands, "fs snap-schedule remove / 1h fs=myfs")
+	assert.NotContains(t, commands, "fs snap-schedule remove / 1d fs=myfs")
+	// the applied retention removed from the spec is removed
+	assert.Contains(t, commands, "fs snap-schedule retention remove /data 24h fs=myfs")
+	assert.NotContains(t, commands, "fs snap-schedule retention remove / 7d fs=myfs")
+	// the mirroring schedule is kept
+	assert.NotContains(t, commands, "fs snap-schedule remove /mirrored 1h fs=myfs")
+	assert.Equal(t, fs.Spec.SnapshotSchedules, getApplied())
+
+	t.Run("schedules removed from the spec", func(t *testing.T) {
+		commands = nil
+		fs.Spec.SnapshotSchedules = nil
+		fs.Spec.Mirroring = nil
+		err := r.reconcileSnapshotSchedules(fs)
+		assert.NoError(t, err)
+		assert.Contains(t, commands, "fs snap-schedule remove / 1d fs=myfs")
+		assert.Contains(t, commands, "fs snap-schedule retention remove / 7d fs=myfs")
+		assert.Contains(t, commands, "fs snap-schedule remove /data 6h fs=myfs")
+		assert.Nil(t, getApplied())
+	})
+
+	t.Run("schedules never managed by the spec", func(t *testing.T) {
+		commands = nil
+		err := r.reconcileSnapshotSchedules(fs)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+	})
+}
@@ -113,5 +113,5 @@ func toCustomResourceStatus(currentStatus *cephv1.CephFilesystemStatus, mirrorSt
 	// Always display the details, typically an error
 	mirrorSnapScheduleStatusSpec.Details = details
 
-	return &cephv1.CephFilesystemStatus{MirroringStatus: mirrorStatusSpec, SnapshotScheduleStatus: mirrorSnapScheduleStatusSpec, Phase: currentStatus.Phase, Info: currentStatus.Info}
+	return &cephv1.CephFilesystemStatus{MirroringStatus: mirrorStatusSpec, SnapshotScheduleStatus: mirrorSnapScheduleStatusSpec, Phase: currentStatus.Phase, Info: currentStatus.Info, SnapshotSchedules: currentStatus.SnapshotSchedules, AppliedSnapshotSchedules: currentStatus.AppliedSnapshotSchedules, MDSAutoscaler: currentStatus.MDSAutoscaler, Pinning: currentStatus.Pinning, Scrub: currentStatus.Scrub}
 }
@@ -21,12 +21,14 @@ import (
 	"context"
 	"fmt"
 	"reflect"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
 
 	"github.com/pkg/errors"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util"
 	"github.com/rook/rook/pkg/util/exec"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	"sigs.k8s.io/controller-runtime/pkg/controller"
@@ -42,6 +44,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/csi"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/tracing"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
@@ -52,6 +55,8 @@ import (
 
 const (
 	controllerName = "ceph-fs-subvolumegroup-controller"
+	// statusRefreshInterval is how often the usage and the status of the snapshot schedules are refreshed
+	statusRefreshInterval = 5 * time.Minute
 )
 
 var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
@@ -93,7 +98,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -114,7 +119,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileCephFilesystemSubVolumeGroup) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
-	reconcileResponse, err := r.reconcile(request)
+	reconcileResponse, err := r.reconcile(context, request)
 	if err != nil {
 		logger.Errorf("failed to reconcile %q. %v", request.NamespacedName, err)
 	}
@@ -122,7 +127,8 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) Reconcile(context context.Contex
 	return reconcileResponse, err
 }
 
-func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Request) (reconcile.Result, error) {
+func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := util.NewContextLogger(ctx, logger)
 	namespacedName := request.NamespacedName
 	// Fetch the CephFilesystemSubVolumeGroup instance
 	cephFilesystemSubVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{}
@@ -148,7 +154,7 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Requ
 
 	// The CR was just created, initializing status fields
 	if cephFilesystemSubVolumeGroup.Status == nil {
-		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, cephv1.ConditionProgressing)
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, cephv1.ConditionProgressing, nil, nil)
 	}
 
 	// Make sure a CephCluster is present otherwise do nothing
@@ -174,7 +180,7 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Requ
 	}
 
 	// Populate clusterInfo during each reconcile
-	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, tracing.ContextWithSpan(r.opManagerContext, ctx), request.NamespacedName.Namespace, &cephCluster.Spec)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
 	}
@@ -218,7 +224,7 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Requ
 		if err != nil {
 			return reconcile.Result{}, errors.Wrap(err, "failed to save cluster config")
 		}
-		r.updateStatus(observedGeneration, namespacedName, cephv1.ConditionReady)
+		r.updateStatus(observedGeneration, namespacedName, cephv1.ConditionReady, nil, nil)
 		return reconcile.Result{}, nil
 	}
 	// Build the NamespacedName to fetch the Filesystem and make sure it exists, if not we cannot
@@ -249,7 +255,7 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Requ
 			logger.Info(opcontroller.OperatorNotInitializedMessage)
 			return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
 		}
-		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, cephv1.ConditionFailure)
+		r.updateStatus(k8sutil.ObservedGenerationNotAvailable, request.NamespacedName, cephv1.ConditionFailure, nil, nil)
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create or update ceph filesystem subvolume group %q", cephFilesystemSubVolumeGroup.Name)
 	}
 
@@ -263,10 +269,21 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) reconcile(request reconcile.Requ
 		return reconcile.Result{}, errors.Wrapf(err, "failed to pin filesystem subvolume group %q", cephFilesystemSubVolumeGroup.Name)
 	}
 
-	r.updateStatus(observedGeneration, request.NamespacedName, cephv1.ConditionReady)
-	// Return and do not requeue
+	snapshotSchedules, err := r.reconcileSnapshotSchedules(cephFilesystemSubVolumeGroup)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrapf(err, "failed to configure snapshot schedules of filesystem subvolume group %q", cephFilesystemSubVolumeGroup.Name)
+	}
+
+	usage, err := r.getSubVolumeGroupUsage(cephFilesystemSubVolumeGroup)
+	if err != nil {
+		// Not fatal, the usage will be refreshed on the next reconcile
+		logger.Warningf("failed to get usage of filesystem subvolume group %q. %v", cephFilesystemSubVolumeGroup.Name, err)
+	}
+
+	r.updateStatus(observedGeneration, request.NamespacedName, cephv1.ConditionReady, usage, snapshotSchedules)
 	logger.Debugf("done reconciling cephFilesystemSubVolumeGroup %q", namespacedName)
-	return reconcile.Result{}, nil
+	// Requeue to refresh the usage and the last and next snapshot times in the status
+	return reconcile.Result{RequeueAfter: statusRefreshInterval}, nil
 }
 
 func getSubvolumeGroupName(cephFilesystemSubVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) string {
@@ -317,14 +334,128 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) updateClusterConfig(cephFilesyst
 func (r *ReconcileCephFilesystemSubVolumeGroup) createOrUpdateSubVolumeGroup(cephFilesystemSubVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) error {
 	logger.Infof("creating ceph filesystem subvolume group %s in namespace %s", cephFilesystemSubVolumeGroup.Name, cephFilesystemSubVolumeGroup.Namespace)
 
-	err := cephclient.CreateCephFSSubVolumeGroup(r.context, r.clusterInfo, cephFilesystemSubVolumeGroup.Spec.FilesystemName, getSubvolumeGroupName(cephFilesystemSubVolumeGroup))
+	fsName := cephFilesystemSubVolumeGroup.Spec.FilesystemName
+	groupName := getSubvolumeGroupName(cephFilesystemSubVolumeGroup)
+	err := cephclient.CreateCephFSSubVolumeGroup(r.context, r.clusterInfo, fsName, groupName, &cephFilesystemSubVolumeGroup.Spec)
 	if err != nil {
 		return errors.Wrapf(err, "failed to create ceph filesystem subvolume group %q", cephFilesystemSubVolumeGroup.Name)
 	}
 
+	// Creating an existing subvolume group is a no-op, so the quota must be applied with a resize
+	info, err := cephclient.GetCephFSSubVolumeGroupInfo(r.context, r.clusterInfo, fsName, groupName)
+	if err != nil {
+		return errors.Wrapf(err, "failed to get ceph filesystem subvolume group %q info", cephFilesystemSubVolumeGroup.Name)
+	}
+	currentQuota, limited := info.Quota()
+	desiredSize := ""
+	if cephFilesystemSubVolumeGroup.Spec.Quota != nil {
+		desiredQuota := cephFilesystemSubVolumeGroup.Spec.Quota.Value()
+		if !limited || currentQuota != uint64(desiredQuota) {
+			desiredSize = strconv.FormatInt(desiredQuota, 10)
+		}
+	} else if limited {
+		// The quota was removed from the spec
+		desiredSize = cephclient.SubVolumeGroupUnlimitedSize
+	}
+	if desiredSize != "" {
+		err = cephclient.ResizeCephFSSubVolumeGroup(r.context, r.clusterInfo, fsName, groupName, desiredSize)
+		if err != nil {
+			return errors.Wrapf(err, "failed to resize ceph filesystem subvolume group %q", cephFilesystemSubVolumeGroup.Name)
+		}
+	}
+
 	return nil
 }
 
+// reconcileSnapshotSchedules applies the snapshot schedules of the subvolume group, removes the schedules
+// applied before that are no longer in the spec and returns the status of the schedules. The schedules
+// applied are recorded in the status so that the schedules configured outside of the spec are never
+// removed, such as the schedules of the subvolumes.
+func (r *ReconcileCephFilesystemSubVolumeGroup) reconcileSnapshotSchedules(cephFilesystemSubVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) ([]cephv1.FilesystemSnapshotScheduleStatus, error) {
+	svg := &cephv1.CephFilesystemSubVolumeGroup{}
+	namespacedName := types.NamespacedName{Namespace: cephFilesystemSubVolumeGroup.Namespace, Name: cephFilesystemSubVolumeGroup.Name}
+	if err := r.client.Get(r.opManagerContext, namespacedName, svg); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("CephFilesystemSubVolumeGroup %q not found. Ignoring since object must be deleted.", namespacedName)
+			return nil, nil
+		}
+		return nil, errors.Wrapf(err, "failed to retrieve ceph filesystem subvolume group %q to configure the snapshot schedules", namespacedName)
+	}
+	if svg.Status == nil {
+		svg.Status = &cephv1.CephFilesystemSubVolumeGroupStatus{}
+	}
+	applied := svg.Status.AppliedSnapshotSchedules
+	desired := cephFilesystemSubVolumeGroup.Spec.SnapshotSchedules
+	if len(applied) == 0 && len(desired) == 0 {
+		// the snapshot schedules are not managed by the spec
+		return nil, nil
+	}
+
+	fsName := cephFilesystemSubVolumeGroup.Spec.FilesystemName
+	groupPath, err := cephclient.GetCephFSSubVolumeGroupPath(r.context, r.clusterInfo, fsName, getSubvolumeGroupName(cephFilesystemSubVolumeGroup))
+	if err != nil {
+		return nil, err
+	}
+
+	// the schedules are recorded before they are applied so that they are removed later even if the
+	// status update fails after they are applied
+	if err := r.updateAppliedSnapshotSchedules(svg, cephclient.MergeSnapshotSchedules(applied, desired)); err != nil {
+		return nil, err
+	}
+
+	err = cephclient.ApplySnapshotSchedules(r.context, r.clusterInfo, fsName, groupPath, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	err = cephclient.PruneSnapshotSchedules(r.context, r.clusterInfo, fsName, groupPath, applied, desired)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := r.updateAppliedSnapshotSchedules(svg, desired); err != nil {
+		return nil, err
+	}
+	if len(desired) == 0 {
+		// clear the status of the removed schedules
+		return []cephv1.FilesystemSnapshotScheduleStatus{}, nil
+	}
+
+	return cephclient.GetSnapshotSchedulesStatus(r.context, r.clusterInfo, fsName, groupPath, desired, time.Now()), nil
+}
+
+func (r *ReconcileCephFilesystemSubVolumeGroup) updateAppliedSnapshotSchedules(svg *cephv1.CephFilesystemSubVolumeGroup, schedules []cephv1.FilesystemSnapshotScheduleSpec) error {
+	if len(schedules) == 0 {
+		schedules = nil
+	}
+	if reflect.DeepEqual(svg.Status.AppliedSnapshotSchedules, schedules) {
+		return nil
+	}
+	svg.Status.AppliedSnapshotSchedules = schedules
+	if err := reporting.UpdateStatus(r.client, svg); err != nil {
+		return errors.Wrapf(err, "failed to update ceph filesystem subvolume group %q applied snapshot schedules status", svg.Name)
+	}
+	return nil
+}
+
+// getSubVolumeGroupUsage returns the current space usage of the ceph filesystem subvolume group
+func (r *ReconcileCephFilesystemSubVolumeGroup) getSubVolumeGroupUsage(cephFilesystemSubVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) (*cephv1.CephFilesystemSubVolumeGroupUsage, error) {
+	info, err := cephclient.GetCephFSSubVolumeGroupInfo(r.context, r.clusterInfo, cephFilesystemSubVolumeGroup.Spec.FilesystemName, getSubvolumeGroupName(cephFilesystemSubVolumeGroup))
+	if err != nil {
+		return nil, err
+	}
+
+	usage := &cephv1.CephFilesystemSubVolumeGroupUsage{
+		BytesUsed: info.BytesUsed,
+		DataPool:  info.DataPool,
+	}
+	if quota, limited := info.Quota(); limited {
+		usage.BytesQuota = quota
+		usage.PercentUsed = info.BytesPercent
+	}
+	return usage, nil
+}
+
 // Delete the ceph filesystem subvolume group
 func (r *ReconcileCephFilesystemSubVolumeGroup) deleteSubVolumeGroup(cephFilesystemSubVolumeGroup *cephv1.CephFilesystemSubVolumeGroup) error {
 	namespacedName := fmt.Sprintf("%s/%s", cephFilesystemSubVolumeGroup.Namespace, cephFilesystemSubVolumeGroup.Name)
@@ -349,8 +480,8 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) deleteSubVolumeGroup(cephFilesys
 	return nil
 }
 
-// updateStatus updates an object with a given status
-func (r *ReconcileCephFilesystemSubVolumeGroup) updateStatus(observedGeneration int64, name types.NamespacedName, status cephv1.ConditionType) {
+// updateStatus updates an object with a given status. The usage and snapshot schedules are only updated if not nil.
+func (r *ReconcileCephFilesystemSubVolumeGroup) updateStatus(observedGeneration int64, name types.NamespacedName, status cephv1.ConditionType, usage *cephv1.CephFilesystemSubVolumeGroupUsage, snapshotSchedules []cephv1.FilesystemSnapshotScheduleStatus) {
 	cephFilesystemSubVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{}
 	if err := r.client.Get(r.opManagerContext, name, cephFilesystemSubVolumeGroup); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -369,6 +500,12 @@ func (r *ReconcileCephFilesystemSubVolumeGroup) updateStatus(observedGeneration
 	if observedGeneration != k8sutil.ObservedGenerationNotAvailable {
 		cephFilesystemSubVolumeGroup.Status.ObservedGeneration = observedGeneration
 	}
+	if usage != nil {
+		cephFilesystemSubVolumeGroup.Status.Usage = usage
+	}
+	if snapshotSchedules != nil {
+		cephFilesystemSubVolumeGroup.Status.SnapshotSchedules = snapshotSchedules
+	}
 	if err := reporting.UpdateStatus(r.client, cephFilesystemSubVolumeGroup); err != nil {
 		logger.Errorf("failed to set ceph filesystem subvolume group %q status to %q. %v", name, status, err)
 		return
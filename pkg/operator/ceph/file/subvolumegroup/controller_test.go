@@ -28,12 +28,14 @@ import (
 	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
 	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
 	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	"github.com/rook/rook/pkg/operator/ceph/csi"
 	"github.com/rook/rook/pkg/operator/k8sutil"
 	testop "github.com/rook/rook/pkg/operator/test"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
@@ -197,6 +199,10 @@ func TestCephClientController(t *testing.T) {
 					return "", nil
 				} else if args[0] == "fs" && args[1] == "subvolumegroup" && args[2] == "pin" {
 					return "", nil
+				} else if args[0] == "fs" && args[1] == "subvolumegroup" && args[2] == "info" {
+					return `{"bytes_used": 1024, "bytes_quota": "infinite", "bytes_pcent": "undefined", "data_pool": "myfs-data0"}`, nil
+				} else if args[0] == "fs" && args[1] == "subvolumegroup" && args[2] == "getpath" {
+					return "/volumes/group-a", nil
 				}
 
 				return "", errors.Errorf("unknown command. %v", args)
@@ -225,11 +231,15 @@ func TestCephClientController(t *testing.T) {
 		res, err := r.Reconcile(ctx, req)
 		assert.NoError(t, err)
 		assert.False(t, res.Requeue)
+		assert.Equal(t, statusRefreshInterval, res.RequeueAfter)
 
 		err = r.client.Get(ctx, req.NamespacedName, cephFilesystemSubVolumeGroup)
 		assert.NoError(t, err)
 		assert.Equal(t, cephv1.ConditionReady, cephFilesystemSubVolumeGroup.Status.Phase)
 		assert.NotEmpty(t, cephFilesystemSubVolumeGroup.Status.Info["clusterID"])
+		assert.Equal(t, uint64(1024), cephFilesystemSubVolumeGroup.Status.Usage.BytesUsed)
+		assert.Equal(t, uint64(0), cephFilesystemSubVolumeGroup.Status.Usage.BytesQuota)
+		assert.Equal(t, "myfs-data0", cephFilesystemSubVolumeGroup.Status.Usage.DataPool)
 
 		// test that csi configmap is created
 		cm, err := c.Clientset.CoreV1().ConfigMaps(namespace).Get(ctx, csi.ConfigName, metav1.GetOptions{})
@@ -332,6 +342,61 @@ func TestCephClientController(t *testing.T) {
 	})
 }
 
+func TestCreateOrUpdateSubVolumeGroupQuota(t *testing.T) {
+	infoOutput := ""
+	var resizeArgs []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "fs" && args[1] == "subvolumegroup" && args[2] == "create" {
+				return "", nil
+			} else if args[0] == "fs" && args[1] == "subvolumegroup" && args[2] == "info" {
+				return infoOutput, nil
+			} else if args[0] == "fs" && args[1] == "subvolumegroup" && args[2] == "resize" {
+				resizeArgs = args[3:6]
+				return "", nil
+			}
+			return "", errors.Errorf("unknown command. %v", args)
+		},
+	}
+	r := &ReconcileCephFilesystemSubVolumeGroup{
+		context:     &clusterd.Context{Executor: executor},
+		clusterInfo: cephclient.AdminTestClusterInfo("rook-ceph"),
+	}
+	cephFilesystemSubVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "rook-ceph", Name: "group-a"},
+		Spec:       cephv1.CephFilesystemSubVolumeGroupSpec{FilesystemName: "myfs"},
+	}
+	limitedInfo := `{"bytes_used": 1024, "bytes_quota": 10737418240, "bytes_pcent": "0.00", "data_pool": "myfs-data0"}`
+	unlimitedInfo := `{"bytes_used": 1024, "bytes_quota": "infinite", "bytes_pcent": "undefined", "data_pool": "myfs-data0"}`
+
+	t.Run("no quota", func(t *testing.T) {
+		infoOutput, resizeArgs = unlimitedInfo, nil
+		assert.NoError(t, r.createOrUpdateSubVolumeGroup(cephFilesystemSubVolumeGroup))
+		assert.Nil(t, resizeArgs)
+	})
+
+	t.Run("quota added", func(t *testing.T) {
+		quota := resource.MustParse("10Gi")
+		cephFilesystemSubVolumeGroup.Spec.Quota = &quota
+		infoOutput, resizeArgs = unlimitedInfo, nil
+		assert.NoError(t, r.createOrUpdateSubVolumeGroup(cephFilesystemSubVolumeGroup))
+		assert.Equal(t, []string{"myfs", "group-a", "10737418240"}, resizeArgs)
+	})
+
+	t.Run("quota unchanged", func(t *testing.T) {
+		infoOutput, resizeArgs = limitedInfo, nil
+		assert.NoError(t, r.createOrUpdateSubVolumeGroup(cephFilesystemSubVolumeGroup))
+		assert.Nil(t, resizeArgs)
+	})
+
+	t.Run("quota removed", func(t *testing.T) {
+		cephFilesystemSubVolumeGroup.Spec.Quota = nil
+		infoOutput, resizeArgs = limitedInfo, nil
+		assert.NoError(t, r.createOrUpdateSubVolumeGroup(cephFilesystemSubVolumeGroup))
+		assert.Equal(t, []string{"myfs", "group-a", "infinite"}, resizeArgs)
+	})
+}
+
 func Test_buildClusterID(t *testing.T) {
 	longName := "foooooooooooooooooooooooooooooooooooooooooooo"
 	cephFilesystemSubVolumeGroup := &cephv1.CephFilesystemSubVolumeGroup{ObjectMeta: metav1.ObjectMeta{Namespace: "rook-ceph", Name: longName}, Spec: cephv1.CephFilesystemSubVolumeGroupSpec{FilesystemName: "myfs"}}
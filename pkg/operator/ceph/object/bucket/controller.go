@@ -33,6 +33,8 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/object"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/tracing"
 	v1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -74,7 +76,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 
 func add(ctx context.Context, mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -105,7 +107,7 @@ func add(ctx context.Context, mgr manager.Manager, r reconcile.Reconciler) error
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileBucket) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
-	reconcileResponse, err := r.reconcile(request)
+	reconcileResponse, err := r.reconcile(context, request)
 	if err != nil {
 		logger.Errorf("failed to reconcile %v", err)
 	}
@@ -113,7 +115,8 @@ func (r *ReconcileBucket) Reconcile(context context.Context, request reconcile.R
 	return reconcileResponse, err
 }
 
-func (r *ReconcileBucket) reconcile(request reconcile.Request) (reconcile.Result, error) {
+func (r *ReconcileBucket) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := util.NewContextLogger(ctx, logger)
 	// See if there is a CephCluster
 	cephCluster := &cephv1.CephCluster{}
 	err := r.client.Get(r.opManagerContext, request.NamespacedName, cephCluster)
@@ -156,7 +159,7 @@ func (r *ReconcileBucket) reconcile(request reconcile.Request) (reconcile.Result
 	}
 
 	// Populate clusterInfo during each reconcile
-	clusterInfo, _, _, err := opcontroller.LoadClusterInfo(r.context, r.opManagerContext, cephCluster.Namespace, &cephCluster.Spec)
+	clusterInfo, _, _, err := opcontroller.LoadClusterInfo(r.context, tracing.ContextWithSpan(r.opManagerContext, ctx), cephCluster.Namespace, &cephCluster.Spec)
 	if err != nil {
 		// This avoids a requeue with exponential backoff and allows the controller to reconcile
 		// more quickly when the cluster is ready.
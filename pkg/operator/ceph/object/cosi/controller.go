@@ -86,7 +86,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	controller, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	controller, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return errors.Wrapf(err, "failed to create %s controller", controllerName)
 	}
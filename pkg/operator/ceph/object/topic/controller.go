@@ -28,6 +28,8 @@ import (
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/tracing"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	"k8s.io/apimachinery/pkg/types"
 	"sigs.k8s.io/controller-runtime/pkg/client"
@@ -66,7 +68,7 @@ func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext contex
 
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -87,7 +89,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileBucketTopic) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
-	reconcileResponse, err := r.reconcile(request)
+	reconcileResponse, err := r.reconcile(context, request)
 	if err != nil {
 		logger.Errorf("failed to reconcile %v", err)
 	}
@@ -95,7 +97,8 @@ func (r *ReconcileBucketTopic) Reconcile(context context.Context, request reconc
 	return reconcileResponse, err
 }
 
-func (r *ReconcileBucketTopic) reconcile(request reconcile.Request) (reconcile.Result, error) {
+func (r *ReconcileBucketTopic) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := util.NewContextLogger(ctx, logger)
 	// Fetch the CephBucketTopic instance
 	cephBucketTopic := &cephv1.CephBucketTopic{}
 	err := r.client.Get(r.opManagerContext, request.NamespacedName, cephBucketTopic)
@@ -147,7 +150,7 @@ func (r *ReconcileBucketTopic) reconcile(request reconcile.Request) (reconcile.R
 	r.clusterSpec = &cephCluster.Spec
 
 	// Populate clusterInfo during each reconcile
-	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, cephCluster.Namespace, r.clusterSpec)
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, tracing.ContextWithSpan(r.opManagerContext, ctx), cephCluster.Namespace, r.clusterSpec)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
 	}
@@ -25,6 +25,7 @@ import (
 
 	"github.com/coreos/pkg/capnslog"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util"
 	"github.com/rook/rook/pkg/util/exec"
 
 	"github.com/pkg/errors"
@@ -36,6 +37,7 @@ import (
 	"github.com/rook/rook/pkg/operator/ceph/csi/peermap"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util/tracing"
 	corev1 "k8s.io/api/core/v1"
 	kerrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -74,8 +76,13 @@ type ReconcileCephBlockPool struct {
 	context           *clusterd.Context
 	clusterInfo       *cephclient.ClusterInfo
 	blockPoolContexts map[string]*blockPoolHealth
+	poolMigrations    map[string]*poolMigration
+	usageCheckers     map[string]*usageMonitoring
 	opManagerContext  context.Context
 	recorder          record.EventRecorder
+
+	// scheduledSnapshotsCheckers take the scheduled snapshots of the images of the pools
+	scheduledSnapshotsCheckers ScheduledSnapshotsCheckers
 }
 
 type blockPoolHealth struct {
@@ -97,6 +104,8 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 		scheme:            mgr.GetScheme(),
 		context:           context,
 		blockPoolContexts: make(map[string]*blockPoolHealth),
+		poolMigrations:    make(map[string]*poolMigration),
+		usageCheckers:     make(map[string]*usageMonitoring),
 		opManagerContext:  opManagerContext,
 		recorder:          mgr.GetEventRecorderFor("rook-" + controllerName),
 	}
@@ -104,7 +113,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 
 func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -139,12 +148,13 @@ func add(opManagerContext context.Context, mgr manager.Manager, r reconcile.Reco
 // Result.Requeue is true, otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileCephBlockPool) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
-	reconcileResponse, cephBlockPool, err := r.reconcile(request)
+	reconcileResponse, cephBlockPool, err := r.reconcile(context, request)
 
 	return reporting.ReportReconcileResult(logger, r.recorder, request, &cephBlockPool, reconcileResponse, err)
 }
 
-func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile.Result, cephv1.CephBlockPool, error) {
+func (r *ReconcileCephBlockPool) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, cephv1.CephBlockPool, error) {
+	logger := util.NewContextLogger(ctx, logger)
 	// Fetch the CephBlockPool instance
 	cephBlockPool := &cephv1.CephBlockPool{}
 	err := r.client.Get(r.opManagerContext, request.NamespacedName, cephBlockPool)
@@ -157,6 +167,8 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 			cephBlockPool.Name = request.Name
 			cephBlockPool.Namespace = request.Namespace
 			r.cancelMirrorMonitoring(cephBlockPool)
+			r.cancelUsageMonitoring(cephBlockPool)
+			r.scheduledSnapshotsCheckers.Stop(blockPoolChannelKeyName(cephBlockPool))
 			return reconcile.Result{}, *cephBlockPool, nil
 		}
 		// Error reading the object - requeue the request.
@@ -190,6 +202,8 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		if !cephBlockPool.GetDeletionTimestamp().IsZero() && !cephClusterExists {
 			// don't leak the health checker routine if we are force-deleting
 			r.cancelMirrorMonitoring(cephBlockPool)
+			r.cancelUsageMonitoring(cephBlockPool)
+			r.scheduledSnapshotsCheckers.Stop(blockPoolChannelKeyName(cephBlockPool))
 
 			// Remove finalizer
 			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephBlockPool)
@@ -204,7 +218,7 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 	}
 
 	// Populate clusterInfo during each reconcile
-	clusterInfo, _, _, err := opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	clusterInfo, _, _, err := opcontroller.LoadClusterInfo(r.context, tracing.ContextWithSpan(r.opManagerContext, ctx), request.NamespacedName.Namespace, &cephCluster.Spec)
 	if err != nil {
 		return opcontroller.ImmediateRetryResult, *cephBlockPool, errors.Wrap(err, "failed to populate cluster info")
 	}
@@ -229,13 +243,20 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 			return reconcile.Result{}, *cephBlockPool, err
 		}
 		if !deps.Empty() {
-			err := reporting.ReportDeletionBlockedDueToDependents(r.opManagerContext, logger, r.client, cephBlockPool, deps)
+			err := reporting.ReportDeletionBlockedDueToDependents(r.opManagerContext, logger.PackageLogger, r.client, cephBlockPool, deps)
 			return opcontroller.WaitForRequeueIfFinalizerBlocked, *cephBlockPool, err
 		}
-		reporting.ReportDeletionNotBlockedDueToDependents(r.opManagerContext, logger, r.client, r.recorder, cephBlockPool)
+		reporting.ReportDeletionNotBlockedDueToDependents(r.opManagerContext, logger.PackageLogger, r.client, r.recorder, cephBlockPool)
 		// If the ceph block pool is still in the map, we must remove it during CR deletion
 		// We must remove it first otherwise the checker will panic since the status/info will be nil
 		r.cancelMirrorMonitoring(cephBlockPool)
+		r.cancelUsageMonitoring(cephBlockPool)
+		r.scheduledSnapshotsCheckers.Stop(blockPoolChannelKeyName(cephBlockPool))
+
+		// Stop the migration of the pool and delete the pools created by the migration
+		if err := r.cancelMigration(cephBlockPool); err != nil {
+			return opcontroller.ImmediateRetryResult, *cephBlockPool, errors.Wrapf(err, "failed to cancel the migration of pool %q", cephBlockPool.Name)
+		}
 
 		r.recorder.Event(cephBlockPool, corev1.EventTypeNormal, string(cephv1.ReconcileStarted), "starting blockpool deletion")
 
@@ -280,6 +301,19 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 	}
 	r.clusterInfo.CephVersion = *cephVersion
 
+	// MIGRATE: the layout of the pool changed
+	migrating, err := r.reconcileMigration(&cephCluster.Spec, cephBlockPool, request.NamespacedName)
+	if err != nil {
+		updateStatus(r.opManagerContext, r.client, request.NamespacedName, cephv1.ConditionFailure, nil, k8sutil.ObservedGenerationNotAvailable)
+		return opcontroller.ImmediateRetryResult, *cephBlockPool, errors.Wrapf(err, "failed to migrate pool %q", cephBlockPool.Name)
+	}
+	if migrating {
+		// the pool is not updated until it is migrated
+		logger.Debugf("pool %q is being migrated, waiting for the migration to complete", cephBlockPool.Name)
+		updateStatus(r.opManagerContext, r.client, request.NamespacedName, cephv1.ConditionProgressing, nil, k8sutil.ObservedGenerationNotAvailable)
+		return reconcile.Result{RequeueAfter: migrationCheckInterval}, *cephBlockPool, nil
+	}
+
 	// CREATE/UPDATE
 	reconcileResponse, err = r.reconcileCreatePool(clusterInfo, &cephCluster.Spec, cephBlockPool)
 	if err != nil {
@@ -296,6 +330,9 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		return reconcile.Result{}, *cephBlockPool, errors.Wrap(err, "failed to enable/disable stats collection for pool(s)")
 	}
 
+	// apply the IO limits of the rbd images
+	r.reconcileQoS(cephBlockPool, request.NamespacedName)
+
 	poolSpec := cephBlockPool.ToNamedPoolSpec()
 	checker := newMirrorChecker(r.context, r.client, r.clusterInfo, request.NamespacedName, &poolSpec)
 	// ADD PEERS
@@ -348,17 +385,33 @@ func (r *ReconcileCephBlockPool) reconcile(request reconcile.Request) (reconcile
 		if blockPoolContextsExists && r.blockPoolContexts[blockPoolChannelKey].started {
 			r.cancelMirrorMonitoring(cephBlockPool)
 			// Reset the MirrorHealthCheckSpec
-			checker.updateStatusMirroring(nil, nil, nil, "")
+			checker.updateStatusMirroring(nil, nil, nil, "", nil)
 		}
 	}
 
-	// Return and do not requeue
+	// Report the usage of the pool periodically
+	r.reconcileUsageMonitoring(cephBlockPool, request.NamespacedName)
+
+	// Take the scheduled snapshots of the images in the background
+	r.reconcileScheduledSnapshots(cephBlockPool, request.NamespacedName)
+
+	// Promote or demote the mirrored images for a planned failover or failback
+	if !r.reconcileMirroringRole(cephBlockPool, request.NamespacedName) {
+		logger.Debug("done reconciling, waiting for the mirrored images to converge to their role")
+		return reconcile.Result{RequeueAfter: MirroringRoleCheckInterval}, *cephBlockPool, nil
+	}
+
 	logger.Debug("done reconciling")
 	return reconcile.Result{}, *cephBlockPool, nil
 }
 
 func (r *ReconcileCephBlockPool) reconcileCreatePool(clusterInfo *cephclient.ClusterInfo, cephCluster *cephv1.ClusterSpec, cephBlockPool *cephv1.CephBlockPool) (reconcile.Result, error) {
 	poolSpec := cephBlockPool.ToNamedPoolSpec()
+	// the pool of a replicated pool migrated to an erasure coded layout keeps the metadata of the
+	// images, the erasure coded data pool has the layout of the spec
+	if dataPool := migrationDataPool(cephBlockPool); dataPool != "" {
+		poolSpec.Name = dataPool
+	}
 	err := createPool(r.context, clusterInfo, cephCluster, &poolSpec)
 	if err != nil {
 		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to create pool %q.", cephBlockPool.GetName())
@@ -496,4 +549,6 @@ func (r *ReconcileCephBlockPool) cancelMirrorMonitoring(cephBlockPool *cephv1.Ce
 		// Remove ceph block pool from the map
 		delete(r.blockPoolContexts, channelKey)
 	}
+
+	deleteMirroredImagesMetrics(cephBlockPool.Namespace, cephBlockPool.Name)
 }
@@ -228,6 +228,9 @@ func TestCephBlockPoolController(t *testing.T) {
 			if args[0] == "status" {
 				return `{"fsid":"c47cac40-9bee-4d52-823b-ccd803ba5bfe","health":{"checks":{},"status":"HEALTH_ERR"},"pgmap":{"num_pgs":100,"pgs_by_state":[{"state_name":"active+clean","count":100}]}}`, nil
 			}
+			if args[0] == "osd" && args[1] == "lspools" {
+				return `[]`, nil
+			}
 
 			return "", nil
 		},
@@ -251,6 +254,7 @@ func TestCephBlockPoolController(t *testing.T) {
 		scheme:            s,
 		context:           c,
 		blockPoolContexts: make(map[string]*blockPoolHealth),
+		poolMigrations:    make(map[string]*poolMigration),
 		opManagerContext:  context.TODO(),
 		recorder:          record.NewFakeRecorder(5),
 	}
@@ -307,6 +311,7 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			poolMigrations:    make(map[string]*poolMigration),
 			opManagerContext:  context.TODO(),
 			recorder:          record.NewFakeRecorder(5),
 		}
@@ -335,6 +340,9 @@ func TestCephBlockPoolController(t *testing.T) {
 				if args[0] == "config" && args[2] == "mgr" && args[3] == "mgr/prometheus/rbd_stats_pools" {
 					return "", nil
 				}
+				if args[0] == "osd" && args[1] == "lspools" {
+					return `[]`, nil
+				}
 
 				return "", nil
 			},
@@ -365,6 +373,7 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			poolMigrations:    make(map[string]*poolMigration),
 			opManagerContext:  context.TODO(),
 			recorder:          record.NewFakeRecorder(5),
 		}
@@ -426,6 +435,9 @@ func TestCephBlockPoolController(t *testing.T) {
 				if args[0] == "mirror" && args[1] == "pool" && args[2] == "peer" && args[3] == "bootstrap" && args[4] == "create" {
 					return `eyJmc2lkIjoiYzZiMDg3ZjItNzgyOS00ZGJiLWJjZmMtNTNkYzM0ZTBiMzVkIiwiY2xpZW50X2lkIjoicmJkLW1pcnJvci1wZWVyIiwia2V5IjoiQVFBV1lsWmZVQ1Q2RGhBQVBtVnAwbGtubDA5YVZWS3lyRVV1NEE9PSIsIm1vbl9ob3N0IjoiW3YyOjE5Mi4xNjguMTExLjEwOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTA6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjEyOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTI6Njc4OV0sW3YyOjE5Mi4xNjguMTExLjExOjMzMDAsdjE6MTkyLjE2OC4xMTEuMTE6Njc4OV0ifQ==`, nil
 				}
+				if args[0] == "osd" && args[1] == "lspools" {
+					return `[]`, nil
+				}
 				return "", nil
 			},
 		}
@@ -435,6 +447,7 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			poolMigrations:    make(map[string]*poolMigration),
 			opManagerContext:  context.TODO(),
 			recorder:          record.NewFakeRecorder(5),
 		}
@@ -477,6 +490,7 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			poolMigrations:    make(map[string]*poolMigration),
 			opManagerContext:  context.TODO(),
 			recorder:          record.NewFakeRecorder(5),
 		}
@@ -515,6 +529,7 @@ func TestCephBlockPoolController(t *testing.T) {
 			scheme:            s,
 			context:           c,
 			blockPoolContexts: make(map[string]*blockPoolHealth),
+			poolMigrations:    make(map[string]*poolMigration),
 			opManagerContext:  context.TODO(),
 			recorder:          record.NewFakeRecorder(5),
 		}
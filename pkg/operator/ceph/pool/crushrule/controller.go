@@ -0,0 +1,335 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package crushrule to manage custom CRUSH rules
+package crushrule
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/tracing"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/controller"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/manager"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	"sigs.k8s.io/controller-runtime/pkg/source"
+)
+
+const (
+	controllerName = "ceph-crush-rule-controller"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", controllerName)
+
+var crushRuleKind = reflect.TypeOf(cephv1.CephCrushRule{}).Name()
+
+// Sets the type meta for the controller main object
+var controllerTypeMeta = metav1.TypeMeta{
+	Kind:       crushRuleKind,
+	APIVersion: fmt.Sprintf("%s/%s", cephv1.CustomResourceGroup, cephv1.Version),
+}
+
+// ReconcileCephCrushRule reconciles a CephCrushRule object
+type ReconcileCephCrushRule struct {
+	client           client.Client
+	scheme           *runtime.Scheme
+	context          *clusterd.Context
+	clusterInfo      *cephclient.ClusterInfo
+	opManagerContext context.Context
+	recorder         record.EventRecorder
+}
+
+// Add creates a new CephCrushRule Controller and adds it to the Manager. The Manager will set fields
+// on the Controller and Start it when the Manager is Started.
+func Add(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context, opConfig opcontroller.OperatorConfig) error {
+	return add(mgr, newReconciler(mgr, context, opManagerContext))
+}
+
+// newReconciler returns a new reconcile.Reconciler
+func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerContext context.Context) reconcile.Reconciler {
+	return &ReconcileCephCrushRule{
+		client:           mgr.GetClient(),
+		scheme:           mgr.GetScheme(),
+		context:          context,
+		opManagerContext: opManagerContext,
+		recorder:         mgr.GetEventRecorderFor("rook-" + controllerName),
+	}
+}
+
+func add(mgr manager.Manager, r reconcile.Reconciler) error {
+	// Create a new controller
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
+	if err != nil {
+		return err
+	}
+	logger.Info("successfully started")
+
+	// Watch for changes on the CephCrushRule CRD object
+	err = c.Watch(source.Kind(mgr.GetCache(), &cephv1.CephCrushRule{TypeMeta: controllerTypeMeta}), &handler.EnqueueRequestForObject{}, opcontroller.WatchControllerPredicate())
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// Reconcile reads that state of the cluster for a CephCrushRule object and makes changes based on
+// the state read and what is in the CephCrushRule.Spec The Controller will requeue the Request to
+// be processed again if the returned error is non-nil or Result.Requeue is true, otherwise upon
+// completion it will remove the work from the queue.
+func (r *ReconcileCephCrushRule) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
+	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
+	reconcileResponse, err := r.reconcile(context, request)
+	if err != nil {
+		logger.Errorf("failed to reconcile %q %v", request.NamespacedName, err)
+	}
+
+	return reconcileResponse, err
+}
+
+func (r *ReconcileCephCrushRule) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := util.NewContextLogger(ctx, logger)
+	namespacedName := request.NamespacedName
+	// Fetch the CephCrushRule instance
+	cephCrushRule := &cephv1.CephCrushRule{}
+	err := r.client.Get(r.opManagerContext, namespacedName, cephCrushRule)
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("cephCrushRule resource %q not found. Ignoring since object must be deleted.", namespacedName)
+			return reconcile.Result{}, nil
+		}
+		// Error reading the object - requeue the request.
+		return reconcile.Result{}, errors.Wrap(err, "failed to get cephCrushRule")
+	}
+
+	// Set a finalizer so we can do cleanup before the object goes away
+	err = opcontroller.AddFinalizerIfNotPresent(r.opManagerContext, r.client, cephCrushRule)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to add finalizer")
+	}
+
+	// The CR was just created, initializing status fields
+	if cephCrushRule.Status == nil {
+		r.updateStatus(namespacedName, cephv1.ConditionProgressing, nil, 0, false)
+	}
+
+	// Make sure a CephCluster is present otherwise do nothing
+	cephCluster, isReadyToReconcile, cephClusterExists, reconcileResponse := opcontroller.IsReadyToReconcile(r.opManagerContext, r.client, namespacedName, controllerName)
+	if !isReadyToReconcile {
+		// This handles the case where the Ceph Cluster is gone and we want to delete that CR
+		// We skip the rule deletion since everything is gone already
+		//
+		// Also, only remove the finalizer if the CephCluster is gone
+		// If not, we should wait for it to be ready
+		// This handles the case where the operator is not ready to accept Ceph command but the cluster exists
+		if !cephCrushRule.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			// Remove finalizer
+			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephCrushRule)
+			if err != nil {
+				return opcontroller.ImmediateRetryResult, errors.Wrap(err, "failed to remove finalizer")
+			}
+
+			// Return and do not requeue. Successful deletion.
+			return reconcile.Result{}, nil
+		}
+		return reconcileResponse, nil
+	}
+
+	// Populate clusterInfo during each reconcile
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, tracing.ContextWithSpan(r.opManagerContext, ctx), namespacedName.Namespace, &cephCluster.Spec)
+	if err != nil {
+		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
+	}
+	r.clusterInfo.Context = r.opManagerContext
+
+	// DELETE: the CR was deleted
+	if !cephCrushRule.GetDeletionTimestamp().IsZero() {
+		logger.Debugf("delete cephCrushRule %q", namespacedName)
+		deps, err := cephCrushRuleDependents(r.context, r.clusterInfo, cephCrushRule)
+		if err != nil {
+			return reconcile.Result{}, err
+		}
+		if !deps.Empty() {
+			err := reporting.ReportDeletionBlockedDueToDependents(r.opManagerContext, logger.PackageLogger, r.client, cephCrushRule, deps)
+			return opcontroller.WaitForRequeueIfFinalizerBlocked, err
+		}
+		reporting.ReportDeletionNotBlockedDueToDependents(r.opManagerContext, logger.PackageLogger, r.client, r.recorder, cephCrushRule)
+
+		// On external cluster, we don't delete the crush rule, it has to be deleted manually
+		if cephCluster.Spec.External.Enable {
+			logger.Warningf("external crush rule %q deletion is not supported, delete it manually", namespacedName)
+		} else {
+			err := r.deleteCrushRule(cephCrushRule)
+			if err != nil {
+				if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+					logger.Info(opcontroller.OperatorNotInitializedMessage)
+					return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
+				}
+				return reconcile.Result{}, errors.Wrapf(err, "failed to delete crush rule %q", cephCrushRule.Name)
+			}
+		}
+
+		// Remove finalizer
+		err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephCrushRule)
+		if err != nil {
+			return reconcile.Result{}, errors.Wrap(err, "failed to remove finalizer")
+		}
+
+		// Return and do not requeue. Successful deletion.
+		return reconcile.Result{}, nil
+	}
+
+	if cephCluster.Spec.External.Enable {
+		logger.Debugf("external crush rule %q creation is not supported, create it manually, the controller will assume it's there", namespacedName)
+		r.updateStatus(namespacedName, cephv1.ConditionReady, nil, cephCrushRule.Generation, false)
+		return reconcile.Result{}, nil
+	}
+
+	// Get CephCluster version
+	cephVersion, err := opcontroller.GetImageVersion(cephCluster)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to fetch ceph version from cephcluster %q", cephCluster.Name)
+	}
+	r.clusterInfo.CephVersion = *cephVersion
+	if cephclient.IsMSRCrushRule(cephCrushRule.Spec) && !r.clusterInfo.CephVersion.IsAtLeast(cephclient.MSRCrushRuleVersion) {
+		r.updateStatus(namespacedName, cephv1.ConditionFailure, nil, 0, false)
+		return reconcile.Result{}, errors.Errorf("multi-step retry crush rule %q requires ceph version %q, but is running %s", cephCrushRule.Name, cephclient.MSRCrushRuleVersion.String(), r.clusterInfo.CephVersion.String())
+	}
+
+	// Create or update the crush rule
+	ruleID, err := r.createOrUpdateCrushRule(namespacedName, cephCrushRule)
+	if err != nil {
+		if strings.Contains(err.Error(), opcontroller.UninitializedCephConfigError) {
+			logger.Info(opcontroller.OperatorNotInitializedMessage)
+			return opcontroller.WaitForRequeueIfOperatorNotInitialized, nil
+		}
+		r.updateStatus(namespacedName, cephv1.ConditionFailure, nil, 0, false)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to create or update crush rule %q", cephCrushRule.Name)
+	}
+
+	r.updateStatus(namespacedName, cephv1.ConditionReady, ruleID, cephCrushRule.Generation, false)
+	logger.Debugf("done reconciling cephCrushRule %q", namespacedName)
+
+	// Return and do not requeue
+	return reconcile.Result{}, nil
+}
+
+// createOrUpdateCrushRule applies the rule to the CRUSH map. The CRUSH map is not changed again until
+// the spec changes, unless the rule was removed from the CRUSH map. A rule that already exists in
+// the CRUSH map without being created by the operator is not taken over.
+func (r *ReconcileCephCrushRule) createOrUpdateCrushRule(namespacedName types.NamespacedName, cephCrushRule *cephv1.CephCrushRule) (*int, error) {
+	if cephCrushRule.Status == nil || !cephCrushRule.Status.Owned {
+		exists, err := cephclient.CrushRuleExists(r.context, r.clusterInfo, cephCrushRule.Name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			return nil, errors.Errorf("crush rule %q already exists in the crush map and was not created by the operator, rename the CephCrushRule or delete the rule", cephCrushRule.Name)
+		}
+		// the ownership is recorded before the rule is created so that the rule is not refused if
+		// the status update fails after the creation
+		r.updateStatus(namespacedName, cephv1.ConditionProgressing, nil, 0, true)
+	}
+
+	if cephCrushRule.Status != nil && cephCrushRule.Status.Phase == cephv1.ConditionReady && cephCrushRule.Status.ObservedGeneration == cephCrushRule.Generation {
+		exists, err := cephclient.CrushRuleExists(r.context, r.clusterInfo, cephCrushRule.Name)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			logger.Debugf("crush rule %q is up to date", cephCrushRule.Name)
+			return cephCrushRule.Status.RuleID, nil
+		}
+	}
+
+	logger.Infof("creating crush rule %q", cephCrushRule.Name)
+	ruleID, err := cephclient.CreateOrUpdateCrushRule(r.context, r.clusterInfo, cephCrushRule.Name, cephCrushRule.Spec)
+	if err != nil {
+		return nil, err
+	}
+
+	return &ruleID, nil
+}
+
+// deleteCrushRule removes the rule from the CRUSH map if the operator created it
+func (r *ReconcileCephCrushRule) deleteCrushRule(cephCrushRule *cephv1.CephCrushRule) error {
+	if cephCrushRule.Status == nil || !cephCrushRule.Status.Owned {
+		logger.Infof("crush rule %q was not created by the operator, it is not deleted", cephCrushRule.Name)
+		return nil
+	}
+
+	exists, err := cephclient.CrushRuleExists(r.context, r.clusterInfo, cephCrushRule.Name)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		logger.Infof("crush rule %q already deleted", cephCrushRule.Name)
+		return nil
+	}
+
+	return cephclient.DeleteCrushRule(r.context, r.clusterInfo, cephCrushRule.Name)
+}
+
+// updateStatus updates an object with a given status. The ownership of the rule is only recorded
+// once, it is never cleared.
+func (r *ReconcileCephCrushRule) updateStatus(name types.NamespacedName, status cephv1.ConditionType, ruleID *int, observedGeneration int64, owned bool) {
+	cephCrushRule := &cephv1.CephCrushRule{}
+	if err := r.client.Get(r.opManagerContext, name, cephCrushRule); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("CephCrushRule resource %q not found. Ignoring since object must be deleted.", name)
+			return
+		}
+		logger.Warningf("failed to retrieve crush rule %q to update status to %q. %v", name, status, err)
+		return
+	}
+	if cephCrushRule.Status == nil {
+		cephCrushRule.Status = &cephv1.CrushRuleStatus{}
+	}
+
+	cephCrushRule.Status.Phase = status
+	if ruleID != nil {
+		cephCrushRule.Status.RuleID = ruleID
+	}
+	if observedGeneration != 0 {
+		cephCrushRule.Status.ObservedGeneration = observedGeneration
+	}
+	if owned {
+		cephCrushRule.Status.Owned = true
+	}
+	if err := reporting.UpdateStatus(r.client, cephCrushRule); err != nil {
+		logger.Errorf("failed to set crush rule %q status to %q. %v", name, status, err)
+		return
+	}
+	logger.Debugf("crush rule %q status updated to %q", name, status)
+}
@@ -0,0 +1,222 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crushrule
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	rookclient "github.com/rook/rook/pkg/client/clientset/versioned/fake"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	"github.com/rook/rook/pkg/operator/k8sutil"
+	testop "github.com/rook/rook/pkg/operator/test"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/tools/record"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+const crushMapRules = `{"rules":[{"rule_id":0,"rule_name":"replicated_rule","type":1,"steps":[]}]}`
+
+func TestCephCrushRuleController(t *testing.T) {
+	ctx := context.TODO()
+	namespace := "rook-ceph"
+	req := reconcile.Request{NamespacedName: types.NamespacedName{Name: "two-per-dc", Namespace: namespace}}
+
+	cephCrushRule := &cephv1.CephCrushRule{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:       req.Name,
+			Namespace:  namespace,
+			Generation: 1,
+		},
+		Spec: cephv1.CrushRuleSpec{
+			Steps: []cephv1.CrushRuleStep{
+				{Op: cephv1.CrushRuleStepTake, Item: "default"},
+				{Op: cephv1.CrushRuleStepChoose, Number: 2, Type: "datacenter"},
+				{Op: cephv1.CrushRuleStepChooseLeaf, Number: 2, Type: "host"},
+				{Op: cephv1.CrushRuleStepEmit},
+			},
+		},
+	}
+	cephCluster := &cephv1.CephCluster{
+		ObjectMeta: metav1.ObjectMeta{Name: namespace, Namespace: namespace},
+		Status: cephv1.ClusterStatus{
+			Phase:       cephv1.ConditionReady,
+			CephVersion: &cephv1.ClusterVersion{Version: "18.2.0-0"},
+			CephStatus:  &cephv1.CephStatus{Health: "HEALTH_OK"},
+		},
+	}
+
+	crushMap := crushMapRules
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if command == "crushtool" {
+				commands = append(commands, "crushtool "+args[0])
+				if args[0] == "--decompile" {
+					return "", os.WriteFile(args[3], []byte("# begin crush map\n"), 0600)
+				}
+				return "", nil
+			}
+			if args[0] == "osd" && args[1] == "crush" {
+				if args[2] == "dump" {
+					return crushMap, nil
+				}
+				commands = append(commands, "osd crush "+args[2]+" "+args[3]+" "+args[4])
+				return "", nil
+			}
+			if args[0] == "osd" && (args[1] == "getcrushmap" || args[1] == "setcrushmap") {
+				commands = append(commands, "osd "+args[1])
+			}
+			return "", nil
+		},
+	}
+	c := &clusterd.Context{
+		Executor:      executor,
+		Clientset:     testop.New(t, 1),
+		RookClientset: rookclient.NewSimpleClientset(),
+	}
+
+	// Mock clusterInfo
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: "rook-ceph-mon", Namespace: namespace},
+		Data: map[string][]byte{
+			"fsid":         []byte("c47cac40-9bee-4d52-823b-ccd803ba5bfe"),
+			"mon-secret":   []byte("monsecret"),
+			"admin-secret": []byte("adminsecret"),
+		},
+		Type: k8sutil.RookType,
+	}
+	_, err := c.Clientset.CoreV1().Secrets(namespace).Create(ctx, secret, metav1.CreateOptions{})
+	assert.NoError(t, err)
+
+	s := scheme.Scheme
+	s.AddKnownTypes(cephv1.SchemeGroupVersion, &cephv1.CephCluster{}, &cephv1.CephClusterList{})
+	cl := fake.NewClientBuilder().WithScheme(s).WithObjects(cephCrushRule, cephCluster).WithStatusSubresource(cephCrushRule).Build()
+	r := &ReconcileCephCrushRule{
+		client:           cl,
+		scheme:           s,
+		context:          c,
+		opManagerContext: ctx,
+		recorder:         record.NewFakeRecorder(10),
+	}
+
+	getCrushRule := func() *cephv1.CephCrushRule {
+		updated := &cephv1.CephCrushRule{}
+		assert.NoError(t, cl.Get(ctx, req.NamespacedName, updated))
+		return updated
+	}
+
+	t.Run("existing rule not taken over", func(t *testing.T) {
+		crushMap = `{"rules":[{"rule_id":0,"rule_name":"replicated_rule"},{"rule_id":1,"rule_name":"two-per-dc"}]}`
+		_, err := r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, "was not created by the operator")
+		assert.Empty(t, commands)
+
+		updated := getCrushRule()
+		assert.Equal(t, cephv1.ConditionFailure, updated.Status.Phase)
+		assert.False(t, updated.Status.Owned)
+		crushMap = crushMapRules
+	})
+
+	t.Run("rule created", func(t *testing.T) {
+		res, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.False(t, res.Requeue)
+		assert.Equal(t, []string{"osd getcrushmap", "crushtool --decompile", "crushtool --compile", "osd setcrushmap"}, commands)
+
+		updated := getCrushRule()
+		assert.Equal(t, cephv1.ConditionReady, updated.Status.Phase)
+		assert.True(t, updated.Status.Owned)
+		assert.Equal(t, 1, *updated.Status.RuleID)
+		assert.Equal(t, int64(1), updated.Status.ObservedGeneration)
+	})
+
+	t.Run("rule not updated until the spec changes", func(t *testing.T) {
+		commands = nil
+		crushMap = `{"rules":[{"rule_id":0,"rule_name":"replicated_rule"},{"rule_id":1,"rule_name":"two-per-dc"}]}`
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("rule recreated when missing from the crush map", func(t *testing.T) {
+		commands = nil
+		crushMap = crushMapRules
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Contains(t, commands, "osd setcrushmap")
+	})
+
+	t.Run("multi-step retry rule requires squid", func(t *testing.T) {
+		commands = nil
+		updated := getCrushRule()
+		updated.Spec.Type = "msr_firstn"
+		assert.NoError(t, cl.Update(ctx, updated))
+		_, err := r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, "requires ceph version")
+		assert.Empty(t, commands)
+
+		updated = getCrushRule()
+		updated.Spec.Type = ""
+		assert.NoError(t, cl.Update(ctx, updated))
+	})
+
+	t.Run("deletion blocked by a pool", func(t *testing.T) {
+		commands = nil
+		crushMap = `{"rules":[{"rule_id":0,"rule_name":"replicated_rule"},{"rule_id":1,"rule_name":"two-per-dc"}]}`
+		pool := &cephv1.CephBlockPool{
+			ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: namespace},
+			Spec:       cephv1.NamedBlockPoolSpec{PoolSpec: cephv1.PoolSpec{CrushRule: req.Name}},
+		}
+		_, err := c.RookClientset.CephV1().CephBlockPools(namespace).Create(ctx, pool, metav1.CreateOptions{})
+		assert.NoError(t, err)
+		assert.NoError(t, cl.Delete(ctx, getCrushRule()))
+
+		_, err = r.Reconcile(ctx, req)
+		assert.ErrorContains(t, err, "CephBlockPools: [replicapool]")
+		assert.Empty(t, commands)
+
+		assert.NoError(t, c.RookClientset.CephV1().CephBlockPools(namespace).Delete(ctx, pool.Name, metav1.DeleteOptions{}))
+	})
+
+	t.Run("rule deleted", func(t *testing.T) {
+		_, err := r.Reconcile(ctx, req)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"osd crush rule rm two-per-dc"}, commands)
+
+		err = cl.Get(ctx, req.NamespacedName, &cephv1.CephCrushRule{})
+		assert.True(t, kerrors.IsNotFound(err))
+	})
+
+	t.Run("rule not created by the operator not deleted", func(t *testing.T) {
+		commands = nil
+		crushMap = `{"rules":[{"rule_id":0,"rule_name":"replicated_rule"},{"rule_id":1,"rule_name":"manual"}]}`
+		notOwned := &cephv1.CephCrushRule{ObjectMeta: metav1.ObjectMeta{Name: "manual", Namespace: namespace}, Status: &cephv1.CrushRuleStatus{Phase: cephv1.ConditionFailure}}
+		assert.NoError(t, r.deleteCrushRule(notOwned))
+		assert.Empty(t, commands)
+	})
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package crushrule
+
+import (
+	"fmt"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/util/dependents"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// cephCrushRuleDependents returns the pools which use the crush rule and should block its deletion
+func cephCrushRuleDependents(clusterdCtx *clusterd.Context, clusterInfo *cephclient.ClusterInfo, crushRule *cephv1.CephCrushRule) (*dependents.DependentList, error) {
+	nsName := fmt.Sprintf("%s/%s", crushRule.Namespace, crushRule.Name)
+	baseErrMsg := fmt.Sprintf("failed to get dependents of CephCrushRule %q", nsName)
+
+	deps := dependents.NewDependentList()
+
+	// CephBlockPools
+	blockPools, err := clusterdCtx.RookClientset.CephV1().CephBlockPools(crushRule.Namespace).List(clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		return deps, errors.Wrapf(err, "%s. failed to list CephBlockPools", baseErrMsg)
+	}
+	for _, blockPool := range blockPools.Items {
+		if blockPool.Spec.CrushRule == crushRule.Name {
+			deps.Add("CephBlockPools", blockPool.Name)
+		}
+	}
+
+	// CephFilesystems
+	filesystems, err := clusterdCtx.RookClientset.CephV1().CephFilesystems(crushRule.Namespace).List(clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		return deps, errors.Wrapf(err, "%s. failed to list CephFilesystems", baseErrMsg)
+	}
+	for _, filesystem := range filesystems.Items {
+		pools := []cephv1.PoolSpec{filesystem.Spec.MetadataPool}
+		for _, dataPool := range filesystem.Spec.DataPools {
+			pools = append(pools, dataPool.PoolSpec)
+		}
+		if usesCrushRule(pools, crushRule.Name) {
+			deps.Add("CephFilesystems", filesystem.Name)
+		}
+	}
+
+	// CephObjectStores
+	objectStores, err := clusterdCtx.RookClientset.CephV1().CephObjectStores(crushRule.Namespace).List(clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		return deps, errors.Wrapf(err, "%s. failed to list CephObjectStores", baseErrMsg)
+	}
+	for _, objectStore := range objectStores.Items {
+		if usesCrushRule([]cephv1.PoolSpec{objectStore.Spec.MetadataPool, objectStore.Spec.DataPool}, crushRule.Name) {
+			deps.Add("CephObjectStores", objectStore.Name)
+		}
+	}
+
+	return deps, nil
+}
+
+func usesCrushRule(pools []cephv1.PoolSpec, crushRuleName string) bool {
+	for _, pool := range pools {
+		if pool.CrushRule == crushRuleName {
+			return true
+		}
+	}
+	return false
+}
@@ -66,7 +66,7 @@ func (c *mirrorChecker) checkMirroring(context context.Context) {
 	// check the mirroring health immediately before starting the loop
 	err := c.checkMirroringHealth()
 	if err != nil {
-		c.updateStatusMirroring(nil, nil, nil, err.Error())
+		c.updateStatusMirroring(nil, nil, nil, err.Error(), nil)
 		logger.Debugf("failed to check pool mirroring status for ceph block pool %q. %v", c.namespacedName.Name, err)
 	}
 
@@ -80,7 +80,7 @@ func (c *mirrorChecker) checkMirroring(context context.Context) {
 			logger.Debugf("checking pool mirroring status %q", c.namespacedName.Name)
 			err := c.checkMirroringHealth()
 			if err != nil {
-				c.updateStatusMirroring(nil, nil, nil, err.Error())
+				c.updateStatusMirroring(nil, nil, nil, err.Error(), nil)
 				logger.Debugf("failed to check pool mirroring status for ceph block pool %q. %v", c.namespacedName.Name, err)
 			}
 		}
@@ -88,16 +88,18 @@ func (c *mirrorChecker) checkMirroring(context context.Context) {
 }
 
 func (c *mirrorChecker) checkMirroringHealth() error {
-	// Check mirroring status
-	mirrorStatus, err := cephclient.GetPoolMirroringStatus(c.context, c.clusterInfo, c.poolSpec.Name)
+	// Check mirroring status of the pool and of its images
+	mirrorStatus, err := cephclient.GetPoolMirroredImagesStatus(c.context, c.clusterInfo, c.poolSpec.Name)
 	if err != nil {
-		c.updateStatusMirroring(nil, nil, nil, err.Error())
+		return err
 	}
+	images := mirroredImagesStatus(mirrorStatus.Images)
+	updateMirroredImagesMetrics(c.namespacedName.Namespace, c.poolSpec.Name, images)
 
 	// Check mirroring info
 	mirrorInfo, err := cephclient.GetPoolMirroringInfo(c.context, c.clusterInfo, c.poolSpec.Name)
 	if err != nil {
-		c.updateStatusMirroring(nil, nil, nil, err.Error())
+		c.updateStatusMirroring(nil, nil, nil, err.Error(), nil)
 	}
 
 	// If snapshot scheduling is enabled let's add it to the status
@@ -106,12 +108,12 @@ func (c *mirrorChecker) checkMirroringHealth() error {
 	if c.poolSpec.Mirroring.SnapshotSchedulesEnabled() {
 		snapSchedStatus, err = cephclient.ListSnapshotSchedulesRecursively(c.context, c.clusterInfo, c.poolSpec.Name)
 		if err != nil {
-			c.updateStatusMirroring(nil, nil, nil, err.Error())
+			c.updateStatusMirroring(nil, nil, nil, err.Error(), nil)
 		}
 	}
 
 	// On success
-	c.updateStatusMirroring(mirrorStatus.Summary, mirrorInfo, snapSchedStatus, "")
+	c.updateStatusMirroring(mirrorStatus.Summary, mirrorInfo, snapSchedStatus, "", images)
 
 	return nil
 }
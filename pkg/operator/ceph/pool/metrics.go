@@ -0,0 +1,170 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"sigs.k8s.io/controller-runtime/pkg/metrics"
+)
+
+// maxWorstMirroredImages is the maximum number of mirrored images reported in the pool status
+const maxWorstMirroredImages = 10
+
+var (
+	mirroredImageLabels = []string{"namespace", "pool", "image"}
+
+	// reportedImageStates is the state of the images reported in the metrics, by pool and image
+	reportedImageStates       = map[string]map[string]string{}
+	mirroredImagesMetricsLock sync.Mutex
+
+	mirroredImageState = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "rook_ceph",
+		Subsystem: "rbd_mirror",
+		Name:      "image_state",
+		Help:      "Mirroring state of a mirrored image, the value is always 1",
+	}, append(mirroredImageLabels, "state"))
+	mirroredImageLastSync = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "rook_ceph",
+		Subsystem: "rbd_mirror",
+		Name:      "image_last_sync_timestamp_seconds",
+		Help:      "Time of the last snapshot synced to the non-primary image",
+	}, mirroredImageLabels)
+	mirroredImageLag = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "rook_ceph",
+		Subsystem: "rbd_mirror",
+		Name:      "image_lag_seconds",
+		Help:      "Time between the last snapshot of the primary image and the last snapshot synced to the non-primary image",
+	}, mirroredImageLabels)
+	mirroredImageBytesBehind = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "rook_ceph",
+		Subsystem: "rbd_mirror",
+		Name:      "image_bytes_behind",
+		Help:      "Estimated number of bytes of the snapshot being synced that remain to be synced",
+	}, mirroredImageLabels)
+	mirroredImageEntriesBehind = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Namespace: "rook_ceph",
+		Subsystem: "rbd_mirror",
+		Name:      "image_entries_behind",
+		Help:      "Number of journal entries the non-primary image is behind the primary image",
+	}, mirroredImageLabels)
+)
+
+func init() {
+	metrics.Registry.MustRegister(mirroredImageState, mirroredImageLastSync, mirroredImageLag, mirroredImageBytesBehind, mirroredImageEntriesBehind)
+}
+
+// mirroredImagesStatus returns the replication status of the mirrored images, sorted from the most
+// behind to the least behind with the images in error first
+func mirroredImagesStatus(images []cephclient.MirroredImageStatus) []cephv1.MirroredImageStatusSpec {
+	statuses := make([]cephv1.MirroredImageStatusSpec, 0, len(images))
+	for i := range images {
+		image := &images[i]
+		status := cephv1.MirroredImageStatusSpec{Name: image.Name, State: image.ReplicationState()}
+		if replay := image.ReplayStatus(); replay != nil {
+			status.ReplayState = replay.ReplayState
+			if replay.LocalSnapshotTimestamp > 0 {
+				status.LastSyncTime = time.Unix(replay.LocalSnapshotTimestamp, 0).UTC().Format(time.RFC3339)
+				if replay.RemoteSnapshotTimestamp > replay.LocalSnapshotTimestamp {
+					status.LagSeconds = replay.RemoteSnapshotTimestamp - replay.LocalSnapshotTimestamp
+				}
+			}
+			if replay.SyncingSnapshotTimestamp > 0 && replay.SyncingPercent < 100 {
+				status.BytesBehind = int64(replay.BytesPerSnapshot * (100 - replay.SyncingPercent) / 100)
+			}
+			status.EntriesBehind = replay.EntriesBehindPrimary
+		}
+		statuses = append(statuses, status)
+	}
+
+	sort.SliceStable(statuses, func(i, j int) bool {
+		iError, jError := strings.HasSuffix(statuses[i].State, "+error"), strings.HasSuffix(statuses[j].State, "+error")
+		if iError != jError {
+			return iError
+		}
+		if statuses[i].LagSeconds != statuses[j].LagSeconds {
+			return statuses[i].LagSeconds > statuses[j].LagSeconds
+		}
+		if statuses[i].BytesBehind != statuses[j].BytesBehind {
+			return statuses[i].BytesBehind > statuses[j].BytesBehind
+		}
+		return statuses[i].EntriesBehind > statuses[j].EntriesBehind
+	})
+
+	return statuses
+}
+
+// updateMirroredImagesMetrics exports the replication status of the mirrored images of a pool. The
+// series of the images are updated in place, only the series of the images that are no longer
+// mirrored and of the previous state of the images are removed.
+func updateMirroredImagesMetrics(namespace, poolName string, images []cephv1.MirroredImageStatusSpec) {
+	mirroredImagesMetricsLock.Lock()
+	defer mirroredImagesMetricsLock.Unlock()
+
+	poolKey := namespace + "/" + poolName
+	previousStates := reportedImageStates[poolKey]
+	states := make(map[string]string, len(images))
+	for _, image := range images {
+		labels := prometheus.Labels{"namespace": namespace, "pool": poolName, "image": image.Name}
+		if previous, ok := previousStates[image.Name]; ok && previous != image.State {
+			mirroredImageState.MustCurryWith(labels).DeleteLabelValues(previous)
+		}
+		mirroredImageState.MustCurryWith(labels).WithLabelValues(image.State).Set(1)
+		states[image.Name] = image.State
+
+		lastSync, err := time.Parse(time.RFC3339, image.LastSyncTime)
+		if image.LastSyncTime != "" && err == nil {
+			mirroredImageLastSync.With(labels).Set(float64(lastSync.Unix()))
+		} else {
+			mirroredImageLastSync.Delete(labels)
+		}
+		mirroredImageLag.With(labels).Set(float64(image.LagSeconds))
+		mirroredImageBytesBehind.With(labels).Set(float64(image.BytesBehind))
+		mirroredImageEntriesBehind.With(labels).Set(float64(image.EntriesBehind))
+	}
+
+	// the metrics of the images that are no longer mirrored are removed
+	for image := range previousStates {
+		if _, ok := states[image]; !ok {
+			deleteMetricsMatching(prometheus.Labels{"namespace": namespace, "pool": poolName, "image": image})
+		}
+	}
+	reportedImageStates[poolKey] = states
+}
+
+// deleteMirroredImagesMetrics removes the metrics of the mirrored images of a pool
+func deleteMirroredImagesMetrics(namespace, poolName string) {
+	mirroredImagesMetricsLock.Lock()
+	defer mirroredImagesMetricsLock.Unlock()
+
+	delete(reportedImageStates, namespace+"/"+poolName)
+	deleteMetricsMatching(prometheus.Labels{"namespace": namespace, "pool": poolName})
+}
+
+func deleteMetricsMatching(labels prometheus.Labels) {
+	mirroredImageState.DeletePartialMatch(labels)
+	mirroredImageLastSync.DeletePartialMatch(labels)
+	mirroredImageLag.DeletePartialMatch(labels)
+	mirroredImageBytesBehind.DeletePartialMatch(labels)
+	mirroredImageEntriesBehind.DeletePartialMatch(labels)
+}
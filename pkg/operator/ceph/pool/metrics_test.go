@@ -0,0 +1,80 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMirroredImagesStatus(t *testing.T) {
+	images := []cephclient.MirroredImageStatus{
+		{Name: "synced", State: "up+replaying", Description: `replaying, {"local_snapshot_timestamp":1709287200,"remote_snapshot_timestamp":1709287200,"replay_state":"idle"}`},
+		{Name: "behind", State: "up+replaying", Description: `replaying, {"bytes_per_snapshot":4096.0,"local_snapshot_timestamp":1709287200,"remote_snapshot_timestamp":1709287500,"replay_state":"syncing","syncing_percent":25,"syncing_snapshot_timestamp":1709287500}`},
+		{Name: "journal", State: "up+replaying", Description: `replaying, {"entries_behind_primary":12}`},
+		{Name: "error", State: "up+error", Description: "split-brain"},
+	}
+
+	statuses := mirroredImagesStatus(images)
+	assert.Equal(t, 4, len(statuses))
+	assert.Equal(t, "error", statuses[0].Name)
+	assert.Equal(t, "behind", statuses[1].Name)
+	assert.Equal(t, "journal", statuses[2].Name)
+	assert.Equal(t, "synced", statuses[3].Name)
+
+	behind := statuses[1]
+	assert.Equal(t, "syncing", behind.ReplayState)
+	assert.Equal(t, "2024-03-01T10:00:00Z", behind.LastSyncTime)
+	assert.Equal(t, int64(300), behind.LagSeconds)
+	assert.Equal(t, int64(3072), behind.BytesBehind)
+	assert.Equal(t, int64(12), statuses[2].EntriesBehind)
+	assert.Equal(t, int64(0), statuses[3].LagSeconds)
+}
+
+func TestUpdateMirroredImagesMetrics(t *testing.T) {
+	images := mirroredImagesStatus([]cephclient.MirroredImageStatus{
+		{Name: "img1", State: "up+replaying", Description: `replaying, {"local_snapshot_timestamp":1709287200,"remote_snapshot_timestamp":1709287260}`},
+		{Name: "img2", State: "up+error", Description: "split-brain"},
+	})
+
+	updateMirroredImagesMetrics("rook-ceph", "replicapool", images)
+	assert.Equal(t, 2, testutil.CollectAndCount(mirroredImageState))
+	assert.Equal(t, 60.0, testutil.ToFloat64(mirroredImageLag.WithLabelValues("rook-ceph", "replicapool", "img1")))
+	assert.Equal(t, 1709287200.0, testutil.ToFloat64(mirroredImageLastSync.WithLabelValues("rook-ceph", "replicapool", "img1")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(mirroredImageState.WithLabelValues("rook-ceph", "replicapool", "img2", "up+error")))
+
+	// the state of an image is updated in place, the images in error are sorted first
+	assert.Equal(t, "img2", images[0].Name)
+	images[0].State = "up+replaying"
+	updateMirroredImagesMetrics("rook-ceph", "replicapool", images)
+	assert.Equal(t, 2, testutil.CollectAndCount(mirroredImageState))
+	assert.Equal(t, 2, testutil.CollectAndCount(mirroredImageLag))
+	assert.Equal(t, 1.0, testutil.ToFloat64(mirroredImageState.WithLabelValues("rook-ceph", "replicapool", "img2", "up+replaying")))
+
+	// the metrics of the images that are no longer mirrored are removed
+	updateMirroredImagesMetrics("rook-ceph", "replicapool", images[:1])
+	assert.Equal(t, 1, testutil.CollectAndCount(mirroredImageState))
+	assert.Equal(t, 1, testutil.CollectAndCount(mirroredImageLag))
+	assert.Equal(t, 0, testutil.CollectAndCount(mirroredImageLastSync))
+
+	deleteMirroredImagesMetrics("rook-ceph", "replicapool")
+	assert.Equal(t, 0, testutil.CollectAndCount(mirroredImageState))
+	assert.Equal(t, 0, testutil.CollectAndCount(mirroredImageLag))
+}
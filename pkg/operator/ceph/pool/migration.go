@@ -0,0 +1,617 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// maxReportedInUseImages is the maximum number of images in use reported in the migration status
+	maxReportedInUseImages = 10
+	// rbdProvisionerSuffix is the suffix of the provisioner of the rbd storage classes
+	rbdProvisionerSuffix = "rbd.csi.ceph.com"
+	// defaultReplicatedCrushRule is the crush rule created by ceph for the replicated pools
+	defaultReplicatedCrushRule = "replicated_rule"
+)
+
+// migrationCheckInterval is the interval at which the progress of the migration of a pool is checked
+var migrationCheckInterval = time.Minute
+
+// poolMigration tracks the goroutine migrating a pool
+type poolMigration struct {
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+func (m *poolMigration) finished() bool {
+	select {
+	case <-m.done:
+		return true
+	default:
+		return false
+	}
+}
+
+// poolMigrator migrates the data of a pool to a new pool with the layout of the spec. The new pool
+// is created with a temporary name, the data of the RBD images is live-migrated to the new pool,
+// then the new pool takes the name of the pool and the previous pool is deleted once the images
+// created in the meantime are migrated as well. The images of a replicated pool migrated to an
+// erasure coded layout stay in the pool, only their data is migrated to a new erasure coded data
+// pool, which is replaced the same way by the next migrations.
+type poolMigrator struct {
+	context        *clusterd.Context
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	clusterSpec    *cephv1.ClusterSpec
+	namespacedName types.NamespacedName
+	// pool is the pool replaced by the migration with the layout of the spec, either the pool of
+	// the CephBlockPool or its erasure coded data pool
+	pool cephv1.NamedPoolSpec
+	// imagesPool is the pool of the CephBlockPool, which stores the metadata of its images
+	imagesPool string
+	status     cephv1.PoolMigrationStatus
+}
+
+// reconcileMigration starts or resumes the migration of the pool if its layout changed and returns
+// whether the pool is being migrated
+func (r *ReconcileCephBlockPool) reconcileMigration(clusterSpec *cephv1.ClusterSpec, cephBlockPool *cephv1.CephBlockPool, namespacedName types.NamespacedName) (bool, error) {
+	key := blockPoolChannelKeyName(cephBlockPool)
+	if migration, ok := r.poolMigrations[key]; ok {
+		if !migration.finished() {
+			return true, nil
+		}
+		delete(r.poolMigrations, key)
+	}
+
+	poolSpec := cephBlockPool.ToNamedPoolSpec()
+	var status *cephv1.PoolMigrationStatus
+	if cephBlockPool.Status != nil {
+		status = cephBlockPool.Status.MigrationStatus
+	}
+
+	// a migration in progress is always resumed until the pool is migrated
+	if status == nil || status.Phase == cephv1.ConditionReady {
+		dataPool := migrationDataPool(cephBlockPool)
+		layoutPool := poolSpec
+		if dataPool != "" {
+			layoutPool.Name = dataPool
+		}
+		changed, sourceLayout, err := poolLayoutChanged(r.context, r.clusterInfo, layoutPool)
+		if err != nil {
+			return false, errors.Wrapf(err, "failed to check the layout of pool %q", layoutPool.Name)
+		}
+		if !changed {
+			return false, nil
+		}
+		targetLayout := poolLayout(poolSpec.PoolSpec)
+		if cephBlockPool.Spec.Migration == nil || !cephBlockPool.Spec.Migration.Enabled {
+			logger.Warningf("layout of pool %q changed from %s to %s, but the pool is not migrated since the migration is not enabled", poolSpec.Name, sourceLayout, targetLayout)
+			return false, nil
+		}
+		if poolSpec.Application != "" && poolSpec.Application != poolApplicationNameRBD {
+			return false, errors.Errorf("pool %q cannot be migrated to %s since only the pools of RBD images can be migrated", poolSpec.Name, targetLayout)
+		}
+
+		status = &cephv1.PoolMigrationStatus{
+			Phase:        cephv1.ConditionProgressing,
+			SourceLayout: sourceLayout,
+			TargetLayout: targetLayout,
+			TargetPool:   migrationTargetPoolName(layoutPool.Name),
+			DataPool:     dataPool,
+			StartTime:    time.Now().UTC().Format(time.RFC3339),
+		}
+		if dataPool != "" && !poolSpec.IsErasureCoded() {
+			// the data of the images is migrated back to the pool, then the data pool is deleted
+			status.TargetPool = poolSpec.Name
+		} else if dataPool == "" && poolSpec.IsErasureCoded() {
+			images, err := cephclient.ListImagesByDataPool(r.context, r.clusterInfo, []string{poolSpec.Name})
+			if err != nil {
+				return false, errors.Wrapf(err, "failed to list the images of pool %q", poolSpec.Name)
+			}
+			if len(images) > 0 {
+				// an erasure coded pool cannot store the metadata of the images, they stay in the
+				// pool and their data is migrated to an erasure coded data pool
+				status.DataPool = migrationDataPoolName(poolSpec.Name)
+				status.TargetPool = status.DataPool
+			}
+		}
+		logger.Infof("migrating pool %q from %s to %s to pool %q", layoutPool.Name, sourceLayout, targetLayout, status.TargetPool)
+		updateStatusMigration(r.opManagerContext, r.client, namespacedName, status)
+	}
+
+	ctx, cancel := context.WithCancel(r.opManagerContext)
+	migration := &poolMigration{cancel: cancel, done: make(chan struct{})}
+	r.poolMigrations[key] = migration
+	layoutPool := poolSpec
+	if status.DataPool != "" && status.TargetPool != status.DataPool {
+		layoutPool.Name = status.DataPool
+	}
+	migrator := &poolMigrator{
+		context:        r.context,
+		client:         r.client,
+		clusterInfo:    r.clusterInfo,
+		clusterSpec:    clusterSpec,
+		namespacedName: namespacedName,
+		pool:           layoutPool,
+		imagesPool:     poolSpec.Name,
+		status:         *status,
+	}
+	go func() {
+		defer close(migration.done)
+		migrator.migrate(ctx)
+	}()
+
+	return true, nil
+}
+
+// cancelMigration stops the migration of the pool and deletes the pools created by the migration,
+// unless they store RBD images or their data. The previous pool of an unfinished migration is never
+// deleted since it stores the data of the images that were not migrated yet.
+func (r *ReconcileCephBlockPool) cancelMigration(cephBlockPool *cephv1.CephBlockPool) error {
+	key := blockPoolChannelKeyName(cephBlockPool)
+	if migration, ok := r.poolMigrations[key]; ok {
+		migration.cancel()
+		<-migration.done
+		delete(r.poolMigrations, key)
+	}
+
+	if cephBlockPool.Status == nil || cephBlockPool.Status.MigrationStatus == nil {
+		return nil
+	}
+	status := cephBlockPool.Status.MigrationStatus
+	if status.SourcePool != "" {
+		logger.Warningf("pool %q of the unfinished migration of pool %q is not deleted since it stores the data of the images that were not migrated yet, it must be deleted manually", status.SourcePool, cephBlockPool.Name)
+	}
+
+	createdPools := []string{}
+	if status.TargetPool != "" && status.TargetPool != cephBlockPool.Name {
+		createdPools = append(createdPools, status.TargetPool)
+	}
+	if status.DataPool != "" && status.DataPool != status.TargetPool {
+		createdPools = append(createdPools, status.DataPool)
+	}
+	for _, poolName := range createdPools {
+		hasData, err := cephclient.PoolHasRBDData(r.context, r.clusterInfo, poolName)
+		if err != nil {
+			return errors.Wrapf(err, "failed to check whether pool %q stores rbd images", poolName)
+		}
+		if hasData {
+			logger.Warningf("pool %q created by the migration of pool %q is not deleted since it stores rbd images or their data, it must be deleted manually", poolName, cephBlockPool.Name)
+			continue
+		}
+		logger.Infof("deleting pool %q created by the migration of pool %q", poolName, cephBlockPool.Name)
+		if err := deletePool(r.context, r.clusterInfo, &cephv1.NamedPoolSpec{Name: poolName}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrate runs the migration until the pool is migrated, or until images in use prevent it to
+// continue, in which case it is resumed by the next reconcile
+func (m *poolMigrator) migrate(ctx context.Context) {
+	err := m.run(ctx)
+	if ctx.Err() != nil {
+		logger.Infof("migration of pool %q canceled", m.pool.Name)
+		return
+	}
+	if err != nil {
+		logger.Errorf("failed to migrate pool %q. %v", m.pool.Name, err)
+		m.status.Phase = cephv1.ConditionFailure
+		m.status.Details = err.Error()
+	}
+	m.updateStatus()
+}
+
+func (m *poolMigrator) run(ctx context.Context) error {
+	m.status.Phase = cephv1.ConditionProgressing
+	m.status.Details = ""
+
+	switch {
+	case m.status.DataPool != "" && m.status.TargetPool == m.status.DataPool:
+		return m.createDataPool(ctx)
+	case m.status.DataPool != "" && m.status.TargetPool == m.imagesPool:
+		return m.removeDataPool(ctx)
+	}
+	return m.replacePool(ctx)
+}
+
+// replacePool migrates the data of the images to a new pool that replaces the pool
+func (m *poolMigrator) replacePool(ctx context.Context) error {
+	if m.status.SourcePool == "" {
+		target := m.pool
+		target.Name = m.status.TargetPool
+		if err := createPool(m.context, m.clusterInfo, m.clusterSpec, &target); err != nil {
+			return errors.Wrapf(err, "failed to create the target pool %q", target.Name)
+		}
+
+		migrated, err := m.migrateImages(ctx, m.pool.Name, target.Name)
+		if err != nil || !migrated {
+			return err
+		}
+
+		if err := m.swapPools(); err != nil {
+			return err
+		}
+	}
+
+	// the images created with the previous pool as data pool until the pools were swapped are
+	// migrated before the previous pool is deleted
+	migrated, err := m.migrateImages(ctx, m.status.SourcePool, m.pool.Name)
+	if err != nil || !migrated {
+		return err
+	}
+
+	sourceRule, err := m.deleteMigratedPool(m.status.SourcePool)
+	if err != nil {
+		return err
+	}
+	m.replaceCrushRule(sourceRule)
+
+	m.status.SourcePool = ""
+	m.completed()
+	return nil
+}
+
+// createDataPool creates the erasure coded data pool of the images of the pool and migrates the
+// data of the images to it. The images stay in the pool, which keeps its replicated layout.
+func (m *poolMigrator) createDataPool(ctx context.Context) error {
+	dataPool := m.pool
+	dataPool.Name = m.status.DataPool
+	if err := createPool(m.context, m.clusterInfo, m.clusterSpec, &dataPool); err != nil {
+		return errors.Wrapf(err, "failed to create the data pool %q", dataPool.Name)
+	}
+
+	migrated, err := m.migrateImages(ctx, m.imagesPool, dataPool.Name)
+	if err != nil || !migrated {
+		return err
+	}
+
+	m.status.TargetPool = ""
+	m.completed()
+	return nil
+}
+
+// removeDataPool migrates the data of the images back to the pool and deletes the data pool
+func (m *poolMigrator) removeDataPool(ctx context.Context) error {
+	migrated, err := m.migrateImages(ctx, m.status.DataPool, m.imagesPool)
+	if err != nil || !migrated {
+		return err
+	}
+
+	if _, err := m.deleteMigratedPool(m.status.DataPool); err != nil {
+		return err
+	}
+
+	m.status.TargetPool = ""
+	m.status.DataPool = ""
+	m.completed()
+	return nil
+}
+
+func (m *poolMigrator) completed() {
+	logger.Infof("successfully migrated pool %q to %s", m.pool.Name, m.status.TargetLayout)
+	m.status.Phase = cephv1.ConditionReady
+	m.status.CompletionTime = time.Now().UTC().Format(time.RFC3339)
+}
+
+// deleteMigratedPool deletes a pool once the data of the images has been migrated out of it and
+// returns the crush rule the pool used. The pool is not deleted if it still stores rbd images or
+// their data, such as the data of images that are not in the pools known to use it.
+func (m *poolMigrator) deleteMigratedPool(poolName string) (string, error) {
+	exists, err := poolExists(m.context, m.clusterInfo, poolName)
+	if err != nil || !exists {
+		return "", err
+	}
+
+	hasData, err := cephclient.PoolHasRBDData(m.context, m.clusterInfo, poolName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to check whether pool %q stores rbd images", poolName)
+	}
+	if hasData {
+		return "", errors.Errorf("pool %q still stores rbd images or their data after the migration, the images of the pools that are not the pool of the CephBlockPool or of a storage class using it as data pool must be migrated manually", poolName)
+	}
+
+	details, err := cephclient.GetPoolDetails(m.context, m.clusterInfo, poolName)
+	if err != nil {
+		return "", errors.Wrapf(err, "failed to get the details of pool %q", poolName)
+	}
+	if err := deletePool(m.context, m.clusterInfo, &cephv1.NamedPoolSpec{Name: poolName}); err != nil {
+		return "", errors.Wrapf(err, "failed to delete the migrated pool %q", poolName)
+	}
+	return details.CrushRule, nil
+}
+
+// replaceCrushRule deletes the crush rule of the previous pool and gives its name to the crush rule
+// of the new pool, which is named after the temporary name of the new pool. A failure to clean up
+// the crush rules does not fail the migration.
+func (m *poolMigrator) replaceCrushRule(sourceRule string) {
+	if sourceRule != "" && sourceRule != m.pool.CrushRule && sourceRule != defaultReplicatedCrushRule {
+		// ceph refuses to delete a rule still used by another pool
+		if err := cephclient.DeleteCrushRule(m.context, m.clusterInfo, sourceRule); err != nil {
+			logger.Warningf("failed to delete the crush rule %q of the migrated pool %q. %v", sourceRule, m.pool.Name, err)
+		}
+	}
+
+	details, err := cephclient.GetPoolDetails(m.context, m.clusterInfo, m.pool.Name)
+	if err != nil {
+		logger.Warningf("failed to get the crush rule of pool %q. %v", m.pool.Name, err)
+		return
+	}
+	targetPool := migrationTargetPoolName(m.pool.Name)
+	if !strings.HasPrefix(details.CrushRule, targetPool) {
+		return
+	}
+	ruleName := m.pool.Name + strings.TrimPrefix(details.CrushRule, targetPool)
+	exists, err := cephclient.CrushRuleExists(m.context, m.clusterInfo, ruleName)
+	if err != nil {
+		logger.Warningf("failed to check whether crush rule %q exists. %v", ruleName, err)
+		return
+	}
+	if exists {
+		logger.Warningf("crush rule %q of pool %q is not renamed to %q since the rule already exists", details.CrushRule, m.pool.Name, ruleName)
+		return
+	}
+	if err := cephclient.RenameCrushRule(m.context, m.clusterInfo, details.CrushRule, ruleName); err != nil {
+		logger.Warningf("failed to rename the crush rule of pool %q. %v", m.pool.Name, err)
+	}
+}
+
+// migrateImages migrates the images with their data in a pool to another pool and returns whether
+// all the images were migrated
+func (m *poolMigrator) migrateImages(ctx context.Context, fromPool, toPool string) (bool, error) {
+	imagePools, err := m.imagePools(fromPool, toPool, m.pool.Name)
+	if err != nil {
+		return false, err
+	}
+	images, err := cephclient.ListImagesByDataPool(m.context, m.clusterInfo, imagePools)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list the images to migrate")
+	}
+
+	// the data pool of an image is already the target pool once its migration is prepared
+	pending := []string{}
+	for _, imageSpec := range images[toPool] {
+		status, err := cephclient.GetImageStatus(m.context, m.clusterInfo, imageSpec)
+		if err != nil {
+			return false, err
+		}
+		if status.Migration != nil {
+			pending = append(pending, imageSpec)
+		}
+	}
+	pending = append(pending, images[fromPool]...)
+
+	m.status.ImageCount = m.status.MigratedCount + len(pending)
+	m.status.InUseImages = nil
+	m.updateStatus()
+
+	inUse := []string{}
+	for _, imageSpec := range pending {
+		if ctx.Err() != nil {
+			return false, ctx.Err()
+		}
+		status, err := cephclient.GetImageStatus(m.context, m.clusterInfo, imageSpec)
+		if err != nil {
+			return false, err
+		}
+		if status.Migration == nil && len(status.Watchers) > 0 {
+			logger.Infof("image %q of pool %q is not migrated while it is in use", imageSpec, m.pool.Name)
+			inUse = append(inUse, imageSpec)
+			continue
+		}
+		if err := cephclient.MigrateImageDataPool(m.context, m.clusterInfo, imageSpec, toPool); err != nil {
+			return false, err
+		}
+		m.status.MigratedCount++
+		m.updateStatus()
+	}
+
+	if len(inUse) > 0 {
+		m.status.Details = fmt.Sprintf("%d images cannot be migrated until they are no longer in use", len(inUse))
+		if len(inUse) > maxReportedInUseImages {
+			inUse = inUse[:maxReportedInUseImages]
+		}
+		m.status.InUseImages = inUse
+		return false, nil
+	}
+	return true, nil
+}
+
+// imagePools returns the pools whose images may store their data in the given pools: the pool of
+// the CephBlockPool and the pools of the rbd storage classes using one of the pools as data pool.
+// Only the images of these pools are inspected, not all the images of the cluster.
+func (m *poolMigrator) imagePools(dataPools ...string) ([]string, error) {
+	storageClasses, err := m.context.Clientset.StorageV1().StorageClasses().List(m.clusterInfo.Context, metav1.ListOptions{})
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to list the storage classes")
+	}
+
+	pools := []string{m.imagesPool}
+	for _, storageClass := range storageClasses.Items {
+		if !strings.HasSuffix(storageClass.Provisioner, rbdProvisionerSuffix) {
+			continue
+		}
+		pool, dataPool := storageClass.Parameters["pool"], storageClass.Parameters["dataPool"]
+		if pool == "" || !slices.Contains(dataPools, dataPool) || slices.Contains(pools, pool) {
+			continue
+		}
+		// the storage classes of other clusters may have the same pool names
+		exists, err := poolExists(m.context, m.clusterInfo, pool)
+		if err != nil {
+			return nil, err
+		}
+		if exists {
+			pools = append(pools, pool)
+		}
+	}
+	return pools, nil
+}
+
+// swapPools renames the pool to the source pool name and the target pool to the pool name. New
+// images are created in the target pool from then on.
+func (m *poolMigrator) swapPools() error {
+	sourcePool := migrationSourcePoolName(m.pool.Name)
+	pools, err := cephclient.ListPoolSummaries(m.context, m.clusterInfo)
+	if err != nil {
+		return errors.Wrap(err, "failed to list pools")
+	}
+	exists := map[string]bool{}
+	for _, pool := range pools {
+		exists[pool.Name] = true
+	}
+
+	if !exists[sourcePool] {
+		if err := cephclient.RenamePool(m.context, m.clusterInfo, m.pool.Name, sourcePool); err != nil {
+			return err
+		}
+	}
+	if exists[m.status.TargetPool] {
+		if err := cephclient.RenamePool(m.context, m.clusterInfo, m.status.TargetPool, m.pool.Name); err != nil {
+			return err
+		}
+	}
+
+	m.status.SourcePool = sourcePool
+	m.status.TargetPool = ""
+	m.updateStatus()
+	return nil
+}
+
+func (m *poolMigrator) updateStatus() {
+	m.status.LastChecked = time.Now().UTC().Format(time.RFC3339)
+	status := m.status
+	updateStatusMigration(m.clusterInfo.Context, m.client, m.namespacedName, &status)
+}
+
+// updateStatusMigration updates the migration status of the pool
+func updateStatusMigration(ctx context.Context, cl client.Client, namespacedName types.NamespacedName, status *cephv1.PoolMigrationStatus) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := cl.Get(ctx, namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update migration status. %v", namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	blockPool.Status.MigrationStatus = status
+	if err := reporting.UpdateStatus(cl, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q migration status. %v", namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("ceph block pool %q migration status updated", namespacedName.Name)
+}
+
+// poolLayoutChanged returns whether the layout of the pool differs from the layout of the spec,
+// and the current layout of the pool. A pool that does not exist yet has not changed.
+func poolLayoutChanged(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, pool cephv1.NamedPoolSpec) (bool, string, error) {
+	exists, err := poolExists(context, clusterInfo, pool.Name)
+	if err != nil || !exists {
+		return false, "", err
+	}
+
+	details, err := cephclient.GetPoolDetails(context, clusterInfo, pool.Name)
+	if err != nil {
+		return false, "", err
+	}
+	current := cephv1.PoolSpec{}
+	if details.ErasureCodeProfile == "" {
+		current.Replicated.Size = details.Size
+	} else {
+		profile, err := cephclient.GetErasureCodeProfileDetails(context, clusterInfo, details.ErasureCodeProfile)
+		if err != nil {
+			return false, "", err
+		}
+		current.ErasureCoded.DataChunks = profile.DataChunkCount
+		current.ErasureCoded.CodingChunks = profile.CodingChunkCount
+	}
+
+	return !sameLayout(current, pool.PoolSpec), poolLayout(current), nil
+}
+
+// sameLayout returns whether two pools have the same layout. A change of the replicated size is not
+// a change of layout since it is applied to the pool in place.
+func sameLayout(a, b cephv1.PoolSpec) bool {
+	if a.IsErasureCoded() != b.IsErasureCoded() {
+		return false
+	}
+	if !a.IsErasureCoded() {
+		return true
+	}
+	return a.ErasureCoded.DataChunks == b.ErasureCoded.DataChunks && a.ErasureCoded.CodingChunks == b.ErasureCoded.CodingChunks
+}
+
+func poolLayout(p cephv1.PoolSpec) string {
+	if p.IsErasureCoded() {
+		return fmt.Sprintf("erasure coded %d+%d", p.ErasureCoded.DataChunks, p.ErasureCoded.CodingChunks)
+	}
+	return fmt.Sprintf("replicated size %d", p.Replicated.Size)
+}
+
+func migrationTargetPoolName(poolName string) string {
+	return poolName + "-migration"
+}
+
+func migrationDataPoolName(poolName string) string {
+	return poolName + "-data"
+}
+
+// migrationDataPool returns the erasure coded data pool of the images of the pool, if any
+func migrationDataPool(cephBlockPool *cephv1.CephBlockPool) string {
+	if cephBlockPool.Status == nil || cephBlockPool.Status.MigrationStatus == nil {
+		return ""
+	}
+	return cephBlockPool.Status.MigrationStatus.DataPool
+}
+
+// poolExists returns whether a pool exists
+func poolExists(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string) (bool, error) {
+	pools, err := cephclient.ListPoolSummaries(context, clusterInfo)
+	if err != nil {
+		return false, errors.Wrap(err, "failed to list pools")
+	}
+	for _, pool := range pools {
+		if pool.Name == poolName {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func migrationSourcePoolName(poolName string) string {
+	return poolName + "-migration-source"
+}
@@ -0,0 +1,362 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	storagev1 "k8s.io/api/storage/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	k8sfake "k8s.io/client-go/kubernetes/fake"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestPoolLayoutChanged(t *testing.T) {
+	profile := ""
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "osd" && args[1] == "lspools":
+			return `[{"poolnum":1,"poolname":"ecpool"}]`, nil
+		case args[0] == "osd" && args[1] == "pool" && args[2] == "get":
+			if profile == "" {
+				return `{"pool":"ecpool","pool_id":1,"size":3}`, nil
+			}
+			return `{"pool":"ecpool","pool_id":1,"size":3}{"erasure_code_profile":"` + profile + `"}`, nil
+		case args[0] == "osd" && args[1] == "erasure-code-profile" && args[2] == "get":
+			assert.Equal(t, profile, args[3])
+			return `{"k":"2","m":"1","plugin":"jerasure","technique":"reed_sol_van"}`, nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+
+	replicated := cephv1.PoolSpec{Replicated: cephv1.ReplicatedSpec{Size: 2}}
+	ec21 := cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}}
+	ec42 := cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 4, CodingChunks: 2}}
+
+	t.Run("pool does not exist", func(t *testing.T) {
+		changed, _, err := poolLayoutChanged(context, clusterInfo, cephv1.NamedPoolSpec{Name: "newpool", PoolSpec: ec21})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+	})
+
+	t.Run("replicated size change", func(t *testing.T) {
+		changed, layout, err := poolLayoutChanged(context, clusterInfo, cephv1.NamedPoolSpec{Name: "ecpool", PoolSpec: replicated})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, "replicated size 3", layout)
+	})
+
+	t.Run("replicated to erasure coded", func(t *testing.T) {
+		changed, _, err := poolLayoutChanged(context, clusterInfo, cephv1.NamedPoolSpec{Name: "ecpool", PoolSpec: ec21})
+		assert.NoError(t, err)
+		assert.True(t, changed)
+	})
+
+	t.Run("erasure coded chunks", func(t *testing.T) {
+		profile = "ecpool_ecprofile"
+		changed, layout, err := poolLayoutChanged(context, clusterInfo, cephv1.NamedPoolSpec{Name: "ecpool", PoolSpec: ec21})
+		assert.NoError(t, err)
+		assert.False(t, changed)
+		assert.Equal(t, "erasure coded 2+1", layout)
+
+		changed, _, err = poolLayoutChanged(context, clusterInfo, cephv1.NamedPoolSpec{Name: "ecpool", PoolSpec: ec42})
+		assert.NoError(t, err)
+		assert.True(t, changed)
+
+		changed, _, err = poolLayoutChanged(context, clusterInfo, cephv1.NamedPoolSpec{Name: "ecpool", PoolSpec: replicated})
+		assert.NoError(t, err)
+		assert.True(t, changed)
+	})
+}
+
+func TestPoolMigratorMigrateImages(t *testing.T) {
+	pools := map[string]bool{"ecpool": true, "ecpool-migration": true}
+	dataPools := map[string]string{"img1": "ecpool", "img2": "ecpool", "img3": "ecpool-migration"}
+	watchers := map[string]bool{"img2": true}
+	var commands []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "osd" && args[1] == "lspools":
+			list := []string{`{"poolnum":1,"poolname":"replicapool"}`}
+			for pool := range pools {
+				list = append(list, `{"poolnum":2,"poolname":"`+pool+`"}`)
+			}
+			return "[" + strings.Join(list, ",") + "]", nil
+		case args[0] == "osd" && args[1] == "pool" && args[2] == "rename":
+			commands = append(commands, "rename "+args[3]+" "+args[4])
+			delete(pools, args[3])
+			pools[args[4]] = true
+			for image, pool := range dataPools {
+				if pool == args[3] {
+					dataPools[image] = args[4]
+				}
+			}
+			return "", nil
+		case args[0] == "namespace":
+			return `[]`, nil
+		case args[0] == "ls":
+			if args[2] != "replicapool" {
+				return `[]`, nil
+			}
+			return `[{"image":"img1"},{"image":"img2"},{"image":"img3"}]`, nil
+		case args[0] == "info":
+			return `{"data_pool":"` + dataPools[strings.TrimPrefix(args[1], "replicapool/")] + `"}`, nil
+		case args[0] == "status":
+			image := strings.TrimPrefix(args[1], "replicapool/")
+			if image == "img3" {
+				return `{"watchers":[{"address":"10.0.0.1:0/1"}],"migration":{"state":"executed"}}`, nil
+			}
+			if watchers[image] {
+				return `{"watchers":[{"address":"10.0.0.1:0/1"}]}`, nil
+			}
+			return `{"watchers":[]}`, nil
+		case args[0] == "migration":
+			commands = append(commands, args[1]+" "+args[2])
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+
+	nn := types.NamespacedName{Name: "ecpool", Namespace: "rook-ceph"}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace}, Status: &cephv1.CephBlockPoolStatus{}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephBlockPool).WithStatusSubresource(cephBlockPool).Build()
+	// the images of replicapool store their data in ecpool
+	storageClass := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "rook-ceph-block"},
+		Provisioner: "rook-ceph.rbd.csi.ceph.com",
+		Parameters:  map[string]string{"pool": "replicapool", "dataPool": "ecpool"},
+	}
+	otherStorageClass := &storagev1.StorageClass{
+		ObjectMeta:  metav1.ObjectMeta{Name: "other-block"},
+		Provisioner: "rook-ceph.rbd.csi.ceph.com",
+		Parameters:  map[string]string{"pool": "otherpool", "dataPool": "otherecpool"},
+	}
+	migrator := &poolMigrator{
+		context:        &clusterd.Context{Executor: executor, Clientset: k8sfake.NewSimpleClientset(storageClass, otherStorageClass)},
+		client:         cl,
+		clusterInfo:    cephclient.AdminTestClusterInfo("mycluster"),
+		namespacedName: nn,
+		pool:           cephv1.NamedPoolSpec{Name: "ecpool"},
+		imagesPool:     "ecpool",
+		status:         cephv1.PoolMigrationStatus{Phase: cephv1.ConditionProgressing, TargetPool: "ecpool-migration"},
+	}
+	getStatus := func() *cephv1.PoolMigrationStatus {
+		updated := &cephv1.CephBlockPool{}
+		assert.NoError(t, cl.Get(context.TODO(), nn, updated))
+		return updated.Status.MigrationStatus
+	}
+
+	t.Run("pools of the images", func(t *testing.T) {
+		pools, err := migrator.imagePools("ecpool", "ecpool-migration")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"ecpool", "replicapool"}, pools)
+	})
+
+	t.Run("images in use are not migrated", func(t *testing.T) {
+		migrated, err := migrator.migrateImages(context.TODO(), "ecpool", "ecpool-migration")
+		assert.NoError(t, err)
+		assert.False(t, migrated)
+		assert.Equal(t, []string{"commit replicapool/img3", "prepare replicapool/img1", "execute replicapool/img1", "commit replicapool/img1"}, commands)
+		status := getStatus()
+		assert.Equal(t, 3, status.ImageCount)
+		assert.Equal(t, 2, status.MigratedCount)
+		// the images in use are reported once the migration stops
+		assert.Equal(t, []string{"replicapool/img2"}, migrator.status.InUseImages)
+		assert.Contains(t, migrator.status.Details, "1 images cannot be migrated")
+	})
+
+	t.Run("all images migrated", func(t *testing.T) {
+		commands = nil
+		watchers = map[string]bool{}
+		dataPools = map[string]string{"img1": "ecpool-migration", "img2": "ecpool", "img3": "ecpool-migration"}
+		migrated, err := migrator.migrateImages(context.TODO(), "ecpool", "ecpool-migration")
+		assert.NoError(t, err)
+		assert.True(t, migrated)
+		assert.Equal(t, []string{"commit replicapool/img3", "prepare replicapool/img2", "execute replicapool/img2", "commit replicapool/img2"}, commands)
+		assert.Empty(t, migrator.status.InUseImages)
+	})
+
+	t.Run("swap the pools", func(t *testing.T) {
+		commands = nil
+		assert.NoError(t, migrator.swapPools())
+		assert.Equal(t, []string{"rename ecpool ecpool-migration-source", "rename ecpool-migration ecpool"}, commands)
+		status := getStatus()
+		assert.Equal(t, "ecpool-migration-source", status.SourcePool)
+		assert.Empty(t, status.TargetPool)
+
+		// the pools are not renamed again if the swap is retried
+		commands = nil
+		migrator.status.TargetPool = "ecpool-migration"
+		assert.NoError(t, migrator.swapPools())
+		assert.Empty(t, commands)
+	})
+}
+
+func TestReconcileMigrationNotEnabled(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "osd" && args[1] == "lspools":
+			return `[{"poolnum":1,"poolname":"replicapool"}]`, nil
+		case args[0] == "osd" && args[1] == "pool" && args[2] == "get":
+			return `{"pool":"replicapool","pool_id":1,"size":3}`, nil
+		case args[0] == "namespace" && args[1] == "ls":
+			return `[]`, nil
+		case args[0] == "ls":
+			return `[{"image":"img1"}]`, nil
+		case args[0] == "info":
+			return `{"name":"img1"}`, nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+
+	nn := types.NamespacedName{Name: "replicapool", Namespace: "rook-ceph"}
+	cephBlockPool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+		Spec: cephv1.NamedBlockPoolSpec{
+			PoolSpec: cephv1.PoolSpec{ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}},
+		},
+		Status: &cephv1.CephBlockPoolStatus{},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephBlockPool).WithStatusSubresource(cephBlockPool).Build()
+	r := &ReconcileCephBlockPool{
+		client:           cl,
+		context:          &clusterd.Context{Executor: executor},
+		clusterInfo:      cephclient.AdminTestClusterInfo("mycluster"),
+		poolMigrations:   make(map[string]*poolMigration),
+		opManagerContext: context.TODO(),
+	}
+
+	// the layout changed but the migration is not enabled
+	migrating, err := r.reconcileMigration(&cephv1.ClusterSpec{}, cephBlockPool, nn)
+	assert.NoError(t, err)
+	assert.False(t, migrating)
+
+	// the data of the images of a replicated pool is migrated to an erasure coded data pool
+	canceledContext, cancel := context.WithCancel(context.TODO())
+	cancel()
+	r.opManagerContext = canceledContext
+	cephBlockPool.Spec.Migration = &cephv1.PoolMigrationSpec{Enabled: true}
+	migrating, err = r.reconcileMigration(&cephv1.ClusterSpec{}, cephBlockPool, nn)
+	assert.NoError(t, err)
+	assert.True(t, migrating)
+	<-r.poolMigrations[blockPoolChannelKeyName(cephBlockPool)].done
+
+	updated := &cephv1.CephBlockPool{}
+	assert.NoError(t, cl.Get(context.TODO(), nn, updated))
+	assert.Equal(t, "replicapool-data", updated.Status.MigrationStatus.DataPool)
+	assert.Equal(t, "replicapool-data", updated.Status.MigrationStatus.TargetPool)
+	assert.Equal(t, cephv1.ConditionProgressing, updated.Status.MigrationStatus.Phase)
+}
+
+func TestReconcileMigrationNotRBD(t *testing.T) {
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "osd" && args[1] == "lspools":
+			return `[{"poolnum":1,"poolname":"mypool"}]`, nil
+		case args[0] == "osd" && args[1] == "pool" && args[2] == "get":
+			return `{"pool":"mypool","pool_id":1,"size":3}`, nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+
+	nn := types.NamespacedName{Name: "mypool", Namespace: "rook-ceph"}
+	cephBlockPool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+		Spec: cephv1.NamedBlockPoolSpec{
+			PoolSpec: cephv1.PoolSpec{
+				ErasureCoded: cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1},
+				Application:  "cephfs",
+			},
+			Migration: &cephv1.PoolMigrationSpec{Enabled: true},
+		},
+	}
+	r := &ReconcileCephBlockPool{
+		context:          &clusterd.Context{Executor: executor},
+		clusterInfo:      cephclient.AdminTestClusterInfo("mycluster"),
+		poolMigrations:   make(map[string]*poolMigration),
+		opManagerContext: context.TODO(),
+	}
+
+	migrating, err := r.reconcileMigration(&cephv1.ClusterSpec{}, cephBlockPool, nn)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "only the pools of RBD images can be migrated")
+	assert.False(t, migrating)
+	assert.Empty(t, r.poolMigrations)
+}
+
+func TestCancelMigration(t *testing.T) {
+	var deleted []string
+	executor := &exectest.MockExecutor{}
+	executor.MockExecuteCommandWithOutput = func(command string, args ...string) (string, error) {
+		switch {
+		case args[0] == "df" && args[1] == "detail":
+			return `{"pools":[{"name":"replicapool-migration","stats":{"objects":5000}},{"name":"replicapool-data","stats":{"objects":0}},{"name":"replicapool-migration-source","stats":{"objects":0}}]}`, nil
+		case args[0] == "osd" && args[1] == "lspools":
+			return `[{"poolnum":1,"poolname":"replicapool"},{"poolnum":2,"poolname":"replicapool-migration"},{"poolnum":3,"poolname":"replicapool-data"},{"poolnum":4,"poolname":"replicapool-migration-source"}]`, nil
+		case args[0] == "osd" && args[1] == "pool" && args[2] == "get":
+			return `{"pool":"` + args[3] + `","pool_id":3,"size":3}`, nil
+		case args[0] == "pool" && args[1] == "stats":
+			return `{"images":{"count":0,"snap_count":0}}`, nil
+		case args[0] == "osd" && args[1] == "pool" && args[2] == "delete":
+			deleted = append(deleted, args[3])
+			return "", nil
+		case args[0] == "osd" && args[1] == "crush" && args[2] == "rule" && args[3] == "rm":
+			return "", nil
+		}
+		return "", errors.Errorf("unexpected command %v", args)
+	}
+	r := &ReconcileCephBlockPool{
+		context:        &clusterd.Context{Executor: executor},
+		clusterInfo:    cephclient.AdminTestClusterInfo("mycluster"),
+		poolMigrations: make(map[string]*poolMigration),
+	}
+	cephBlockPool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"},
+		Status: &cephv1.CephBlockPoolStatus{MigrationStatus: &cephv1.PoolMigrationStatus{
+			Phase:      cephv1.ConditionFailure,
+			TargetPool: "replicapool-migration",
+			SourcePool: "replicapool-migration-source",
+			DataPool:   "replicapool-data",
+		}},
+	}
+
+	// the target pool storing data and the source pool are kept, only the empty data pool is deleted
+	assert.NoError(t, r.cancelMigration(cephBlockPool))
+	assert.Equal(t, []string{"replicapool-data"}, deleted)
+
+	// the pool itself is never deleted as a target pool
+	deleted = nil
+	cephBlockPool.Status.MigrationStatus = &cephv1.PoolMigrationStatus{Phase: cephv1.ConditionProgressing, TargetPool: "replicapool"}
+	assert.NoError(t, r.cancelMigration(cephBlockPool))
+	assert.Empty(t, deleted)
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// MirroringRoleCheckInterval is the interval at which the progress of the promotion or demotion of
+// the mirrored images is checked until they have converged
+var MirroringRoleCheckInterval = 30 * time.Second
+
+var applyMirroringRole = cephclient.ApplyMirroringRole
+
+// reconcileMirroringRole promotes or demotes the mirrored images of the pool to the role of the
+// spec and returns whether the images have converged to the role
+func (r *ReconcileCephBlockPool) reconcileMirroringRole(cephBlockPool *cephv1.CephBlockPool, namespacedName types.NamespacedName) bool {
+	var current *cephv1.MirroringRoleStatusSpec
+	if cephBlockPool.Status != nil {
+		current = cephBlockPool.Status.MirroringRoleStatus
+	}
+	mirroring := cephBlockPool.Spec.Mirroring
+	if !mirroring.Enabled {
+		mirroring = cephv1.MirroringSpec{}
+	}
+
+	status := ReconcileMirroringRole(r.context, r.clusterInfo, cephBlockPool.Name, mirroring.Role, mirroring.ForcePromote, mirroring.ResyncSplitBrain, current)
+	if status != current {
+		r.updateStatusMirroringRole(namespacedName, status)
+	}
+	return status == nil || status.Phase == cephv1.ConditionReady
+}
+
+// ReconcileMirroringRole promotes or demotes the mirrored images of a pool or a rados namespace to
+// the role. poolName is either a pool or a rados namespace in the form pool/namespace. It returns
+// the status of the role, nil if no role is set, or the current status unchanged if the images have
+// already converged to the role.
+func ReconcileMirroringRole(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string, role cephv1.MirroringRole, forcePromote, resyncSplitBrain bool, current *cephv1.MirroringRoleStatusSpec) *cephv1.MirroringRoleStatusSpec {
+	if role == "" {
+		return nil
+	}
+	// once converged, the images are not checked again until the role changes since new images are
+	// created primary on the primary cluster and mirrored as non-primary to the secondary cluster
+	if current != nil && current.Role == role && current.Phase == cephv1.ConditionReady {
+		return current
+	}
+
+	logger.Infof("converging the mirrored images of pool %q to %s", poolName, role)
+	now := time.Now().UTC().Format(time.RFC3339)
+	status, err := applyMirroringRole(context, clusterInfo, poolName, role, forcePromote, resyncSplitBrain)
+	if err != nil {
+		logger.Warningf("failed to converge the mirrored images of pool %q to %s. %v", poolName, role, err)
+		status = &cephv1.MirroringRoleStatusSpec{Role: role, Phase: cephv1.ConditionFailure, LastChecked: now, Details: err.Error()}
+	}
+	status.LastChanged = now
+	if current != nil && current.Role == role {
+		status.LastChanged = current.LastChanged
+	}
+	return status
+}
+
+// updateStatusMirroringRole updates the mirroring role status of the pool
+func (r *ReconcileCephBlockPool) updateStatusMirroringRole(namespacedName types.NamespacedName, status *cephv1.MirroringRoleStatusSpec) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := r.client.Get(r.opManagerContext, namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update mirroring role status. %v", namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	blockPool.Status.MirroringRoleStatus = status
+	if err := reporting.UpdateStatus(r.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q mirroring role status. %v", namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("ceph block pool %q mirroring role status updated", namespacedName.Name)
+}
@@ -0,0 +1,127 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileMirroringRole(t *testing.T) {
+	calls := 0
+	var applyErr error
+	phase := cephv1.ConditionProgressing
+	applyMirroringRole = func(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string, role cephv1.MirroringRole, force, resyncSplitBrain bool) (*cephv1.MirroringRoleStatusSpec, error) {
+		calls++
+		assert.Equal(t, "replicapool", poolName)
+		assert.Equal(t, role == cephv1.MirroringRoleSecondary, resyncSplitBrain)
+		if applyErr != nil {
+			return nil, applyErr
+		}
+		return &cephv1.MirroringRoleStatusSpec{Role: role, Phase: phase, ImageCount: 2}, nil
+	}
+	defer func() { applyMirroringRole = cephclient.ApplyMirroringRole }()
+
+	nn := types.NamespacedName{Name: "replicapool", Namespace: "rook-ceph"}
+	cephBlockPool := &cephv1.CephBlockPool{
+		ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace},
+		Spec: cephv1.NamedBlockPoolSpec{
+			PoolSpec: cephv1.PoolSpec{Mirroring: cephv1.MirroringSpec{Enabled: true, Mode: "image"}},
+		},
+		Status: &cephv1.CephBlockPoolStatus{},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephBlockPool).WithStatusSubresource(cephBlockPool).Build()
+	r := &ReconcileCephBlockPool{client: cl, context: &clusterd.Context{}, clusterInfo: cephclient.AdminTestClusterInfo("mycluster"), opManagerContext: context.TODO()}
+
+	getStatus := func() *cephv1.MirroringRoleStatusSpec {
+		updated := &cephv1.CephBlockPool{}
+		assert.NoError(t, cl.Get(context.TODO(), nn, updated))
+		cephBlockPool.Status = updated.Status
+		return updated.Status.MirroringRoleStatus
+	}
+
+	t.Run("no role", func(t *testing.T) {
+		assert.True(t, r.reconcileMirroringRole(cephBlockPool, nn))
+		assert.Equal(t, 0, calls)
+		assert.Nil(t, getStatus())
+	})
+
+	t.Run("images converging", func(t *testing.T) {
+		cephBlockPool.Spec.Mirroring.Role = cephv1.MirroringRoleSecondary
+		cephBlockPool.Spec.Mirroring.ResyncSplitBrain = true
+		assert.False(t, r.reconcileMirroringRole(cephBlockPool, nn))
+		assert.Equal(t, 1, calls)
+		status := getStatus()
+		assert.Equal(t, cephv1.MirroringRoleSecondary, status.Role)
+		assert.Equal(t, cephv1.ConditionProgressing, status.Phase)
+		assert.NotEmpty(t, status.LastChanged)
+	})
+
+	t.Run("failure to get the images", func(t *testing.T) {
+		lastChanged := cephBlockPool.Status.MirroringRoleStatus.LastChanged
+		applyErr = errors.New("failed to retrieve mirroring status")
+		assert.False(t, r.reconcileMirroringRole(cephBlockPool, nn))
+		status := getStatus()
+		assert.Equal(t, cephv1.ConditionFailure, status.Phase)
+		assert.Contains(t, status.Details, "failed to retrieve mirroring status")
+		assert.Equal(t, lastChanged, status.LastChanged)
+		applyErr = nil
+	})
+
+	t.Run("images converged", func(t *testing.T) {
+		phase = cephv1.ConditionReady
+		assert.True(t, r.reconcileMirroringRole(cephBlockPool, nn))
+		assert.Equal(t, cephv1.ConditionReady, getStatus().Phase)
+
+		// the images are not checked again until the role changes
+		calls = 0
+		assert.True(t, r.reconcileMirroringRole(cephBlockPool, nn))
+		assert.Equal(t, 0, calls)
+
+		phase = cephv1.ConditionProgressing
+		cephBlockPool.Spec.Mirroring.Role = cephv1.MirroringRolePrimary
+		cephBlockPool.Spec.Mirroring.ResyncSplitBrain = false
+		assert.False(t, r.reconcileMirroringRole(cephBlockPool, nn))
+		assert.Equal(t, 1, calls)
+		assert.Equal(t, cephv1.MirroringRolePrimary, getStatus().Role)
+	})
+
+	t.Run("mirroring disabled", func(t *testing.T) {
+		cephBlockPool.Spec.Mirroring.Enabled = false
+		calls = 0
+		assert.True(t, r.reconcileMirroringRole(cephBlockPool, nn))
+		assert.Equal(t, 0, calls)
+		assert.Nil(t, getStatus())
+		cephBlockPool.Spec.Mirroring.Enabled = true
+	})
+
+	t.Run("role removed", func(t *testing.T) {
+		cephBlockPool.Spec.Mirroring.Role = ""
+		assert.True(t, r.reconcileMirroringRole(cephBlockPool, nn))
+		assert.Nil(t, getStatus())
+	})
+}
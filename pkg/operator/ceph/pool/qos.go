@@ -0,0 +1,94 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"time"
+
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileQoS applies the IO limits of the RBD images of the pool and reports the effective limits
+// in the status. A failure is reported in the status without failing the reconcile.
+func (r *ReconcileCephBlockPool) reconcileQoS(cephBlockPool *cephv1.CephBlockPool, namespacedName types.NamespacedName) {
+	var current *cephv1.RBDQoSStatus
+	if cephBlockPool.Status != nil {
+		current = cephBlockPool.Status.QoSStatus
+	}
+
+	status := ReconcileQoS(r.context, r.clusterInfo, cephBlockPool.ToNamedPoolSpec().Name, cephBlockPool.Spec.QoS, current)
+	if status != current {
+		r.updateStatusQoS(namespacedName, status)
+	}
+}
+
+// ReconcileQoS applies the IO limits of the RBD images of a pool or a rados namespace. poolName is
+// either a pool or a rados namespace in the form pool/namespace. It returns the status with the
+// effective limits, nil once the limits of a removed qos are removed from ceph, or the current
+// status unchanged if there is nothing to do.
+func ReconcileQoS(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string, qos *cephv1.RBDQoSSpec, current *cephv1.RBDQoSStatus) *cephv1.RBDQoSStatus {
+	if qos == nil {
+		if current == nil {
+			return current
+		}
+		// the limits are removed from ceph before the status is cleared, so a failure is retried
+		if err := cephclient.RemoveRBDQoS(context, clusterInfo, poolName); err != nil {
+			logger.Warningf("failed to remove the qos of pool %q. %v", poolName, err)
+			status := current.DeepCopy()
+			status.Details = err.Error()
+			return status
+		}
+		return nil
+	}
+
+	status := &cephv1.RBDQoSStatus{LastChecked: time.Now().UTC().Format(time.RFC3339)}
+	limits, err := cephclient.ApplyRBDQoS(context, clusterInfo, poolName, qos)
+	if err != nil {
+		logger.Warningf("failed to apply the qos of pool %q. %v", poolName, err)
+		status.Details = err.Error()
+	}
+	status.Limits = limits
+	return status
+}
+
+// updateStatusQoS updates the qos status of the pool
+func (r *ReconcileCephBlockPool) updateStatusQoS(namespacedName types.NamespacedName, status *cephv1.RBDQoSStatus) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := r.client.Get(r.opManagerContext, namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update qos status. %v", namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	blockPool.Status.QoSStatus = status
+	if err := reporting.UpdateStatus(r.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q qos status. %v", namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("ceph block pool %q qos status updated", namespacedName.Name)
+}
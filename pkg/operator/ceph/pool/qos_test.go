@@ -0,0 +1,84 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReconcileQoS(t *testing.T) {
+	config := map[string]string{}
+	var configErr error
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			assert.Equal(t, "rbd", command)
+			assert.Equal(t, "replicapool/ns-a", args[3])
+			if configErr != nil {
+				return "", configErr
+			}
+			switch args[2] {
+			case "list":
+				options := []map[string]string{}
+				for name, value := range config {
+					options = append(options, map[string]string{"name": name, "value": value, "source": "pool"})
+				}
+				out, err := json.Marshal(options)
+				return string(out), err
+			case "set":
+				config[args[4]] = args[5]
+			case "remove":
+				delete(config, args[4])
+			}
+			return "", nil
+		},
+	}
+	context := &clusterd.Context{Executor: executor}
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+
+	t.Run("no qos", func(t *testing.T) {
+		assert.Nil(t, ReconcileQoS(context, clusterInfo, "replicapool/ns-a", nil, nil))
+	})
+
+	var status *cephv1.RBDQoSStatus
+	t.Run("apply the qos", func(t *testing.T) {
+		status = ReconcileQoS(context, clusterInfo, "replicapool/ns-a", &cephv1.RBDQoSSpec{IOPSLimit: 100}, nil)
+		assert.Equal(t, map[string]string{"rbd_qos_iops_limit": "100"}, status.Limits)
+		assert.Empty(t, status.Details)
+		assert.NotEmpty(t, status.LastChecked)
+	})
+
+	t.Run("failure to remove the qos", func(t *testing.T) {
+		configErr = errors.New("failed to list")
+		removed := ReconcileQoS(context, clusterInfo, "replicapool/ns-a", nil, status)
+		assert.Equal(t, status.Limits, removed.Limits)
+		assert.Contains(t, removed.Details, "failed to list")
+		configErr = nil
+	})
+
+	t.Run("remove the qos", func(t *testing.T) {
+		assert.Nil(t, ReconcileQoS(context, clusterInfo, "replicapool/ns-a", nil, status))
+		assert.Empty(t, config)
+	})
+}
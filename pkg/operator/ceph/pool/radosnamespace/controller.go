@@ -29,8 +29,11 @@ import (
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
 	opcontroller "github.com/rook/rook/pkg/operator/ceph/controller"
 	"github.com/rook/rook/pkg/operator/ceph/csi"
+	cephpool "github.com/rook/rook/pkg/operator/ceph/pool"
 	"github.com/rook/rook/pkg/operator/ceph/reporting"
 	"github.com/rook/rook/pkg/operator/k8sutil"
+	"github.com/rook/rook/pkg/util"
+	"github.com/rook/rook/pkg/util/tracing"
 
 	"github.com/coreos/pkg/capnslog"
 	"github.com/pkg/errors"
@@ -70,6 +73,9 @@ type ReconcileCephBlockPoolRadosNamespace struct {
 	clusterInfo      *cephclient.ClusterInfo
 	opManagerContext context.Context
 	opConfig         opcontroller.OperatorConfig
+
+	// scheduledSnapshotsCheckers take the scheduled snapshots of the images of the rados namespaces
+	scheduledSnapshotsCheckers cephpool.ScheduledSnapshotsCheckers
 }
 
 // Add creates a new CephBlockPoolRadosNamespace Controller and adds it to the
@@ -92,7 +98,7 @@ func newReconciler(mgr manager.Manager, context *clusterd.Context, opManagerCont
 
 func add(mgr manager.Manager, r reconcile.Reconciler) error {
 	// Create a new controller
-	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: r})
+	c, err := controller.New(controllerName, mgr, controller.Options{Reconciler: opcontroller.NewTracedReconciler(controllerName, r)})
 	if err != nil {
 		return err
 	}
@@ -114,7 +120,7 @@ func add(mgr manager.Manager, r reconcile.Reconciler) error {
 // otherwise upon completion it will remove the work from the queue.
 func (r *ReconcileCephBlockPoolRadosNamespace) Reconcile(context context.Context, request reconcile.Request) (reconcile.Result, error) {
 	// workaround because the rook logging mechanism is not compatible with the controller-runtime logging interface
-	reconcileResponse, err := r.reconcile(request)
+	reconcileResponse, err := r.reconcile(context, request)
 	if err != nil {
 		logger.Errorf("failed to reconcile %q %v", request.NamespacedName, err)
 	}
@@ -122,7 +128,8 @@ func (r *ReconcileCephBlockPoolRadosNamespace) Reconcile(context context.Context
 	return reconcileResponse, err
 }
 
-func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Request) (reconcile.Result, error) {
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(ctx context.Context, request reconcile.Request) (reconcile.Result, error) {
+	logger := util.NewContextLogger(ctx, logger)
 	namespacedName := request.NamespacedName
 	// Fetch the CephBlockPoolRadosNamespace instance
 	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
@@ -130,6 +137,8 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 	if err != nil {
 		if kerrors.IsNotFound(err) {
 			logger.Debugf("cephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", namespacedName)
+			// Ensure the scheduled snapshots are stopped when the resource is gone
+			r.scheduledSnapshotsCheckers.Stop(namespacedName.String())
 			return reconcile.Result{}, nil
 		}
 		// Error reading the object - requeue the request.
@@ -157,6 +166,8 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 		// If not, we should wait for it to be ready
 		// This handles the case where the operator is not ready to accept Ceph command but the cluster exists
 		if !cephBlockPoolRadosNamespace.GetDeletionTimestamp().IsZero() && !cephClusterExists {
+			r.scheduledSnapshotsCheckers.Stop(namespacedName.String())
+
 			// Remove finalizer
 			err = opcontroller.RemoveFinalizer(r.opManagerContext, r.client, cephBlockPoolRadosNamespace)
 			if err != nil {
@@ -170,7 +181,7 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 	}
 
 	// Populate clusterInfo during each reconcile
-	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, r.opManagerContext, request.NamespacedName.Namespace, &cephCluster.Spec)
+	r.clusterInfo, _, _, err = opcontroller.LoadClusterInfo(r.context, tracing.ContextWithSpan(r.opManagerContext, ctx), request.NamespacedName.Namespace, &cephCluster.Spec)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to populate cluster info")
 	}
@@ -179,9 +190,10 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 	// DELETE: the CR was deleted
 	if !cephBlockPoolRadosNamespace.GetDeletionTimestamp().IsZero() {
 		logger.Debugf("delete cephBlockPoolRadosNamespace %q", namespacedName)
+		r.scheduledSnapshotsCheckers.Stop(namespacedName.String())
 		// On external cluster, we don't delete the rados namespace, it has to be deleted manually
 		if cephCluster.Spec.External.Enable {
-			logger.Warning("external rados namespace %q deletion is not supported, delete it manually", namespacedName)
+			logger.Warningf("external rados namespace %q deletion is not supported, delete it manually", namespacedName)
 		} else {
 			err := r.deleteRadosNamespace(cephBlockPoolRadosNamespace)
 			if err != nil {
@@ -233,6 +245,19 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 		// We know the CR is present so it should a matter of second for it to become ready
 		return reconcile.Result{Requeue: true, RequeueAfter: 10 * time.Second}, errors.Wrapf(err, "failed to fetch ceph blockpool %q, cannot create rados namespace %q", cephBlockPoolRadosNamespace.Spec.BlockPoolName, cephBlockPoolRadosNamespace.Name)
 	}
+	// Get CephCluster version
+	cephVersion, err := opcontroller.GetImageVersion(cephCluster)
+	if err != nil {
+		return opcontroller.ImmediateRetryResult, errors.Wrapf(err, "failed to fetch ceph version from cephcluster %q", cephCluster.Name)
+	}
+	r.clusterInfo.CephVersion = *cephVersion
+
+	// validate the mirroring settings
+	if err := validateMirroring(r.clusterInfo, cephBlockPoolRadosNamespace); err != nil {
+		r.updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure)
+		return reconcile.Result{}, errors.Wrapf(err, "invalid ceph blockpool rados namespace %q spec", cephBlockPoolRadosNamespace.Name)
+	}
+
 	// Create or Update rados namespace
 	err = r.createOrUpdateRadosNamespace(cephBlockPoolRadosNamespace)
 	if err != nil {
@@ -244,14 +269,36 @@ func (r *ReconcileCephBlockPoolRadosNamespace) reconcile(request reconcile.Reque
 		return reconcile.Result{}, errors.Wrapf(err, "failed to create or update ceph pool rados namespace %q", cephBlockPoolRadosNamespace.Name)
 	}
 
+	// Enable or disable the mirroring of the rados namespace
+	err = r.reconcileMirroring(cephBlockPoolRadosNamespace, cephBlockPool)
+	if err != nil {
+		r.updateStatus(r.client, request.NamespacedName, cephv1.ConditionFailure)
+		return reconcile.Result{}, errors.Wrapf(err, "failed to reconcile mirroring of ceph pool rados namespace %q", cephBlockPoolRadosNamespace.Name)
+	}
+
 	err = r.updateClusterConfig(cephBlockPoolRadosNamespace, cephCluster)
 	if err != nil {
 		return reconcile.Result{}, errors.Wrap(err, "failed to save cluster config")
 	}
 
 	r.updateStatus(r.client, namespacedName, cephv1.ConditionReady)
-	// Return and do not requeue
+	r.updateMirroringStatus(namespacedName)
+	r.reconcileQoS(cephBlockPoolRadosNamespace, namespacedName)
+	r.reconcileScheduledSnapshots(cephBlockPoolRadosNamespace, namespacedName)
+
+	// Promote or demote the mirrored images for a planned failover or failback
+	if !r.reconcileMirroringRole(cephBlockPoolRadosNamespace, namespacedName) {
+		logger.Debugf("done reconciling cephBlockPoolRadosNamespace %q, waiting for the mirrored images to converge to their role", namespacedName)
+		return reconcile.Result{RequeueAfter: cephpool.MirroringRoleCheckInterval}, nil
+	}
+
 	logger.Debugf("done reconciling cephBlockPoolRadosNamespace %q", namespacedName)
+
+	// The mirroring status of a mirrored rados namespace is refreshed periodically
+	if cephBlockPoolRadosNamespace.Spec.Mirroring != nil {
+		return reconcile.Result{RequeueAfter: mirroringStatusInterval(cephBlockPool)}, nil
+	}
+
 	return reconcile.Result{}, nil
 }
 
@@ -320,6 +367,14 @@ func (r *ReconcileCephBlockPoolRadosNamespace) deleteRadosNamespace(cephBlockPoo
 	namespacedName := fmt.Sprintf("%s/%s", cephBlockPoolRadosNamespace.Namespace, cephBlockPoolRadosNamespace.Name)
 	logger.Infof("deleting ceph blockpool rados namespace object %q", namespacedName)
 
+	// The mirroring must be disabled before the rados namespace can be removed
+	if cephBlockPoolRadosNamespace.Spec.Mirroring != nil || mirroringReported(cephBlockPoolRadosNamespace) {
+		err := cephclient.DisableRBDRadosNamespaceMirroring(r.context, r.clusterInfo, cephBlockPoolRadosNamespace.Spec.BlockPoolName, getRadosNamespaceName(cephBlockPoolRadosNamespace))
+		if err != nil {
+			return errors.Wrapf(err, "failed to disable mirroring of ceph blockpool rados namespace %q", cephBlockPoolRadosNamespace.Name)
+		}
+	}
+
 	if err := cephclient.DeleteRadosNamespace(r.context, r.clusterInfo, cephBlockPoolRadosNamespace.Spec.BlockPoolName, getRadosNamespaceName(cephBlockPoolRadosNamespace)); err != nil {
 		return errors.Wrapf(err, "failed to delete ceph blockpool rados namespace %q", cephBlockPoolRadosNamespace.Name)
 	}
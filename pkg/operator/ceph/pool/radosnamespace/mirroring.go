@@ -0,0 +1,228 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephpool "github.com/rook/rook/pkg/operator/ceph/pool"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// defaultMirroringStatusInterval is the interval at which the mirroring status of a mirrored rados
+// namespace is refreshed, unless the status check interval of the block pool is set
+var defaultMirroringStatusInterval = 1 * time.Minute
+
+// reconcileMirroring enables the mirroring of the rados namespace, or disables it if it was enabled
+// and has been removed from the spec
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileMirroring(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, cephBlockPool *cephv1.CephBlockPool) error {
+	poolName := cephBlockPoolRadosNamespace.Spec.BlockPoolName
+	radosNamespace := getRadosNamespaceName(cephBlockPoolRadosNamespace)
+
+	if cephBlockPoolRadosNamespace.Spec.Mirroring == nil {
+		if !mirroringReported(cephBlockPoolRadosNamespace) {
+			return nil
+		}
+		logger.Infof("disabling mirroring of rados namespace %q of ceph blockpool %q", radosNamespace, poolName)
+		return cephclient.DisableRBDRadosNamespaceMirroring(r.context, r.clusterInfo, poolName, radosNamespace)
+	}
+
+	// the images of the namespace can only be mirrored to the peers of the pool
+	if !cephBlockPool.Spec.Mirroring.Enabled {
+		return errors.Errorf("mirroring must be enabled on ceph blockpool %q to mirror rados namespace %q", poolName, radosNamespace)
+	}
+
+	return cephclient.EnableRBDRadosNamespaceMirroring(r.context, r.clusterInfo, poolName, radosNamespace, *cephBlockPoolRadosNamespace.Spec.Mirroring)
+}
+
+// validateMirroring validates the mirroring settings of the rados namespace
+func validateMirroring(clusterInfo *cephclient.ClusterInfo, cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace) error {
+	mirroring := cephBlockPoolRadosNamespace.Spec.Mirroring
+	if mirroring == nil || mirroring.RemoteNamespace == nil {
+		return nil
+	}
+	if !clusterInfo.CephVersion.IsAtLeast(cephclient.RemoteNamespaceMirroringVersion) {
+		return errors.Errorf("mirroring to a remote namespace requires ceph version %q, but is running %s", cephclient.RemoteNamespaceMirroringVersion.String(), clusterInfo.CephVersion.String())
+	}
+	return nil
+}
+
+// reconcileMirroringRole promotes or demotes the mirrored images of the rados namespace to the role
+// of the spec and returns whether the images have converged to the role
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileMirroringRole(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName) bool {
+	var current *cephv1.MirroringRoleStatusSpec
+	if cephBlockPoolRadosNamespace.Status != nil {
+		current = cephBlockPoolRadosNamespace.Status.MirroringRoleStatus
+	}
+	mirroring := cephv1.RadosNamespaceMirroring{}
+	if cephBlockPoolRadosNamespace.Spec.Mirroring != nil {
+		mirroring = *cephBlockPoolRadosNamespace.Spec.Mirroring
+	}
+
+	poolName := cephclient.RadosNamespacePoolName(cephBlockPoolRadosNamespace.Spec.BlockPoolName, getRadosNamespaceName(cephBlockPoolRadosNamespace))
+	status := cephpool.ReconcileMirroringRole(r.context, r.clusterInfo, poolName, mirroring.Role, mirroring.ForcePromote, mirroring.ResyncSplitBrain, current)
+	if status != current {
+		r.updateStatusMirroringRole(name, status)
+	}
+	return status == nil || status.Phase == cephv1.ConditionReady
+}
+
+// updateStatusMirroringRole updates the mirroring role status of the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) updateStatusMirroringRole(name types.NamespacedName, status *cephv1.MirroringRoleStatusSpec) {
+	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	if err := r.client.Get(r.opManagerContext, name, cephBlockPoolRadosNamespace); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("CephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", name)
+			return
+		}
+		logger.Warningf("failed to retrieve ceph blockpool rados namespace %q to update mirroring role status. %v", name, err)
+		return
+	}
+	if cephBlockPoolRadosNamespace.Status == nil {
+		cephBlockPoolRadosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{}
+	}
+
+	cephBlockPoolRadosNamespace.Status.MirroringRoleStatus = status
+	if err := reporting.UpdateStatus(r.client, cephBlockPoolRadosNamespace); err != nil {
+		logger.Errorf("failed to set ceph blockpool rados namespace %q mirroring role status. %v", name, err)
+		return
+	}
+	logger.Debugf("ceph blockpool rados namespace %q mirroring role status updated", name)
+}
+
+// mirroringReported returns whether the mirroring of the rados namespace was enabled by a previous reconcile
+func mirroringReported(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace) bool {
+	status := cephBlockPoolRadosNamespace.Status
+	return status != nil && (status.MirroringStatus != nil || status.MirroringInfo != nil)
+}
+
+// mirroringStatusInterval returns the interval at which the mirroring status of the rados namespace is refreshed
+func mirroringStatusInterval(cephBlockPool *cephv1.CephBlockPool) time.Duration {
+	if cephBlockPool.Spec.StatusCheck.Mirror.Interval != nil {
+		return cephBlockPool.Spec.StatusCheck.Mirror.Interval.Duration
+	}
+	return defaultMirroringStatusInterval
+}
+
+// updateMirroringStatus reports the mirroring health of the rados namespace in its status. The
+// mirroring status is removed from the status when the mirroring is disabled.
+func (r *ReconcileCephBlockPoolRadosNamespace) updateMirroringStatus(name types.NamespacedName) {
+	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	if err := r.client.Get(r.opManagerContext, name, cephBlockPoolRadosNamespace); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("CephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", name)
+			return
+		}
+		logger.Warningf("failed to retrieve ceph blockpool rados namespace %q to update mirroring status. %v", name, err)
+		return
+	}
+	if cephBlockPoolRadosNamespace.Status == nil {
+		cephBlockPoolRadosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{}
+	}
+	status := cephBlockPoolRadosNamespace.Status
+
+	if cephBlockPoolRadosNamespace.Spec.Mirroring == nil {
+		if !mirroringReported(cephBlockPoolRadosNamespace) && status.SnapshotScheduleStatus == nil {
+			return
+		}
+		status.MirroringStatus, status.MirroringInfo, status.SnapshotScheduleStatus = nil, nil, nil
+	} else {
+		poolName := cephclient.RadosNamespacePoolName(cephBlockPoolRadosNamespace.Spec.BlockPoolName, getRadosNamespaceName(cephBlockPoolRadosNamespace))
+		status.MirroringStatus, status.MirroringInfo, status.SnapshotScheduleStatus = r.checkMirroringHealth(poolName, cephBlockPoolRadosNamespace.Spec.Mirroring, status)
+	}
+
+	if err := reporting.UpdateStatus(r.client, cephBlockPoolRadosNamespace); err != nil {
+		logger.Errorf("failed to set ceph blockpool rados namespace %q mirroring status. %v", name, err)
+		return
+	}
+	logger.Debugf("ceph blockpool rados namespace %q mirroring status updated", name)
+}
+
+// checkMirroringHealth returns the mirroring status, info and snapshot schedules of the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) checkMirroringHealth(poolName string, mirroring *cephv1.RadosNamespaceMirroring, current *cephv1.CephBlockPoolRadosNamespaceStatus) (*cephv1.MirroringStatusSpec, *cephv1.MirroringInfoSpec, *cephv1.SnapshotScheduleStatusSpec) {
+	now := time.Now().UTC().Format(time.RFC3339)
+	failures := []string{}
+
+	mirroringStatus := &cephv1.MirroringStatusSpec{}
+	if current.MirroringStatus != nil {
+		mirroringStatus.LastChanged = current.MirroringStatus.LastChanged
+	}
+	poolMirroringStatus, err := cephclient.GetPoolMirroringStatus(r.context, r.clusterInfo, poolName)
+	if err != nil {
+		failures = append(failures, err.Error())
+	} else {
+		if current.MirroringStatus == nil || current.MirroringStatus.Summary == nil || poolMirroringStatus.Summary == nil ||
+			current.MirroringStatus.Summary.Health != poolMirroringStatus.Summary.Health {
+			mirroringStatus.LastChanged = now
+		}
+		mirroringStatus.Summary = poolMirroringStatus.Summary
+		mirroringStatus.LastChecked = now
+	}
+
+	mirroringInfo := &cephv1.MirroringInfoSpec{}
+	if current.MirroringInfo != nil {
+		mirroringInfo.LastChanged = current.MirroringInfo.LastChanged
+	}
+	poolMirroringInfo, err := cephclient.GetPoolMirroringInfo(r.context, r.clusterInfo, poolName)
+	if err != nil {
+		failures = append(failures, err.Error())
+	} else {
+		if current.MirroringInfo == nil || current.MirroringInfo.PoolMirroringInfo == nil ||
+			current.MirroringInfo.Mode != poolMirroringInfo.Mode {
+			mirroringInfo.LastChanged = now
+		}
+		mirroringInfo.PoolMirroringInfo = poolMirroringInfo
+		mirroringInfo.LastChecked = now
+	}
+
+	var snapshotScheduleStatus *cephv1.SnapshotScheduleStatusSpec
+	if len(mirroring.SnapshotSchedules) > 0 {
+		snapshotScheduleStatus = &cephv1.SnapshotScheduleStatusSpec{}
+		if current.SnapshotScheduleStatus != nil {
+			snapshotScheduleStatus.LastChanged = current.SnapshotScheduleStatus.LastChanged
+		}
+		snapshotSchedules, err := cephclient.ListSnapshotSchedulesRecursively(r.context, r.clusterInfo, poolName)
+		if err != nil {
+			failures = append(failures, err.Error())
+		} else {
+			if current.SnapshotScheduleStatus == nil || len(current.SnapshotScheduleStatus.SnapshotSchedules) != len(snapshotSchedules) {
+				snapshotScheduleStatus.LastChanged = now
+			}
+			snapshotScheduleStatus.SnapshotSchedules = snapshotSchedules
+			snapshotScheduleStatus.LastChecked = now
+		}
+	}
+
+	// Always display the details, typically an error
+	details := strings.Join(failures, "; ")
+	if details != "" {
+		logger.Debugf("failed to check mirroring status of rados namespace %q. %s", poolName, details)
+	}
+	mirroringStatus.Details = details
+	mirroringInfo.Details = details
+	if snapshotScheduleStatus != nil {
+		snapshotScheduleStatus.Details = details
+	}
+
+	return mirroringStatus, mirroringInfo, snapshotScheduleStatus
+}
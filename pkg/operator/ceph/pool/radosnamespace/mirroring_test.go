@@ -0,0 +1,179 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+func TestReconcileMirroring(t *testing.T) {
+	var commands []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" {
+				commands = append(commands, args[1]+" "+args[2]+" "+args[3])
+				if args[3] == "ls" {
+					return "[]", nil
+				}
+				return "", nil
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		context:     &clusterd.Context{Executor: executor},
+		clusterInfo: cephclient.AdminTestClusterInfo("mycluster"),
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+		},
+	}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "replicapool", Namespace: "rook-ceph"}}
+
+	t.Run("mirroring not enabled", func(t *testing.T) {
+		commands = nil
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool)
+		assert.NoError(t, err)
+		assert.Empty(t, commands)
+	})
+
+	t.Run("pool not mirrored", func(t *testing.T) {
+		commands = nil
+		radosNamespace.Spec.Mirroring = &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage}
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool)
+		assert.Error(t, err)
+		assert.Contains(t, err.Error(), "mirroring must be enabled on ceph blockpool")
+		assert.Empty(t, commands)
+	})
+
+	t.Run("mirroring enabled", func(t *testing.T) {
+		commands = nil
+		cephBlockPool.Spec.Mirroring = cephv1.MirroringSpec{Enabled: true, Mode: "image"}
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"pool enable replicapool/namespace-a", "snapshot schedule ls"}, commands)
+	})
+
+	t.Run("mirroring removed from the spec", func(t *testing.T) {
+		commands = nil
+		radosNamespace.Spec.Mirroring = nil
+		radosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{MirroringInfo: &cephv1.MirroringInfoSpec{}}
+		err := r.reconcileMirroring(radosNamespace, cephBlockPool)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"snapshot schedule ls", "pool disable replicapool/namespace-a"}, commands)
+	})
+}
+
+func TestValidateMirroring(t *testing.T) {
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	clusterInfo.CephVersion = cephver.CephVersion{Major: 19, Minor: 1}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+
+	// no mirroring
+	assert.NoError(t, validateMirroring(clusterInfo, radosNamespace))
+
+	// mirrored to the namespace with the same name
+	radosNamespace.Spec.Mirroring = &cephv1.RadosNamespaceMirroring{Mode: cephv1.RadosNamespaceMirroringModeImage}
+	assert.NoError(t, validateMirroring(clusterInfo, radosNamespace))
+
+	// the remote namespace requires squid 19.2
+	remoteNamespace := "namespace-b"
+	radosNamespace.Spec.Mirroring.RemoteNamespace = &remoteNamespace
+	assert.Error(t, validateMirroring(clusterInfo, radosNamespace))
+	clusterInfo.CephVersion = cephver.CephVersion{Major: 19, Minor: 2}
+	assert.NoError(t, validateMirroring(clusterInfo, radosNamespace))
+}
+
+func TestUpdateMirroringStatus(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if args[0] == "mirror" {
+				switch args[2] {
+				case "status":
+					assert.Equal(t, "replicapool/namespace-a", args[3])
+					return `{"summary":{"health":"WARNING","daemon_health":"OK","image_health":"WARNING","states":{"replaying":1}}}`, nil
+				case "info":
+					assert.Equal(t, "replicapool/namespace-a", args[3])
+					return `{"mode":"image","site_name":"site-a","peers":[]}`, nil
+				case "schedule":
+					return `[{"pool":"replicapool","namespace":"namespace-a","image":"-","items":[{"interval":"1h","start_time":""}]}]`, nil
+				}
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+	radosNamespace := &cephv1.CephBlockPoolRadosNamespace{
+		ObjectMeta: metav1.ObjectMeta{Name: "namespace-a", Namespace: "rook-ceph"},
+		Spec: cephv1.CephBlockPoolRadosNamespaceSpec{
+			BlockPoolName: "replicapool",
+			Mirroring: &cephv1.RadosNamespaceMirroring{
+				Mode:              cephv1.RadosNamespaceMirroringModeImage,
+				SnapshotSchedules: []cephv1.SnapshotScheduleSpec{{Interval: "1h"}},
+			},
+		},
+	}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(radosNamespace).WithStatusSubresource(radosNamespace).Build()
+	r := &ReconcileCephBlockPoolRadosNamespace{
+		client:           cl,
+		context:          &clusterd.Context{Executor: executor},
+		clusterInfo:      cephclient.AdminTestClusterInfo("mycluster"),
+		opManagerContext: context.TODO(),
+	}
+	nn := types.NamespacedName{Name: "namespace-a", Namespace: "rook-ceph"}
+
+	r.updateMirroringStatus(nn)
+	updated := &cephv1.CephBlockPoolRadosNamespace{}
+	assert.NoError(t, cl.Get(context.TODO(), nn, updated))
+	assert.Equal(t, "WARNING", updated.Status.MirroringStatus.Summary.Health)
+	assert.NotEmpty(t, updated.Status.MirroringStatus.LastChecked)
+	assert.Empty(t, updated.Status.MirroringStatus.Details)
+	assert.Equal(t, "image", updated.Status.MirroringInfo.Mode)
+	assert.Equal(t, "namespace-a", updated.Status.SnapshotScheduleStatus.SnapshotSchedules[0].Namespace)
+
+	// the mirroring status is removed when the mirroring is disabled
+	updated.Spec.Mirroring = nil
+	assert.NoError(t, cl.Update(context.TODO(), updated))
+	r.updateMirroringStatus(nn)
+	assert.NoError(t, cl.Get(context.TODO(), nn, updated))
+	assert.Nil(t, updated.Status.MirroringStatus)
+	assert.Nil(t, updated.Status.MirroringInfo)
+	assert.Nil(t, updated.Status.SnapshotScheduleStatus)
+}
+
+func TestMirroringStatusInterval(t *testing.T) {
+	cephBlockPool := &cephv1.CephBlockPool{}
+	assert.Equal(t, time.Minute, mirroringStatusInterval(cephBlockPool))
+
+	cephBlockPool.Spec.StatusCheck.Mirror.Interval = &metav1.Duration{Duration: 30 * time.Second}
+	assert.Equal(t, 30*time.Second, mirroringStatusInterval(cephBlockPool))
+}
@@ -0,0 +1,64 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephpool "github.com/rook/rook/pkg/operator/ceph/pool"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileQoS applies the IO limits of the RBD images of the rados namespace and reports the
+// effective limits in the status. A failure is reported in the status without failing the reconcile.
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileQoS(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName) {
+	var current *cephv1.RBDQoSStatus
+	if cephBlockPoolRadosNamespace.Status != nil {
+		current = cephBlockPoolRadosNamespace.Status.QoSStatus
+	}
+
+	namespacePoolName := cephclient.RadosNamespacePoolName(cephBlockPoolRadosNamespace.Spec.BlockPoolName, getRadosNamespaceName(cephBlockPoolRadosNamespace))
+	status := cephpool.ReconcileQoS(r.context, r.clusterInfo, namespacePoolName, cephBlockPoolRadosNamespace.Spec.QoS, current)
+	if status != current {
+		r.updateStatusQoS(name, status)
+	}
+}
+
+// updateStatusQoS updates the qos status of the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) updateStatusQoS(name types.NamespacedName, status *cephv1.RBDQoSStatus) {
+	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	if err := r.client.Get(r.opManagerContext, name, cephBlockPoolRadosNamespace); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("CephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", name)
+			return
+		}
+		logger.Warningf("failed to retrieve ceph blockpool rados namespace %q to update qos status. %v", name, err)
+		return
+	}
+	if cephBlockPoolRadosNamespace.Status == nil {
+		cephBlockPoolRadosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{}
+	}
+
+	cephBlockPoolRadosNamespace.Status.QoSStatus = status
+	if err := reporting.UpdateStatus(r.client, cephBlockPoolRadosNamespace); err != nil {
+		logger.Errorf("failed to set ceph blockpool rados namespace %q qos status. %v", name, err)
+		return
+	}
+	logger.Debugf("ceph blockpool rados namespace %q qos status updated", name)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package radosnamespace
+
+import (
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephpool "github.com/rook/rook/pkg/operator/ceph/pool"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// reconcileScheduledSnapshots starts the checker of the scheduled snapshots of the RBD images of the
+// rados namespace, or stops it and clears the status if no snapshot is scheduled
+func (r *ReconcileCephBlockPoolRadosNamespace) reconcileScheduledSnapshots(cephBlockPoolRadosNamespace *cephv1.CephBlockPoolRadosNamespace, name types.NamespacedName) {
+	var current *cephv1.RBDScheduledSnapshotsStatus
+	if cephBlockPoolRadosNamespace.Status != nil {
+		current = cephBlockPoolRadosNamespace.Status.ScheduledSnapshots
+	}
+	spec := cephBlockPoolRadosNamespace.Spec.ScheduledSnapshots
+	if spec == nil {
+		r.scheduledSnapshotsCheckers.Stop(name.String())
+		if current != nil {
+			r.updateStatusScheduledSnapshots(name, nil)
+		}
+		return
+	}
+
+	namespacePoolName := cephclient.RadosNamespacePoolName(cephBlockPoolRadosNamespace.Spec.BlockPoolName, getRadosNamespaceName(cephBlockPoolRadosNamespace))
+	checker := cephpool.NewScheduledSnapshotsChecker(r.context, r.clusterInfo, namespacePoolName, spec, current, func(status *cephv1.RBDScheduledSnapshotsStatus) {
+		r.updateStatusScheduledSnapshots(name, status)
+	})
+	r.scheduledSnapshotsCheckers.Start(r.opManagerContext, name.String(), checker)
+}
+
+// updateStatusScheduledSnapshots updates the scheduled snapshots status of the rados namespace
+func (r *ReconcileCephBlockPoolRadosNamespace) updateStatusScheduledSnapshots(name types.NamespacedName, status *cephv1.RBDScheduledSnapshotsStatus) {
+	cephBlockPoolRadosNamespace := &cephv1.CephBlockPoolRadosNamespace{}
+	if err := r.client.Get(r.opManagerContext, name, cephBlockPoolRadosNamespace); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debugf("CephBlockPoolRadosNamespace resource %q not found. Ignoring since object must be deleted.", name)
+			return
+		}
+		logger.Warningf("failed to retrieve ceph blockpool rados namespace %q to update scheduled snapshots status. %v", name, err)
+		return
+	}
+	if cephBlockPoolRadosNamespace.Status == nil {
+		cephBlockPoolRadosNamespace.Status = &cephv1.CephBlockPoolRadosNamespaceStatus{}
+	}
+
+	cephBlockPoolRadosNamespace.Status.ScheduledSnapshots = status
+	if err := reporting.UpdateStatus(r.client, cephBlockPoolRadosNamespace); err != nil {
+		logger.Errorf("failed to set ceph blockpool rados namespace %q scheduled snapshots status. %v", name, err)
+		return
+	}
+	logger.Debugf("ceph blockpool rados namespace %q scheduled snapshots status updated", name)
+}
@@ -0,0 +1,247 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"reflect"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+const (
+	// defaultScheduledSnapshotsRetention is the number of scheduled snapshots kept for each image
+	// when the retention is not set
+	defaultScheduledSnapshotsRetention = 7
+	// scheduledSnapshotsRetryInterval is the interval at which the scheduled snapshots are retried
+	// when the images of the pool cannot be listed
+	scheduledSnapshotsRetryInterval = time.Minute
+)
+
+// ScheduledSnapshotsChecker takes the scheduled snapshots of the RBD images of a pool or a rados
+// namespace in the background, so the snapshots of a pool with many images do not block the reconcile
+type ScheduledSnapshotsChecker struct {
+	context      *clusterd.Context
+	clusterInfo  *cephclient.ClusterInfo
+	poolName     string
+	spec         cephv1.RBDScheduledSnapshotsSpec
+	status       *cephv1.RBDScheduledSnapshotsStatus
+	updateStatus func(*cephv1.RBDScheduledSnapshotsStatus)
+}
+
+// NewScheduledSnapshotsChecker creates a checker of the scheduled snapshots of a pool or a rados
+// namespace. poolName is either a pool or a rados namespace in the form pool/namespace. The checker
+// starts from the current status and reports the new status with updateStatus.
+func NewScheduledSnapshotsChecker(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string, spec *cephv1.RBDScheduledSnapshotsSpec, current *cephv1.RBDScheduledSnapshotsStatus, updateStatus func(*cephv1.RBDScheduledSnapshotsStatus)) *ScheduledSnapshotsChecker {
+	return &ScheduledSnapshotsChecker{
+		context:      context,
+		clusterInfo:  clusterInfo,
+		poolName:     poolName,
+		spec:         *spec,
+		status:       current.DeepCopy(),
+		updateStatus: updateStatus,
+	}
+}
+
+// checkScheduledSnapshots takes the scheduled snapshots when they are due until the context is canceled
+func (c *ScheduledSnapshotsChecker) checkScheduledSnapshots(ctx context.Context) {
+	for {
+		status, wait := takeScheduledSnapshots(c.context, c.clusterInfo, c.poolName, &c.spec, c.status, time.Now().UTC())
+		if !reflect.DeepEqual(status, c.status) {
+			c.updateStatus(status)
+		}
+		c.status = status
+
+		// an invalid schedule is not retried until the spec changes and the checker is restarted
+		var next <-chan time.Time
+		if wait > 0 {
+			next = time.After(wait)
+		}
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping scheduled snapshots of pool %q", c.poolName)
+			return
+
+		case <-next:
+			logger.Debugf("checking scheduled snapshots of pool %q", c.poolName)
+		}
+	}
+}
+
+// ScheduledSnapshotsCheckers are the running checkers of the scheduled snapshots of the pools or the
+// rados namespaces of a controller
+type ScheduledSnapshotsCheckers struct {
+	checkers map[string]*scheduledSnapshotsMonitoring
+}
+
+type scheduledSnapshotsMonitoring struct {
+	cancel context.CancelFunc
+	spec   cephv1.RBDScheduledSnapshotsSpec
+}
+
+// Start starts the checker of the scheduled snapshots of the pool with the given key, or restarts it
+// if the schedule changed. This is a noop if the checker is already running with the same schedule.
+func (s *ScheduledSnapshotsCheckers) Start(ctx context.Context, key string, checker *ScheduledSnapshotsChecker) {
+	if monitoring, ok := s.checkers[key]; ok {
+		if monitoring.spec == checker.spec {
+			logger.Debugf("pool %q scheduled snapshots go routine already running", checker.poolName)
+			return
+		}
+		s.Stop(key)
+	}
+
+	if s.checkers == nil {
+		s.checkers = map[string]*scheduledSnapshotsMonitoring{}
+	}
+	internalCtx, internalCancel := context.WithCancel(ctx)
+	s.checkers[key] = &scheduledSnapshotsMonitoring{cancel: internalCancel, spec: checker.spec}
+	go checker.checkScheduledSnapshots(internalCtx)
+}
+
+// Stop stops the checker of the scheduled snapshots of the pool with the given key. This is a noop if
+// the checker is not running.
+func (s *ScheduledSnapshotsCheckers) Stop(key string) {
+	if monitoring, ok := s.checkers[key]; ok {
+		monitoring.cancel()
+		delete(s.checkers, key)
+	}
+}
+
+// reconcileScheduledSnapshots starts the checker of the scheduled snapshots of the RBD images of the
+// pool, or stops it and clears the status if no snapshot is scheduled
+func (r *ReconcileCephBlockPool) reconcileScheduledSnapshots(cephBlockPool *cephv1.CephBlockPool, name types.NamespacedName) {
+	var current *cephv1.RBDScheduledSnapshotsStatus
+	if cephBlockPool.Status != nil {
+		current = cephBlockPool.Status.ScheduledSnapshots
+	}
+	key := blockPoolChannelKeyName(cephBlockPool)
+	spec := cephBlockPool.Spec.ScheduledSnapshots
+	if spec == nil {
+		r.scheduledSnapshotsCheckers.Stop(key)
+		if current != nil {
+			r.updateStatusScheduledSnapshots(name, nil)
+		}
+		return
+	}
+
+	poolName := cephBlockPool.ToNamedPoolSpec().Name
+	checker := NewScheduledSnapshotsChecker(r.context, r.clusterInfo, poolName, spec, current, func(status *cephv1.RBDScheduledSnapshotsStatus) {
+		r.updateStatusScheduledSnapshots(name, status)
+	})
+	r.scheduledSnapshotsCheckers.Start(r.opManagerContext, key, checker)
+}
+
+// takeScheduledSnapshots takes the scheduled snapshots of the RBD images of a pool or a rados
+// namespace when they are due. It returns the status of the schedule and the time until the next
+// snapshots, or zero if the schedule is invalid.
+func takeScheduledSnapshots(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo, poolName string, spec *cephv1.RBDScheduledSnapshotsSpec, current *cephv1.RBDScheduledSnapshotsStatus, now time.Time) (*cephv1.RBDScheduledSnapshotsStatus, time.Duration) {
+	status := &cephv1.RBDScheduledSnapshotsStatus{}
+	if current != nil {
+		status = current.DeepCopy()
+	}
+
+	interval, err := scheduledSnapshotsInterval(spec.Interval)
+	if err != nil {
+		status.Details = err.Error()
+		return status, 0
+	}
+	next := nextScheduledSnapshotsTime(status.LastRun, interval, now)
+	if next.After(now) {
+		status.NextRun = next.Format(time.RFC3339)
+		return status, next.Sub(now)
+	}
+
+	retention := spec.Retention
+	if retention <= 0 {
+		retention = defaultScheduledSnapshotsRetention
+	}
+	run, err := cephclient.CreateScheduledSnapshots(context, clusterInfo, poolName, cephclient.ScheduledSnapshotName(now), retention)
+	if run == nil {
+		// the snapshots could not be taken at all, keep the last run and retry shortly
+		logger.Warningf("failed to take the scheduled snapshots of pool %q. %v", poolName, err)
+		status.Details = err.Error()
+		return status, scheduledSnapshotsRetryInterval
+	}
+	run.LastRun = now.Format(time.RFC3339)
+	run.NextRun = now.Add(interval).Format(time.RFC3339)
+	if err != nil {
+		logger.Warningf("%v", err)
+		run.Details = err.Error()
+	}
+	return run, interval
+}
+
+// scheduledSnapshotsInterval returns the interval of a snapshot schedule
+func scheduledSnapshotsInterval(interval string) (time.Duration, error) {
+	if len(interval) < 2 {
+		return 0, errors.Errorf("invalid scheduled snapshots interval %q", interval)
+	}
+	count, err := strconv.Atoi(interval[:len(interval)-1])
+	if err != nil || count <= 0 {
+		return 0, errors.Errorf("invalid scheduled snapshots interval %q", interval)
+	}
+	switch interval[len(interval)-1] {
+	case 'm':
+		return time.Duration(count) * time.Minute, nil
+	case 'h':
+		return time.Duration(count) * time.Hour, nil
+	case 'd':
+		return time.Duration(count) * 24 * time.Hour, nil
+	}
+	return 0, errors.Errorf("invalid scheduled snapshots interval %q", interval)
+}
+
+// nextScheduledSnapshotsTime returns the time of the next snapshots, the snapshots are due
+// immediately if they were never taken
+func nextScheduledSnapshotsTime(lastRun string, interval time.Duration, now time.Time) time.Time {
+	last, err := time.Parse(time.RFC3339, lastRun)
+	if err != nil {
+		return now
+	}
+	return last.Add(interval)
+}
+
+// updateStatusScheduledSnapshots updates the scheduled snapshots status of the pool
+func (r *ReconcileCephBlockPool) updateStatusScheduledSnapshots(name types.NamespacedName, status *cephv1.RBDScheduledSnapshotsStatus) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := r.client.Get(r.opManagerContext, name, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update scheduled snapshots status. %v", name.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	blockPool.Status.ScheduledSnapshots = status
+	if err := reporting.UpdateStatus(r.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q scheduled snapshots status. %v", name.Name, err)
+		return
+	}
+	logger.Debugf("ceph block pool %q scheduled snapshots status updated", name.Name)
+}
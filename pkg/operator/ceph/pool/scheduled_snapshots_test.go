@@ -0,0 +1,146 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScheduledSnapshotsInterval(t *testing.T) {
+	interval, err := scheduledSnapshotsInterval("30m")
+	assert.NoError(t, err)
+	assert.Equal(t, 30*time.Minute, interval)
+	interval, err = scheduledSnapshotsInterval("2d")
+	assert.NoError(t, err)
+	assert.Equal(t, 48*time.Hour, interval)
+
+	for _, invalid := range []string{"", "h", "0h", "1w", "xh"} {
+		_, err = scheduledSnapshotsInterval(invalid)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestTakeScheduledSnapshots(t *testing.T) {
+	listFailure := false
+	var created []string
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "ls":
+				if listFailure {
+					return "", errors.New("timed out")
+				}
+				return `[{"image":"img1","size":1024,"format":2}]`, nil
+			case args[0] == "snap" && args[1] == "create":
+				created = append(created, args[2])
+				return "", nil
+			case args[0] == "snap" && args[1] == "ls":
+				return "[]", nil
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+	clusterdContext := &clusterd.Context{Executor: executor}
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	spec := &cephv1.RBDScheduledSnapshotsSpec{Interval: "1h"}
+	now := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	// the snapshots are taken immediately the first time
+	status, wait := takeScheduledSnapshots(clusterdContext, clusterInfo, "replicapool", spec, nil, now)
+	assert.Equal(t, time.Hour, wait)
+	assert.Equal(t, []string{"replicapool/img1@rook-scheduled-20240301-120000"}, created)
+	assert.Equal(t, "2024-03-01T12:00:00Z", status.LastRun)
+	assert.Equal(t, "2024-03-01T13:00:00Z", status.NextRun)
+	assert.Equal(t, 1, status.SnapshotsCreated)
+	assert.Empty(t, status.Details)
+
+	// nothing is taken before the next run
+	created = nil
+	status, wait = takeScheduledSnapshots(clusterdContext, clusterInfo, "replicapool", spec, status, now.Add(20*time.Minute))
+	assert.Equal(t, 40*time.Minute, wait)
+	assert.Empty(t, created)
+	assert.Equal(t, "2024-03-01T12:00:00Z", status.LastRun)
+
+	// the last run is kept when the images cannot be listed
+	listFailure = true
+	status, wait = takeScheduledSnapshots(clusterdContext, clusterInfo, "replicapool", spec, status, now.Add(time.Hour))
+	assert.Equal(t, scheduledSnapshotsRetryInterval, wait)
+	assert.Equal(t, "2024-03-01T12:00:00Z", status.LastRun)
+	assert.Equal(t, 1, status.SnapshotsCreated)
+	assert.Contains(t, status.Details, "timed out")
+
+	listFailure = false
+	status, wait = takeScheduledSnapshots(clusterdContext, clusterInfo, "replicapool", spec, status, now.Add(61*time.Minute))
+	assert.Equal(t, time.Hour, wait)
+	assert.Equal(t, []string{"replicapool/img1@rook-scheduled-20240301-130100"}, created)
+	assert.Equal(t, "2024-03-01T13:01:00Z", status.LastRun)
+	assert.Empty(t, status.Details)
+}
+
+func TestScheduledSnapshotsCheckers(t *testing.T) {
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			switch {
+			case args[0] == "ls":
+				return `[{"image":"img1","size":1024,"format":2}]`, nil
+			case args[0] == "snap" && args[1] == "ls":
+				return "[]", nil
+			}
+			return "", nil
+		},
+	}
+	clusterdContext := &clusterd.Context{Executor: executor}
+	clusterInfo := cephclient.AdminTestClusterInfo("mycluster")
+	ctx, cancel := context.WithCancel(context.TODO())
+	defer cancel()
+
+	statuses := make(chan *cephv1.RBDScheduledSnapshotsStatus, 1)
+	updateStatus := func(status *cephv1.RBDScheduledSnapshotsStatus) { statuses <- status }
+	checkers := ScheduledSnapshotsCheckers{}
+
+	// the checker takes the snapshots in the background and reports the status
+	spec := &cephv1.RBDScheduledSnapshotsSpec{Interval: "1h"}
+	checkers.Start(ctx, "replicapool", NewScheduledSnapshotsChecker(clusterdContext, clusterInfo, "replicapool", spec, nil, updateStatus))
+	status := <-statuses
+	assert.Equal(t, 1, status.SnapshotsCreated)
+	assert.Len(t, checkers.checkers, 1)
+	monitoring := checkers.checkers["replicapool"]
+
+	// the same schedule does not restart the checker
+	checkers.Start(ctx, "replicapool", NewScheduledSnapshotsChecker(clusterdContext, clusterInfo, "replicapool", spec, status, updateStatus))
+	assert.Same(t, monitoring, checkers.checkers["replicapool"])
+
+	// a new schedule restarts the checker
+	spec = &cephv1.RBDScheduledSnapshotsSpec{Interval: "2h"}
+	checkers.Start(ctx, "replicapool", NewScheduledSnapshotsChecker(clusterdContext, clusterInfo, "replicapool", spec, status, updateStatus))
+	assert.NotSame(t, monitoring, checkers.checkers["replicapool"])
+	assert.Equal(t, "2h", checkers.checkers["replicapool"].spec.Interval)
+
+	checkers.Stop("replicapool")
+	assert.Empty(t, checkers.checkers)
+	// stopping a checker that is not running is a noop
+	checkers.Stop("replicapool")
+}
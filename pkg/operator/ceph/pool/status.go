@@ -59,7 +59,7 @@ func updateStatus(ctx context.Context, client client.Client, poolName types.Name
 }
 
 // updateStatusBucket updates an object with a given status
-func (c *mirrorChecker) updateStatusMirroring(mirrorStatus *cephv1.PoolMirroringStatusSummarySpec, mirrorInfo *cephv1.PoolMirroringInfo, snapSchedStatus []cephv1.SnapshotSchedulesSpec, details string) {
+func (c *mirrorChecker) updateStatusMirroring(mirrorStatus *cephv1.PoolMirroringStatusSummarySpec, mirrorInfo *cephv1.PoolMirroringInfo, snapSchedStatus []cephv1.SnapshotSchedulesSpec, details string, images []cephv1.MirroredImageStatusSpec) {
 	blockPool := &cephv1.CephBlockPool{}
 	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, blockPool); err != nil {
 		if kerrors.IsNotFound(err) {
@@ -75,6 +75,12 @@ func (c *mirrorChecker) updateStatusMirroring(mirrorStatus *cephv1.PoolMirroring
 
 	// Update the CephBlockPool CR status field
 	blockPool.Status.MirroringStatus, blockPool.Status.MirroringInfo, blockPool.Status.SnapshotScheduleStatus = toCustomResourceStatus(blockPool.Status.MirroringStatus, mirrorStatus, blockPool.Status.MirroringInfo, mirrorInfo, blockPool.Status.SnapshotScheduleStatus, snapSchedStatus, details)
+	// Only the images that are the most behind are reported, all the images are exported as metrics
+	blockPool.Status.MirroringStatus.ImageCount = len(images)
+	if len(images) > maxWorstMirroredImages {
+		images = images[:maxWorstMirroredImages]
+	}
+	blockPool.Status.MirroringStatus.WorstImages = images
 	if err := reporting.UpdateStatus(c.client, blockPool); err != nil {
 		logger.Errorf("failed to set ceph block pool %q mirroring status. %v", c.namespacedName.Name, err)
 		return
@@ -0,0 +1,280 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	"github.com/rook/rook/pkg/operator/ceph/reporting"
+	"github.com/rook/rook/pkg/util/display"
+	v1 "k8s.io/api/core/v1"
+	kerrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// quotaNearlyFullPercent is the percentage of a quota above which the quota is reported nearly full
+const quotaNearlyFullPercent = 90
+
+type usageChecker struct {
+	context        *clusterd.Context
+	interval       time.Duration
+	client         client.Client
+	clusterInfo    *cephclient.ClusterInfo
+	namespacedName types.NamespacedName
+	poolName       string
+}
+
+// newUsageChecker creates a checker of the usage of a pool
+func newUsageChecker(context *clusterd.Context, client client.Client, clusterInfo *cephclient.ClusterInfo, namespacedName types.NamespacedName, poolSpec *cephv1.NamedPoolSpec) *usageChecker {
+	c := &usageChecker{
+		context:        context,
+		interval:       defaultHealthCheckInterval,
+		client:         client,
+		clusterInfo:    clusterInfo,
+		namespacedName: namespacedName,
+		poolName:       poolSpec.Name,
+	}
+
+	// allow overriding the check interval
+	if checkInterval := poolSpec.StatusCheck.Usage.Interval; checkInterval != nil {
+		c.interval = checkInterval.Duration
+	}
+
+	return c
+}
+
+// reconcileUsageMonitoring starts the periodic check of the usage of the pool, or stops it if it
+// is disabled
+func (r *ReconcileCephBlockPool) reconcileUsageMonitoring(cephBlockPool *cephv1.CephBlockPool, namespacedName types.NamespacedName) {
+	poolSpec := cephBlockPool.ToNamedPoolSpec()
+	checker := newUsageChecker(r.context, r.client, r.clusterInfo, namespacedName, &poolSpec)
+	if cephBlockPool.Spec.StatusCheck.Usage.Disabled {
+		r.cancelUsageMonitoring(cephBlockPool)
+		if cephBlockPool.Status != nil && cephBlockPool.Status.Usage != nil {
+			checker.updateStatusUsage(nil)
+		}
+		return
+	}
+
+	key := blockPoolChannelKeyName(cephBlockPool)
+	if monitoring, ok := r.usageCheckers[key]; ok {
+		if monitoring.interval == checker.interval {
+			logger.Debugf("pool %q usage monitoring go routine already running", namespacedName.Name)
+			return
+		}
+		// restart the check with the new interval
+		r.cancelUsageMonitoring(cephBlockPool)
+	}
+
+	if r.usageCheckers == nil {
+		r.usageCheckers = map[string]*usageMonitoring{}
+	}
+	internalCtx, internalCancel := context.WithCancel(r.opManagerContext)
+	r.usageCheckers[key] = &usageMonitoring{cancel: internalCancel, interval: checker.interval}
+	go checker.checkUsage(internalCtx)
+}
+
+type usageMonitoring struct {
+	cancel   context.CancelFunc
+	interval time.Duration
+}
+
+// cancelUsageMonitoring stops the check of the usage of the pool. This is a noop if the check is
+// not running.
+func (r *ReconcileCephBlockPool) cancelUsageMonitoring(cephBlockPool *cephv1.CephBlockPool) {
+	key := blockPoolChannelKeyName(cephBlockPool)
+	if monitoring, ok := r.usageCheckers[key]; ok {
+		monitoring.cancel()
+		delete(r.usageCheckers, key)
+	}
+}
+
+// checkUsage periodically reports the usage of the pool in the status
+func (c *usageChecker) checkUsage(ctx context.Context) {
+	c.updateUsage()
+
+	for {
+		select {
+		case <-ctx.Done():
+			logger.Infof("stopping monitoring pool usage %q", c.namespacedName.Name)
+			return
+
+		case <-time.After(c.interval):
+			logger.Debugf("checking pool usage %q", c.namespacedName.Name)
+			c.updateUsage()
+		}
+	}
+}
+
+func (c *usageChecker) updateUsage() {
+	usage, err := c.getUsage()
+	if err != nil {
+		logger.Debugf("failed to check the usage of ceph block pool %q. %v", c.namespacedName.Name, err)
+		usage = &cephv1.PoolUsageStatus{LastChecked: time.Now().UTC().Format(time.RFC3339), Details: err.Error()}
+	}
+	c.updateStatusUsage(usage)
+}
+
+func (c *usageChecker) getUsage() (*cephv1.PoolUsageStatus, error) {
+	stats, err := cephclient.GetPoolStats(c.context, c.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	autoscaleStatus, err := cephclient.GetPoolAutoscaleStatus(c.context, c.clusterInfo)
+	if err != nil {
+		return nil, err
+	}
+	return poolUsageStatus(c.poolName, stats, autoscaleStatus)
+}
+
+// poolUsageStatus builds the usage status of a pool from the pool stats and the autoscaler status
+func poolUsageStatus(poolName string, stats *cephclient.CephStoragePoolStats, autoscaleStatus []cephclient.PoolAutoscaleStatus) (*cephv1.PoolUsageStatus, error) {
+	usage := &cephv1.PoolUsageStatus{LastChecked: time.Now().UTC().Format(time.RFC3339)}
+	found := false
+	for _, pool := range stats.Pools {
+		if pool.Name != poolName {
+			continue
+		}
+		found = true
+		usage.StoredBytes = uint64(pool.Stats.Stored)
+		usage.Stored = display.BytesToString(usage.StoredBytes)
+		usage.UsedBytes = uint64(pool.Stats.BytesUsed)
+		usage.AvailableBytes = uint64(pool.Stats.MaxAvail)
+		usage.Available = display.BytesToString(usage.AvailableBytes)
+		usage.Objects = uint64(pool.Stats.Objects)
+
+		if pool.Stats.QuotaBytes > 0 || pool.Stats.QuotaObjects > 0 {
+			usage.Quota = &cephv1.PoolQuotaStatus{
+				MaxBytes:   uint64(pool.Stats.QuotaBytes),
+				MaxObjects: uint64(pool.Stats.QuotaObjects),
+			}
+			if pool.Stats.QuotaBytes > 0 {
+				usage.Quota.BytesUsedPercent = int(100 * pool.Stats.Stored / pool.Stats.QuotaBytes)
+			}
+			if pool.Stats.QuotaObjects > 0 {
+				usage.Quota.ObjectsUsedPercent = int(100 * pool.Stats.Objects / pool.Stats.QuotaObjects)
+			}
+		}
+
+		if pool.Stats.CompressUnderBytes > 0 {
+			usage.Compression = &cephv1.PoolCompressionStatus{
+				CompressedBytes:   uint64(pool.Stats.CompressBytesUsed),
+				UncompressedBytes: uint64(pool.Stats.CompressUnderBytes),
+			}
+			if pool.Stats.CompressUnderBytes > pool.Stats.CompressBytesUsed {
+				usage.Compression.SavedBytes = uint64(pool.Stats.CompressUnderBytes - pool.Stats.CompressBytesUsed)
+			}
+		}
+	}
+	if !found {
+		return nil, errors.Errorf("pool %q not found in the pool stats", poolName)
+	}
+
+	for _, pool := range autoscaleStatus {
+		if pool.Name == poolName {
+			usage.PGs = &cephv1.PoolPGStatus{
+				PGNum:         pool.PGNumTarget,
+				AutoscaleMode: pool.AutoscaleMode,
+				TargetPGNum:   pool.PGNumFinal,
+			}
+		}
+	}
+
+	return usage, nil
+}
+
+// quotaCondition returns the condition of the quota of the pool, or nil if the pool has no quota
+// and never had one
+func quotaCondition(usage *cephv1.PoolUsageStatus, conditions []cephv1.Condition) *cephv1.Condition {
+	if usage == nil || usage.Quota == nil {
+		if cephv1.FindStatusCondition(conditions, cephv1.ConditionPoolQuotaNearlyFull) == nil {
+			return nil
+		}
+		message := "the pool has no quota"
+		if usage == nil {
+			message = "the usage of the pool is not checked"
+		}
+		return &cephv1.Condition{
+			Type:    cephv1.ConditionPoolQuotaNearlyFull,
+			Status:  v1.ConditionFalse,
+			Reason:  cephv1.PoolQuotaAvailableReason,
+			Message: message,
+		}
+	}
+
+	percent := usage.Quota.BytesUsedPercent
+	kind := "bytes"
+	if usage.Quota.ObjectsUsedPercent > percent {
+		percent = usage.Quota.ObjectsUsedPercent
+		kind = "objects"
+	}
+	message := fmt.Sprintf("%d%% of the %s quota is used", percent, kind)
+	if percent >= quotaNearlyFullPercent {
+		return &cephv1.Condition{
+			Type:    cephv1.ConditionPoolQuotaNearlyFull,
+			Status:  v1.ConditionTrue,
+			Reason:  cephv1.PoolQuotaNearlyFullReason,
+			Message: message,
+		}
+	}
+	return &cephv1.Condition{
+		Type:    cephv1.ConditionPoolQuotaNearlyFull,
+		Status:  v1.ConditionFalse,
+		Reason:  cephv1.PoolQuotaAvailableReason,
+		Message: message,
+	}
+}
+
+// updateStatusUsage updates the usage status and the quota condition of the pool
+func (c *usageChecker) updateStatusUsage(usage *cephv1.PoolUsageStatus) {
+	blockPool := &cephv1.CephBlockPool{}
+	if err := c.client.Get(c.clusterInfo.Context, c.namespacedName, blockPool); err != nil {
+		if kerrors.IsNotFound(err) {
+			logger.Debug("CephBlockPool resource not found. Ignoring since object must be deleted.")
+			return
+		}
+		logger.Warningf("failed to retrieve ceph block pool %q to update usage status. %v", c.namespacedName.Name, err)
+		return
+	}
+	if blockPool.Status == nil {
+		blockPool.Status = &cephv1.CephBlockPoolStatus{}
+	}
+
+	// keep the last known usage when the usage could not be checked
+	if usage != nil && usage.Details != "" && blockPool.Status.Usage != nil {
+		current := *blockPool.Status.Usage
+		current.LastChecked = usage.LastChecked
+		current.Details = usage.Details
+		usage = &current
+	}
+	blockPool.Status.Usage = usage
+	if condition := quotaCondition(usage, blockPool.Status.Conditions); condition != nil {
+		cephv1.SetStatusCondition(&blockPool.Status.Conditions, *condition)
+	}
+	if err := reporting.UpdateStatus(c.client, blockPool); err != nil {
+		logger.Errorf("failed to set ceph block pool %q usage status. %v", c.namespacedName.Name, err)
+		return
+	}
+	logger.Debugf("ceph block pool %q usage status updated", c.namespacedName.Name)
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package pool
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/pkg/errors"
+	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
+	"github.com/rook/rook/pkg/client/clientset/versioned/scheme"
+	"github.com/rook/rook/pkg/clusterd"
+	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	exectest "github.com/rook/rook/pkg/util/exec/test"
+	"github.com/stretchr/testify/assert"
+	v1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	dfDetail = `{"pools":[
+{"name":".mgr","id":1,"stats":{"stored":1000,"objects":2,"bytes_used":3000,"max_avail":1000000}},
+{"name":"replicapool","id":2,"stats":{"stored":943718400,"objects":950,"bytes_used":2831155200,"max_avail":10737418240,
+"quota_bytes":1048576000,"quota_objects":0,"compress_bytes_used":100,"compress_under_bytes":400}}]}`
+	autoscaleStatus = `[{"pool_name":".mgr","pg_num_target":1,"pg_num_final":1,"pg_autoscale_mode":"on"},
+{"pool_name":"replicapool","pg_num_target":32,"pg_num_final":64,"pg_autoscale_mode":"warn"}]`
+)
+
+func TestPoolUsageStatus(t *testing.T) {
+	var stats cephclient.CephStoragePoolStats
+	assert.NoError(t, json.Unmarshal([]byte(dfDetail), &stats))
+	var autoscale []cephclient.PoolAutoscaleStatus
+	assert.NoError(t, json.Unmarshal([]byte(autoscaleStatus), &autoscale))
+
+	usage, err := poolUsageStatus("replicapool", &stats, autoscale)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(943718400), usage.StoredBytes)
+	assert.Equal(t, "900.00 MiB", usage.Stored)
+	assert.Equal(t, uint64(2831155200), usage.UsedBytes)
+	assert.Equal(t, "10.00 GiB", usage.Available)
+	assert.Equal(t, uint64(950), usage.Objects)
+	assert.Equal(t, &cephv1.PoolQuotaStatus{MaxBytes: 1048576000, BytesUsedPercent: 90}, usage.Quota)
+	assert.Equal(t, &cephv1.PoolPGStatus{PGNum: 32, AutoscaleMode: "warn", TargetPGNum: 64}, usage.PGs)
+	assert.Equal(t, &cephv1.PoolCompressionStatus{CompressedBytes: 100, UncompressedBytes: 400, SavedBytes: 300}, usage.Compression)
+	assert.NotEmpty(t, usage.LastChecked)
+
+	usage, err = poolUsageStatus(".mgr", &stats, autoscale)
+	assert.NoError(t, err)
+	assert.Nil(t, usage.Quota)
+	assert.Nil(t, usage.Compression)
+
+	_, err = poolUsageStatus("unknown", &stats, autoscale)
+	assert.EqualError(t, err, `pool "unknown" not found in the pool stats`)
+}
+
+func TestQuotaCondition(t *testing.T) {
+	// no quota and no previous condition
+	assert.Nil(t, quotaCondition(&cephv1.PoolUsageStatus{}, nil))
+
+	usage := &cephv1.PoolUsageStatus{Quota: &cephv1.PoolQuotaStatus{MaxBytes: 100, BytesUsedPercent: 50, MaxObjects: 10, ObjectsUsedPercent: 95}}
+	condition := quotaCondition(usage, nil)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+	assert.Equal(t, cephv1.PoolQuotaNearlyFullReason, condition.Reason)
+	assert.Equal(t, "95% of the objects quota is used", condition.Message)
+
+	usage.Quota.ObjectsUsedPercent = 10
+	condition = quotaCondition(usage, nil)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, "50% of the bytes quota is used", condition.Message)
+
+	// the quota was removed
+	conditions := []cephv1.Condition{*condition}
+	condition = quotaCondition(&cephv1.PoolUsageStatus{}, conditions)
+	assert.Equal(t, v1.ConditionFalse, condition.Status)
+	assert.Equal(t, "the pool has no quota", condition.Message)
+}
+
+func TestUpdateUsage(t *testing.T) {
+	nn := types.NamespacedName{Name: "replicapool", Namespace: "rook-ceph"}
+	cephBlockPool := &cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: nn.Name, Namespace: nn.Namespace}}
+	cl := fake.NewClientBuilder().WithScheme(scheme.Scheme).WithObjects(cephBlockPool).WithStatusSubresource(cephBlockPool).Build()
+
+	failure := false
+	executor := &exectest.MockExecutor{
+		MockExecuteCommandWithOutput: func(command string, args ...string) (string, error) {
+			if failure {
+				return "", errors.New("timed out")
+			}
+			if args[0] == "df" {
+				return dfDetail, nil
+			}
+			if args[0] == "osd" && args[2] == "autoscale-status" {
+				return autoscaleStatus, nil
+			}
+			return "", errors.New("unknown command")
+		},
+	}
+	poolSpec := cephBlockPool.ToNamedPoolSpec()
+	checker := newUsageChecker(&clusterd.Context{Executor: executor}, cl, cephclient.AdminTestClusterInfo("rook-ceph"), nn, &poolSpec)
+
+	checker.updateUsage()
+	assert.NoError(t, cl.Get(checker.clusterInfo.Context, nn, cephBlockPool))
+	assert.Equal(t, 90, cephBlockPool.Status.Usage.Quota.BytesUsedPercent)
+	condition := cephv1.FindStatusCondition(cephBlockPool.Status.Conditions, cephv1.ConditionPoolQuotaNearlyFull)
+	assert.Equal(t, v1.ConditionTrue, condition.Status)
+
+	// the last known usage is kept when the usage cannot be checked
+	failure = true
+	checker.updateUsage()
+	assert.NoError(t, cl.Get(checker.clusterInfo.Context, nn, cephBlockPool))
+	assert.Equal(t, uint64(950), cephBlockPool.Status.Usage.Objects)
+	assert.Equal(t, "failed to get pool stats: timed out", cephBlockPool.Status.Usage.Details)
+}
@@ -22,6 +22,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 )
 
 // validatePool Validate the pool arguments
@@ -40,6 +41,24 @@ func validatePool(context *clusterd.Context, clusterInfo *cephclient.ClusterInfo
 	if err := ValidatePoolSpec(context, clusterInfo, clusterSpec, &p.Spec.PoolSpec); err != nil {
 		return err
 	}
+
+	if p.Spec.QoS != nil {
+		if p.Spec.Application != "" && p.Spec.Application != "rbd" {
+			return errors.Errorf("qos only applies to rbd pools, not to %q pools", p.Spec.Application)
+		}
+		if _, err := cephclient.RBDQoSOptions(p.Spec.QoS); err != nil {
+			return errors.Wrap(err, "invalid qos")
+		}
+	}
+
+	if p.Spec.ScheduledSnapshots != nil {
+		if p.Spec.Application != "" && p.Spec.Application != "rbd" {
+			return errors.Errorf("scheduled snapshots only apply to rbd pools, not to %q pools", p.Spec.Application)
+		}
+		if _, err := scheduledSnapshotsInterval(p.Spec.ScheduledSnapshots.Interval); err != nil {
+			return err
+		}
+	}
 	return nil
 }
 
@@ -64,7 +83,7 @@ func ValidatePoolSpec(context *clusterd.Context, clusterInfo *cephclient.Cluster
 	}
 
 	// validate pools for stretch clusters
-	if clusterSpec.IsStretchCluster() {
+	if clusterSpec.StretchModeEnabled() {
 		if p.IsReplicated() {
 			if p.Replicated.Size != 4 {
 				return errors.New("pools in a stretch cluster must have replication size 4")
@@ -73,6 +92,19 @@ func ValidatePoolSpec(context *clusterd.Context, clusterInfo *cephclient.Cluster
 		if p.IsErasureCoded() {
 			return errors.New("erasure coded pools are not supported in stretch clusters")
 		}
+		if p.CrushRule != "" {
+			return errors.New("custom crush rules are not supported in stretch clusters")
+		}
+	}
+
+	if p.Stretch != nil {
+		if err := validateStretchPool(clusterInfo, clusterSpec, p); err != nil {
+			return err
+		}
+	}
+
+	if p.CrushRule != "" && p.IsHybridStoragePool() {
+		return errors.New("a custom crush rule cannot be specified with hybrid storage")
 	}
 
 	var crush cephclient.CrushMap
@@ -215,3 +247,23 @@ func validateDeviceClassOSDs(context *clusterd.Context, clusterInfo *cephclient.
 
 	return nil
 }
+
+// validateStretchPool validates the stretch settings of a pool
+func validateStretchPool(clusterInfo *cephclient.ClusterInfo, clusterSpec *cephv1.ClusterSpec, p *cephv1.PoolSpec) error {
+	if clusterSpec.StretchModeEnabled() {
+		return errors.New("stretch settings are not supported on pools of a cluster in the ceph stretch mode, all the pools are already stretched")
+	}
+	if !p.IsReplicated() {
+		return errors.New("stretch settings are only supported on replicated pools")
+	}
+	if !clusterInfo.CephVersion.IsAtLeast(cephver.Squid) {
+		return errors.Errorf("stretch pools require ceph version %q, but is running %s", cephver.Squid.String(), clusterInfo.CephVersion.String())
+	}
+	if p.Stretch.PeeringCrushBucketCount < 1 {
+		return errors.Errorf("invalid stretch peering crush bucket count %d, must be at least 1", p.Stretch.PeeringCrushBucketCount)
+	}
+	if p.Stretch.PeeringCrushBucketTarget != 0 && p.Stretch.PeeringCrushBucketTarget < p.Stretch.PeeringCrushBucketCount {
+		return errors.Errorf("stretch peering crush bucket target %d must not be less than the peering crush bucket count %d", p.Stretch.PeeringCrushBucketTarget, p.Stretch.PeeringCrushBucketCount)
+	}
+	return nil
+}
@@ -24,6 +24,7 @@ import (
 	cephv1 "github.com/rook/rook/pkg/apis/ceph.rook.io/v1"
 	"github.com/rook/rook/pkg/clusterd"
 	cephclient "github.com/rook/rook/pkg/daemon/ceph/client"
+	cephver "github.com/rook/rook/pkg/operator/ceph/version"
 	exectest "github.com/rook/rook/pkg/util/exec/test"
 	"github.com/stretchr/testify/assert"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
@@ -206,6 +207,85 @@ func TestValidatePool(t *testing.T) {
 		assert.Error(t, err)
 		assert.EqualError(t, err, "failure and subfailure domain cannot be identical")
 	})
+
+	t.Run("custom crush rule", func(t *testing.T) {
+		p := cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
+		p.Spec.Replicated.Size = 4
+		p.Spec.CrushRule = "my-rule"
+		err := validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.NoError(t, err)
+
+		stretchClusterSpec := &cephv1.ClusterSpec{Mon: cephv1.MonSpec{StretchCluster: &cephv1.StretchClusterSpec{Zones: []cephv1.MonZoneSpec{{Name: "a"}, {Name: "b"}, {Name: "c", Arbiter: true}}}}}
+		err = validatePool(context, clusterInfo, stretchClusterSpec, &p)
+		assert.EqualError(t, err, "custom crush rules are not supported in stretch clusters")
+	})
+
+	t.Run("stretch pool", func(t *testing.T) {
+		p := cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
+		p.Spec.Replicated.Size = 4
+		p.Spec.Stretch = &cephv1.StretchPoolSpec{PeeringCrushBucketCount: 2}
+		squidClusterInfo := &cephclient.ClusterInfo{Namespace: clusterInfo.Namespace, CephVersion: cephver.Squid}
+		err := validatePool(context, squidClusterInfo, clusterSpec, &p)
+		assert.NoError(t, err)
+
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.ErrorContains(t, err, "stretch pools require ceph version")
+
+		p.Spec.Stretch.PeeringCrushBucketTarget = 1
+		err = validatePool(context, squidClusterInfo, clusterSpec, &p)
+		assert.EqualError(t, err, "stretch peering crush bucket target 1 must not be less than the peering crush bucket count 2")
+
+		p.Spec.Stretch.PeeringCrushBucketTarget = 3
+		stretchClusterSpec := &cephv1.ClusterSpec{Mon: cephv1.MonSpec{StretchCluster: &cephv1.StretchClusterSpec{Zones: []cephv1.MonZoneSpec{{Name: "a"}, {Name: "b"}, {Name: "c", Arbiter: true}}}}}
+		err = validatePool(context, squidClusterInfo, stretchClusterSpec, &p)
+		assert.ErrorContains(t, err, "stretch settings are not supported on pools of a cluster in the ceph stretch mode")
+
+		// pools of any size and with custom crush rules are allowed without the ceph stretch mode
+		stretchClusterSpec.Mon.StretchCluster.Mode = cephv1.StretchModePools
+		p.Spec.Replicated.Size = 6
+		p.Spec.CrushRule = "my-rule"
+		err = validatePool(context, squidClusterInfo, stretchClusterSpec, &p)
+		assert.NoError(t, err)
+
+		p.Spec.Replicated.Size = 0
+		p.Spec.ErasureCoded = cephv1.ErasureCodedSpec{DataChunks: 2, CodingChunks: 1}
+		err = validatePool(context, squidClusterInfo, stretchClusterSpec, &p)
+		assert.EqualError(t, err, "stretch settings are only supported on replicated pools")
+	})
+
+	t.Run("qos", func(t *testing.T) {
+		p := cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
+		p.Spec.Replicated.Size = 1
+		p.Spec.QoS = &cephv1.RBDQoSSpec{IOPSLimit: 1000, BPSLimit: "100Mi"}
+		err := validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.NoError(t, err)
+
+		p.Spec.QoS.WriteBPSBurst = "fast"
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.ErrorContains(t, err, "invalid value \"fast\" of rbd_qos_write_bps_burst")
+
+		p.Spec.QoS.WriteBPSBurst = ""
+		p.Spec.Application = "rgw"
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.EqualError(t, err, "qos only applies to rbd pools, not to \"rgw\" pools")
+	})
+
+	t.Run("scheduled snapshots", func(t *testing.T) {
+		p := cephv1.CephBlockPool{ObjectMeta: metav1.ObjectMeta{Name: "mypool", Namespace: clusterInfo.Namespace}}
+		p.Spec.Replicated.Size = 1
+		p.Spec.ScheduledSnapshots = &cephv1.RBDScheduledSnapshotsSpec{Interval: "6h", Retention: 4}
+		err := validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.NoError(t, err)
+
+		p.Spec.ScheduledSnapshots.Interval = "0d"
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.EqualError(t, err, "invalid scheduled snapshots interval \"0d\"")
+
+		p.Spec.ScheduledSnapshots.Interval = "1d"
+		p.Spec.Application = "rgw"
+		err = validatePool(context, clusterInfo, clusterSpec, &p)
+		assert.EqualError(t, err, "scheduled snapshots only apply to rbd pools, not to \"rgw\" pools")
+	})
 }
 
 func TestValidateCrushProperties(t *testing.T) {
@@ -120,3 +120,54 @@ func DeleteServiceMonitor(context *clusterd.Context, ctx context.Context, ns str
 	}
 	return nil
 }
+
+// CreateOrUpdatePrometheusRule creates or updates a PrometheusRule
+func CreateOrUpdatePrometheusRule(context *clusterd.Context, ctx context.Context, ruleDefinition *monitoringv1.PrometheusRule) (*monitoringv1.PrometheusRule, error) {
+	name := ruleDefinition.GetName()
+	namespace := ruleDefinition.GetNamespace()
+	logger.Debugf("creating prometheusrule %s", name)
+	client, err := getMonitoringClient(context)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get monitoring client. %v", err)
+	}
+	oldRule, err := client.MonitoringV1().PrometheusRules(namespace).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			rule, err := client.MonitoringV1().PrometheusRules(namespace).Create(ctx, ruleDefinition, metav1.CreateOptions{})
+			if err != nil {
+				return nil, fmt.Errorf("failed to create prometheusrule. %v", err)
+			}
+			return rule, nil
+		}
+		return nil, fmt.Errorf("failed to retrieve prometheusrule. %v", err)
+	}
+	oldRule.Spec = ruleDefinition.Spec
+	oldRule.ObjectMeta.Labels = ruleDefinition.ObjectMeta.Labels
+	rule, err := client.MonitoringV1().PrometheusRules(namespace).Update(ctx, oldRule, metav1.UpdateOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("failed to update prometheusrule. %v", err)
+	}
+	return rule, nil
+}
+
+// DeletePrometheusRule deletes a PrometheusRule and returns the error if any
+func DeletePrometheusRule(context *clusterd.Context, ctx context.Context, ns string, name string) error {
+	client, err := getMonitoringClient(context)
+	if err != nil {
+		return fmt.Errorf("failed to get monitoring client. %v", err)
+	}
+	_, err = client.MonitoringV1().PrometheusRules(ns).Get(ctx, name, metav1.GetOptions{})
+	if err != nil {
+		// Either the prometheus rule does not exist or there are no privileges to detect it
+		// so we ignore any errors
+		return nil
+	}
+	err = client.MonitoringV1().PrometheusRules(ns).Delete(ctx, name, metav1.DeleteOptions{})
+	if err != nil {
+		if kerrors.IsNotFound(err) {
+			return nil
+		}
+		return errors.Wrapf(err, "failed to delete prometheus rule %q", name)
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"strings"
+
+	"github.com/rook/rook/pkg/util/tracing"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// maxTracedSubcommandArgs is the max number of args of the subcommand added to the span of a command
+const maxTracedSubcommandArgs = 3
+
+// StartCommandSpan starts the span of a command when the context is traced, such as during a
+// reconcile. The returned function ends the span with the error of the command.
+func StartCommandSpan(ctx context.Context, command string, arg ...string) func(error) {
+	if ctx == nil || !tracing.IsRecording(ctx) {
+		return func(error) {}
+	}
+
+	subcommand := tracedSubcommand(arg...)
+	name := command
+	if subcommand != "" {
+		name = command + " " + subcommand
+	}
+	_, span := tracing.Tracer().Start(ctx, name, trace.WithAttributes(
+		attribute.String("exec.command", command),
+		attribute.String("exec.subcommand", subcommand),
+	))
+	return func(err error) {
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		}
+		span.End()
+	}
+}
+
+// tracedSubcommand returns the first args of a command until the first flag, such as "osd pool get" for
+// "ceph osd pool get replicapool all --format json". The other args are not returned since they may
+// hold values such as keys that must not be exported.
+func tracedSubcommand(arg ...string) string {
+	args := []string{}
+	for _, a := range arg {
+		if strings.HasPrefix(a, "-") || len(args) == maxTracedSubcommandArgs {
+			break
+		}
+		args = append(args, a)
+	}
+	return strings.Join(args, " ")
+}
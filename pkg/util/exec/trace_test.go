@@ -0,0 +1,71 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package exec
+
+import (
+	"context"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestTracedSubcommand(t *testing.T) {
+	assert.Equal(t, "osd pool get", tracedSubcommand("osd", "pool", "get", "replicapool", "all", "--format", "json"))
+	assert.Equal(t, "status", tracedSubcommand("status", "--format", "json"))
+	assert.Equal(t, "config-key set mykey", tracedSubcommand("config-key", "set", "mykey", "secret"))
+	assert.Equal(t, "", tracedSubcommand("--version"))
+	assert.Equal(t, "", tracedSubcommand())
+}
+
+func TestStartCommandSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	defaultProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(defaultProvider)
+
+	t.Run("not traced", func(t *testing.T) {
+		StartCommandSpan(context.TODO(), "ceph", "status")(nil)
+		assert.Empty(t, recorder.Ended())
+	})
+
+	t.Run("traced", func(t *testing.T) {
+		ctx, parent := provider.Tracer("test").Start(context.TODO(), "reconcile")
+		StartCommandSpan(ctx, "ceph", "osd", "pool", "get", "replicapool", "--format", "json")(nil)
+		StartCommandSpan(ctx, "rbd", "mirror", "pool", "info")(errors.New("failed"))
+		parent.End()
+
+		spans := recorder.Ended()
+		assert.Len(t, spans, 3)
+		assert.Equal(t, "ceph osd pool get", spans[0].Name())
+		assert.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+		assert.Contains(t, spans[0].Attributes(), attribute.String("exec.subcommand", "osd pool get"))
+		assert.Equal(t, codes.Unset, spans[0].Status().Code)
+		assert.Equal(t, "rbd mirror pool info", spans[1].Name())
+		assert.Equal(t, codes.Error, spans[1].Status().Code)
+
+		// the commands are not traced once the reconcile is done
+		StartCommandSpan(ctx, "ceph", "status")(nil)
+		assert.Len(t, recorder.Ended(), 3)
+	})
+}
@@ -17,11 +17,133 @@ limitations under the License.
 package util
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
 	"github.com/coreos/pkg/capnslog"
 )
 
 const DefaultLogLevel = capnslog.INFO
 
+const (
+	// LogFormatText is the log format of the messages printed as text, the default
+	LogFormatText = "text"
+	// LogFormatJSON is the log format of the messages printed as json objects, one per line
+	LogFormatJSON = "json"
+)
+
+// LogFields are the fields of a structured log entry
+type LogFields map[string]string
+
+// StructuredLogEntry is a log message with fields. The fields are appended to the message as
+// "key=value" pairs with the text log format, and are keys of the entry with the json log format.
+type StructuredLogEntry struct {
+	Message string
+	Fields  LogFields
+}
+
+func (e StructuredLogEntry) String() string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	b.WriteString(e.Message)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%q", k, e.Fields[k])
+	}
+	return b.String()
+}
+
+type logFieldsContextKey struct{}
+
+// ContextWithLogFields returns a copy of the context with the fields of the logs of its ContextLogger
+func ContextWithLogFields(ctx context.Context, fields LogFields) context.Context {
+	return context.WithValue(ctx, logFieldsContextKey{}, fields)
+}
+
+// LogFieldsFromContext returns the log fields of the context, or nil if the context has none
+func LogFieldsFromContext(ctx context.Context) LogFields {
+	fields, _ := ctx.Value(logFieldsContextKey{}).(LogFields)
+	return fields
+}
+
+// ContextLogger is a package logger adding the log fields of a context to the messages, such as the
+// name, namespace and ID of a reconcile. The fields are only added with the json log format so that
+// the text logs are unchanged.
+type ContextLogger struct {
+	*capnslog.PackageLogger
+	fields LogFields
+}
+
+// NewContextLogger returns the logger of the package with the log fields of the context
+func NewContextLogger(ctx context.Context, logger *capnslog.PackageLogger) *ContextLogger {
+	return &ContextLogger{PackageLogger: logger, fields: LogFieldsFromContext(ctx)}
+}
+
+func (l *ContextLogger) log(logFunc func(entries ...interface{}), message string) {
+	if len(l.fields) == 0 || currentLogFormat != LogFormatJSON {
+		logFunc(message)
+		return
+	}
+	logFunc(StructuredLogEntry{Message: message, Fields: l.fields})
+}
+
+func (l *ContextLogger) Errorf(format string, args ...interface{}) {
+	l.log(l.PackageLogger.Error, fmt.Sprintf(format, args...))
+}
+
+func (l *ContextLogger) Error(entries ...interface{}) {
+	l.log(l.PackageLogger.Error, fmt.Sprint(entries...))
+}
+
+func (l *ContextLogger) Warningf(format string, args ...interface{}) {
+	l.log(l.PackageLogger.Warning, fmt.Sprintf(format, args...))
+}
+
+func (l *ContextLogger) Warning(entries ...interface{}) {
+	l.log(l.PackageLogger.Warning, fmt.Sprint(entries...))
+}
+
+func (l *ContextLogger) Noticef(format string, args ...interface{}) {
+	l.log(l.PackageLogger.Notice, fmt.Sprintf(format, args...))
+}
+
+func (l *ContextLogger) Notice(entries ...interface{}) {
+	l.log(l.PackageLogger.Notice, fmt.Sprint(entries...))
+}
+
+func (l *ContextLogger) Infof(format string, args ...interface{}) {
+	l.log(l.PackageLogger.Info, fmt.Sprintf(format, args...))
+}
+
+func (l *ContextLogger) Info(entries ...interface{}) {
+	l.log(l.PackageLogger.Info, fmt.Sprint(entries...))
+}
+
+// Debugf does not format the message when the debug level is disabled, like the package logger
+func (l *ContextLogger) Debugf(format string, args ...interface{}) {
+	if !l.LevelAt(capnslog.DEBUG) {
+		return
+	}
+	l.log(l.PackageLogger.Debug, fmt.Sprintf(format, args...))
+}
+
+func (l *ContextLogger) Debug(entries ...interface{}) {
+	if !l.LevelAt(capnslog.DEBUG) {
+		return
+	}
+	l.log(l.PackageLogger.Debug, fmt.Sprint(entries...))
+}
+
 func SetGlobalLogLevel(userLogLevelSelection string, logger *capnslog.PackageLogger) {
 	// capnslog supports trace level logging, but in Rook we want to treat trace logging as insecure
 	// and block users from finding the value in most circumstances. If they request "TRACE" level
@@ -52,3 +174,65 @@ func SetGlobalLogLevel(userLogLevelSelection string, logger *capnslog.PackageLog
 
 	capnslog.SetGlobalLogLevel(logLevel)
 }
+
+// currentLogFormat is the log format set last. The default formatter of capnslog is kept until the
+// format is changed.
+var currentLogFormat = LogFormatText
+
+// SetLogFormat sets the format of the logs of all the packages, "text" or "json"
+func SetLogFormat(format string, logger *capnslog.PackageLogger) {
+	if format == "" {
+		format = LogFormatText
+	}
+	if format != LogFormatText && format != LogFormatJSON {
+		logger.Errorf("invalid log format %q. defaulting to %q", format, LogFormatText)
+		format = LogFormatText
+	}
+	if format == currentLogFormat {
+		return
+	}
+
+	if format == LogFormatJSON {
+		capnslog.SetFormatter(NewJSONFormatter(os.Stderr))
+	} else {
+		capnslog.SetFormatter(capnslog.NewPrettyFormatter(os.Stderr, false))
+	}
+	currentLogFormat = format
+}
+
+// NewJSONFormatter returns a formatter printing each log entry as a json object with the time, the
+// level, the package and the message of the entry, plus the fields of the structured entries
+func NewJSONFormatter(w io.Writer) capnslog.Formatter {
+	return &jsonFormatter{w: w}
+}
+
+type jsonFormatter struct {
+	w io.Writer
+}
+
+// Format is called with the lock of the capnslog loggers held, so the entries are not interleaved
+func (f *jsonFormatter) Format(pkg string, level capnslog.LogLevel, _ int, entries ...interface{}) {
+	entry := map[string]string{}
+	messages := []interface{}{}
+	for _, e := range entries {
+		if structured, ok := e.(StructuredLogEntry); ok {
+			for k, v := range structured.Fields {
+				entry[k] = v
+			}
+			messages = append(messages, structured.Message)
+			continue
+		}
+		messages = append(messages, e)
+	}
+	// the standard keys are set last so that they are not overwritten by the fields
+	entry["ts"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = level.String()
+	entry["logger"] = pkg
+	entry["msg"] = strings.TrimSuffix(fmt.Sprint(messages...), "\n")
+
+	// a map of strings is always marshalled, the invalid characters are replaced
+	line, _ := json.Marshal(entry)
+	_, _ = f.w.Write(append(line, '\n'))
+}
+
+func (f *jsonFormatter) Flush() {}
@@ -17,6 +17,10 @@ limitations under the License.
 package util
 
 import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/coreos/pkg/capnslog"
@@ -53,3 +57,85 @@ func TestSetGlobalLogLevel(t *testing.T) {
 		})
 	}
 }
+
+func TestStructuredLogEntry(t *testing.T) {
+	entry := StructuredLogEntry{Message: "reconcile done", Fields: LogFields{"namespace": "rook-ceph", "name": "my-store"}}
+	assert.Equal(t, `reconcile done name="my-store" namespace="rook-ceph"`, entry.String())
+	assert.Equal(t, "reconcile done", StructuredLogEntry{Message: "reconcile done"}.String())
+}
+
+func TestJSONFormatter(t *testing.T) {
+	var b bytes.Buffer
+	formatter := NewJSONFormatter(&b)
+
+	formatter.Format("ceph-object-controller", capnslog.INFO, 0, "creating object store \"my-store\"\n")
+	formatter.Format("ceph-object-controller", capnslog.DEBUG, 0, StructuredLogEntry{
+		Message: "reconcile done",
+		Fields:  LogFields{"namespace": "rook-ceph", "name": "my-store", "reconcileID": "1234", "level": "ignored"},
+	})
+
+	lines := bytes.Split(bytes.TrimSpace(b.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	entry := map[string]string{}
+	assert.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, "ceph-object-controller", entry["logger"])
+	assert.Equal(t, `creating object store "my-store"`, entry["msg"])
+	assert.NotEmpty(t, entry["ts"])
+
+	entry = map[string]string{}
+	assert.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.Equal(t, "DEBUG", entry["level"])
+	assert.Equal(t, "reconcile done", entry["msg"])
+	assert.Equal(t, "rook-ceph", entry["namespace"])
+	assert.Equal(t, "my-store", entry["name"])
+	assert.Equal(t, "1234", entry["reconcileID"])
+}
+
+func TestSetLogFormat(t *testing.T) {
+	logger := capnslog.NewPackageLogger("github.com/rook/rook", "pkg/util/logging_test")
+	defer SetLogFormat(LogFormatText, logger)
+
+	SetLogFormat(LogFormatJSON, logger)
+	assert.Equal(t, LogFormatJSON, currentLogFormat)
+	SetLogFormat("invalid", logger)
+	assert.Equal(t, LogFormatText, currentLogFormat)
+	SetLogFormat("", logger)
+	assert.Equal(t, LogFormatText, currentLogFormat)
+}
+
+func TestContextLogger(t *testing.T) {
+	var b bytes.Buffer
+	capnslog.SetFormatter(NewJSONFormatter(&b))
+	currentLogFormat = LogFormatJSON
+	defer func() {
+		capnslog.SetFormatter(capnslog.NewPrettyFormatter(os.Stderr, false))
+		currentLogFormat = LogFormatText
+	}()
+	capnslog.SetGlobalLogLevel(capnslog.INFO)
+	logger := capnslog.NewPackageLogger("github.com/rook/rook", "ceph-object-controller")
+
+	ctx := ContextWithLogFields(context.TODO(), LogFields{"namespace": "rook-ceph", "name": "my-store", "reconcileID": "1234"})
+	contextLogger := NewContextLogger(ctx, logger)
+	contextLogger.Infof("creating object store %q", "my-store")
+	contextLogger.Debugf("not printed at the info level")
+	NewContextLogger(context.TODO(), logger).Warning("no fields")
+
+	lines := bytes.Split(bytes.TrimSpace(b.Bytes()), []byte("\n"))
+	assert.Len(t, lines, 2)
+
+	entry := map[string]string{}
+	assert.NoError(t, json.Unmarshal(lines[0], &entry))
+	assert.Equal(t, "INFO", entry["level"])
+	assert.Equal(t, `creating object store "my-store"`, entry["msg"])
+	assert.Equal(t, "rook-ceph", entry["namespace"])
+	assert.Equal(t, "my-store", entry["name"])
+	assert.Equal(t, "1234", entry["reconcileID"])
+
+	entry = map[string]string{}
+	assert.NoError(t, json.Unmarshal(lines[1], &entry))
+	assert.Equal(t, "WARNING", entry["level"])
+	assert.Equal(t, "no fields", entry["msg"])
+	assert.NotContains(t, entry, "namespace")
+}
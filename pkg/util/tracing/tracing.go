@@ -0,0 +1,114 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package tracing exports the OpenTelemetry traces of the operator to an OTLP collector.
+package tracing
+
+import (
+	"context"
+
+	"github.com/coreos/pkg/capnslog"
+	"github.com/pkg/errors"
+	"github.com/rook/rook/pkg/version"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var logger = capnslog.NewPackageLogger("github.com/rook/rook", "tracing")
+
+// tracerName is the name of the instrumentation of the spans created by rook
+const tracerName = "github.com/rook/rook"
+
+// Config is the configuration of the export of the traces
+type Config struct {
+	// Endpoint is the host:port of the OTLP gRPC receiver of the collector. If empty, the endpoint of
+	// the OTEL_EXPORTER_OTLP_ENDPOINT env var is used, or "localhost:4317" by default.
+	Endpoint string
+	// Insecure disables TLS with the collector, such as with a collector running next to the operator
+	Insecure bool
+}
+
+// Start exports the traces of the service to the OTLP collector of the config until the returned
+// function is called, which flushes the remaining spans
+func Start(ctx context.Context, serviceName string, config Config) (func(context.Context) error, error) {
+	opts := []otlptracegrpc.Option{}
+	if config.Endpoint != "" {
+		opts = append(opts, otlptracegrpc.WithEndpoint(config.Endpoint))
+	}
+	if config.Insecure {
+		opts = append(opts, otlptracegrpc.WithInsecure())
+	}
+	// the exporter connects to the collector in the background, so a collector not running yet
+	// does not prevent the operator from starting
+	exporter, err := otlptracegrpc.New(ctx, opts...)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the otlp trace exporter")
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewSchemaless(
+		attribute.String("service.name", serviceName),
+		attribute.String("service.version", version.Version),
+	))
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to create the trace resource")
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warningf("failed to export traces. %v", err)
+	}))
+
+	if config.Endpoint != "" {
+		logger.Infof("exporting the traces of %q to the otlp endpoint %q", serviceName, config.Endpoint)
+	} else {
+		logger.Infof("exporting the traces of %q to the default otlp endpoint", serviceName)
+	}
+	return provider.Shutdown, nil
+}
+
+// Tracer returns the tracer of the spans of rook. The spans are not recorded unless the export of
+// the traces was started.
+func Tracer() trace.Tracer {
+	return otel.Tracer(tracerName)
+}
+
+// IsRecording returns whether the span of the context is recording, which is the case during a
+// traced reconcile. The span ends with the reconcile, so that the calls made by the goroutines
+// started during the reconcile are not added to the trace of the reconcile once it is done.
+func IsRecording(ctx context.Context) bool {
+	return trace.SpanFromContext(ctx).IsRecording()
+}
+
+// ContextWithSpan returns the context with the span of another context, such as the operator
+// context with the span of a reconcile. The cancellation and the values of the context are kept.
+// The context is returned as is if the other context has no span.
+func ContextWithSpan(ctx, spanContext context.Context) context.Context {
+	span := trace.SpanFromContext(spanContext)
+	if !span.SpanContext().IsValid() {
+		return ctx
+	}
+	return trace.ContextWithSpan(ctx, span)
+}
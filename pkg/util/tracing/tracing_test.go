@@ -0,0 +1,44 @@
+/*
+Copyright 2024 The Rook Authors. All rights reserved.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+	http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestContextWithSpan(t *testing.T) {
+	opManagerContext, cancel := context.WithCancel(context.TODO())
+
+	// no span to add
+	assert.Equal(t, opManagerContext, ContextWithSpan(opManagerContext, context.TODO()))
+	//nolint:staticcheck // the reconcilers of the unit tests may not have an operator context
+	assert.Nil(t, ContextWithSpan(nil, context.TODO()))
+
+	reconcileContext, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.TODO(), "reconcile")
+	ctx := ContextWithSpan(opManagerContext, reconcileContext)
+	assert.True(t, IsRecording(ctx))
+	span.End()
+	assert.False(t, IsRecording(ctx))
+
+	// the cancellation of the operator context is kept
+	cancel()
+	assert.Error(t, ctx.Err())
+}